@@ -0,0 +1,40 @@
+package audit
+
+// Map of audited actions.
+const (
+	LoginAction          = "auth.login"
+	LogoutAction         = "auth.logout"
+	RefreshAction        = "auth.refresh"
+	SignUpAction         = "auth.signUp"
+	PasswordChangeAction = "auth.password.change"
+	PasswordResetAction  = "auth.password.reset"
+	SessionRevokeAction  = "auth.session.revoke"
+	SessionsRevokeAction = "auth.sessions.revoke"
+	AccountUnlockAction  = "auth.account.unlock"
+
+	TwoFactorDisableAction = "auth.twoFactor.disable"
+	TokenCreateAction      = "auth.token.create"
+	TokenRevokeAction      = "auth.token.revoke"
+
+	ImpersonateAction = "auth.impersonate"
+
+	UserCreateAction = "user.create"
+	UserDeleteAction = "user.delete"
+
+	WorkspaceMemberSetAction    = "workspace.member.set"
+	WorkspaceMemberRemoveAction = "workspace.member.remove"
+
+	ParticipantRoleAction   = "conversation.participant.role"
+	OwnershipTransferAction = "conversation.owner.transfer"
+
+	RoleCreateAction   = "policy.role.create"
+	RoleUpdateAction   = "policy.role.update"
+	RoleDeleteAction   = "policy.role.delete"
+	GrantsUpdateAction = "policy.grants.update"
+	PresetUpdateAction = "policy.preset.update"
+	PresetDeleteAction = "policy.preset.delete"
+
+	// AccessDeniedAction - request rejected by the authorization, e.g. because of user's role
+	// or Workspace membership.
+	AccessDeniedAction = "authorization.denied"
+)
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"errors"
+	"log"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+// ErrEntryRecorded - returned when recording an entry, which has been recorded already.
+// Recorded entries cannot be changed.
+var ErrEntryRecorded = errors.New("Audit log entry has been already recorded.")
+
+// ErrEntryIncomplete - returned when recording an entry without action or result.
+var ErrEntryIncomplete = errors.New("Audit log entry has to describe an action and its result.")
+
+// Logger - records entries of the audit log. The log is append-only - Logger never
+// changes nor deletes recorded entries.
+type Logger struct {
+	broker persist.DBBroker
+}
+
+// NewLogger - Logger constructor func.
+func NewLogger() *Logger {
+	return &Logger{
+		broker: persist.GormBroker,
+	}
+}
+
+// Record - saves given entry, as created by its actor.
+func (l *Logger) Record(entry *models.AuditLogModel) error {
+	if entry.ID != uuid.Nil {
+		return ErrEntryRecorded
+	}
+
+	if entry.Action == "" || entry.Result == "" {
+		return ErrEntryIncomplete
+	}
+
+	entry.CreatedBy = entry.ActorID
+	entry.UpdatedBy = entry.ActorID
+
+	return l.broker.Save(entry).Err()
+}
+
+// Log - records given entry, only logging the failure. Meant for the actions, which
+// have been already performed when they are audited.
+func (l *Logger) Log(entry *models.AuditLogModel) {
+	if err := l.Record(entry); err != nil {
+		log.Printf("Recording %v audit log entry failed: %v", entry.Action, err)
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type loggerSuite struct {
+	suite.Suite
+	logger   *Logger
+	gormMock *mocks.GormMock
+}
+
+func (s *loggerSuite) SetupTest() {
+	s.gormMock = mocks.NewGormMock()
+	s.gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	s.logger = &Logger{
+		broker: s.gormMock,
+	}
+}
+
+func TestLoggerSuite(t *testing.T) {
+	suite.Run(t, new(loggerSuite))
+}
+
+func (s *loggerSuite) TestNewLogger() {
+	logger := NewLogger()
+
+	assert.NotNil(s.T(), logger)
+}
+
+func (s *loggerSuite) TestRecord() {
+	actorID := uuid.New()
+
+	entry := &models.AuditLogModel{
+		ActorID: actorID,
+		Action:  LoginAction,
+		Result:  models.AuditSuccessResult,
+	}
+
+	err := s.logger.Record(entry)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), actorID, entry.CreatedBy)
+
+	s.gormMock.AssertCalled(s.T(), "Save", entry)
+}
+
+func (s *loggerSuite) TestRecord_Recorded() {
+	entry := &models.AuditLogModel{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Action:    LoginAction,
+		Result:    models.AuditSuccessResult,
+	}
+
+	err := s.logger.Record(entry)
+
+	assert.Equal(s.T(), ErrEntryRecorded, err)
+
+	s.gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *loggerSuite) TestRecord_Incomplete() {
+	err := s.logger.Record(&models.AuditLogModel{Action: LoginAction})
+
+	assert.Equal(s.T(), ErrEntryIncomplete, err)
+
+	s.gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *loggerSuite) TestLog_Error() {
+	gormMock := mocks.NewGormMock()
+	gormMock.On("Save", mock.Anything).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
+
+	s.logger.broker = gormMock
+
+	assert.NotPanics(s.T(), func() {
+		s.logger.Log(&models.AuditLogModel{
+			Action: UserDeleteAction,
+			Result: models.AuditSuccessResult,
+		})
+	})
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Default Argon2id parameters, following RFC 9106 recommendations
+// for memory-constrained environments.
+const (
+	DefaultArgon2Memory      = 64 * 1024
+	DefaultArgon2Iterations  = 3
+	DefaultArgon2Parallelism = 2
+)
+
+const (
+	argon2idID = "argon2id"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2idHasher - PasswordHasher using Argon2id. Hashes are stored in PHC string format:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>.
+type Argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// argon2idParams - parameters and values read from Argon2id hash.
+type argon2idParams struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// NewArgon2idHasher - Argon2idHasher constructor func. Memory is given in KiB.
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) *Argon2idHasher {
+	return &Argon2idHasher{
+		memory:      memory,
+		iterations:  iterations,
+		parallelism: parallelism,
+	}
+}
+
+// Hash - PasswordHasher implementation.
+func (ah *Argon2idHasher) Hash(password []byte) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey(password, salt, ah.iterations, ah.memory, ah.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf(
+		"$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idID,
+		argon2.Version,
+		ah.memory,
+		ah.iterations,
+		ah.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify - PasswordHasher implementation.
+func (ah *Argon2idHasher) Verify(hash string, password []byte) error {
+	params, err := parseArgon2idHash(hash)
+
+	if err != nil {
+		return err
+	}
+
+	key := argon2.IDKey(password, params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+
+	if subtle.ConstantTimeCompare(key, params.key) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+// Handles - PasswordHasher implementation.
+func (ah *Argon2idHasher) Handles(hash string) bool {
+	return hashAlgorithmID(hash) == argon2idID
+}
+
+// NeedsRehash - PasswordHasher implementation.
+func (ah *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, err := parseArgon2idHash(hash)
+
+	if err != nil {
+		return true
+	}
+
+	return params.version != argon2.Version ||
+		params.memory != ah.memory ||
+		params.iterations != ah.iterations ||
+		params.parallelism != ah.parallelism ||
+		len(params.key) != argon2KeyLength
+}
+
+// parseArgon2idHash - reads parameters, salt and key from given PHC formatted hash.
+func parseArgon2idHash(hash string) (*argon2idParams, error) {
+	parts := strings.Split(hash, "$")
+
+	if len(parts) != 6 || parts[1] != argon2idID {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	params := &argon2idParams{}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &params.version); err != nil {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	if params.version != argon2.Version {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism)
+
+	if err != nil || params.iterations == 0 || params.parallelism == 0 {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 {
+		return nil, ErrUnknownPasswordHash
+	}
+
+	return params, nil
+}
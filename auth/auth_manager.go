@@ -10,7 +10,6 @@ import (
 	"github.com/el-Mike/gochat/models"
 	"github.com/el-Mike/gochat/persist"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type jwtProvider interface {
@@ -18,68 +17,195 @@ type jwtProvider interface {
 	ParseToken(tokenString string, apiSecret string) (*jwt.Token, error)
 }
 
-type cryptoProvider interface {
-	GenerateFromPassword(password []byte, cost int) ([]byte, error)
-	CompareHashAndPassword(hashedPassword, password []byte) error
-}
-
-type bcryptDelegate struct{}
+const (
+	// AccessTokenTTL - lifetime of JWT access tokens.
+	AccessTokenTTL = 15 * time.Minute
 
-func (bw *bcryptDelegate) GenerateFromPassword(password []byte, cost int) ([]byte, error) {
-	return bcrypt.GenerateFromPassword(password, cost)
-}
+	// RefreshTokenTTL - lifetime of refresh tokens. As refresh token is rotated
+	// on every use, session expires only after being unused for that long.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
 
-func (bw *bcryptDelegate) CompareHashAndPassword(hashedPassword, password []byte) error {
-	return bcrypt.CompareHashAndPassword(hashedPassword, password)
+// TokenPair - tokens issued for a session.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
 }
 
 // AuthManager - manages auth related operations.
 type AuthManager struct {
-	cache  persist.Cache
-	jwt    jwtProvider
-	crypto cryptoProvider
-	ctx    context.Context
+	cache   persist.Cache
+	jwt     jwtProvider
+	hasher  PasswordHasher
+	cookies *CookieSessions
+	ctx     context.Context
 }
 
 // NewAuthManager - AuthManager constructor func.
 func NewAuthManager() *AuthManager {
 	return &AuthManager{
-		cache:  persist.RedisCache,
-		jwt:    NewJWTManager(),
-		crypto: &bcryptDelegate{},
-		ctx:    context.Background(),
+		cache:   persist.RedisCache,
+		jwt:     NewJWTManager(),
+		hasher:  DefaultPasswordHasher,
+		cookies: DefaultCookieSessions,
+		ctx:     context.Background(),
 	}
 }
 
-// Login - authenticates a user.
-func (am *AuthManager) Login(user *models.UserModel, apiSecret string) (string, error) {
-	authUUID := uuid.New().String()
-	userID := user.ID.String()
-	email := user.Email
-	role := user.Role
-	expiresAt := time.Now().Add(time.Minute * 15).Unix()
+// Login - authenticates a user, starting a new session used from given client.
+// Users with two-factor authentication enabled should be logged in only after
+// their second factor has been verified.
+func (am *AuthManager) Login(user *models.UserModel, client *ClientInfo, apiSecret string) (*TokenPair, error) {
+	session := newSession(user.ID, client)
+	session.TwoFactorVerified = user.TOTPEnabled
 
-	token, err := am.jwt.CreateToken(authUUID, userID, email, role, apiSecret, expiresAt)
+	accessToken, err := am.createAccessToken(session.ID, user, apiSecret)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Saving authorization allows us to double check the token - when user logs out,
 	// token will be removed, and no one will be able to use it anymore, even if it's not
 	// expired.
-	err = am.cache.Set(am.ctx, authUUID, userID, 0).Err()
+	if err := am.saveSession(session); err != nil {
+		return nil, err
+	}
+
+	if err := am.cache.SAdd(am.ctx, userSessionsKey(user.ID), session.ID.String()).Err(); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := am.issueRefreshToken(session.ID)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return token, nil
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// VerifyRefreshToken - checks if given refresh token is the current token of an active session.
+// If token has already been rotated, whole session is revoked and RefreshTokenReusedError
+// is returned.
+func (am *AuthManager) VerifyRefreshToken(refreshToken string) (*RefreshSession, error) {
+	authUUID, err := parseRefreshToken(refreshToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tokenHash := hashToken(refreshToken)
+	currentHash := am.cache.Get(am.ctx, refreshTokenKey(authUUID))
+
+	if currentHash.Err() != nil || currentHash.Val() != tokenHash {
+		if am.cache.Get(am.ctx, usedRefreshTokenKey(tokenHash)).Err() != nil {
+			return nil, ErrRefreshTokenInvalid
+		}
+
+		if err := am.Logout(authUUID.String()); err != nil {
+			return nil, err
+		}
+
+		return nil, &RefreshTokenReusedError{AuthUUID: authUUID}
+	}
+
+	session, err := am.GetSession(authUUID)
+
+	if err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	return &RefreshSession{
+		AuthUUID:  authUUID,
+		UserID:    session.UserID,
+		session:   session,
+		tokenHash: tokenHash,
+	}, nil
 }
 
-// Logout - logs user out by removing it's authorization entry from Redis store.
+// Refresh - rotates refresh token of given, verified session and issues new tokens.
+// Session's lifetime is extended as well. If the token has been rotated in the meantime
+// (e.g. by concurrent request using the same token), it's treated as reused.
+func (am *AuthManager) Refresh(session *RefreshSession, user *models.UserModel, apiSecret string) (*TokenPair, error) {
+	accessToken, err := am.createAccessToken(session.AuthUUID, user, apiSecret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Rotated token is remembered until it would expire, so its reuse can be detected.
+	err = am.cache.Set(am.ctx, usedRefreshTokenKey(session.tokenHash), session.AuthUUID.String(), RefreshTokenTTL).Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	session.session.LastSeenAt = now
+	session.session.ExpiresAt = now.Add(RefreshTokenTTL)
+
+	// Session is saved before the rotation, so it cannot be brought back after being
+	// revoked by a request which lost the rotation.
+	if err := am.saveSession(session.session); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newRefreshToken(session.AuthUUID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := am.cache.CompareAndSet(
+		am.ctx,
+		refreshTokenKey(session.AuthUUID),
+		session.tokenHash,
+		hashToken(refreshToken),
+		RefreshTokenTTL,
+	)
+
+	if res.Err() != nil {
+		return nil, res.Err()
+	}
+
+	if res.Val() != "1" {
+		if err := am.Logout(session.AuthUUID.String()); err != nil {
+			return nil, err
+		}
+
+		return nil, &RefreshTokenReusedError{AuthUUID: session.AuthUUID}
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// Logout - logs user out by removing it's session and refresh token from Redis store.
 func (am *AuthManager) Logout(authUUID string) error {
-	return am.cache.Del(am.ctx, authUUID).Err()
+	parsedAuthUUID, err := uuid.Parse(authUUID)
+
+	if err != nil {
+		return err
+	}
+
+	session, sessionErr := am.GetSession(parsedAuthUUID)
+
+	if err := am.cache.Del(am.ctx, authUUID, refreshTokenKey(parsedAuthUUID)).Err(); err != nil {
+		return err
+	}
+
+	if sessionErr != nil {
+		return nil
+	}
+
+	return am.cache.SRem(am.ctx, userSessionsKey(session.UserID), authUUID).Err()
 }
 
 // VerifyToken - verifies and parses JWT token.
@@ -95,8 +221,53 @@ func (am *AuthManager) VerifyToken(request *http.Request, apiSecret string) (*jw
 	return token, nil
 }
 
-// extractToken - extracts bearer token from request's headers.
+// createAccessToken - returns new JWT access token for given authorization.
+func (am *AuthManager) createAccessToken(authUUID uuid.UUID, user *models.UserModel, apiSecret string) (string, error) {
+	expiresAt := time.Now().Add(AccessTokenTTL).Unix()
+
+	return am.jwt.CreateToken(authUUID.String(), user.ID.String(), user.Email, user.Role, apiSecret, expiresAt)
+}
+
+// issueRefreshToken - creates new refresh token for given authorization,
+// replacing the current one.
+func (am *AuthManager) issueRefreshToken(authUUID uuid.UUID) (string, error) {
+	refreshToken, err := newRefreshToken(authUUID)
+
+	if err != nil {
+		return "", err
+	}
+
+	err = am.cache.Set(am.ctx, refreshTokenKey(authUUID), hashToken(refreshToken), RefreshTokenTTL).Err()
+
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// VerifyCSRFToken - checks CSRF token of given request, if it's authenticated with
+// session cookie. Requests with Authorization header cannot be forged cross-site.
+func (am *AuthManager) VerifyCSRFToken(request *http.Request) error {
+	if am.cookies == nil || ExtractToken(request) != "" || am.cookies.AccessToken(request) == "" {
+		return nil
+	}
+
+	return am.cookies.VerifyCSRFToken(request)
+}
+
+// extractToken - extracts bearer token from request's headers or, if cookie sessions
+// are enabled, from access token cookie. Header takes precedence.
 func (am *AuthManager) extractToken(request *http.Request) string {
+	if token := ExtractToken(request); token != "" || am.cookies == nil {
+		return token
+	}
+
+	return am.cookies.AccessToken(request)
+}
+
+// ExtractToken - extracts bearer token from request's headers.
+func ExtractToken(request *http.Request) string {
 	token := request.Header.Get("Authorization")
 
 	parts := strings.Split(token, " ")
@@ -108,25 +279,39 @@ func (am *AuthManager) extractToken(request *http.Request) string {
 	return ""
 }
 
-// HashAndSalt - returned bcrypt hashed password.
+// HashAndSalt - returns the hash of given password, created with current PasswordHasher.
 func (am *AuthManager) HashAndSalt(password []byte) (string, error) {
-	hash, err := am.crypto.GenerateFromPassword(password, bcrypt.MinCost)
+	return am.hasher.Hash(password)
+}
 
-	if err != nil {
-		return "", err
+// ComparePasswords - checks if password matches it's hashed version. Hashes created
+// with any of the supported algorithms can be verified.
+func (am *AuthManager) ComparePasswords(hashedPassword string, plainPassword []byte) error {
+	hasher := am.getHasher(hashedPassword)
+
+	if hasher == nil {
+		return ErrUnknownPasswordHash
 	}
 
-	return string(hash), nil
+	return hasher.Verify(hashedPassword, plainPassword)
 }
 
-// ComparePasswords - checks if password matches it's hased version.
-func (am *AuthManager) ComparePasswords(hashedPassword string, plainPassword []byte) error {
-	byteHash := []byte(hashedPassword)
+// PasswordNeedsRehash - returns true if given hash has been created with outdated
+// algorithm or parameters, and should be replaced.
+func (am *AuthManager) PasswordNeedsRehash(hashedPassword string) bool {
+	return !am.hasher.Handles(hashedPassword) || am.hasher.NeedsRehash(hashedPassword)
+}
 
-	err := am.crypto.CompareHashAndPassword(byteHash, plainPassword)
+// getHasher - returns PasswordHasher able to verify given hash, or nil if there is none.
+func (am *AuthManager) getHasher(hashedPassword string) PasswordHasher {
+	if am.hasher.Handles(hashedPassword) {
+		return am.hasher
+	}
 
-	if err != nil {
-		return err
+	for _, hasher := range knownPasswordHashers {
+		if hasher.Handles(hashedPassword) {
+			return hasher
+		}
 	}
 
 	return nil
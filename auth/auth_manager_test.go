@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/el-Mike/gochat/mocks"
 	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -37,28 +39,32 @@ func (jm *jwtManagerMock) ParseToken(tokenString string, apiSecret string) (*jwt
 	return args.Get(0).(*jwt.Token), args.Error(1)
 }
 
-type cryptoMock struct {
+type hasherMock struct {
 	mock.Mock
 }
 
-func (cr *cryptoMock) GenerateFromPassword(password []byte, cost int) ([]byte, error) {
-	args := cr.Called(password, cost)
+func (hm *hasherMock) Hash(password []byte) (string, error) {
+	args := hm.Called(password)
 
-	if _, ok := args.Get(0).([]byte); ok {
-		return args.Get(0).([]byte), args.Error(1)
-	}
+	return args.String(0), args.Error(1)
+}
+
+func (hm *hasherMock) Verify(hash string, password []byte) error {
+	args := hm.Called(hash, password)
 
-	return []byte(args.String(0)), args.Error(1)
+	return args.Error(0)
 }
 
-func (cr *cryptoMock) CompareHashAndPassword(hashedPassword, password []byte) error {
-	args := cr.Called(hashedPassword, password)
+func (hm *hasherMock) Handles(hash string) bool {
+	args := hm.Called(hash)
 
-	if args.Get(0) == nil {
-		return nil
-	}
+	return args.Bool(0)
+}
 
-	return args.Error(0)
+func (hm *hasherMock) NeedsRehash(hash string) bool {
+	args := hm.Called(hash)
+
+	return args.Bool(0)
 }
 
 type authManagerSuite struct {
@@ -77,6 +83,8 @@ type authManagerSuite struct {
 	testAuthHeader  string
 	testPassword    string
 	testHash        string
+	testClient      *ClientInfo
+	testSession     *Session
 }
 
 func (s *authManagerSuite) SetupSuite() {
@@ -93,13 +101,27 @@ func (s *authManagerSuite) SetupSuite() {
 	s.testAuthHeader = fmt.Sprintf("Bearer %v", s.testTokenString)
 	s.testPassword = "test_password"
 	s.testHash = "test_hash"
+	s.testClient = &ClientInfo{IP: "127.0.0.1", UserAgent: "test_user_agent"}
+	s.testSession = &Session{
+		ID:         s.testAuthUUID,
+		UserID:     s.testUserID,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(RefreshTokenTTL),
+	}
+}
+
+func (s *authManagerSuite) getSessionCacheResponse(session *Session) *persist.CacheResponse {
+	data, err := json.Marshal(session)
+	s.Require().Nil(err)
+
+	return mocks.GetValueCacheResponse(string(data))
 }
 
 func (s *authManagerSuite) SetupTest() {
 	s.authManager = &AuthManager{
 		cache:  mocks.NewRedisCacheMock(),
 		jwt:    &jwtManagerMock{},
-		crypto: &cryptoMock{},
+		hasher: &hasherMock{},
 		ctx:    context.Background(),
 	}
 }
@@ -137,11 +159,17 @@ func (s *authManagerSuite) TestLogin() {
 		mock.Anything,
 		mock.Anything,
 	).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On(
+		"SAdd",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
 
 	authManager.jwt = jwtMock
 	authManager.cache = cacheMock
 
-	token, err := authManager.Login(testUser, s.testSecret)
+	tokens, err := authManager.Login(testUser, s.testClient, s.testSecret)
 
 	jwtMock.AssertNumberOfCalls(s.T(), "CreateToken", 1)
 	jwtMock.AssertCalled(
@@ -155,11 +183,17 @@ func (s *authManagerSuite) TestLogin() {
 		mock.Anything,
 	)
 
-	cacheMock.AssertNumberOfCalls(s.T(), "Set", 1)
+	cacheMock.AssertNumberOfCalls(s.T(), "Set", 2)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testTokenString, tokens.AccessToken)
+
+	authUUID, err := parseRefreshToken(tokens.RefreshToken)
 
-	assert.NotEmpty(s.T(), token)
-	assert.Equal(s.T(), s.testTokenString, token)
 	assert.Nil(s.T(), err)
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, authUUID.String(), mock.Anything, mock.Anything)
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, refreshTokenKey(authUUID), hashToken(tokens.RefreshToken), RefreshTokenTTL)
+	cacheMock.AssertCalled(s.T(), "SAdd", mock.Anything, userSessionsKey(testUser.ID), []string{authUUID.String()})
 }
 
 func (s *authManagerSuite) TestLogin_Errors() {
@@ -188,9 +222,9 @@ func (s *authManagerSuite) TestLogin_Errors() {
 	authManager.jwt = jwtMock
 	authManager.cache = cacheMock
 
-	token, err := authManager.Login(s.testUser, s.testSecret)
+	tokens, err := authManager.Login(s.testUser, s.testClient, s.testSecret)
 
-	assert.Empty(s.T(), token)
+	assert.Nil(s.T(), tokens)
 	assert.NotNil(s.T(), err)
 	jwtMock.AssertNumberOfCalls(s.T(), "CreateToken", 1)
 	cacheMock.AssertNumberOfCalls(s.T(), "Set", 0)
@@ -208,9 +242,9 @@ func (s *authManagerSuite) TestLogin_Errors() {
 
 	authManager.jwt = jwtMock
 
-	token, err = authManager.Login(s.testUser, s.testSecret)
+	tokens, err = authManager.Login(s.testUser, s.testClient, s.testSecret)
 
-	assert.Empty(s.T(), token)
+	assert.Nil(s.T(), tokens)
 	assert.NotNil(s.T(), err)
 	jwtMock.AssertNumberOfCalls(s.T(), "CreateToken", 1)
 	cacheMock.AssertNumberOfCalls(s.T(), "Set", 1)
@@ -221,11 +255,22 @@ func (s *authManagerSuite) TestLogout() {
 	authManager := s.authManager
 
 	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		s.testAuthUUID.String(),
+	).Return(s.getSessionCacheResponse(s.testSession))
 	cacheMock.On(
 		"Del",
 		mock.Anything,
 		mock.Anything,
 	).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On(
+		"SRem",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
 
 	authManager.cache = cacheMock
 
@@ -233,10 +278,228 @@ func (s *authManagerSuite) TestLogout() {
 
 	assert.Nil(s.T(), err)
 
-	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{s.testAuthUUID.String()})
+	cacheMock.AssertCalled(s.T(), "SRem", mock.Anything, userSessionsKey(s.testUserID), []string{s.testAuthUUID.String()})
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{s.testAuthUUID.String(), refreshTokenKey(s.testAuthUUID)})
 	cacheMock.AssertNumberOfCalls(s.T(), "Del", 1)
 }
 
+func (s *authManagerSuite) TestVerifyRefreshToken() {
+	authManager := s.authManager
+
+	refreshToken, _ := newRefreshToken(s.testAuthUUID)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		refreshTokenKey(s.testAuthUUID),
+	).Return(mocks.GetValueCacheResponse(hashToken(refreshToken)))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		s.testAuthUUID.String(),
+	).Return(s.getSessionCacheResponse(s.testSession))
+
+	authManager.cache = cacheMock
+
+	session, err := authManager.VerifyRefreshToken(refreshToken)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testAuthUUID, session.AuthUUID)
+	assert.Equal(s.T(), s.testUserID, session.UserID)
+}
+
+func (s *authManagerSuite) TestVerifyRefreshToken_Invalid() {
+	authManager := s.authManager
+
+	refreshToken, _ := newRefreshToken(s.testAuthUUID)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	authManager.cache = cacheMock
+
+	for _, token := range []string{"malformed", "malformed.token", refreshToken} {
+		session, err := authManager.VerifyRefreshToken(token)
+
+		assert.Nil(s.T(), session)
+		assert.Equal(s.T(), ErrRefreshTokenInvalid, err)
+	}
+
+	cacheMock.AssertNotCalled(s.T(), "Del", mock.Anything, mock.Anything)
+}
+
+func (s *authManagerSuite) TestVerifyRefreshToken_Reused() {
+	authManager := s.authManager
+
+	usedToken, _ := newRefreshToken(s.testAuthUUID)
+	currentToken, _ := newRefreshToken(s.testAuthUUID)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		refreshTokenKey(s.testAuthUUID),
+	).Return(mocks.GetValueCacheResponse(hashToken(currentToken)))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		usedRefreshTokenKey(hashToken(usedToken)),
+	).Return(mocks.GetValueCacheResponse(s.testAuthUUID.String()))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		s.testAuthUUID.String(),
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	authManager.cache = cacheMock
+
+	session, err := authManager.VerifyRefreshToken(usedToken)
+
+	assert.Nil(s.T(), session)
+	assert.Equal(s.T(), &RefreshTokenReusedError{AuthUUID: s.testAuthUUID}, err)
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{s.testAuthUUID.String(), refreshTokenKey(s.testAuthUUID)})
+}
+
+func (s *authManagerSuite) TestRefresh() {
+	authManager := s.authManager
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+	}
+
+	session := &RefreshSession{
+		AuthUUID:  s.testAuthUUID,
+		UserID:    s.testUserID,
+		session:   &Session{ID: s.testAuthUUID, UserID: s.testUserID},
+		tokenHash: "test_hash",
+	}
+
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On(
+		"CreateToken",
+		s.testAuthUUID.String(),
+		s.testUserID.String(),
+		mock.Anything,
+		mock.Anything,
+		s.testSecret,
+		mock.Anything,
+	).Return(s.testTokenString, nil)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On(
+		"CompareAndSet",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("1"))
+
+	authManager.jwt = jwtMock
+	authManager.cache = cacheMock
+
+	tokens, err := authManager.Refresh(session, testUser, s.testSecret)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testTokenString, tokens.AccessToken)
+
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, usedRefreshTokenKey("test_hash"), s.testAuthUUID.String(), RefreshTokenTTL)
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, s.testAuthUUID.String(), mock.Anything, mock.Anything)
+
+	assert.WithinDuration(s.T(), time.Now().Add(RefreshTokenTTL), session.session.ExpiresAt, time.Minute)
+	cacheMock.AssertCalled(
+		s.T(),
+		"CompareAndSet",
+		mock.Anything,
+		refreshTokenKey(s.testAuthUUID),
+		"test_hash",
+		hashToken(tokens.RefreshToken),
+		RefreshTokenTTL,
+	)
+}
+
+func (s *authManagerSuite) TestRefresh_AlreadyRotated() {
+	authManager := s.authManager
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+	}
+
+	session := &RefreshSession{
+		AuthUUID:  s.testAuthUUID,
+		UserID:    s.testUserID,
+		session:   &Session{ID: s.testAuthUUID, UserID: s.testUserID},
+		tokenHash: "test_hash",
+	}
+
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On(
+		"CreateToken",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(s.testTokenString, nil)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On(
+		"CompareAndSet",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("0"))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		s.testAuthUUID.String(),
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	authManager.jwt = jwtMock
+	authManager.cache = cacheMock
+
+	tokens, err := authManager.Refresh(session, testUser, s.testSecret)
+
+	assert.Nil(s.T(), tokens)
+	assert.Equal(s.T(), &RefreshTokenReusedError{AuthUUID: s.testAuthUUID}, err)
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{s.testAuthUUID.String(), refreshTokenKey(s.testAuthUUID)})
+}
+
 func (s *authManagerSuite) TestVerifyToken() {
 	authManager := s.authManager
 
@@ -288,82 +551,123 @@ func (s *authManagerSuite) TestVerifyToken_MissingToken() {
 func (s *authManagerSuite) TestHashAndSalt() {
 	authManager := s.authManager
 
-	cryptoMock := new(cryptoMock)
-	cryptoMock.On(
-		"GenerateFromPassword",
-		mock.Anything,
+	hasherMock := new(hasherMock)
+	hasherMock.On(
+		"Hash",
 		mock.Anything,
 	).Return(s.testHash, nil)
 
-	authManager.crypto = cryptoMock
+	authManager.hasher = hasherMock
 
 	hash, err := authManager.HashAndSalt([]byte(s.testPassword))
 
-	assert.NotEmpty(s.T(), hash)
-	assert.IsType(s.T(), "string", hash)
+	assert.Equal(s.T(), s.testHash, hash)
 	assert.Nil(s.T(), err)
 
-	cryptoMock.AssertNumberOfCalls(s.T(), "GenerateFromPassword", 1)
-	cryptoMock.AssertCalled(s.T(), "GenerateFromPassword", []byte(s.testPassword), bcrypt.MinCost)
-
+	hasherMock.AssertNumberOfCalls(s.T(), "Hash", 1)
+	hasherMock.AssertCalled(s.T(), "Hash", []byte(s.testPassword))
 }
 
 func (s *authManagerSuite) TestHashAndSalt_Error() {
 	authManager := s.authManager
 
-	cryptoMock := new(cryptoMock)
-	cryptoMock.On(
-		"GenerateFromPassword",
-		mock.Anything,
+	hasherMock := new(hasherMock)
+	hasherMock.On(
+		"Hash",
 		mock.Anything,
 	).Return("", errors.New("crypto_error"))
 
-	authManager.crypto = cryptoMock
+	authManager.hasher = hasherMock
 
 	hash, err := authManager.HashAndSalt([]byte(s.testPassword))
 
 	assert.Empty(s.T(), hash)
 	assert.NotNil(s.T(), err)
 
-	cryptoMock.AssertNumberOfCalls(s.T(), "GenerateFromPassword", 1)
-	cryptoMock.AssertCalled(s.T(), "GenerateFromPassword", []byte(s.testPassword), bcrypt.MinCost)
+	hasherMock.AssertNumberOfCalls(s.T(), "Hash", 1)
 }
 
 func (s *authManagerSuite) TestComparePasswords() {
 	authManager := s.authManager
 
-	cryptoMock := new(cryptoMock)
-	cryptoMock.On(
-		"CompareHashAndPassword",
+	hasherMock := new(hasherMock)
+	hasherMock.On(
+		"Handles",
+		s.testHash,
+	).Return(true)
+	hasherMock.On(
+		"Verify",
 		mock.Anything,
 		mock.Anything,
 	).Return(nil)
 
-	authManager.crypto = cryptoMock
+	authManager.hasher = hasherMock
 
-	err := authManager.ComparePasswords(s.testPassword, []byte(s.testPassword))
+	err := authManager.ComparePasswords(s.testHash, []byte(s.testPassword))
 
 	assert.Nil(s.T(), err)
 
-	cryptoMock.AssertNumberOfCalls(s.T(), "CompareHashAndPassword", 1)
-	cryptoMock.AssertCalled(s.T(), "CompareHashAndPassword", []byte(s.testPassword), []byte(s.testPassword))
+	hasherMock.AssertNumberOfCalls(s.T(), "Verify", 1)
+	hasherMock.AssertCalled(s.T(), "Verify", s.testHash, []byte(s.testPassword))
 }
 
 func (s *authManagerSuite) TestComparePasswords_Error() {
 	authManager := s.authManager
 
-	cryptoMock := new(cryptoMock)
-	cryptoMock.On(
-		"CompareHashAndPassword",
+	hasherMock := new(hasherMock)
+	hasherMock.On(
+		"Handles",
+		mock.Anything,
+	).Return(true)
+	hasherMock.On(
+		"Verify",
 		mock.Anything,
 		mock.Anything,
-	).Return(errors.New("crypto_error"))
+	).Return(ErrPasswordMismatch)
 
-	authManager.crypto = cryptoMock
+	authManager.hasher = hasherMock
 
-	err := authManager.ComparePasswords(s.testPassword, []byte(s.testPassword))
+	err := authManager.ComparePasswords(s.testHash, []byte(s.testPassword))
 
-	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), ErrPasswordMismatch, err)
+
+	hasherMock.AssertNumberOfCalls(s.T(), "Verify", 1)
+}
+
+func (s *authManagerSuite) TestComparePasswords_OtherAlgorithm() {
+	authManager := s.authManager
+
+	// Hashes created before switching the algorithm can still be verified.
+	bcryptHash, _ := NewBcryptHasher(bcrypt.MinCost).Hash([]byte(s.testPassword))
+
+	hasherMock := new(hasherMock)
+	hasherMock.On(
+		"Handles",
+		mock.Anything,
+	).Return(false)
+
+	authManager.hasher = hasherMock
+
+	assert.Nil(s.T(), authManager.ComparePasswords(bcryptHash, []byte(s.testPassword)))
+	assert.Equal(s.T(), ErrPasswordMismatch, authManager.ComparePasswords(bcryptHash, []byte("other_password")))
+	assert.Equal(s.T(), ErrUnknownPasswordHash, authManager.ComparePasswords("plain_text", []byte(s.testPassword)))
+
+	hasherMock.AssertNotCalled(s.T(), "Verify", mock.Anything, mock.Anything)
+}
+
+func (s *authManagerSuite) TestPasswordNeedsRehash() {
+	authManager := s.authManager
+
+	hasherMock := new(hasherMock)
+	hasherMock.On("Handles", "current_hash").Return(true)
+	hasherMock.On("NeedsRehash", "current_hash").Return(false)
+	hasherMock.On("Handles", "outdated_hash").Return(true)
+	hasherMock.On("NeedsRehash", "outdated_hash").Return(true)
+	hasherMock.On("Handles", "other_algorithm_hash").Return(false)
+
+	authManager.hasher = hasherMock
 
-	cryptoMock.AssertNumberOfCalls(s.T(), "CompareHashAndPassword", 1)
+	assert.False(s.T(), authManager.PasswordNeedsRehash("current_hash"))
+	assert.True(s.T(), authManager.PasswordNeedsRehash("outdated_hash"))
+	assert.True(s.T(), authManager.PasswordNeedsRehash("other_algorithm_hash"))
 }
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultBcryptCost - cost of bcrypt hashes, if it's not configured.
+const DefaultBcryptCost = 12
+
+// BcryptMaxPasswordBytes - number of password's bytes bcrypt takes into account - the rest
+// is silently ignored, so longer passwords cannot be hashed safely.
+const BcryptMaxPasswordBytes = 72
+
+// BcryptHasher - PasswordHasher using bcrypt. Bcrypt's own hash format ($2a$<cost>$...)
+// is already compatible with PHC string format.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher - BcryptHasher constructor func.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{
+		cost: cost,
+	}
+}
+
+// Hash - PasswordHasher implementation.
+func (bh *BcryptHasher) Hash(password []byte) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword(password, bh.cost)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Verify - PasswordHasher implementation.
+func (bh *BcryptHasher) Verify(hash string, password []byte) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), password)
+
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return ErrPasswordMismatch
+	}
+
+	if err != nil {
+		return ErrUnknownPasswordHash
+	}
+
+	return nil
+}
+
+// Handles - PasswordHasher implementation.
+func (bh *BcryptHasher) Handles(hash string) bool {
+	switch hashAlgorithmID(hash) {
+	case "2a", "2b", "2y":
+		return true
+	default:
+		return false
+	}
+}
+
+// NeedsRehash - PasswordHasher implementation.
+func (bh *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+
+	return err != nil || cost != bh.cost
+}
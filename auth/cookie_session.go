@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// AccessTokenCookie - cookie holding session's access token. "__Host-" prefix makes browsers
+	// accept it only if it's Secure, set for the whole host and not for its subdomains.
+	AccessTokenCookie = "__Host-gochat-access"
+
+	// RefreshTokenCookie - cookie holding session's refresh token. It's sent only to the
+	// refresh endpoint.
+	RefreshTokenCookie = "__Secure-gochat-refresh"
+
+	// CSRFTokenCookie - cookie holding CSRF token. It's readable by scripts, so the frontend
+	// can send it back with CSRFTokenHeader.
+	CSRFTokenCookie = "__Host-gochat-csrf"
+
+	// CSRFTokenHeader - header unsafe requests authenticated with cookies have to contain
+	// CSRF token in.
+	CSRFTokenHeader = "X-CSRF-Token"
+
+	// Path of the refresh endpoint, the only one refresh token cookie is sent to.
+	refreshTokenCookiePath = "/api/auth/refresh"
+
+	// Number of random bytes CSRF tokens are made of.
+	csrfTokenSize = 32
+)
+
+// ErrCSRFTokenInvalid - returned when unsafe request authenticated with cookies
+// does not contain matching CSRF token.
+var ErrCSRFTokenInvalid = errors.New("CSRF token is missing or invalid.")
+
+// CookieSessions - delivers session's tokens as HttpOnly cookies, so they cannot be read
+// by scripts. Unsafe requests are protected from CSRF with double-submit token.
+type CookieSessions struct {
+	sameSite http.SameSite
+}
+
+// DefaultCookieSessions - CookieSessions used for issued sessions. Nil if cookie
+// sessions are disabled, and tokens are returned in response body only.
+var DefaultCookieSessions *CookieSessions
+
+// NewCookieSessions - CookieSessions constructor func.
+func NewCookieSessions(sameSite http.SameSite) *CookieSessions {
+	return &CookieSessions{
+		sameSite: sameSite,
+	}
+}
+
+// InitCookieSessions - initializes DefaultCookieSessions, if AUTH_COOKIE_MODE env is set to "true".
+// Cookies' SameSite attribute is set by AUTH_COOKIE_SAMESITE env - "strict" (default) or "lax".
+func InitCookieSessions() (*CookieSessions, error) {
+	if os.Getenv("AUTH_COOKIE_MODE") != "true" {
+		DefaultCookieSessions = nil
+
+		return nil, nil
+	}
+
+	sameSite := http.SameSiteStrictMode
+
+	switch strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")) {
+	case "", "strict":
+	case "lax":
+		sameSite = http.SameSiteLaxMode
+	default:
+		return nil, fmt.Errorf("AUTH_COOKIE_SAMESITE has to be \"strict\" or \"lax\"")
+	}
+
+	DefaultCookieSessions = NewCookieSessions(sameSite)
+
+	return DefaultCookieSessions, nil
+}
+
+// SetTokens - sets cookies holding given tokens, together with new CSRF token,
+// which is returned.
+func (cs *CookieSessions) SetTokens(w http.ResponseWriter, tokens *TokenPair) (string, error) {
+	csrfToken, err := newCSRFToken()
+
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, cs.newCookie(AccessTokenCookie, tokens.AccessToken, "/", int(AccessTokenTTL.Seconds()), true))
+	http.SetCookie(w, cs.newCookie(
+		RefreshTokenCookie,
+		tokens.RefreshToken,
+		refreshTokenCookiePath,
+		int(RefreshTokenTTL.Seconds()),
+		true,
+	))
+	http.SetCookie(w, cs.newCookie(CSRFTokenCookie, csrfToken, "/", int(RefreshTokenTTL.Seconds()), false))
+
+	return csrfToken, nil
+}
+
+// Clear - removes session's cookies.
+func (cs *CookieSessions) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, cs.newCookie(AccessTokenCookie, "", "/", -1, true))
+	http.SetCookie(w, cs.newCookie(RefreshTokenCookie, "", refreshTokenCookiePath, -1, true))
+	http.SetCookie(w, cs.newCookie(CSRFTokenCookie, "", "/", -1, false))
+}
+
+// AccessToken - returns access token sent in given request's cookie.
+func (cs *CookieSessions) AccessToken(request *http.Request) string {
+	return getCookieValue(request, AccessTokenCookie)
+}
+
+// RefreshToken - returns refresh token sent in given request's cookie.
+func (cs *CookieSessions) RefreshToken(request *http.Request) string {
+	return getCookieValue(request, RefreshTokenCookie)
+}
+
+// VerifyCSRFToken - checks if unsafe request contains CSRF token in both the cookie and
+// the header. Cross-site requests cannot read the cookie, so they cannot set the header.
+func (cs *CookieSessions) VerifyCSRFToken(request *http.Request) error {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	cookieToken := getCookieValue(request, CSRFTokenCookie)
+	headerToken := request.Header.Get(CSRFTokenHeader)
+
+	if cookieToken == "" || subtle.ConstantTimeCompare([]byte(cookieToken), []byte(headerToken)) != 1 {
+		return ErrCSRFTokenInvalid
+	}
+
+	return nil
+}
+
+// newCookie - returns Secure cookie with given params. Negative maxAge removes the cookie.
+func (cs *CookieSessions) newCookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: cs.sameSite,
+	}
+}
+
+// newCSRFToken - returns new, random CSRF token.
+func newCSRFToken() (string, error) {
+	token := make([]byte, csrfTokenSize)
+
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func getCookieValue(request *http.Request, name string) string {
+	cookie, err := request.Cookie(name)
+
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type cookieSessionSuite struct {
+	suite.Suite
+	cookies     *CookieSessions
+	authManager *AuthManager
+	testTokens  *TokenPair
+}
+
+func (s *cookieSessionSuite) SetupTest() {
+	s.cookies = NewCookieSessions(http.SameSiteStrictMode)
+	s.authManager = &AuthManager{
+		cache:   mocks.NewRedisCacheMock(),
+		jwt:     &jwtManagerMock{},
+		cookies: s.cookies,
+		ctx:     context.Background(),
+	}
+
+	s.testTokens = &TokenPair{
+		AccessToken:  "test_access_token",
+		RefreshToken: "test_refresh_token",
+	}
+}
+
+func TestCookieSessionSuite(t *testing.T) {
+	suite.Run(t, new(cookieSessionSuite))
+}
+
+// getRequest - returns request with given method, sending given cookies.
+func (s *cookieSessionSuite) getRequest(method string, cookies map[string]string) *http.Request {
+	request := httptest.NewRequest(method, "/api/conversations", nil)
+
+	for name, value := range cookies {
+		request.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	return request
+}
+
+func (s *cookieSessionSuite) getCookies(recorder *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := map[string]*http.Cookie{}
+
+	for _, cookie := range recorder.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	return cookies
+}
+
+func (s *cookieSessionSuite) TestSetTokens() {
+	recorder := httptest.NewRecorder()
+
+	csrfToken, err := s.cookies.SetTokens(recorder, s.testTokens)
+
+	assert.Nil(s.T(), err)
+	assert.NotEmpty(s.T(), csrfToken)
+
+	cookies := s.getCookies(recorder)
+
+	accessCookie := cookies[AccessTokenCookie]
+	assert.Equal(s.T(), "test_access_token", accessCookie.Value)
+	assert.Equal(s.T(), "/", accessCookie.Path)
+	assert.True(s.T(), accessCookie.HttpOnly)
+	assert.True(s.T(), accessCookie.Secure)
+	assert.Equal(s.T(), http.SameSiteStrictMode, accessCookie.SameSite)
+
+	refreshCookie := cookies[RefreshTokenCookie]
+	assert.Equal(s.T(), "test_refresh_token", refreshCookie.Value)
+	assert.Equal(s.T(), "/api/auth/refresh", refreshCookie.Path)
+	assert.True(s.T(), refreshCookie.HttpOnly)
+	assert.True(s.T(), refreshCookie.Secure)
+
+	// CSRF token has to be readable by the frontend.
+	csrfCookie := cookies[CSRFTokenCookie]
+	assert.Equal(s.T(), csrfToken, csrfCookie.Value)
+	assert.False(s.T(), csrfCookie.HttpOnly)
+	assert.True(s.T(), csrfCookie.Secure)
+}
+
+func (s *cookieSessionSuite) TestClear() {
+	recorder := httptest.NewRecorder()
+
+	s.cookies.Clear(recorder)
+
+	cookies := s.getCookies(recorder)
+
+	assert.Len(s.T(), cookies, 3)
+
+	for _, cookie := range cookies {
+		assert.Empty(s.T(), cookie.Value)
+		assert.True(s.T(), cookie.MaxAge < 0)
+	}
+
+	assert.Equal(s.T(), "/api/auth/refresh", cookies[RefreshTokenCookie].Path)
+}
+
+func (s *cookieSessionSuite) TestVerifyCSRFToken() {
+	request := s.getRequest(http.MethodPost, map[string]string{CSRFTokenCookie: "test_csrf_token"})
+	request.Header.Set(CSRFTokenHeader, "test_csrf_token")
+
+	assert.Nil(s.T(), s.cookies.VerifyCSRFToken(request))
+}
+
+func (s *cookieSessionSuite) TestVerifyCSRFToken_SafeMethod() {
+	request := s.getRequest(http.MethodGet, map[string]string{})
+
+	assert.Nil(s.T(), s.cookies.VerifyCSRFToken(request))
+}
+
+func (s *cookieSessionSuite) TestVerifyCSRFToken_Invalid() {
+	missingHeader := s.getRequest(http.MethodDelete, map[string]string{CSRFTokenCookie: "test_csrf_token"})
+
+	assert.Equal(s.T(), ErrCSRFTokenInvalid, s.cookies.VerifyCSRFToken(missingHeader))
+
+	mismatch := s.getRequest(http.MethodPost, map[string]string{CSRFTokenCookie: "test_csrf_token"})
+	mismatch.Header.Set(CSRFTokenHeader, "other_csrf_token")
+
+	assert.Equal(s.T(), ErrCSRFTokenInvalid, s.cookies.VerifyCSRFToken(mismatch))
+
+	// Empty tokens do not match each other.
+	missingBoth := s.getRequest(http.MethodPost, map[string]string{})
+
+	assert.Equal(s.T(), ErrCSRFTokenInvalid, s.cookies.VerifyCSRFToken(missingBoth))
+}
+
+func (s *cookieSessionSuite) TestVerifyToken_Cookie() {
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On("ParseToken", "test_access_token", "test_secret").Return(nil, errors.New("jwt_error"))
+
+	s.authManager.jwt = jwtMock
+
+	request := s.getRequest(http.MethodGet, map[string]string{AccessTokenCookie: "test_access_token"})
+
+	s.authManager.VerifyToken(request, "test_secret")
+
+	jwtMock.AssertCalled(s.T(), "ParseToken", "test_access_token", "test_secret")
+}
+
+func (s *cookieSessionSuite) TestVerifyToken_HeaderPrecedence() {
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On("ParseToken", "test_header_token", "test_secret").Return(nil, errors.New("jwt_error"))
+
+	s.authManager.jwt = jwtMock
+
+	request := s.getRequest(http.MethodGet, map[string]string{AccessTokenCookie: "test_access_token"})
+	request.Header.Set("Authorization", "Bearer test_header_token")
+
+	s.authManager.VerifyToken(request, "test_secret")
+
+	jwtMock.AssertCalled(s.T(), "ParseToken", "test_header_token", "test_secret")
+}
+
+func (s *cookieSessionSuite) TestVerifyToken_CookiesDisabled() {
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On("ParseToken", "", "test_secret").Return(nil, errors.New("jwt_error"))
+
+	s.authManager.jwt = jwtMock
+	s.authManager.cookies = nil
+
+	request := s.getRequest(http.MethodGet, map[string]string{AccessTokenCookie: "test_access_token"})
+
+	s.authManager.VerifyToken(request, "test_secret")
+
+	jwtMock.AssertCalled(s.T(), "ParseToken", "", "test_secret")
+}
+
+func (s *cookieSessionSuite) TestAuthManagerVerifyCSRFToken() {
+	cookieRequest := s.getRequest(http.MethodPost, map[string]string{AccessTokenCookie: "test_access_token"})
+
+	assert.Equal(s.T(), ErrCSRFTokenInvalid, s.authManager.VerifyCSRFToken(cookieRequest))
+
+	// Requests with Authorization header are not protected with CSRF token.
+	headerRequest := s.getRequest(http.MethodPost, map[string]string{AccessTokenCookie: "test_access_token"})
+	headerRequest.Header.Set("Authorization", "Bearer test_header_token")
+
+	assert.Nil(s.T(), s.authManager.VerifyCSRFToken(headerRequest))
+
+	s.authManager.cookies = nil
+
+	assert.Nil(s.T(), s.authManager.VerifyCSRFToken(cookieRequest))
+}
+
+func (s *cookieSessionSuite) TestInitCookieSessions() {
+	defer os.Unsetenv("AUTH_COOKIE_MODE")
+	defer os.Unsetenv("AUTH_COOKIE_SAMESITE")
+
+	os.Setenv("AUTH_COOKIE_MODE", "")
+
+	cookies, err := InitCookieSessions()
+
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), cookies)
+
+	os.Setenv("AUTH_COOKIE_MODE", "true")
+	os.Setenv("AUTH_COOKIE_SAMESITE", "lax")
+
+	cookies, err = InitCookieSessions()
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), http.SameSiteLaxMode, cookies.sameSite)
+
+	os.Setenv("AUTH_COOKIE_SAMESITE", "none")
+
+	_, err = InitCookieSessions()
+
+	assert.NotNil(s.T(), err)
+
+	DefaultCookieSessions = nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// ErrEdDSAVerification - returned when EdDSA signature is invalid.
+var ErrEdDSAVerification = errors.New("EdDSA verification failed")
+
+// SigningMethodEd25519 - jwt.SigningMethod implementation for EdDSA with Ed25519 keys
+// (RFC 8037), which is not supported by jwt-go itself.
+type SigningMethodEd25519 struct{}
+
+// SigningMethodEdDSA - EdDSA signing method instance, registered as "EdDSA" alg.
+var SigningMethodEdDSA = &SigningMethodEd25519{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+// Alg - jwt.SigningMethod implementation.
+func (sm *SigningMethodEd25519) Alg() string {
+	return EdDSAAlgorithm
+}
+
+// Verify - jwt.SigningMethod implementation. Key has to be ed25519.PublicKey.
+func (sm *SigningMethodEd25519) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+
+	if !ok || len(publicKey) != ed25519.PublicKeySize {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return ErrEdDSAVerification
+	}
+
+	return nil
+}
+
+// Sign - jwt.SigningMethod implementation. Key has to be ed25519.PrivateKey.
+func (sm *SigningMethodEd25519) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+
+	if !ok || len(privateKey) != ed25519.PrivateKeySize {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
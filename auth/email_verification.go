@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationPolicy - defines what users with unverified email are not allowed to do.
+type EmailVerificationPolicy string
+
+const (
+	// EmailVerificationOptional - users with unverified email are not restricted.
+	EmailVerificationOptional EmailVerificationPolicy = "optional"
+
+	// EmailVerificationRequiredForLogin - users with unverified email cannot log in.
+	EmailVerificationRequiredForLogin EmailVerificationPolicy = "login"
+
+	// EmailVerificationRequiredForMessages - users with unverified email can log in,
+	// but cannot send messages.
+	EmailVerificationRequiredForMessages EmailVerificationPolicy = "messages"
+)
+
+const (
+	// EmailVerificationTokenTTL - lifetime of email verification tokens.
+	EmailVerificationTokenTTL = 24 * time.Hour
+
+	// Prefix of the cache keys holding hashes of email verification tokens.
+	emailVerificationTokenKeyPrefix = "email-verification:"
+)
+
+var (
+	// ErrEmailVerificationTokenInvalid - returned when email verification token does not exist,
+	// has expired or has already been used.
+	ErrEmailVerificationTokenInvalid = errors.New("Email verification token is invalid or expired.")
+
+	// ErrEmailNotVerified - returned when user with unverified email tries to perform
+	// an action current EmailVerificationPolicy does not allow.
+	ErrEmailNotVerified = errors.New("Email address has not been verified.")
+)
+
+// GetEmailVerificationPolicy - returns the policy set by EMAIL_VERIFICATION_POLICY env variable.
+// Verification is optional if the variable is not set or its value is unknown.
+func GetEmailVerificationPolicy() EmailVerificationPolicy {
+	switch policy := EmailVerificationPolicy(os.Getenv("EMAIL_VERIFICATION_POLICY")); policy {
+	case EmailVerificationRequiredForLogin, EmailVerificationRequiredForMessages:
+		return policy
+	default:
+		return EmailVerificationOptional
+	}
+}
+
+// BlocksLogin - returns true if users with unverified email cannot log in.
+func (p EmailVerificationPolicy) BlocksLogin() bool {
+	return p == EmailVerificationRequiredForLogin
+}
+
+// BlocksMessages - returns true if users with unverified email cannot send messages.
+// Policy blocking login blocks messages as well, as sessions could have been
+// started before the policy was enabled.
+func (p EmailVerificationPolicy) BlocksMessages() bool {
+	return p == EmailVerificationRequiredForLogin || p == EmailVerificationRequiredForMessages
+}
+
+// CreateEmailVerificationToken - returns new, single-use token confirming given user's email.
+func (am *AuthManager) CreateEmailVerificationToken(userID uuid.UUID) (string, error) {
+	return am.createOneTimeToken(emailVerificationTokenKeyPrefix, userID, EmailVerificationTokenTTL)
+}
+
+// ConsumeEmailVerificationToken - invalidates given email verification token, and returns
+// the ID of the user it has been created for.
+func (am *AuthManager) ConsumeEmailVerificationToken(token string) (uuid.UUID, error) {
+	userID, err := am.consumeOneTimeToken(emailVerificationTokenKeyPrefix, token)
+
+	if err == errOneTimeTokenInvalid {
+		return uuid.Nil, ErrEmailVerificationTokenInvalid
+	}
+
+	return userID, err
+}
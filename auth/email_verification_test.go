@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type emailVerificationSuite struct {
+	suite.Suite
+	authManager *AuthManager
+	testUserID  uuid.UUID
+}
+
+func (s *emailVerificationSuite) SetupTest() {
+	s.authManager = &AuthManager{
+		cache: mocks.NewRedisCacheMock(),
+		ctx:   context.Background(),
+	}
+
+	s.testUserID = uuid.New()
+}
+
+func TestEmailVerificationSuite(t *testing.T) {
+	suite.Run(t, new(emailVerificationSuite))
+}
+
+func (s *emailVerificationSuite) TestGetEmailVerificationPolicy() {
+	defer os.Unsetenv("EMAIL_VERIFICATION_POLICY")
+
+	os.Unsetenv("EMAIL_VERIFICATION_POLICY")
+	assert.Equal(s.T(), EmailVerificationOptional, GetEmailVerificationPolicy())
+
+	os.Setenv("EMAIL_VERIFICATION_POLICY", "unknown")
+	assert.Equal(s.T(), EmailVerificationOptional, GetEmailVerificationPolicy())
+
+	os.Setenv("EMAIL_VERIFICATION_POLICY", "login")
+	assert.Equal(s.T(), EmailVerificationRequiredForLogin, GetEmailVerificationPolicy())
+
+	os.Setenv("EMAIL_VERIFICATION_POLICY", "messages")
+	assert.Equal(s.T(), EmailVerificationRequiredForMessages, GetEmailVerificationPolicy())
+}
+
+func (s *emailVerificationSuite) TestPolicyRestrictions() {
+	assert.False(s.T(), EmailVerificationOptional.BlocksLogin())
+	assert.False(s.T(), EmailVerificationOptional.BlocksMessages())
+
+	assert.True(s.T(), EmailVerificationRequiredForLogin.BlocksLogin())
+	assert.True(s.T(), EmailVerificationRequiredForLogin.BlocksMessages())
+
+	assert.False(s.T(), EmailVerificationRequiredForMessages.BlocksLogin())
+	assert.True(s.T(), EmailVerificationRequiredForMessages.BlocksMessages())
+}
+
+func (s *emailVerificationSuite) TestConsumeEmailVerificationToken() {
+	token := "test_verification_token"
+	key := oneTimeTokenKey(emailVerificationTokenKeyPrefix, token)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		key,
+	).Return(mocks.GetValueCacheResponse(s.testUserID.String()))
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("1"))
+
+	s.authManager.cache = cacheMock
+
+	userID, err := s.authManager.ConsumeEmailVerificationToken(token)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testUserID, userID)
+
+	// Password reset token with the same value must not be accepted.
+	assert.NotEqual(s.T(), passwordResetTokenKey(token), key)
+}
+
+func (s *emailVerificationSuite) TestConsumeEmailVerificationToken_Invalid() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(ErrEmailVerificationTokenInvalid))
+
+	s.authManager.cache = cacheMock
+
+	userID, err := s.authManager.ConsumeEmailVerificationToken("test_verification_token")
+
+	assert.Equal(s.T(), uuid.Nil, userID)
+	assert.Equal(s.T(), ErrEmailVerificationTokenInvalid, err)
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseGroupRoles - parses comma-separated "group=ROLE" pairs, mapping external
+// identity provider's or directory's groups to Gochat roles.
+func ParseGroupRoles(value string) (map[string]string, error) {
+	groupRoles := map[string]string{}
+
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("Group role mapping \"%s\" is malformed", pair)
+		}
+
+		groupRoles[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return groupRoles, nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+// ImpersonationTTL - lifetime of impersonated sessions. They cannot be refreshed,
+// so the access token expires together with the session.
+const ImpersonationTTL = 30 * time.Minute
+
+// Actor - the admin who actually acts within impersonated session.
+type Actor struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+// Impersonate - starts a session of given user, used by given actor from given client.
+// Returns the access token of the session - no refresh token is issued for it.
+func (am *AuthManager) Impersonate(
+	user *models.UserModel,
+	actor *Actor,
+	client *ClientInfo,
+	apiSecret string,
+) (string, *Session, error) {
+	session := newSession(user.ID, client)
+	session.ExpiresAt = session.CreatedAt.Add(ImpersonationTTL)
+	session.Actor = actor
+
+	// Actor has already verified their own second factor, as their role requires it.
+	session.TwoFactorVerified = true
+
+	accessToken, err := am.jwt.CreateToken(
+		session.ID.String(),
+		user.ID.String(),
+		user.Email,
+		user.Role,
+		apiSecret,
+		session.ExpiresAt.Unix(),
+	)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := am.saveSession(session); err != nil {
+		return "", nil, err
+	}
+
+	// Session is listed with user's own ones, so the user can see and revoke it,
+	// and it's closed together with them.
+	if err := am.cache.SAdd(am.ctx, userSessionsKey(user.ID), session.ID.String()).Err(); err != nil {
+		return "", nil, err
+	}
+
+	return accessToken, session, nil
+}
+
+// ImpersonationAuditor - records actions performed within impersonated sessions.
+type ImpersonationAuditor struct {
+	broker persist.DBBroker
+}
+
+// NewImpersonationAuditor - ImpersonationAuditor constructor func.
+func NewImpersonationAuditor() *ImpersonationAuditor {
+	return &ImpersonationAuditor{
+		broker: persist.GormBroker,
+	}
+}
+
+// RecordAction - saves given action, as performed by its actor.
+func (ia *ImpersonationAuditor) RecordAction(action *models.ImpersonationActionModel) error {
+	action.CreatedBy = action.ActorID
+	action.UpdatedBy = action.ActorID
+
+	return ia.broker.Save(action).Err()
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type impersonationSuite struct {
+	suite.Suite
+	authManager *AuthManager
+	testUser    *models.UserModel
+	testActor   *Actor
+	testClient  *ClientInfo
+}
+
+func (s *impersonationSuite) SetupTest() {
+	s.authManager = &AuthManager{
+		cache: mocks.NewRedisCacheMock(),
+		jwt:   &jwtManagerMock{},
+		ctx:   context.Background(),
+	}
+
+	s.testUser = &models.UserModel{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "user@gochat.com",
+		Role:      "USER",
+	}
+	s.testActor = &Actor{ID: uuid.New(), Email: "admin@gochat.com"}
+	s.testClient = &ClientInfo{IP: "127.0.0.1", UserAgent: "test_user_agent"}
+}
+
+func TestImpersonationSuite(t *testing.T) {
+	suite.Run(t, new(impersonationSuite))
+}
+
+func (s *impersonationSuite) TestImpersonate() {
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On(
+		"CreateToken",
+		mock.Anything,
+		s.testUser.ID.String(),
+		s.testUser.Email,
+		s.testUser.Role,
+		"test_secret",
+		mock.Anything,
+	).Return("test_token", nil)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On("SAdd", mock.Anything, mock.Anything, mock.Anything).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.jwt = jwtMock
+	s.authManager.cache = cacheMock
+
+	token, session, err := s.authManager.Impersonate(s.testUser, s.testActor, s.testClient, "test_secret")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "test_token", token)
+	assert.Equal(s.T(), s.testUser.ID, session.UserID)
+	assert.Equal(s.T(), s.testActor, session.Actor)
+	assert.True(s.T(), session.TwoFactorVerified)
+	assert.Equal(s.T(), session.CreatedAt.Add(ImpersonationTTL), session.ExpiresAt)
+
+	// Token expires together with the session, as it cannot be refreshed.
+	jwtMock.AssertCalled(
+		s.T(),
+		"CreateToken",
+		session.ID.String(),
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		session.ExpiresAt.Unix(),
+	)
+
+	savedSession := &Session{}
+	err = json.Unmarshal([]byte(cacheMock.Calls[0].Arguments.String(2)), savedSession)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testActor, savedSession.Actor)
+
+	cacheMock.AssertNumberOfCalls(s.T(), "Set", 1)
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, session.ID.String(), mock.Anything, mock.MatchedBy(
+		func(ttl time.Duration) bool {
+			return ttl > 0 && ttl <= ImpersonationTTL
+		},
+	))
+	cacheMock.AssertCalled(s.T(), "SAdd", mock.Anything, userSessionsKey(s.testUser.ID), []string{session.ID.String()})
+}
+
+func (s *impersonationSuite) TestImpersonate_TokenError() {
+	jwtMock := new(jwtManagerMock)
+	jwtMock.On(
+		"CreateToken",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return("", errors.New("TokenError"))
+
+	cacheMock := new(mocks.RedisCacheMock)
+
+	s.authManager.jwt = jwtMock
+	s.authManager.cache = cacheMock
+
+	_, session, err := s.authManager.Impersonate(s.testUser, s.testActor, s.testClient, "test_secret")
+
+	assert.Nil(s.T(), session)
+	assert.NotNil(s.T(), err)
+
+	cacheMock.AssertNotCalled(s.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *impersonationSuite) TestRecordAction() {
+	gormMock := mocks.NewGormMock()
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	auditor := &ImpersonationAuditor{broker: gormMock}
+
+	action := &models.ImpersonationActionModel{
+		SessionID: uuid.New(),
+		UserID:    s.testUser.ID,
+		ActorID:   s.testActor.ID,
+		Method:    "DELETE",
+		Path:      "/api/conversations/1",
+		Status:    200,
+	}
+
+	err := auditor.RecordAction(action)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testActor.ID, action.CreatedBy)
+
+	gormMock.AssertCalled(s.T(), "Save", action)
+}
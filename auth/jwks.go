@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK - public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+
+	// RSA public key members.
+	Modulus  string `json:"n,omitempty"`
+	Exponent string `json:"e,omitempty"`
+
+	// Octet key pair (Ed25519) public key members.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS - JSON Web Key Set, listing keys JWTs can be verified with.
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// newJWK - returns JWK describing public part of given key.
+func newJWK(key *SigningKey) *JWK {
+	jwk := &JWK{
+		Use:       "sig",
+		Algorithm: key.Algorithm,
+		KeyID:     key.ID,
+	}
+
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return jwk
+}
+
+// parseJWK - returns public key described by given JWK.
+func parseJWK(jwk *JWK) (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		modulus, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+
+		if err != nil {
+			return nil, err
+		}
+
+		exponent, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(modulus) == 0 || len(exponent) == 0 || len(exponent) > 4 {
+			return nil, errors.New("RSA JWK is malformed")
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}, nil
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return nil, ErrSigningKeyUnsupported
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("Ed25519 JWK is malformed")
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, ErrSigningKeyUnsupported
+}
+
+// jwkThumbprint - returns JWK thumbprint (RFC 7638) of given key - base64url encoded SHA-256
+// of its required members, serialized in lexicographic order without whitespace.
+func jwkThumbprint(jwk *JWK) string {
+	var members string
+
+	switch jwk.KeyType {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.Exponent, jwk.Modulus)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Curve, jwk.X)
+	}
+
+	sum := sha256.Sum256([]byte(members))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
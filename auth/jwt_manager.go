@@ -17,16 +17,25 @@ type JWTClaims struct {
 }
 
 type tokenCreator interface {
-	CreateToken(claims jwt.Claims, secret string) (string, error)
+	CreateToken(claims jwt.Claims, method jwt.SigningMethod, keyID string, key interface{}) (string, error)
 	ParseToken(tokenString string, validateFunc jwt.Keyfunc) (*jwt.Token, error)
 }
 
 type tokenProvider struct{}
 
-func (tp *tokenProvider) CreateToken(claims jwt.Claims, secret string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+func (tp *tokenProvider) CreateToken(
+	claims jwt.Claims,
+	method jwt.SigningMethod,
+	keyID string,
+	key interface{},
+) (string, error) {
+	token := jwt.NewWithClaims(method, claims)
 
-	return token.SignedString([]byte(secret))
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+
+	return token.SignedString(key)
 }
 
 func (tp *tokenProvider) ParseToken(tokenString string, validateFunc jwt.Keyfunc) (*jwt.Token, error) {
@@ -36,22 +45,25 @@ func (tp *tokenProvider) ParseToken(tokenString string, validateFunc jwt.Keyfunc
 // JWTManager - manages operations specific to JWT handling.
 type JWTManager struct {
 	tokenProvider tokenCreator
+	keys          *KeySet
 }
 
 // NewJWTManager - JWTManager constructor func.
 func NewJWTManager() *JWTManager {
 	return &JWTManager{
 		tokenProvider: &tokenProvider{},
+		keys:          DefaultKeySet,
 	}
 }
 
-// CreateToken - creates a new token for the given user.
+// CreateToken - creates a new token for the given user. Secret is used only
+// if tokens are signed with HS256 - otherwise, KeySet's signing key is used.
 func (jm *JWTManager) CreateToken(authUUID, userID, email, role, secret string, time int64) (string, error) {
 	if authUUID == "" ||
 		userID == "" ||
 		email == "" ||
 		role == "" ||
-		secret == "" ||
+		(secret == "" && jm.keys.UsesSecret()) ||
 		time == 0 {
 		return "", errors.New("Missing claims for JWT Token!")
 	}
@@ -65,17 +77,26 @@ func (jm *JWTManager) CreateToken(authUUID, userID, email, role, secret string,
 
 	claims.ExpiresAt = time
 
-	return jm.tokenProvider.CreateToken(claims, secret)
+	if jm.keys.UsesSecret() {
+		return jm.tokenProvider.CreateToken(claims, jwt.SigningMethodHS256, "", []byte(secret))
+	}
+
+	signingKey := jm.keys.SigningKey()
+
+	return jm.tokenProvider.CreateToken(
+		claims,
+		jwt.GetSigningMethod(signingKey.Algorithm),
+		signingKey.ID,
+		signingKey.PrivateKey,
+	)
 }
 
 // ParseToken - parses given token string and returns an instance of jwt.Token.
+// HS256 tokens are accepted only while tokens are signed with the API secret - others
+// have to be signed with one of KeySet's keys, identified by "kid" header.
 func (jm *JWTManager) ParseToken(tokenString string, apiSecret string) (*jwt.Token, error) {
 	token, err := jm.tokenProvider.ParseToken(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		return []byte(apiSecret), nil
+		return jm.getVerificationKey(token, apiSecret)
 	})
 
 	if err != nil {
@@ -84,3 +105,28 @@ func (jm *JWTManager) ParseToken(tokenString string, apiSecret string) (*jwt.Tok
 
 	return token, nil
 }
+
+// getVerificationKey - returns the key given token's signature should be verified with.
+// Algorithm of the key has to match token's one, so keys cannot be used with other algorithms.
+func (jm *JWTManager) getVerificationKey(token *jwt.Token, apiSecret string) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if !jm.keys.UsesSecret() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return []byte(apiSecret), nil
+	}
+
+	keyID, _ := token.Header["kid"].(string)
+	key, ok := jm.keys.VerificationKey(keyID)
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %v", token.Header["kid"])
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
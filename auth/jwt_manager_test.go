@@ -15,8 +15,13 @@ type tokenProviderMock struct {
 	mock.Mock
 }
 
-func (mtp *tokenProviderMock) CreateToken(claims jwt.Claims, secret string) (string, error) {
-	args := mtp.Called(claims, secret)
+func (mtp *tokenProviderMock) CreateToken(
+	claims jwt.Claims,
+	method jwt.SigningMethod,
+	keyID string,
+	key interface{},
+) (string, error) {
+	args := mtp.Called(claims, method, keyID, key)
 
 	return args.String(0), args.Error(1)
 }
@@ -64,6 +69,7 @@ func (s *jwtManagerSuite) SetupSuite() {
 func (s *jwtManagerSuite) SetupTest() {
 	s.jwtManager = &JWTManager{
 		tokenProvider: &tokenProviderMock{},
+		keys:          NewHMACKeySet(),
 	}
 }
 
@@ -86,6 +92,8 @@ func (s *jwtManagerSuite) TestCreateTokenValid() {
 		"CreateToken",
 		mock.Anything,
 		mock.Anything,
+		mock.Anything,
+		mock.Anything,
 	).Return(s.testTokenString, nil)
 
 	jwtManager.tokenProvider = tokenMock
@@ -103,6 +111,7 @@ func (s *jwtManagerSuite) TestCreateTokenValid() {
 	assert.Nil(s.T(), err)
 
 	tokenMock.AssertNumberOfCalls(s.T(), "CreateToken", 1)
+	tokenMock.AssertCalled(s.T(), "CreateToken", mock.Anything, jwt.SigningMethodHS256, "", []byte(s.testSecret))
 }
 
 func (s *jwtManagerSuite) TestCreateToken_MissingArgs() {
@@ -113,6 +122,8 @@ func (s *jwtManagerSuite) TestCreateToken_MissingArgs() {
 		"CreateToken",
 		mock.Anything,
 		mock.Anything,
+		mock.Anything,
+		mock.Anything,
 	).Return(s.testTokenString, nil)
 
 	jwtManager.tokenProvider = tokenMock
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	// DefaultLDAPUserFilter - filter user's entry is searched with, unless LDAP_USER_FILTER is set.
+	// "%s" is replaced with escaped login.
+	DefaultLDAPUserFilter = "(&(objectClass=person)(mail=%s))"
+
+	// DefaultLDAPGroupAttribute - attribute holding user's groups, unless LDAP_GROUP_ATTRIBUTE is set.
+	DefaultLDAPGroupAttribute = "memberOf"
+
+	// Timeout of connections and requests sent to the directory.
+	ldapTimeout = 10 * time.Second
+)
+
+// LDAPConfig - describes the directory and the way users are found in it.
+type LDAPConfig struct {
+	URL string
+
+	// BindDN and BindPassword - service account used to search for users. Users are searched
+	// anonymously if BindDN is empty.
+	BindDN       string
+	BindPassword string
+
+	BaseDN         string
+	UserFilter     string
+	GroupAttribute string
+	StartTLS       bool
+
+	// GroupRoles - maps directory's groups to Gochat roles.
+	GroupRoles map[string]string
+}
+
+// LDAPEntry - directory's entry of authenticated user.
+type LDAPEntry struct {
+	DN        string
+	Email     string
+	FirstName string
+	LastName  string
+	Groups    []string
+}
+
+// ldapConnection - subset of ldap.Conn methods used for authentication.
+type ldapConnection interface {
+	Bind(username, password string) error
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close()
+}
+
+// LDAPDirectory - authenticates users with LDAP simple bind.
+type LDAPDirectory struct {
+	config *LDAPConfig
+	dial   func() (ldapConnection, error)
+}
+
+// DefaultLDAPDirectory - directory used for LDAP authentication. It's nil if LDAP
+// authentication is not configured.
+var DefaultLDAPDirectory *LDAPDirectory
+
+// NewLDAPDirectory - LDAPDirectory constructor func.
+func NewLDAPDirectory(config *LDAPConfig) *LDAPDirectory {
+	directory := &LDAPDirectory{
+		config: config,
+	}
+
+	directory.dial = directory.dialURL
+
+	return directory
+}
+
+// InitLDAPDirectory - initializes DefaultLDAPDirectory, based on LDAP_* env variables.
+// LDAP authentication stays disabled if LDAP_URL is not set.
+func InitLDAPDirectory() (*LDAPDirectory, error) {
+	directoryURL := os.Getenv("LDAP_URL")
+
+	if directoryURL == "" {
+		DefaultLDAPDirectory = nil
+
+		return nil, nil
+	}
+
+	config := &LDAPConfig{
+		URL:            directoryURL,
+		BindDN:         os.Getenv("LDAP_BIND_DN"),
+		BindPassword:   os.Getenv("LDAP_BIND_PASSWORD"),
+		BaseDN:         os.Getenv("LDAP_BASE_DN"),
+		UserFilter:     DefaultLDAPUserFilter,
+		GroupAttribute: DefaultLDAPGroupAttribute,
+		StartTLS:       os.Getenv("LDAP_START_TLS") == "true",
+	}
+
+	if config.BaseDN == "" {
+		return nil, errors.New("LDAP_BASE_DN is required for LDAP authentication")
+	}
+
+	if userFilter := os.Getenv("LDAP_USER_FILTER"); userFilter != "" {
+		if strings.Count(userFilter, "%s") != 1 {
+			return nil, errors.New("LDAP_USER_FILTER has to contain single %s placeholder")
+		}
+
+		config.UserFilter = userFilter
+	}
+
+	if groupAttribute := os.Getenv("LDAP_GROUP_ATTRIBUTE"); groupAttribute != "" {
+		config.GroupAttribute = groupAttribute
+	}
+
+	groupRoles, err := ParseGroupRoles(os.Getenv("LDAP_GROUP_ROLES"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.GroupRoles = groupRoles
+
+	DefaultLDAPDirectory = NewLDAPDirectory(config)
+
+	return DefaultLDAPDirectory, nil
+}
+
+// URL - returns directory's URL.
+func (ld *LDAPDirectory) URL() string {
+	return ld.config.URL
+}
+
+// GroupRoles - returns configured mapping of directory's groups to Gochat roles.
+func (ld *LDAPDirectory) GroupRoles() map[string]string {
+	return ld.config.GroupRoles
+}
+
+// Authenticate - finds the entry of the user with given login, and verifies given password
+// by binding as that entry. Returns ErrLoginCredentialsIncorrect if there is no such entry,
+// or the password is incorrect.
+func (ld *LDAPDirectory) Authenticate(login, password string) (*LDAPEntry, error) {
+	// Bind with empty password is an unauthenticated bind, which succeeds for any DN.
+	if login == "" || password == "" {
+		return nil, ErrLoginCredentialsIncorrect
+	}
+
+	conn, err := ld.dial()
+
+	if err != nil {
+		return nil, fmt.Errorf("Connecting to LDAP directory failed: %v", err)
+	}
+
+	defer conn.Close()
+
+	if ld.config.BindDN != "" {
+		if err := conn.Bind(ld.config.BindDN, ld.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("Binding LDAP service account failed: %v", err)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		ld.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		// Two entries are enough to tell that the login is ambiguous.
+		2,
+		int(ldapTimeout.Seconds()),
+		false,
+		fmt.Sprintf(ld.config.UserFilter, ldap.EscapeFilter(login)),
+		[]string{"mail", "givenName", "sn", ld.config.GroupAttribute},
+		nil,
+	))
+
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, ErrLoginCredentialsIncorrect
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Searching LDAP directory failed: %v", err)
+	}
+
+	if len(result.Entries) != 1 {
+		return nil, ErrLoginCredentialsIncorrect
+	}
+
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrLoginCredentialsIncorrect
+		}
+
+		return nil, fmt.Errorf("Binding LDAP user failed: %v", err)
+	}
+
+	return &LDAPEntry{
+		DN:        entry.DN,
+		Email:     entry.GetAttributeValue("mail"),
+		FirstName: entry.GetAttributeValue("givenName"),
+		LastName:  entry.GetAttributeValue("sn"),
+		Groups:    getLDAPGroupNames(entry.GetAttributeValues(ld.config.GroupAttribute)),
+	}, nil
+}
+
+// dialURL - connects to the directory, upgrading the connection with StartTLS if configured.
+func (ld *LDAPDirectory) dialURL() (ldapConnection, error) {
+	conn, err := ldap.DialURL(ld.config.URL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetTimeout(ldapTimeout)
+
+	if ld.config.StartTLS {
+		if err := conn.StartTLS(&tls.Config{ServerName: getLDAPHostname(ld.config.URL)}); err != nil {
+			conn.Close()
+
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// getLDAPGroupNames - returns given groups, together with common names of the ones being DNs,
+// so groups can be mapped to roles with either of them.
+func getLDAPGroupNames(groups []string) []string {
+	names := []string{}
+
+	for _, group := range groups {
+		names = append(names, group)
+
+		dn, err := ldap.ParseDN(group)
+
+		if err != nil || len(dn.RDNs) == 0 {
+			continue
+		}
+
+		for _, attribute := range dn.RDNs[0].Attributes {
+			if strings.EqualFold(attribute.Type, "cn") {
+				names = append(names, attribute.Value)
+			}
+		}
+	}
+
+	return names
+}
+
+// getLDAPHostname - returns the hostname of given directory URL.
+func getLDAPHostname(directoryURL string) string {
+	parsedURL, err := url.Parse(directoryURL)
+
+	if err != nil {
+		return ""
+	}
+
+	return parsedURL.Hostname()
+}
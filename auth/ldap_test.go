@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ldapConnectionMock struct {
+	mock.Mock
+}
+
+func (cm *ldapConnectionMock) Bind(username, password string) error {
+	args := cm.Called(username, password)
+
+	return args.Error(0)
+}
+
+func (cm *ldapConnectionMock) Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	args := cm.Called(searchRequest)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*ldap.SearchResult), args.Error(1)
+}
+
+func (cm *ldapConnectionMock) Close() {
+	cm.Called()
+}
+
+type ldapSuite struct {
+	suite.Suite
+	directory *LDAPDirectory
+	testDN    string
+}
+
+func (s *ldapSuite) SetupTest() {
+	s.directory = NewLDAPDirectory(&LDAPConfig{
+		URL:            "ldap://ldap.example.com:389",
+		BindDN:         "cn=gochat,ou=services,dc=example,dc=com",
+		BindPassword:   "service-password",
+		BaseDN:         "ou=people,dc=example,dc=com",
+		UserFilter:     DefaultLDAPUserFilter,
+		GroupAttribute: DefaultLDAPGroupAttribute,
+	})
+
+	s.testDN = "uid=jdoe,ou=people,dc=example,dc=com"
+}
+
+func TestLDAPSuite(t *testing.T) {
+	suite.Run(t, new(ldapSuite))
+}
+
+// useConnection - makes the directory use given connection mock.
+func (s *ldapSuite) useConnection(conn *ldapConnectionMock) {
+	conn.On("Close").Return()
+
+	s.directory.dial = func() (ldapConnection, error) {
+		return conn, nil
+	}
+}
+
+func (s *ldapSuite) getTestEntry() *ldap.Entry {
+	return ldap.NewEntry(s.testDN, map[string][]string{
+		"mail":      {"jdoe@example.com"},
+		"givenName": {"John"},
+		"sn":        {"Doe"},
+		"memberOf": {
+			"cn=gochat-admins,ou=groups,dc=example,dc=com",
+			"staff",
+		},
+	})
+}
+
+func (s *ldapSuite) TestAuthenticate() {
+	conn := new(ldapConnectionMock)
+	conn.On("Bind", "cn=gochat,ou=services,dc=example,dc=com", "service-password").Return(nil)
+	conn.On("Search", mock.Anything).Return(&ldap.SearchResult{
+		Entries: []*ldap.Entry{s.getTestEntry()},
+	}, nil)
+	conn.On("Bind", s.testDN, "user-password").Return(nil)
+
+	s.useConnection(conn)
+
+	entry, err := s.directory.Authenticate("jdoe@example.com", "user-password")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &LDAPEntry{
+		DN:        s.testDN,
+		Email:     "jdoe@example.com",
+		FirstName: "John",
+		LastName:  "Doe",
+		Groups:    []string{"cn=gochat-admins,ou=groups,dc=example,dc=com", "gochat-admins", "staff"},
+	}, entry)
+
+	searchRequest := conn.Calls[1].Arguments.Get(0).(*ldap.SearchRequest)
+
+	assert.Equal(s.T(), "ou=people,dc=example,dc=com", searchRequest.BaseDN)
+	assert.Equal(s.T(), "(&(objectClass=person)(mail=jdoe@example.com))", searchRequest.Filter)
+	assert.Equal(s.T(), []string{"mail", "givenName", "sn", "memberOf"}, searchRequest.Attributes)
+
+	conn.AssertNumberOfCalls(s.T(), "Close", 1)
+}
+
+func (s *ldapSuite) TestAuthenticate_FilterEscaped() {
+	conn := new(ldapConnectionMock)
+	conn.On("Bind", mock.Anything, mock.Anything).Return(nil)
+	conn.On("Search", mock.Anything).Return(&ldap.SearchResult{}, nil)
+
+	s.useConnection(conn)
+
+	_, err := s.directory.Authenticate("*)(mail=*", "user-password")
+
+	assert.Equal(s.T(), ErrLoginCredentialsIncorrect, err)
+
+	searchRequest := conn.Calls[1].Arguments.Get(0).(*ldap.SearchRequest)
+
+	assert.Equal(s.T(), `(&(objectClass=person)(mail=\2a\29\28mail=\2a))`, searchRequest.Filter)
+}
+
+func (s *ldapSuite) TestAuthenticate_EmptyPassword() {
+	conn := new(ldapConnectionMock)
+
+	s.useConnection(conn)
+
+	_, err := s.directory.Authenticate("jdoe@example.com", "")
+
+	assert.Equal(s.T(), ErrLoginCredentialsIncorrect, err)
+
+	conn.AssertNotCalled(s.T(), "Bind", mock.Anything, mock.Anything)
+}
+
+func (s *ldapSuite) TestAuthenticate_IncorrectPassword() {
+	conn := new(ldapConnectionMock)
+	conn.On("Bind", "cn=gochat,ou=services,dc=example,dc=com", mock.Anything).Return(nil)
+	conn.On("Search", mock.Anything).Return(&ldap.SearchResult{
+		Entries: []*ldap.Entry{s.getTestEntry()},
+	}, nil)
+	conn.On("Bind", s.testDN, mock.Anything).Return(
+		ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("Invalid Credentials")),
+	)
+
+	s.useConnection(conn)
+
+	_, err := s.directory.Authenticate("jdoe@example.com", "wrong-password")
+
+	assert.Equal(s.T(), ErrLoginCredentialsIncorrect, err)
+}
+
+func (s *ldapSuite) TestAuthenticate_AmbiguousLogin() {
+	conn := new(ldapConnectionMock)
+	conn.On("Bind", mock.Anything, mock.Anything).Return(nil)
+	conn.On("Search", mock.Anything).Return(
+		&ldap.SearchResult{Entries: []*ldap.Entry{s.getTestEntry(), s.getTestEntry()}},
+		ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("Size Limit Exceeded")),
+	)
+
+	s.useConnection(conn)
+
+	_, err := s.directory.Authenticate("jdoe@example.com", "user-password")
+
+	assert.Equal(s.T(), ErrLoginCredentialsIncorrect, err)
+
+	conn.AssertNumberOfCalls(s.T(), "Bind", 1)
+}
+
+func (s *ldapSuite) TestAuthenticate_ServiceBindError() {
+	conn := new(ldapConnectionMock)
+	conn.On("Bind", mock.Anything, mock.Anything).Return(
+		ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("Invalid Credentials")),
+	)
+
+	s.useConnection(conn)
+
+	_, err := s.directory.Authenticate("jdoe@example.com", "user-password")
+
+	// Misconfigured service account is not user's mistake.
+	assert.NotNil(s.T(), err)
+	assert.NotEqual(s.T(), ErrLoginCredentialsIncorrect, err)
+
+	conn.AssertNotCalled(s.T(), "Search", mock.Anything)
+}
+
+func (s *ldapSuite) TestAuthenticate_AnonymousSearch() {
+	s.directory.config.BindDN = ""
+
+	conn := new(ldapConnectionMock)
+	conn.On("Search", mock.Anything).Return(&ldap.SearchResult{
+		Entries: []*ldap.Entry{s.getTestEntry()},
+	}, nil)
+	conn.On("Bind", s.testDN, "user-password").Return(nil)
+
+	s.useConnection(conn)
+
+	_, err := s.directory.Authenticate("jdoe@example.com", "user-password")
+
+	assert.Nil(s.T(), err)
+
+	conn.AssertNumberOfCalls(s.T(), "Bind", 1)
+}
+
+func (s *ldapSuite) TestParseGroupRoles() {
+	groupRoles, err := ParseGroupRoles(" gochat-admins=ADMIN, ops = SUPER_ADMIN ,")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), map[string]string{
+		"gochat-admins": "ADMIN",
+		"ops":           "SUPER_ADMIN",
+	}, groupRoles)
+
+	_, err = ParseGroupRoles("gochat-admins")
+
+	assert.NotNil(s.T(), err)
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LoginFailuresTTL - time after the last failed login attempt, after which failures are forgotten.
+	LoginFailuresTTL = 24 * time.Hour
+
+	// LoginBackoffBase - lockout duration after the first failed attempt exceeding free attempts.
+	// It's doubled with every following failure.
+	LoginBackoffBase = time.Second
+
+	// LoginLockoutMax - the longest time account or IP can be locked for.
+	LoginLockoutMax = 15 * time.Minute
+
+	// AccountFreeLoginAttempts - number of failed login attempts to a single account,
+	// allowed without any delay.
+	AccountFreeLoginAttempts = 3
+
+	// IPFreeLoginAttempts - number of failed login attempts from a single IP address,
+	// allowed without any delay. It's higher than the account's one, as many users
+	// can share an address.
+	IPFreeLoginAttempts = 20
+
+	// Prefix of the cache keys holding the numbers of failed login attempts.
+	loginFailuresKeyPrefix = "login:failures:"
+
+	// Prefix of the cache keys holding the time login is locked until.
+	loginLockKeyPrefix = "login:lock:"
+)
+
+// ErrLoginCredentialsIncorrect - returned when there is no user with given email,
+// or the password is incorrect.
+var ErrLoginCredentialsIncorrect = errors.New("Login credentials are incorrect.")
+
+// AccountLockedError - returned when login is temporarily locked because
+// of too many failed attempts.
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+// Error - satisfies standard Error interface.
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("Too many failed login attempts, try again in %d seconds.", retryAfterSeconds(e.RetryAfter))
+}
+
+// RetryAfterSeconds - returns the number of seconds after which login can be retried.
+func (e *AccountLockedError) RetryAfterSeconds() int {
+	return retryAfterSeconds(e.RetryAfter)
+}
+
+// loginThrottle - describes failed login attempts counting of single kind of subject.
+type loginThrottle struct {
+	subject      string
+	freeAttempts int64
+}
+
+var (
+	accountThrottle = &loginThrottle{subject: "account", freeAttempts: AccountFreeLoginAttempts}
+	ipThrottle      = &loginThrottle{subject: "ip", freeAttempts: IPFreeLoginAttempts}
+)
+
+// CheckLoginAllowed - returns AccountLockedError if logging in to the account with given email,
+// or from given IP address, is currently locked.
+func (am *AuthManager) CheckLoginAllowed(email, ip string) error {
+	var retryAfter time.Duration
+
+	for throttle, id := range am.throttledSubjects(email, ip) {
+		lockedFor, err := am.getLockDuration(throttle, id)
+
+		if err != nil {
+			return err
+		}
+
+		if lockedFor > retryAfter {
+			retryAfter = lockedFor
+		}
+	}
+
+	if retryAfter > 0 {
+		return &AccountLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// RegisterLoginFailure - counts failed login attempt to the account with given email, from
+// given IP address. Once free attempts are used, every failure locks login for exponentially
+// growing time.
+func (am *AuthManager) RegisterLoginFailure(email, ip string) error {
+	for throttle, id := range am.throttledSubjects(email, ip) {
+		failuresKey := loginFailuresKey(throttle, id)
+
+		res := am.cache.Incr(am.ctx, failuresKey)
+
+		if res.Err() != nil {
+			return res.Err()
+		}
+
+		if err := am.cache.Expire(am.ctx, failuresKey, LoginFailuresTTL).Err(); err != nil {
+			return err
+		}
+
+		failures, err := strconv.ParseInt(res.Val(), 10, 64)
+
+		if err != nil {
+			return err
+		}
+
+		if failures <= throttle.freeAttempts {
+			continue
+		}
+
+		lockFor := loginBackoff(failures - throttle.freeAttempts)
+		lockedUntil := time.Now().Add(lockFor)
+
+		err = am.cache.Set(am.ctx, loginLockKey(throttle, id), strconv.FormatInt(lockedUntil.UnixNano(), 10), lockFor).Err()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResetLoginFailures - forgets failed login attempts to the account with given email,
+// unlocking it. Failures counted for IP addresses are kept.
+func (am *AuthManager) ResetLoginFailures(email string) error {
+	id := normalizeEmail(email)
+
+	return am.cache.Del(am.ctx, loginFailuresKey(accountThrottle, id), loginLockKey(accountThrottle, id)).Err()
+}
+
+// throttledSubjects - returns subjects of a login attempt, with their identifiers.
+func (am *AuthManager) throttledSubjects(email, ip string) map[*loginThrottle]string {
+	subjects := map[*loginThrottle]string{
+		accountThrottle: normalizeEmail(email),
+	}
+
+	if ip != "" {
+		subjects[ipThrottle] = ip
+	}
+
+	return subjects
+}
+
+// getLockDuration - returns the time login of given subject is locked for,
+// or 0 if it's not locked.
+func (am *AuthManager) getLockDuration(throttle *loginThrottle, id string) (time.Duration, error) {
+	res := am.cache.Get(am.ctx, loginLockKey(throttle, id))
+
+	// Lock expires together with its key.
+	if res.Err() != nil {
+		return 0, nil
+	}
+
+	lockedUntil, err := strconv.ParseInt(res.Val(), 10, 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	lockedFor := time.Until(time.Unix(0, lockedUntil))
+
+	if lockedFor < 0 {
+		return 0, nil
+	}
+
+	return lockedFor, nil
+}
+
+// loginBackoff - returns the lockout duration after given number of failures
+// exceeding free attempts.
+func loginBackoff(excessFailures int64) time.Duration {
+	// Prevents overflow - the cap is reached long before that anyway.
+	if excessFailures > 32 {
+		return LoginLockoutMax
+	}
+
+	backoff := LoginBackoffBase << uint(excessFailures-1)
+
+	if backoff > LoginLockoutMax {
+		return LoginLockoutMax
+	}
+
+	return backoff
+}
+
+func retryAfterSeconds(retryAfter time.Duration) int {
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+
+	if seconds < 1 {
+		return 1
+	}
+
+	return seconds
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginFailuresKey(throttle *loginThrottle, id string) string {
+	return loginFailuresKeyPrefix + throttle.subject + ":" + id
+}
+
+func loginLockKey(throttle *loginThrottle, id string) string {
+	return loginLockKeyPrefix + throttle.subject + ":" + id
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type loginThrottleSuite struct {
+	suite.Suite
+	authManager *AuthManager
+	testEmail   string
+	testIP      string
+}
+
+func (s *loginThrottleSuite) SetupTest() {
+	s.authManager = &AuthManager{
+		cache: mocks.NewRedisCacheMock(),
+		ctx:   context.Background(),
+	}
+
+	s.testEmail = "test_email@gochat.com"
+	s.testIP = "127.0.0.1"
+}
+
+func TestLoginThrottleSuite(t *testing.T) {
+	suite.Run(t, new(loginThrottleSuite))
+}
+
+func (s *loginThrottleSuite) TestLoginBackoff() {
+	assert.Equal(s.T(), time.Second, loginBackoff(1))
+	assert.Equal(s.T(), 2*time.Second, loginBackoff(2))
+	assert.Equal(s.T(), 8*time.Second, loginBackoff(4))
+	assert.Equal(s.T(), LoginLockoutMax, loginBackoff(11))
+	assert.Equal(s.T(), LoginLockoutMax, loginBackoff(1000))
+}
+
+func (s *loginThrottleSuite) TestCheckLoginAllowed() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.CheckLoginAllowed(s.testEmail, s.testIP))
+
+	cacheMock.AssertCalled(s.T(), "Get", mock.Anything, "login:lock:account:test_email@gochat.com")
+	cacheMock.AssertCalled(s.T(), "Get", mock.Anything, "login:lock:ip:127.0.0.1")
+}
+
+func (s *loginThrottleSuite) TestCheckLoginAllowed_Locked() {
+	lockedUntil := time.Now().Add(time.Minute).UnixNano()
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		"login:lock:account:test_email@gochat.com",
+	).Return(mocks.GetValueCacheResponse(strconv.FormatInt(lockedUntil, 10)))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	s.authManager.cache = cacheMock
+
+	// Emails are compared case-insensitively.
+	err := s.authManager.CheckLoginAllowed("Test_Email@gochat.com", s.testIP)
+
+	lockedErr, ok := err.(*AccountLockedError)
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 60, lockedErr.RetryAfterSeconds())
+}
+
+func (s *loginThrottleSuite) TestRegisterLoginFailure_FreeAttempt() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Incr",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("1"))
+	cacheMock.On(
+		"Expire",
+		mock.Anything,
+		mock.Anything,
+		LoginFailuresTTL,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.RegisterLoginFailure(s.testEmail, s.testIP))
+
+	cacheMock.AssertCalled(s.T(), "Incr", mock.Anything, "login:failures:account:test_email@gochat.com")
+	cacheMock.AssertCalled(s.T(), "Incr", mock.Anything, "login:failures:ip:127.0.0.1")
+	cacheMock.AssertNotCalled(s.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleSuite) TestRegisterLoginFailure_Backoff() {
+	accountFailures := AccountFreeLoginAttempts + 3
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Incr",
+		mock.Anything,
+		"login:failures:account:test_email@gochat.com",
+	).Return(mocks.GetValueCacheResponse(strconv.Itoa(accountFailures)))
+	cacheMock.On(
+		"Incr",
+		mock.Anything,
+		"login:failures:ip:127.0.0.1",
+	).Return(mocks.GetValueCacheResponse("1"))
+	cacheMock.On(
+		"Expire",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.RegisterLoginFailure(s.testEmail, s.testIP))
+
+	cacheMock.AssertNumberOfCalls(s.T(), "Set", 1)
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, "login:lock:account:test_email@gochat.com", mock.Anything, 4*time.Second)
+}
+
+func (s *loginThrottleSuite) TestResetLoginFailures() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.ResetLoginFailures("TEST_EMAIL@gochat.com"))
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{
+		"login:failures:account:test_email@gochat.com",
+		"login:lock:account:test_email@gochat.com",
+	})
+}
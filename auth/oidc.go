@@ -0,0 +1,445 @@
+package auth
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// DefaultOIDCScopes - scopes requested from identity provider, unless OIDC_SCOPES is set.
+	DefaultOIDCScopes = "openid email profile"
+
+	// DefaultOIDCGroupsClaim - ID token's claim holding user's groups, unless OIDC_GROUPS_CLAIM is set.
+	DefaultOIDCGroupsClaim = "groups"
+
+	// OIDCKeysRefreshInterval - minimal interval between fetching identity provider's keys,
+	// so tokens with unknown key IDs cannot make us query the provider on every request.
+	OIDCKeysRefreshInterval = time.Minute
+
+	// Path of OpenID Provider Configuration document, relative to the issuer.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// Timeout of requests sent to identity provider.
+	oidcRequestTimeout = 10 * time.Second
+)
+
+var (
+	// ErrOIDCNotConfigured - returned when OIDC login is used, but no identity provider is configured.
+	ErrOIDCNotConfigured = errors.New("OpenID Connect login is not configured.")
+
+	// ErrOIDCCodeInvalid - returned when identity provider rejects authorization code,
+	// e.g. because it has expired or has already been used.
+	ErrOIDCCodeInvalid = errors.New("OpenID Connect authorization code is invalid or expired.")
+
+	// ErrOIDCTokenInvalid - returned when ID token issued by identity provider
+	// cannot be verified.
+	ErrOIDCTokenInvalid = errors.New("OpenID Connect ID token is invalid.")
+)
+
+// OIDCConfig - describes identity provider and Gochat's client registered in it.
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+
+	// GroupRoles - maps identity provider's groups to Gochat roles.
+	GroupRoles map[string]string
+}
+
+// OIDCClaims - identity of a user, read from verified ID token.
+type OIDCClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Groups        []string
+}
+
+// oidcDiscovery - subset of OpenID Provider Metadata used by Gochat.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcTokenResponse - identity provider's response to authorization code exchange.
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCProvider - OpenID Connect client of a single identity provider, implementing
+// authorization code flow with PKCE. Provider's metadata is discovered on first use.
+type OIDCProvider struct {
+	config *OIDCConfig
+	client *http.Client
+
+	mutex         sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// DefaultOIDCProvider - identity provider used for OIDC login. It's nil if OIDC login
+// is not configured.
+var DefaultOIDCProvider *OIDCProvider
+
+// NewOIDCProvider - OIDCProvider constructor func.
+func NewOIDCProvider(config *OIDCConfig) *OIDCProvider {
+	return &OIDCProvider{
+		config: config,
+		client: &http.Client{Timeout: oidcRequestTimeout},
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// InitOIDCProvider - initializes DefaultOIDCProvider, based on OIDC_* env variables.
+// OIDC login stays disabled if OIDC_ISSUER_URL is not set.
+func InitOIDCProvider() (*OIDCProvider, error) {
+	issuerURL := os.Getenv("OIDC_ISSUER_URL")
+
+	if issuerURL == "" {
+		DefaultOIDCProvider = nil
+
+		return nil, nil
+	}
+
+	config := &OIDCConfig{
+		IssuerURL:    issuerURL,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(DefaultOIDCScopes),
+		GroupsClaim:  DefaultOIDCGroupsClaim,
+	}
+
+	if config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required for OIDC login")
+	}
+
+	if scopes := os.Getenv("OIDC_SCOPES"); scopes != "" {
+		config.Scopes = strings.Fields(scopes)
+	}
+
+	if groupsClaim := os.Getenv("OIDC_GROUPS_CLAIM"); groupsClaim != "" {
+		config.GroupsClaim = groupsClaim
+	}
+
+	groupRoles, err := ParseGroupRoles(os.Getenv("OIDC_GROUP_ROLES"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.GroupRoles = groupRoles
+
+	DefaultOIDCProvider = NewOIDCProvider(config)
+
+	return DefaultOIDCProvider, nil
+}
+
+// GroupRoles - returns configured mapping of identity provider's groups to Gochat roles.
+func (op *OIDCProvider) GroupRoles() map[string]string {
+	return op.config.GroupRoles
+}
+
+// AuthorizationURL - returns identity provider's URL the user should be redirected to,
+// in order to log in.
+func (op *OIDCProvider) AuthorizationURL(state, nonce, codeChallenge string) (string, error) {
+	discovery, err := op.discover()
+
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {op.config.ClientID},
+		"redirect_uri":          {op.config.RedirectURL},
+		"scope":                 {strings.Join(op.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {PKCEChallengeMethod},
+	}
+
+	separator := "?"
+
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange - exchanges authorization code for ID token, proving the login has been
+// started by us with PKCE code verifier.
+func (op *OIDCProvider) Exchange(code, codeVerifier string) (string, error) {
+	discovery, err := op.discover()
+
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {op.config.RedirectURL},
+		"client_id":     {op.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	// Public clients, relying on PKCE only, do not have a secret.
+	if op.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(op.config.ClientID), url.QueryEscape(op.config.ClientSecret))
+	}
+
+	response, err := op.client.Do(request)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	var tokenResponse oidcTokenResponse
+
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("Reading identity provider's token response failed: %v", err)
+	}
+
+	if response.StatusCode == http.StatusBadRequest && tokenResponse.Error == "invalid_grant" {
+		return "", ErrOIDCCodeInvalid
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(
+			"Identity provider rejected authorization code: %s %s",
+			tokenResponse.Error,
+			tokenResponse.ErrorDescription,
+		)
+	}
+
+	if tokenResponse.IDToken == "" {
+		return "", ErrOIDCTokenInvalid
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+// VerifyIDToken - verifies given ID token's signature, issuer, audience, expiration
+// and nonce, and returns the identity it describes.
+func (op *OIDCProvider) VerifyIDToken(rawIDToken, nonce string) (*OIDCClaims, error) {
+	discovery, err := op.discover()
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() != RS256Algorithm && token.Method.Alg() != EdDSAAlgorithm {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+
+		keyID, _ := token.Header["kid"].(string)
+
+		return op.getKey(discovery, keyID)
+	})
+
+	if err != nil {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if _, ok := claims["exp"]; !ok || !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if !hasAudience(claims["aud"], op.config.ClientID) {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if authorizedParty, ok := claims["azp"].(string); ok && authorizedParty != op.config.ClientID {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); nonce == "" || tokenNonce != nonce {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	oidcClaims := &OIDCClaims{
+		Issuer: discovery.Issuer,
+		Groups: getStringsClaim(claims[op.config.GroupsClaim]),
+	}
+
+	oidcClaims.Subject, _ = claims["sub"].(string)
+	oidcClaims.Email, _ = claims["email"].(string)
+	oidcClaims.EmailVerified, _ = claims["email_verified"].(bool)
+	oidcClaims.GivenName, _ = claims["given_name"].(string)
+	oidcClaims.FamilyName, _ = claims["family_name"].(string)
+
+	if oidcClaims.Subject == "" {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	return oidcClaims, nil
+}
+
+// discover - returns identity provider's metadata, fetching it on first use.
+func (op *OIDCProvider) discover() (*oidcDiscovery, error) {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+
+	if op.discovery != nil {
+		return op.discovery, nil
+	}
+
+	issuerURL := strings.TrimSuffix(op.config.IssuerURL, "/")
+
+	var discovery oidcDiscovery
+
+	if err := op.getJSON(issuerURL+oidcDiscoveryPath, &discovery); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuerURL {
+		return nil, fmt.Errorf("Identity provider's issuer %s does not match %s", discovery.Issuer, op.config.IssuerURL)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("Identity provider's metadata is incomplete")
+	}
+
+	op.discovery = &discovery
+
+	return op.discovery, nil
+}
+
+// getKey - returns identity provider's key with given ID. Keys are fetched again when
+// the key is not known, so provider's key rotation is picked up.
+func (op *OIDCProvider) getKey(discovery *oidcDiscovery, keyID string) (crypto.PublicKey, error) {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+
+	if key := op.findKey(keyID); key != nil {
+		return key, nil
+	}
+
+	if time.Since(op.keysFetchedAt) < OIDCKeysRefreshInterval {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	var keySet JWKS
+
+	if err := op.getJSON(discovery.JWKSURI, &keySet); err != nil {
+		return nil, err
+	}
+
+	op.keys = map[string]crypto.PublicKey{}
+	op.keysFetchedAt = time.Now()
+
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		// Keys of unsupported types are skipped - tokens signed with them are rejected.
+		if key, err := parseJWK(jwk); err == nil {
+			op.keys[jwk.KeyID] = key
+		}
+	}
+
+	if key := op.findKey(keyID); key != nil {
+		return key, nil
+	}
+
+	return nil, ErrOIDCTokenInvalid
+}
+
+// findKey - returns cached key with given ID. Tokens without key ID can be verified
+// only when the provider has a single key.
+func (op *OIDCProvider) findKey(keyID string) crypto.PublicKey {
+	if keyID == "" && len(op.keys) == 1 {
+		for _, key := range op.keys {
+			return key
+		}
+	}
+
+	return op.keys[keyID]
+}
+
+// getJSON - fetches given URL and decodes its JSON body into target.
+func (op *OIDCProvider) getJSON(target string, value interface{}) error {
+	response, err := op.client.Get(target)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Fetching %s failed with status %d", target, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(value)
+}
+
+// hasAudience - returns true if given "aud" claim contains given audience.
+func hasAudience(claim interface{}, audience string) bool {
+	for _, value := range getStringsClaim(claim) {
+		if value == audience {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getStringsClaim - returns the values of a claim, which can be either a string
+// or an array of strings.
+func getStringsClaim(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := []string{}
+
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	// OIDCStateTTL - time in which started OIDC login has to be finished.
+	OIDCStateTTL = 10 * time.Minute
+
+	// PKCEChallengeMethod - the only PKCE code challenge method used by Gochat (RFC 7636).
+	PKCEChallengeMethod = "S256"
+
+	// Prefix of the cache keys holding started OIDC logins.
+	oidcStateKeyPrefix = "oidc_state:"
+
+	// Number of random bytes OIDC state, nonce and PKCE code verifier are made of.
+	oidcRandomSize = 32
+)
+
+// ErrOIDCStateInvalid - returned when OIDC login state does not exist, has expired
+// or has already been used.
+var ErrOIDCStateInvalid = errors.New("OpenID Connect login state is invalid or expired.")
+
+// OIDCLoginState - secrets of started OIDC login, needed to finish it.
+type OIDCLoginState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
+
+// NewOIDCLoginState - returns OIDCLoginState with new, random nonce and PKCE code verifier.
+func NewOIDCLoginState() (*OIDCLoginState, error) {
+	nonce, err := newOIDCRandom()
+
+	if err != nil {
+		return nil, err
+	}
+
+	codeVerifier, err := newOIDCRandom()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCLoginState{
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+// PKCEChallenge - returns S256 code challenge for given PKCE code verifier.
+func PKCEChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// CreateOIDCState - saves given login state, and returns the "state" value it can be
+// retrieved with, once identity provider redirects the user back.
+func (am *AuthManager) CreateOIDCState(loginState *OIDCLoginState) (string, error) {
+	state, err := newOIDCRandom()
+
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(loginState)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Like one-time tokens, state is stored hashed.
+	err = am.cache.Set(am.ctx, oneTimeTokenKey(oidcStateKeyPrefix, state), string(data), OIDCStateTTL).Err()
+
+	if err != nil {
+		return "", err
+	}
+
+	return state, nil
+}
+
+// ConsumeOIDCState - invalidates given state, and returns the login state saved with it.
+func (am *AuthManager) ConsumeOIDCState(state string) (*OIDCLoginState, error) {
+	key := oneTimeTokenKey(oidcStateKeyPrefix, state)
+
+	res := am.cache.Get(am.ctx, key)
+
+	if res.Err() != nil {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	if err := am.cache.Del(am.ctx, key).Err(); err != nil {
+		return nil, err
+	}
+
+	var loginState OIDCLoginState
+
+	if err := json.Unmarshal([]byte(res.Val()), &loginState); err != nil {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	return &loginState, nil
+}
+
+// newOIDCRandom - returns random, URL safe value.
+func newOIDCRandom() (string, error) {
+	value := make([]byte, oidcRandomSize)
+
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
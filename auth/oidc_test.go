@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testOIDCClientID     = "gochat"
+	testOIDCClientSecret = "gochat-secret"
+	testOIDCRedirectURL  = "http://localhost:3000/oidc/callback"
+)
+
+type oidcSuite struct {
+	suite.Suite
+	idp      *mocks.OIDCProviderMock
+	provider *OIDCProvider
+}
+
+func (s *oidcSuite) SetupTest() {
+	s.idp = mocks.NewOIDCProviderMock(testOIDCClientID, testOIDCClientSecret)
+	s.idp.Claims = map[string]interface{}{
+		"sub":            "idp-user-1",
+		"email":          "john@example.com",
+		"email_verified": true,
+		"given_name":     "John",
+		"family_name":    "Doe",
+		"groups":         []string{"staff", "gochat-admins"},
+	}
+
+	s.provider = NewOIDCProvider(&OIDCConfig{
+		IssuerURL:    s.idp.Issuer(),
+		ClientID:     testOIDCClientID,
+		ClientSecret: testOIDCClientSecret,
+		RedirectURL:  testOIDCRedirectURL,
+		Scopes:       []string{"openid", "email"},
+		GroupsClaim:  DefaultOIDCGroupsClaim,
+	})
+}
+
+func (s *oidcSuite) TearDownTest() {
+	s.idp.Close()
+}
+
+func TestOIDCSuite(t *testing.T) {
+	suite.Run(t, new(oidcSuite))
+}
+
+// authorize - goes through the authorization step with given login state, returning the code.
+func (s *oidcSuite) authorize(loginState *OIDCLoginState) string {
+	authorizationURL, err := s.provider.AuthorizationURL("test-state", loginState.Nonce, PKCEChallenge(loginState.CodeVerifier))
+
+	assert.Nil(s.T(), err)
+
+	code, state, err := s.idp.Authorize(authorizationURL)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "test-state", state)
+
+	return code
+}
+
+func (s *oidcSuite) getTestClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   s.idp.Issuer(),
+		"aud":   testOIDCClientID,
+		"sub":   "idp-user-1",
+		"nonce": nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func (s *oidcSuite) TestPKCEChallenge() {
+	// Example from RFC 7636, Appendix B.
+	challenge := PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+
+	assert.Equal(s.T(), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", challenge)
+}
+
+func (s *oidcSuite) TestAuthorizationURL() {
+	authorizationURL, err := s.provider.AuthorizationURL("test-state", "test-nonce", "test-challenge")
+
+	assert.Nil(s.T(), err)
+
+	parsedURL, _ := url.Parse(authorizationURL)
+	query := parsedURL.Query()
+
+	assert.Equal(s.T(), s.idp.Issuer()+"/authorize", parsedURL.Scheme+"://"+parsedURL.Host+parsedURL.Path)
+	assert.Equal(s.T(), "code", query.Get("response_type"))
+	assert.Equal(s.T(), testOIDCClientID, query.Get("client_id"))
+	assert.Equal(s.T(), testOIDCRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(s.T(), "openid email", query.Get("scope"))
+	assert.Equal(s.T(), "test-state", query.Get("state"))
+	assert.Equal(s.T(), "test-nonce", query.Get("nonce"))
+	assert.Equal(s.T(), "test-challenge", query.Get("code_challenge"))
+	assert.Equal(s.T(), PKCEChallengeMethod, query.Get("code_challenge_method"))
+}
+
+func (s *oidcSuite) TestLogin() {
+	loginState, _ := NewOIDCLoginState()
+
+	code := s.authorize(loginState)
+
+	idToken, err := s.provider.Exchange(code, loginState.CodeVerifier)
+
+	assert.Nil(s.T(), err)
+
+	claims, err := s.provider.VerifyIDToken(idToken, loginState.Nonce)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &OIDCClaims{
+		Issuer:        s.idp.Issuer(),
+		Subject:       "idp-user-1",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		GivenName:     "John",
+		FamilyName:    "Doe",
+		Groups:        []string{"staff", "gochat-admins"},
+	}, claims)
+}
+
+func (s *oidcSuite) TestExchange_WrongCodeVerifier() {
+	loginState, _ := NewOIDCLoginState()
+
+	code := s.authorize(loginState)
+
+	_, err := s.provider.Exchange(code, "wrong-code-verifier")
+
+	assert.Equal(s.T(), ErrOIDCCodeInvalid, err)
+}
+
+func (s *oidcSuite) TestExchange_CodeReused() {
+	loginState, _ := NewOIDCLoginState()
+
+	code := s.authorize(loginState)
+
+	_, err := s.provider.Exchange(code, loginState.CodeVerifier)
+
+	assert.Nil(s.T(), err)
+
+	_, err = s.provider.Exchange(code, loginState.CodeVerifier)
+
+	assert.Equal(s.T(), ErrOIDCCodeInvalid, err)
+}
+
+func (s *oidcSuite) TestExchange_WrongClientSecret() {
+	loginState, _ := NewOIDCLoginState()
+
+	code := s.authorize(loginState)
+
+	s.provider.config.ClientSecret = "wrong-secret"
+
+	_, err := s.provider.Exchange(code, loginState.CodeVerifier)
+
+	assert.NotNil(s.T(), err)
+	assert.NotEqual(s.T(), ErrOIDCCodeInvalid, err)
+}
+
+func (s *oidcSuite) TestVerifyIDToken_WrongNonce() {
+	loginState, _ := NewOIDCLoginState()
+
+	code := s.authorize(loginState)
+
+	idToken, _ := s.provider.Exchange(code, loginState.CodeVerifier)
+
+	_, err := s.provider.VerifyIDToken(idToken, "other-nonce")
+
+	assert.Equal(s.T(), ErrOIDCTokenInvalid, err)
+}
+
+func (s *oidcSuite) TestVerifyIDToken_InvalidClaims() {
+	testCases := map[string]func(claims jwt.MapClaims){
+		"wrong audience": func(claims jwt.MapClaims) {
+			claims["aud"] = "other-client"
+		},
+		"wrong issuer": func(claims jwt.MapClaims) {
+			claims["iss"] = "https://other-issuer.example.com"
+		},
+		"wrong authorized party": func(claims jwt.MapClaims) {
+			claims["aud"] = []string{testOIDCClientID, "other-client"}
+			claims["azp"] = "other-client"
+		},
+		"expired": func(claims jwt.MapClaims) {
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		},
+		"missing expiration": func(claims jwt.MapClaims) {
+			delete(claims, "exp")
+		},
+		"missing subject": func(claims jwt.MapClaims) {
+			delete(claims, "sub")
+		},
+	}
+
+	for name, modify := range testCases {
+		claims := s.getTestClaims("test-nonce")
+		modify(claims)
+
+		idToken, _ := s.idp.SignIDToken(claims)
+
+		_, err := s.provider.VerifyIDToken(idToken, "test-nonce")
+
+		assert.Equal(s.T(), ErrOIDCTokenInvalid, err, name)
+	}
+}
+
+func (s *oidcSuite) TestVerifyIDToken_MultipleAudiences() {
+	claims := s.getTestClaims("test-nonce")
+	claims["aud"] = []string{"other-client", testOIDCClientID}
+
+	idToken, _ := s.idp.SignIDToken(claims)
+
+	oidcClaims, err := s.provider.VerifyIDToken(idToken, "test-nonce")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "idp-user-1", oidcClaims.Subject)
+}
+
+func (s *oidcSuite) TestVerifyIDToken_UnknownKey() {
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, s.getTestClaims("test-nonce"))
+	token.Header["kid"] = "other-key"
+
+	idToken, _ := token.SignedString(otherKey)
+
+	_, err := s.provider.VerifyIDToken(idToken, "test-nonce")
+
+	assert.Equal(s.T(), ErrOIDCTokenInvalid, err)
+
+	// Token pretending to be signed with provider's key.
+	token.Header["kid"] = s.idp.KeyID
+
+	idToken, _ = token.SignedString(otherKey)
+
+	_, err = s.provider.VerifyIDToken(idToken, "test-nonce")
+
+	assert.Equal(s.T(), ErrOIDCTokenInvalid, err)
+}
+
+func (s *oidcSuite) TestVerifyIDToken_SymmetricSigningMethod() {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, s.getTestClaims("test-nonce"))
+	token.Header["kid"] = s.idp.KeyID
+
+	idToken, _ := token.SignedString([]byte(testOIDCClientSecret))
+
+	_, err := s.provider.VerifyIDToken(idToken, "test-nonce")
+
+	assert.Equal(s.T(), ErrOIDCTokenInvalid, err)
+}
+
+func (s *oidcSuite) TestDiscovery_IssuerMismatch() {
+	provider := NewOIDCProvider(&OIDCConfig{
+		IssuerURL: s.idp.Issuer() + "/other",
+		ClientID:  testOIDCClientID,
+	})
+
+	_, err := provider.AuthorizationURL("test-state", "test-nonce", "test-challenge")
+
+	assert.NotNil(s.T(), err)
+}
+
+func (s *oidcSuite) TestCreateOIDCState() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	authManager := &AuthManager{
+		cache: cacheMock,
+		ctx:   context.Background(),
+	}
+
+	state, err := authManager.CreateOIDCState(&OIDCLoginState{Nonce: "test-nonce", CodeVerifier: "test-verifier"})
+
+	assert.Nil(s.T(), err)
+	assert.NotEmpty(s.T(), state)
+
+	cacheMock.AssertCalled(
+		s.T(),
+		"Set",
+		mock.Anything,
+		oneTimeTokenKey(oidcStateKeyPrefix, state),
+		`{"nonce":"test-nonce","codeVerifier":"test-verifier"}`,
+		OIDCStateTTL,
+	)
+}
+
+func (s *oidcSuite) TestConsumeOIDCState() {
+	key := oneTimeTokenKey(oidcStateKeyPrefix, "test-state")
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On("Get", mock.Anything, key).Return(
+		mocks.GetValueCacheResponse(`{"nonce":"test-nonce","codeVerifier":"test-verifier"}`),
+	)
+	cacheMock.On("Del", mock.Anything, mock.Anything).Return(mocks.GetDefaultCacheResponse())
+
+	authManager := &AuthManager{
+		cache: cacheMock,
+		ctx:   context.Background(),
+	}
+
+	loginState, err := authManager.ConsumeOIDCState("test-state")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &OIDCLoginState{Nonce: "test-nonce", CodeVerifier: "test-verifier"}, loginState)
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{key})
+}
+
+func (s *oidcSuite) TestConsumeOIDCState_Invalid() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On("Get", mock.Anything, mock.Anything).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	authManager := &AuthManager{
+		cache: cacheMock,
+		ctx:   context.Background(),
+	}
+
+	_, err := authManager.ConsumeOIDCState("test-state")
+
+	assert.Equal(s.T(), ErrOIDCStateInvalid, err)
+
+	cacheMock.AssertNotCalled(s.T(), "Del", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Number of random bytes one-time tokens are made of.
+const oneTimeTokenSize = 32
+
+// errOneTimeTokenInvalid - returned when one-time token does not exist, has expired
+// or has already been used.
+var errOneTimeTokenInvalid = errors.New("One-time token is invalid or expired.")
+
+// createOneTimeToken - returns new, single-use token bound to given user, stored
+// under given key prefix.
+func (am *AuthManager) createOneTimeToken(keyPrefix string, userID uuid.UUID, ttl time.Duration) (string, error) {
+	secret := make([]byte, oneTimeTokenSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	// Only the hash is stored, so tokens cannot be read from the cache.
+	err := am.cache.Set(am.ctx, oneTimeTokenKey(keyPrefix, token), userID.String(), ttl).Err()
+
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeOneTimeToken - invalidates given one-time token, and returns the ID
+// of the user it has been created for. Only the request which actually removes the token
+// consumes it, so the token cannot be used by concurrent requests.
+func (am *AuthManager) consumeOneTimeToken(keyPrefix string, token string) (uuid.UUID, error) {
+	userID, err := am.getOneTimeTokenUserID(keyPrefix, token)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res := am.cache.Del(am.ctx, oneTimeTokenKey(keyPrefix, token))
+
+	if res.Err() != nil {
+		return uuid.Nil, res.Err()
+	}
+
+	if res.Val() != "1" {
+		return uuid.Nil, errOneTimeTokenInvalid
+	}
+
+	return userID, nil
+}
+
+// getOneTimeTokenUserID - returns the ID of the user given one-time token has been created for,
+// without invalidating the token.
+func (am *AuthManager) getOneTimeTokenUserID(keyPrefix string, token string) (uuid.UUID, error) {
+	res := am.cache.Get(am.ctx, oneTimeTokenKey(keyPrefix, token))
+
+	if res.Err() != nil {
+		return uuid.Nil, errOneTimeTokenInvalid
+	}
+
+	userID, err := uuid.Parse(res.Val())
+
+	if err != nil {
+		return uuid.Nil, errOneTimeTokenInvalid
+	}
+
+	return userID, nil
+}
+
+func oneTimeTokenKey(keyPrefix string, token string) string {
+	return keyPrefix + hashToken(token)
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Map of valid PASSWORD_HASH_ALGORITHM env values.
+const (
+	Argon2idAlgorithm = "argon2id"
+	BcryptAlgorithm   = "bcrypt"
+)
+
+var (
+	// ErrPasswordMismatch - returned when password does not match its hash.
+	ErrPasswordMismatch = errors.New("Password does not match.")
+
+	// ErrUnknownPasswordHash - returned when hash has been created with unknown algorithm,
+	// or is malformed.
+	ErrUnknownPasswordHash = errors.New("Password hash has unknown format.")
+)
+
+// PasswordHasher - hashes passwords with a single algorithm. Hashes are self-describing
+// strings in PHC string format ($<id>$<params>$<salt>$<hash>), therefore hashes created
+// with different algorithms and parameters can be stored side by side.
+type PasswordHasher interface {
+	// Hash - returns salted hash of given password, using hasher's current parameters.
+	Hash(password []byte) (string, error)
+
+	// Verify - checks if password matches given hash. Parameters are read from the hash.
+	Verify(hash string, password []byte) error
+
+	// Handles - returns true if given hash has been created with hasher's algorithm.
+	Handles(hash string) bool
+
+	// NeedsRehash - returns true if given hash has been created with parameters
+	// different from hasher's current ones.
+	NeedsRehash(hash string) bool
+}
+
+// DefaultPasswordHasher - PasswordHasher new passwords are hashed with.
+var DefaultPasswordHasher PasswordHasher = NewArgon2idHasher(
+	DefaultArgon2Memory,
+	DefaultArgon2Iterations,
+	DefaultArgon2Parallelism,
+)
+
+// InitPasswordHasher - initializes DefaultPasswordHasher, based on PASSWORD_HASH_ALGORITHM env.
+// Argon2id is used by default, with parameters set by ARGON2_MEMORY (in KiB), ARGON2_ITERATIONS
+// and ARGON2_PARALLELISM envs. For "bcrypt", cost is set by BCRYPT_COST env.
+func InitPasswordHasher() (PasswordHasher, error) {
+	switch algorithm := os.Getenv("PASSWORD_HASH_ALGORITHM"); algorithm {
+	case BcryptAlgorithm:
+		cost, err := getIntEnv("BCRYPT_COST", DefaultBcryptCost)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("BCRYPT_COST has to be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+
+		DefaultPasswordHasher = NewBcryptHasher(cost)
+	case Argon2idAlgorithm, "":
+		memory, err := getIntEnv("ARGON2_MEMORY", DefaultArgon2Memory)
+
+		if err != nil {
+			return nil, err
+		}
+
+		iterations, err := getIntEnv("ARGON2_ITERATIONS", DefaultArgon2Iterations)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parallelism, err := getIntEnv("ARGON2_PARALLELISM", DefaultArgon2Parallelism)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if memory < 8*parallelism || iterations < 1 || parallelism < 1 || parallelism > 255 {
+			return nil, errors.New("Argon2 parameters are invalid")
+		}
+
+		DefaultPasswordHasher = NewArgon2idHasher(uint32(memory), uint32(iterations), uint8(parallelism))
+	default:
+		return nil, fmt.Errorf("Unknown password hash algorithm: %s", algorithm)
+	}
+
+	return DefaultPasswordHasher, nil
+}
+
+// knownPasswordHashers - hashers able to verify hashes of all supported algorithms.
+var knownPasswordHashers = []PasswordHasher{
+	NewArgon2idHasher(DefaultArgon2Memory, DefaultArgon2Iterations, DefaultArgon2Parallelism),
+	NewBcryptHasher(DefaultBcryptCost),
+}
+
+// hashAlgorithmID - returns the identifier of the algorithm given hash has been created with.
+func hashAlgorithmID(hash string) string {
+	parts := strings.SplitN(hash, "$", 3)
+
+	if len(parts) < 3 || parts[0] != "" {
+		return ""
+	}
+
+	return parts[1]
+}
+
+func getIntEnv(name string, defaultValue int) (int, error) {
+	value := os.Getenv(name)
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s has to be an integer", name)
+	}
+
+	return parsed, nil
+}
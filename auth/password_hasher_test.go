@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type passwordHasherSuite struct {
+	suite.Suite
+	testPassword []byte
+}
+
+func (s *passwordHasherSuite) SetupSuite() {
+	s.testPassword = []byte("test_password")
+}
+
+func TestPasswordHasherSuite(t *testing.T) {
+	suite.Run(t, new(passwordHasherSuite))
+}
+
+func (s *passwordHasherSuite) TestArgon2idHasher() {
+	hasher := NewArgon2idHasher(64, 1, 1)
+
+	hash, err := hasher.Hash(s.testPassword)
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.Len(s.T(), strings.Split(hash, "$"), 6)
+
+	assert.True(s.T(), hasher.Handles(hash))
+	assert.Nil(s.T(), hasher.Verify(hash, s.testPassword))
+	assert.Equal(s.T(), ErrPasswordMismatch, hasher.Verify(hash, []byte("other_password")))
+
+	otherHash, _ := hasher.Hash(s.testPassword)
+	assert.NotEqual(s.T(), hash, otherHash)
+}
+
+func (s *passwordHasherSuite) TestArgon2idHasher_KnownHash() {
+	// Hash created with the reference implementation (argon2 CLI), password "password",
+	// salt "somesalt".
+	hash := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+	assert.Nil(s.T(), NewArgon2idHasher(64, 1, 1).Verify(hash, []byte("password")))
+}
+
+func (s *passwordHasherSuite) TestArgon2idHasher_NeedsRehash() {
+	hash, _ := NewArgon2idHasher(64, 1, 1).Hash(s.testPassword)
+
+	assert.False(s.T(), NewArgon2idHasher(64, 1, 1).NeedsRehash(hash))
+	assert.True(s.T(), NewArgon2idHasher(128, 1, 1).NeedsRehash(hash))
+	assert.True(s.T(), NewArgon2idHasher(64, 2, 1).NeedsRehash(hash))
+	assert.True(s.T(), NewArgon2idHasher(64, 1, 2).NeedsRehash(hash))
+}
+
+func (s *passwordHasherSuite) TestArgon2idHasher_Malformed() {
+	hasher := NewArgon2idHasher(64, 1, 1)
+
+	for _, hash := range []string{
+		"",
+		"$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ",
+		"$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=64,t=0,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+	} {
+		assert.Equal(s.T(), ErrUnknownPasswordHash, hasher.Verify(hash, s.testPassword), hash)
+		assert.True(s.T(), hasher.NeedsRehash(hash), hash)
+	}
+}
+
+func (s *passwordHasherSuite) TestBcryptHasher() {
+	hasher := NewBcryptHasher(bcrypt.MinCost)
+
+	hash, err := hasher.Hash(s.testPassword)
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), hasher.Handles(hash))
+	assert.Nil(s.T(), hasher.Verify(hash, s.testPassword))
+	assert.Equal(s.T(), ErrPasswordMismatch, hasher.Verify(hash, []byte("other_password")))
+
+	assert.False(s.T(), hasher.NeedsRehash(hash))
+	assert.True(s.T(), NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(hash))
+
+	assert.False(s.T(), hasher.Handles("$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"))
+}
+
+func (s *passwordHasherSuite) TestInitPasswordHasher() {
+	defaultHasher := DefaultPasswordHasher
+
+	defer func() {
+		DefaultPasswordHasher = defaultHasher
+
+		os.Unsetenv("PASSWORD_HASH_ALGORITHM")
+		os.Unsetenv("BCRYPT_COST")
+		os.Unsetenv("ARGON2_MEMORY")
+	}()
+
+	os.Setenv("PASSWORD_HASH_ALGORITHM", BcryptAlgorithm)
+	os.Setenv("BCRYPT_COST", "11")
+
+	hasher, err := InitPasswordHasher()
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), NewBcryptHasher(11), hasher)
+	assert.Equal(s.T(), hasher, DefaultPasswordHasher)
+
+	os.Setenv("BCRYPT_COST", "1")
+
+	_, err = InitPasswordHasher()
+	assert.NotNil(s.T(), err)
+
+	os.Setenv("PASSWORD_HASH_ALGORITHM", Argon2idAlgorithm)
+	os.Setenv("ARGON2_MEMORY", "32768")
+
+	hasher, err = InitPasswordHasher()
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), NewArgon2idHasher(32768, DefaultArgon2Iterations, DefaultArgon2Parallelism), hasher)
+
+	os.Setenv("PASSWORD_HASH_ALGORITHM", "md5")
+
+	_, err = InitPasswordHasher()
+	assert.NotNil(s.T(), err)
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/el-Mike/gochat/models"
+)
+
+const (
+	// DefaultPasswordMinLength - minimal number of characters in a password, if it's not configured.
+	DefaultPasswordMinLength = 10
+
+	// DefaultPasswordMaxLength - maximal number of characters in a password. It only prevents
+	// abusing expensive hashing with huge inputs, so passphrases of any reasonable length are allowed.
+	DefaultPasswordMaxLength = 128
+
+	// DefaultPasswordMinCharacterClasses - number of character classes (lowercase letters,
+	// uppercase letters, digits and other characters) a password has to mix, if it's not configured.
+	DefaultPasswordMinCharacterClasses = 3
+
+	// DefaultPassphraseLength - length from which passwords are considered passphrases,
+	// and do not have to mix character classes.
+	DefaultPassphraseLength = 20
+
+	// DefaultPasswordBlocklistFile - file with common passwords used, if PASSWORD_BLOCKLIST_FILE is not set.
+	DefaultPasswordBlocklistFile = "resources/common-passwords.txt"
+)
+
+// Map of valid PasswordViolation codes.
+const (
+	PasswordTooShortViolation         = "password/too-short"
+	PasswordTooLongViolation          = "password/too-long"
+	PasswordCharacterClassesViolation = "password/character-classes"
+	PasswordCommonViolation           = "password/common"
+	PasswordPersonalInfoViolation     = "password/personal-info"
+)
+
+// PasswordViolation - describes a single requirement given password does not meet.
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError - returned when password violates PasswordPolicy.
+type PasswordPolicyError struct {
+	Violations []*PasswordViolation
+}
+
+// Error - error interface implementation.
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+
+	return strings.Join(messages, " ")
+}
+
+// PasswordRule - single requirement passwords have to meet.
+type PasswordRule interface {
+	// Check - returns PasswordViolation if given password of given user does not meet
+	// the requirement, nil otherwise.
+	Check(password string, user *models.UserModel) *PasswordViolation
+}
+
+// PasswordPolicy - set of PasswordRules new passwords are validated against.
+type PasswordPolicy struct {
+	rules []PasswordRule
+}
+
+// NewPasswordPolicy - PasswordPolicy constructor func.
+func NewPasswordPolicy(rules ...PasswordRule) *PasswordPolicy {
+	return &PasswordPolicy{
+		rules: rules,
+	}
+}
+
+// Validate - checks given password of given user against all the rules of the policy.
+// Returns PasswordPolicyError with all the violations found, or nil if there are none.
+func (pp *PasswordPolicy) Validate(password string, user *models.UserModel) error {
+	violations := []*PasswordViolation{}
+
+	for _, rule := range pp.rules {
+		if violation := rule.Check(password, user); violation != nil {
+			violations = append(violations, violation)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// DefaultPasswordPolicy - PasswordPolicy new passwords are validated against.
+var DefaultPasswordPolicy = NewPasswordPolicy(
+	NewPasswordLengthRule(DefaultPasswordMinLength, DefaultPasswordMaxLength),
+	NewCharacterClassesRule(DefaultPasswordMinCharacterClasses, DefaultPassphraseLength),
+	NewPersonalInfoRule(),
+)
+
+// InitPasswordPolicy - initializes DefaultPasswordPolicy. Minimal length and number of character
+// classes are set by PASSWORD_MIN_LENGTH and PASSWORD_MIN_CHARACTER_CLASSES envs. Common passwords
+// are loaded from the file set by PASSWORD_BLOCKLIST_FILE env, one password per line. If passwords
+// are hashed with bcrypt, they cannot exceed BcryptMaxPasswordBytes, therefore DefaultPasswordHasher
+// has to be initialized first.
+func InitPasswordPolicy() (*PasswordPolicy, error) {
+	minLength, err := getIntEnv("PASSWORD_MIN_LENGTH", DefaultPasswordMinLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if minLength < 1 || minLength > DefaultPasswordMaxLength {
+		return nil, fmt.Errorf("PASSWORD_MIN_LENGTH has to be between 1 and %d", DefaultPasswordMaxLength)
+	}
+
+	minClasses, err := getIntEnv("PASSWORD_MIN_CHARACTER_CLASSES", DefaultPasswordMinCharacterClasses)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if minClasses < 0 || minClasses > characterClassesCount {
+		return nil, fmt.Errorf("PASSWORD_MIN_CHARACTER_CLASSES has to be between 0 and %d", characterClassesCount)
+	}
+
+	blocklistFile := os.Getenv("PASSWORD_BLOCKLIST_FILE")
+
+	if blocklistFile == "" {
+		blocklistFile = DefaultPasswordBlocklistFile
+	}
+
+	blocklistRule, err := LoadBlocklistRule(blocklistFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []PasswordRule{
+		NewPasswordLengthRule(minLength, DefaultPasswordMaxLength),
+		NewCharacterClassesRule(minClasses, DefaultPassphraseLength),
+		blocklistRule,
+		NewPersonalInfoRule(),
+	}
+
+	if _, ok := DefaultPasswordHasher.(*BcryptHasher); ok {
+		rules = append(rules, NewPasswordBytesRule(BcryptMaxPasswordBytes))
+	}
+
+	DefaultPasswordPolicy = NewPasswordPolicy(rules...)
+
+	return DefaultPasswordPolicy, nil
+}
@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type passwordPolicySuite struct {
+	suite.Suite
+	testUser *models.UserModel
+}
+
+func (s *passwordPolicySuite) SetupSuite() {
+	s.testUser = &models.UserModel{
+		Email:     "john.doe@gochat.com",
+		FirstName: "Jonathan",
+		LastName:  "Smith-Jones",
+	}
+}
+
+func TestPasswordPolicySuite(t *testing.T) {
+	suite.Run(t, new(passwordPolicySuite))
+}
+
+func (s *passwordPolicySuite) TestPasswordLengthRule() {
+	rule := NewPasswordLengthRule(10, 128)
+
+	assert.Nil(s.T(), rule.Check("Ab1!efghij", s.testUser))
+	assert.Equal(s.T(), PasswordTooShortViolation, rule.Check("Ab1!efghi", s.testUser).Code)
+	assert.Equal(s.T(), PasswordTooLongViolation, rule.Check(strings.Repeat("a", 129), s.testUser).Code)
+
+	// Length is counted in characters, not bytes.
+	assert.Equal(s.T(), PasswordTooShortViolation, rule.Check("zażółćgęś", s.testUser).Code)
+	assert.Nil(s.T(), rule.Check("zażółćgęśl", s.testUser))
+}
+
+func (s *passwordPolicySuite) TestPasswordBytesRule() {
+	rule := NewPasswordBytesRule(72)
+
+	assert.Nil(s.T(), rule.Check(strings.Repeat("a", 72), s.testUser))
+	assert.Equal(s.T(), PasswordTooLongViolation, rule.Check(strings.Repeat("a", 73), s.testUser).Code)
+
+	// Multi-byte characters count with all their bytes.
+	assert.Equal(s.T(), PasswordTooLongViolation, rule.Check(strings.Repeat("ż", 37), s.testUser).Code)
+}
+
+func (s *passwordPolicySuite) TestCharacterClassesRule() {
+	rule := NewCharacterClassesRule(3, 20)
+
+	assert.Nil(s.T(), rule.Check("Tr0ub4dor", s.testUser))
+	assert.Nil(s.T(), rule.Check("tr0ub4dor&3", s.testUser))
+	assert.Equal(s.T(), PasswordCharacterClassesViolation, rule.Check("troubadour", s.testUser).Code)
+	assert.Equal(s.T(), PasswordCharacterClassesViolation, rule.Check("troubadour3", s.testUser).Code)
+
+	// Passphrases do not have to mix character classes.
+	assert.Nil(s.T(), rule.Check("correct horse battery staple", s.testUser))
+}
+
+func (s *passwordPolicySuite) TestBlocklistRule() {
+	rule := NewBlocklistRule([]string{"password123", " Qwertyuiop ", ""})
+
+	assert.Equal(s.T(), PasswordCommonViolation, rule.Check("password123", s.testUser).Code)
+	assert.Equal(s.T(), PasswordCommonViolation, rule.Check("PASSWORD123", s.testUser).Code)
+	assert.Equal(s.T(), PasswordCommonViolation, rule.Check("qwertyuiop", s.testUser).Code)
+	assert.Nil(s.T(), rule.Check("password1234", s.testUser))
+	assert.Nil(s.T(), rule.Check("", s.testUser))
+}
+
+func (s *passwordPolicySuite) TestLoadBlocklistRule() {
+	dir, err := ioutil.TempDir("", "gochat-blocklist")
+	assert.Nil(s.T(), err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blocklist.txt")
+	err = ioutil.WriteFile(path, []byte("# comment\nletmein123\n\nWelcome2024\n"), 0600)
+	assert.Nil(s.T(), err)
+
+	rule, err := LoadBlocklistRule(path)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rule.passwords, 2)
+	assert.NotNil(s.T(), rule.Check("LetMeIn123", s.testUser))
+	assert.NotNil(s.T(), rule.Check("welcome2024", s.testUser))
+	assert.Nil(s.T(), rule.Check("# comment", s.testUser))
+
+	_, err = LoadBlocklistRule(filepath.Join(dir, "missing.txt"))
+
+	assert.NotNil(s.T(), err)
+}
+
+func (s *passwordPolicySuite) TestLoadBlocklistRule_DefaultFile() {
+	rule, err := LoadBlocklistRule(filepath.Join("..", DefaultPasswordBlocklistFile))
+
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), rule.Check("Password123", s.testUser))
+}
+
+func (s *passwordPolicySuite) TestPersonalInfoRule() {
+	rule := NewPersonalInfoRule()
+
+	assert.Equal(s.T(), PasswordPersonalInfoViolation, rule.Check("xJohn.Doe@Gochat.com1", s.testUser).Code)
+	assert.Equal(s.T(), PasswordPersonalInfoViolation, rule.Check("my-doe-Pass1", s.testUser).Code)
+	assert.Equal(s.T(), PasswordPersonalInfoViolation, rule.Check("JONATHAN#2024", s.testUser).Code)
+	assert.Equal(s.T(), PasswordPersonalInfoViolation, rule.Check("jones!Secret9", s.testUser).Code)
+	assert.Nil(s.T(), rule.Check("Unrelated#Secret9", s.testUser))
+
+	// Parts shorter than 3 characters are ignored.
+	assert.Nil(s.T(), NewPersonalInfoRule().Check("Bob-Al-Secret9", &models.UserModel{
+		Email:     "al@gochat.com",
+		FirstName: "Al",
+	}))
+
+	assert.Nil(s.T(), rule.Check("JONATHAN#2024", nil))
+}
+
+func (s *passwordPolicySuite) TestValidate() {
+	policy := NewPasswordPolicy(
+		NewPasswordLengthRule(10, 128),
+		NewCharacterClassesRule(3, 20),
+		NewBlocklistRule([]string{"password"}),
+		NewPersonalInfoRule(),
+	)
+
+	assert.Nil(s.T(), policy.Validate("Tr0ub4dor&3", s.testUser))
+
+	err := policy.Validate("doe", s.testUser)
+
+	policyErr, ok := err.(*PasswordPolicyError)
+
+	assert.True(s.T(), ok)
+	assert.Len(s.T(), policyErr.Violations, 3)
+	assert.Equal(s.T(), PasswordTooShortViolation, policyErr.Violations[0].Code)
+	assert.Equal(s.T(), PasswordCharacterClassesViolation, policyErr.Violations[1].Code)
+	assert.Equal(s.T(), PasswordPersonalInfoViolation, policyErr.Violations[2].Code)
+	assert.Contains(s.T(), err.Error(), "at least 10 characters")
+}
+
+func (s *passwordPolicySuite) TestInitPasswordPolicy() {
+	dir, err := ioutil.TempDir("", "gochat-blocklist")
+	assert.Nil(s.T(), err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blocklist.txt")
+	err = ioutil.WriteFile(path, []byte("Tr0ub4dor&3\n"), 0600)
+	assert.Nil(s.T(), err)
+
+	defaultPolicy := DefaultPasswordPolicy
+
+	defer func() {
+		DefaultPasswordPolicy = defaultPolicy
+		os.Unsetenv("PASSWORD_MIN_LENGTH")
+		os.Unsetenv("PASSWORD_BLOCKLIST_FILE")
+	}()
+
+	os.Setenv("PASSWORD_MIN_LENGTH", "12")
+	os.Setenv("PASSWORD_BLOCKLIST_FILE", path)
+
+	policy, err := InitPasswordPolicy()
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DefaultPasswordPolicy, policy)
+	assert.NotNil(s.T(), policy.Validate("Tr0ub4dor&3", s.testUser))
+	assert.NotNil(s.T(), policy.Validate("Tr0ub4dor&4", s.testUser))
+	assert.Nil(s.T(), policy.Validate("Tr0ub4dor&44", s.testUser))
+
+	os.Setenv("PASSWORD_MIN_LENGTH", "0")
+
+	_, err = InitPasswordPolicy()
+
+	assert.NotNil(s.T(), err)
+}
+
+func (s *passwordPolicySuite) TestInitPasswordPolicy_Bcrypt() {
+	dir, err := ioutil.TempDir("", "gochat-blocklist")
+	assert.Nil(s.T(), err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blocklist.txt")
+	err = ioutil.WriteFile(path, []byte("Tr0ub4dor&3\n"), 0600)
+	assert.Nil(s.T(), err)
+
+	defaultPolicy := DefaultPasswordPolicy
+	defaultHasher := DefaultPasswordHasher
+
+	defer func() {
+		DefaultPasswordPolicy = defaultPolicy
+		DefaultPasswordHasher = defaultHasher
+		os.Unsetenv("PASSWORD_BLOCKLIST_FILE")
+	}()
+
+	os.Setenv("PASSWORD_BLOCKLIST_FILE", path)
+
+	passphrase := strings.Repeat("correct horse battery staple ", 3)
+
+	policy, err := InitPasswordPolicy()
+
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), policy.Validate(passphrase, s.testUser))
+
+	// Bcrypt ignores everything past 72 bytes, so such passwords are rejected.
+	DefaultPasswordHasher = NewBcryptHasher(DefaultBcryptCost)
+
+	policy, err = InitPasswordPolicy()
+
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), policy.Validate(passphrase, s.testUser))
+	assert.Nil(s.T(), policy.Validate(passphrase[:BcryptMaxPasswordBytes], s.testUser))
+}
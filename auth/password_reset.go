@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// PasswordResetTokenTTL - lifetime of password reset tokens.
+	PasswordResetTokenTTL = time.Hour
+
+	// Prefix of the cache keys holding hashes of password reset tokens.
+	passwordResetTokenKeyPrefix = "password-reset:"
+)
+
+// ErrPasswordResetTokenInvalid - returned when password reset token does not exist,
+// has expired or has already been used.
+var ErrPasswordResetTokenInvalid = errors.New("Password reset token is invalid or expired.")
+
+// CreatePasswordResetToken - returns new, single-use token allowing to reset given user's password.
+func (am *AuthManager) CreatePasswordResetToken(userID uuid.UUID) (string, error) {
+	return am.createOneTimeToken(passwordResetTokenKeyPrefix, userID, PasswordResetTokenTTL)
+}
+
+// ConsumePasswordResetToken - invalidates given password reset token, and returns
+// the ID of the user it has been created for.
+func (am *AuthManager) ConsumePasswordResetToken(token string) (uuid.UUID, error) {
+	userID, err := am.consumeOneTimeToken(passwordResetTokenKeyPrefix, token)
+
+	if err == errOneTimeTokenInvalid {
+		return uuid.Nil, ErrPasswordResetTokenInvalid
+	}
+
+	return userID, err
+}
+
+// VerifyPasswordResetToken - returns the ID of the user given password reset token has been
+// created for, without invalidating the token.
+func (am *AuthManager) VerifyPasswordResetToken(token string) (uuid.UUID, error) {
+	userID, err := am.getOneTimeTokenUserID(passwordResetTokenKeyPrefix, token)
+
+	if err == errOneTimeTokenInvalid {
+		return uuid.Nil, ErrPasswordResetTokenInvalid
+	}
+
+	return userID, err
+}
+
+func passwordResetTokenKey(token string) string {
+	return oneTimeTokenKey(passwordResetTokenKeyPrefix, token)
+}
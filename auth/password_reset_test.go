@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type passwordResetSuite struct {
+	suite.Suite
+	authManager *AuthManager
+	testUserID  uuid.UUID
+}
+
+func (s *passwordResetSuite) SetupTest() {
+	s.authManager = &AuthManager{
+		cache: mocks.NewRedisCacheMock(),
+		ctx:   context.Background(),
+	}
+
+	s.testUserID = uuid.New()
+}
+
+func TestPasswordResetSuite(t *testing.T) {
+	suite.Run(t, new(passwordResetSuite))
+}
+
+func (s *passwordResetSuite) TestCreatePasswordResetToken() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	token, err := s.authManager.CreatePasswordResetToken(s.testUserID)
+
+	assert.Nil(s.T(), err)
+	assert.NotEmpty(s.T(), token)
+
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, passwordResetTokenKey(token), s.testUserID.String(), PasswordResetTokenTTL)
+}
+
+func (s *passwordResetSuite) TestConsumePasswordResetToken() {
+	token := "test_reset_token"
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		passwordResetTokenKey(token),
+	).Return(mocks.GetValueCacheResponse(s.testUserID.String()))
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("1"))
+
+	s.authManager.cache = cacheMock
+
+	userID, err := s.authManager.ConsumePasswordResetToken(token)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testUserID, userID)
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{passwordResetTokenKey(token)})
+}
+
+func (s *passwordResetSuite) TestConsumePasswordResetToken_AlreadyConsumed() {
+	token := "test_reset_token"
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		passwordResetTokenKey(token),
+	).Return(mocks.GetValueCacheResponse(s.testUserID.String()))
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("0"))
+
+	s.authManager.cache = cacheMock
+
+	userID, err := s.authManager.ConsumePasswordResetToken(token)
+
+	assert.Equal(s.T(), uuid.Nil, userID)
+	assert.Equal(s.T(), ErrPasswordResetTokenInvalid, err)
+}
+
+func (s *passwordResetSuite) TestConsumePasswordResetToken_Invalid() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	s.authManager.cache = cacheMock
+
+	userID, err := s.authManager.ConsumePasswordResetToken("test_reset_token")
+
+	assert.Equal(s.T(), uuid.Nil, userID)
+	assert.Equal(s.T(), ErrPasswordResetTokenInvalid, err)
+
+	cacheMock.AssertNotCalled(s.T(), "Del", mock.Anything, mock.Anything)
+}
+
+func (s *passwordResetSuite) TestVerifyPasswordResetToken() {
+	token := "test_reset_token"
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		passwordResetTokenKey(token),
+	).Return(mocks.GetValueCacheResponse(s.testUserID.String()))
+
+	s.authManager.cache = cacheMock
+
+	userID, err := s.authManager.VerifyPasswordResetToken(token)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testUserID, userID)
+
+	cacheMock.AssertNotCalled(s.T(), "Del", mock.Anything, mock.Anything)
+}
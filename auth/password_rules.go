@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/el-Mike/gochat/models"
+)
+
+// Number of character classes distinguished by CharacterClassesRule.
+const characterClassesCount = 4
+
+// Minimal length of the parts of user's email and name PersonalInfoRule looks for -
+// shorter ones are too likely to appear in passwords by accident.
+const minPersonalInfoLength = 3
+
+// PasswordLengthRule - requires passwords to have a length within given bounds.
+// Length is counted in characters, not bytes.
+type PasswordLengthRule struct {
+	minLength int
+	maxLength int
+}
+
+// NewPasswordLengthRule - PasswordLengthRule constructor func.
+func NewPasswordLengthRule(minLength, maxLength int) *PasswordLengthRule {
+	return &PasswordLengthRule{
+		minLength: minLength,
+		maxLength: maxLength,
+	}
+}
+
+// Check - PasswordRule implementation.
+func (lr *PasswordLengthRule) Check(password string, user *models.UserModel) *PasswordViolation {
+	length := utf8.RuneCountInString(password)
+
+	if length < lr.minLength {
+		return &PasswordViolation{
+			Code:    PasswordTooShortViolation,
+			Message: fmt.Sprintf("Password has to be at least %d characters long.", lr.minLength),
+		}
+	}
+
+	if length > lr.maxLength {
+		return &PasswordViolation{
+			Code:    PasswordTooLongViolation,
+			Message: fmt.Sprintf("Password cannot be longer than %d characters.", lr.maxLength),
+		}
+	}
+
+	return nil
+}
+
+// PasswordBytesRule - requires passwords not to exceed given number of bytes, e.g. because
+// password hashing algorithm ignores the rest of them.
+type PasswordBytesRule struct {
+	maxBytes int
+}
+
+// NewPasswordBytesRule - PasswordBytesRule constructor func.
+func NewPasswordBytesRule(maxBytes int) *PasswordBytesRule {
+	return &PasswordBytesRule{
+		maxBytes: maxBytes,
+	}
+}
+
+// Check - PasswordRule implementation.
+func (br *PasswordBytesRule) Check(password string, user *models.UserModel) *PasswordViolation {
+	if len(password) > br.maxBytes {
+		return &PasswordViolation{
+			Code:    PasswordTooLongViolation,
+			Message: fmt.Sprintf("Password cannot be longer than %d bytes.", br.maxBytes),
+		}
+	}
+
+	return nil
+}
+
+// CharacterClassesRule - requires passwords to mix given number of character classes:
+// lowercase letters, uppercase letters, digits and other characters. Passphrases (passwords
+// at least passphraseLength long) are strong because of their length, so they are not checked.
+type CharacterClassesRule struct {
+	minClasses       int
+	passphraseLength int
+}
+
+// NewCharacterClassesRule - CharacterClassesRule constructor func.
+func NewCharacterClassesRule(minClasses, passphraseLength int) *CharacterClassesRule {
+	return &CharacterClassesRule{
+		minClasses:       minClasses,
+		passphraseLength: passphraseLength,
+	}
+}
+
+// Check - PasswordRule implementation.
+func (cr *CharacterClassesRule) Check(password string, user *models.UserModel) *PasswordViolation {
+	if utf8.RuneCountInString(password) >= cr.passphraseLength {
+		return nil
+	}
+
+	if countCharacterClasses(password) >= cr.minClasses {
+		return nil
+	}
+
+	return &PasswordViolation{
+		Code: PasswordCharacterClassesViolation,
+		Message: fmt.Sprintf(
+			"Password has to contain at least %d of: lowercase letters, uppercase letters, digits "+
+				"and other characters, or be at least %d characters long.",
+			cr.minClasses,
+			cr.passphraseLength,
+		),
+	}
+}
+
+// BlocklistRule - rejects common and breached passwords. Passwords are compared case-insensitively.
+type BlocklistRule struct {
+	passwords map[string]bool
+}
+
+// NewBlocklistRule - BlocklistRule constructor func.
+func NewBlocklistRule(passwords []string) *BlocklistRule {
+	rule := &BlocklistRule{
+		passwords: map[string]bool{},
+	}
+
+	for _, password := range passwords {
+		if password = strings.TrimSpace(password); password != "" {
+			rule.passwords[strings.ToLower(password)] = true
+		}
+	}
+
+	return rule
+}
+
+// LoadBlocklistRule - returns BlocklistRule with passwords read from given file.
+// File should contain one password per line - empty lines and lines starting
+// with "#" are skipped.
+func LoadBlocklistRule(path string) (*BlocklistRule, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Loading password blocklist failed: %v", err)
+	}
+
+	defer file.Close()
+
+	passwords := []string{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if line := scanner.Text(); !strings.HasPrefix(line, "#") {
+			passwords = append(passwords, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Loading password blocklist failed: %v", err)
+	}
+
+	return NewBlocklistRule(passwords), nil
+}
+
+// Check - PasswordRule implementation.
+func (br *BlocklistRule) Check(password string, user *models.UserModel) *PasswordViolation {
+	if !br.passwords[strings.ToLower(password)] {
+		return nil
+	}
+
+	return &PasswordViolation{
+		Code:    PasswordCommonViolation,
+		Message: "Password is too common.",
+	}
+}
+
+// PersonalInfoRule - rejects passwords containing user's email, its parts or user's name.
+type PersonalInfoRule struct{}
+
+// NewPersonalInfoRule - PersonalInfoRule constructor func.
+func NewPersonalInfoRule() *PersonalInfoRule {
+	return &PersonalInfoRule{}
+}
+
+// Check - PasswordRule implementation.
+func (pr *PersonalInfoRule) Check(password string, user *models.UserModel) *PasswordViolation {
+	if user == nil {
+		return nil
+	}
+
+	password = strings.ToLower(password)
+
+	for _, info := range getPersonalInfo(user) {
+		if strings.Contains(password, info) {
+			return &PasswordViolation{
+				Code:    PasswordPersonalInfoViolation,
+				Message: "Password cannot contain your email or name.",
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPersonalInfo - returns lowercased email of given user, its local part and the words
+// it consists of, and words of user's name.
+func getPersonalInfo(user *models.UserModel) []string {
+	email := strings.ToLower(user.Email)
+	localPart := strings.SplitN(email, "@", 2)[0]
+
+	candidates := []string{email, localPart}
+	candidates = append(candidates, splitWords(localPart)...)
+	candidates = append(candidates, splitWords(strings.ToLower(user.FirstName))...)
+	candidates = append(candidates, splitWords(strings.ToLower(user.LastName))...)
+
+	info := []string{}
+
+	for _, candidate := range candidates {
+		if utf8.RuneCountInString(candidate) >= minPersonalInfoLength {
+			info = append(info, candidate)
+		}
+	}
+
+	return info
+}
+
+// splitWords - splits given string on all the characters other than letters and digits.
+func splitWords(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// countCharacterClasses - returns the number of character classes given password mixes.
+func countCharacterClasses(password string) int {
+	var lower, upper, digit, other int
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	return lower + upper + digit + other
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+)
+
+const (
+	// PersonalAccessTokenPrefix - prefix of all personal access tokens, distinguishing them from JWTs
+	// and making leaked tokens easy to find by secret scanners.
+	PersonalAccessTokenPrefix = "gochat_pat_"
+
+	// PersonalAccessTokenUsageInterval - minimal interval between saving token's last usage,
+	// so it does not cause a write on every request.
+	PersonalAccessTokenUsageInterval = time.Minute
+
+	// Number of random bytes personal access tokens are made of.
+	personalAccessTokenSize = 32
+
+	// Number of characters of the token (including PersonalAccessTokenPrefix) stored as its prefix.
+	personalAccessTokenDisplayLength = len(PersonalAccessTokenPrefix) + 6
+)
+
+// ErrPersonalAccessTokenInvalid - returned when personal access token does not exist
+// (e.g. has been revoked) or has expired.
+var ErrPersonalAccessTokenInvalid = errors.New("Personal access token is invalid, expired or revoked.")
+
+// NewPersonalAccessToken - returns new personal access token, together with the hash it
+// should be stored as, and the prefix allowing to recognize it.
+func NewPersonalAccessToken() (token string, hash string, prefix string, err error) {
+	secret := make([]byte, personalAccessTokenSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	token = PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return token, hashToken(token), token[:personalAccessTokenDisplayLength], nil
+}
+
+// IsPersonalAccessToken - returns true if given bearer token is a personal access token.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// PersonalAccessTokenManager - verifies personal access tokens.
+type PersonalAccessTokenManager struct {
+	broker persist.DBBroker
+}
+
+// NewPersonalAccessTokenManager - PersonalAccessTokenManager constructor func.
+func NewPersonalAccessTokenManager() *PersonalAccessTokenManager {
+	return &PersonalAccessTokenManager{
+		broker: persist.GormBroker,
+	}
+}
+
+// VerifyPersonalAccessToken - returns given personal access token's model and its owner.
+// Token's last usage by given client is saved as well.
+func (pm *PersonalAccessTokenManager) VerifyPersonalAccessToken(
+	token string,
+	client *ClientInfo,
+) (*models.PersonalAccessTokenModel, *models.UserModel, error) {
+	if !IsPersonalAccessToken(token) {
+		return nil, nil, ErrPersonalAccessTokenInvalid
+	}
+
+	tokenModel := &models.PersonalAccessTokenModel{}
+
+	if err := pm.broker.FirstWhere(tokenModel, "token_hash = ?", hashToken(token)).Err(); err != nil {
+		return nil, nil, ErrPersonalAccessTokenInvalid
+	}
+
+	now := time.Now()
+
+	if tokenModel.IsExpired(now) {
+		return nil, nil, ErrPersonalAccessTokenInvalid
+	}
+
+	user := &models.UserModel{}
+
+	if err := pm.broker.First(user, tokenModel.UserID).Err(); err != nil {
+		return nil, nil, ErrPersonalAccessTokenInvalid
+	}
+
+	if err := pm.touchPersonalAccessToken(tokenModel, client, now); err != nil {
+		log.Printf("Saving personal access token's usage failed: %v", err)
+	}
+
+	return tokenModel, user, nil
+}
+
+// touchPersonalAccessToken - saves the time and IP address of token's usage, unless
+// it has been saved less than PersonalAccessTokenUsageInterval ago.
+func (pm *PersonalAccessTokenManager) touchPersonalAccessToken(
+	tokenModel *models.PersonalAccessTokenModel,
+	client *ClientInfo,
+	now time.Time,
+) error {
+	if tokenModel.LastUsedAt != nil &&
+		tokenModel.LastUsedIP == client.IP &&
+		now.Sub(*tokenModel.LastUsedAt) < PersonalAccessTokenUsageInterval {
+		return nil
+	}
+
+	tokenModel.LastUsedAt = &now
+	tokenModel.LastUsedIP = client.IP
+
+	return pm.broker.Save(tokenModel).Err()
+}
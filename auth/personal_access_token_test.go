@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type personalAccessTokenSuite struct {
+	suite.Suite
+	tokenManager *PersonalAccessTokenManager
+	testUserID   uuid.UUID
+	testClient   *ClientInfo
+}
+
+func (s *personalAccessTokenSuite) SetupTest() {
+	s.tokenManager = &PersonalAccessTokenManager{
+		broker: mocks.NewGormMock(),
+	}
+
+	s.testUserID = uuid.New()
+	s.testClient = &ClientInfo{IP: "127.0.0.1", UserAgent: "test-agent"}
+}
+
+func TestPersonalAccessTokenSuite(t *testing.T) {
+	suite.Run(t, new(personalAccessTokenSuite))
+}
+
+func (s *personalAccessTokenSuite) mockToken(
+	gormMock *mocks.GormMock,
+	token string,
+	tokenModel *models.PersonalAccessTokenModel,
+) {
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		"token_hash = ?",
+		[]interface{}{hashToken(token)},
+	).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.PersonalAccessTokenModel) = *tokenModel
+	})
+}
+
+func (s *personalAccessTokenSuite) TestNewPersonalAccessToken() {
+	token, hash, prefix, err := NewPersonalAccessToken()
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), IsPersonalAccessToken(token))
+	assert.True(s.T(), strings.HasPrefix(token, prefix))
+	assert.Equal(s.T(), len(PersonalAccessTokenPrefix)+6, len(prefix))
+	assert.Equal(s.T(), hashToken(token), hash)
+	assert.NotContains(s.T(), hash, token)
+
+	otherToken, _, _, _ := NewPersonalAccessToken()
+
+	assert.NotEqual(s.T(), token, otherToken)
+}
+
+func (s *personalAccessTokenSuite) TestVerifyPersonalAccessToken() {
+	token, _, _, _ := NewPersonalAccessToken()
+
+	tokenModel := &models.PersonalAccessTokenModel{
+		UserID: s.testUserID,
+		Scopes: "messages:read",
+	}
+
+	gormMock := mocks.NewGormMock()
+	s.mockToken(gormMock, token, tokenModel)
+	gormMock.On("First", mock.Anything, []interface{}{s.testUserID}).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.UserModel).ID = s.testUserID
+	})
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	s.tokenManager.broker = gormMock
+
+	verifiedToken, user, err := s.tokenManager.VerifyPersonalAccessToken(token, s.testClient)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testUserID, user.ID)
+	assert.Equal(s.T(), []string{"messages:read"}, verifiedToken.GetScopes())
+	assert.NotNil(s.T(), verifiedToken.LastUsedAt)
+	assert.Equal(s.T(), s.testClient.IP, verifiedToken.LastUsedIP)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+}
+
+func (s *personalAccessTokenSuite) TestVerifyPersonalAccessToken_RecentlyUsed() {
+	token, _, _, _ := NewPersonalAccessToken()
+
+	lastUsedAt := time.Now().Add(-10 * time.Second)
+
+	tokenModel := &models.PersonalAccessTokenModel{
+		UserID:     s.testUserID,
+		LastUsedAt: &lastUsedAt,
+		LastUsedIP: s.testClient.IP,
+	}
+
+	gormMock := mocks.NewGormMock()
+	s.mockToken(gormMock, token, tokenModel)
+	gormMock.On("First", mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	s.tokenManager.broker = gormMock
+
+	_, _, err := s.tokenManager.VerifyPersonalAccessToken(token, s.testClient)
+
+	assert.Nil(s.T(), err)
+
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *personalAccessTokenSuite) TestVerifyPersonalAccessToken_Expired() {
+	token, _, _, _ := NewPersonalAccessToken()
+
+	expiresAt := time.Now().Add(-time.Hour)
+
+	tokenModel := &models.PersonalAccessTokenModel{
+		UserID:    s.testUserID,
+		ExpiresAt: &expiresAt,
+	}
+
+	gormMock := mocks.NewGormMock()
+	s.mockToken(gormMock, token, tokenModel)
+
+	s.tokenManager.broker = gormMock
+
+	_, _, err := s.tokenManager.VerifyPersonalAccessToken(token, s.testClient)
+
+	assert.Equal(s.T(), ErrPersonalAccessTokenInvalid, err)
+
+	gormMock.AssertNotCalled(s.T(), "First", mock.Anything, mock.Anything)
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *personalAccessTokenSuite) TestVerifyPersonalAccessToken_Unknown() {
+	token, _, _, _ := NewPersonalAccessToken()
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+
+	s.tokenManager.broker = gormMock
+
+	_, _, err := s.tokenManager.VerifyPersonalAccessToken(token, s.testClient)
+
+	assert.Equal(s.T(), ErrPersonalAccessTokenInvalid, err)
+}
+
+func (s *personalAccessTokenSuite) TestVerifyPersonalAccessToken_NotPersonalAccessToken() {
+	gormMock := mocks.NewGormMock()
+
+	s.tokenManager.broker = gormMock
+
+	_, _, err := s.tokenManager.VerifyPersonalAccessToken("eyJhbGciOiJIUzI1NiJ9.e30.sig", s.testClient)
+
+	assert.Equal(s.T(), ErrPersonalAccessTokenInvalid, err)
+
+	gormMock.AssertNotCalled(s.T(), "FirstWhere", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Prefix of the cache key holding the hash of session's current refresh token.
+	refreshTokenKeyPrefix = "refresh:"
+
+	// Prefix of the cache keys holding hashes of already rotated refresh tokens.
+	usedRefreshTokenKeyPrefix = "refresh:used:"
+
+	// Number of random bytes refresh token's secret part is made of.
+	refreshTokenSecretSize = 32
+)
+
+// ErrRefreshTokenInvalid - returned when refresh token is malformed, expired,
+// or its session has been closed.
+var ErrRefreshTokenInvalid = errors.New("Refresh token is invalid or expired.")
+
+// RefreshTokenReusedError - returned when already rotated refresh token is used again,
+// which means it could have been stolen. Whole session is revoked in such case.
+type RefreshTokenReusedError struct {
+	AuthUUID uuid.UUID
+}
+
+// Error - satisfies standard Error interface.
+func (e *RefreshTokenReusedError) Error() string {
+	return "Refresh token has already been used."
+}
+
+// RefreshSession - session verified refresh token belongs to.
+type RefreshSession struct {
+	AuthUUID uuid.UUID
+	UserID   uuid.UUID
+
+	session   *Session
+	tokenHash string
+}
+
+// newRefreshToken - returns new, opaque refresh token bound to given authorization.
+func newRefreshToken(authUUID uuid.UUID) (string, error) {
+	secret := make([]byte, refreshTokenSecretSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return authUUID.String() + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// parseRefreshToken - returns the authorization given refresh token is bound to.
+func parseRefreshToken(refreshToken string) (uuid.UUID, error) {
+	parts := strings.Split(refreshToken, ".")
+
+	if len(parts) != 2 || parts[1] == "" {
+		return uuid.Nil, ErrRefreshTokenInvalid
+	}
+
+	authUUID, err := uuid.Parse(parts[0])
+
+	if err != nil {
+		return uuid.Nil, ErrRefreshTokenInvalid
+	}
+
+	return authUUID, nil
+}
+
+// hashToken - returns the hash opaque token (e.g. refresh token) is stored as.
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func refreshTokenKey(authUUID uuid.UUID) string {
+	return refreshTokenKeyPrefix + authUUID.String()
+}
+
+func usedRefreshTokenKey(tokenHash string) string {
+	return usedRefreshTokenKeyPrefix + tokenHash
+}
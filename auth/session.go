@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SESSION_RESOURCE - name of Session resource.
+const SESSION_RESOURCE = "Session"
+
+const (
+	// Prefix of the cache keys holding IDs of user's sessions.
+	userSessionsKeyPrefix = "sessions:"
+
+	// SessionTouchInterval - minimal interval between updates of session's last activity.
+	SessionTouchInterval = time.Minute
+)
+
+// ErrSessionNotFound - returned when session does not exist or has expired.
+var ErrSessionNotFound = errors.New("Session not found.")
+
+// ClientInfo - describes the client a session is used from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Session - single logged in session of a user. Its ID is the authUUID
+// of the tokens issued for it.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"userId"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+
+	// TwoFactorVerified - true if session has been started with second factor,
+	// or user has enrolled two-factor authentication within it.
+	TwoFactorVerified bool `json:"twoFactorVerified"`
+
+	// Actor - the admin impersonating session's user, if the session has been
+	// started with impersonation.
+	Actor *Actor `json:"actor,omitempty"`
+
+	// WorkspaceID - the Workspace selected for the session, which requests are made in
+	// unless other one is given in the route.
+	WorkspaceID *uuid.UUID `json:"workspaceId,omitempty"`
+}
+
+// newSession - returns new Session of given user, used from given client.
+func newSession(userID uuid.UUID, client *ClientInfo) *Session {
+	now := time.Now()
+
+	session := &Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(RefreshTokenTTL),
+	}
+
+	if client != nil {
+		session.IP = client.IP
+		session.UserAgent = client.UserAgent
+	}
+
+	return session
+}
+
+// GetSession - returns active session with given ID.
+func (am *AuthManager) GetSession(authUUID uuid.UUID) (*Session, error) {
+	res := am.cache.Get(am.ctx, authUUID.String())
+
+	if res.Err() != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	var session Session
+
+	if err := json.Unmarshal([]byte(res.Val()), &session); err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return &session, nil
+}
+
+// GetSessions - returns all active sessions of given user, starting from the most recently used.
+func (am *AuthManager) GetSessions(userID uuid.UUID) ([]*Session, error) {
+	res := am.cache.SMembers(am.ctx, userSessionsKey(userID))
+
+	if res.Err() != nil {
+		return nil, res.Err()
+	}
+
+	sessions := []*Session{}
+
+	for _, id := range res.Vals() {
+		authUUID, err := uuid.Parse(id)
+
+		if err == nil {
+			if session, err := am.GetSession(authUUID); err == nil {
+				sessions = append(sessions, session)
+				continue
+			}
+		}
+
+		// Sessions expire on their own, therefore their IDs are cleaned up lazily.
+		if err := am.cache.SRem(am.ctx, userSessionsKey(userID), id).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// TouchSession - saves session's last activity, if it has not been saved recently.
+func (am *AuthManager) TouchSession(session *Session, client *ClientInfo) error {
+	if time.Since(session.LastSeenAt) < SessionTouchInterval {
+		return nil
+	}
+
+	session.LastSeenAt = time.Now()
+
+	if client != nil {
+		session.IP = client.IP
+		session.UserAgent = client.UserAgent
+	}
+
+	return am.saveSession(session)
+}
+
+// SelectWorkspace - selects the Workspace requests made within given session are made in,
+// unless other one is given in the route.
+func (am *AuthManager) SelectWorkspace(authUUID uuid.UUID, workspaceID uuid.UUID) error {
+	session, err := am.GetSession(authUUID)
+
+	if err != nil {
+		return err
+	}
+
+	session.WorkspaceID = &workspaceID
+
+	return am.saveSession(session)
+}
+
+// LogoutAll - logs given user out of all the sessions. Returns IDs of closed sessions.
+func (am *AuthManager) LogoutAll(userID uuid.UUID) ([]uuid.UUID, error) {
+	sessions, err := am.GetSessions(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var authUUIDs []uuid.UUID
+
+	for _, session := range sessions {
+		if err := am.Logout(session.ID.String()); err != nil {
+			return authUUIDs, err
+		}
+
+		authUUIDs = append(authUUIDs, session.ID)
+	}
+
+	return authUUIDs, nil
+}
+
+// saveSession - saves given session until it expires.
+func (am *AuthManager) saveSession(session *Session) error {
+	data, err := json.Marshal(session)
+
+	if err != nil {
+		return err
+	}
+
+	return am.cache.Set(am.ctx, session.ID.String(), string(data), time.Until(session.ExpiresAt)).Err()
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return userSessionsKeyPrefix + userID.String()
+}
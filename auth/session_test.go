@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type sessionSuite struct {
+	suite.Suite
+	authManager *AuthManager
+	testUserID  uuid.UUID
+	testClient  *ClientInfo
+}
+
+func (s *sessionSuite) SetupTest() {
+	s.authManager = &AuthManager{
+		cache: mocks.NewRedisCacheMock(),
+		ctx:   context.Background(),
+	}
+
+	s.testUserID = uuid.New()
+	s.testClient = &ClientInfo{IP: "127.0.0.1", UserAgent: "test_user_agent"}
+}
+
+func TestSessionSuite(t *testing.T) {
+	suite.Run(t, new(sessionSuite))
+}
+
+func (s *sessionSuite) getSessionCacheResponse(session *Session) *persist.CacheResponse {
+	data, err := json.Marshal(session)
+	s.Require().Nil(err)
+
+	return mocks.GetValueCacheResponse(string(data))
+}
+
+func (s *sessionSuite) TestGetSession() {
+	session := newSession(s.testUserID, s.testClient)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		session.ID.String(),
+	).Return(s.getSessionCacheResponse(session))
+
+	s.authManager.cache = cacheMock
+
+	result, err := s.authManager.GetSession(session.ID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), session.ID, result.ID)
+	assert.Equal(s.T(), s.testUserID, result.UserID)
+	assert.Equal(s.T(), s.testClient.IP, result.IP)
+	assert.Equal(s.T(), s.testClient.UserAgent, result.UserAgent)
+}
+
+func (s *sessionSuite) TestGetSession_NotFound() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	s.authManager.cache = cacheMock
+
+	result, err := s.authManager.GetSession(uuid.New())
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), ErrSessionNotFound, err)
+}
+
+func (s *sessionSuite) TestGetSessions() {
+	older := newSession(s.testUserID, s.testClient)
+	older.LastSeenAt = time.Now().Add(-time.Hour)
+
+	newer := newSession(s.testUserID, s.testClient)
+	expiredID := uuid.New()
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"SMembers",
+		mock.Anything,
+		userSessionsKey(s.testUserID),
+	).Return(mocks.GetValuesCacheResponse([]string{older.ID.String(), expiredID.String(), newer.ID.String()}))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		older.ID.String(),
+	).Return(s.getSessionCacheResponse(older))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		newer.ID.String(),
+	).Return(s.getSessionCacheResponse(newer))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		expiredID.String(),
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+	cacheMock.On(
+		"SRem",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	sessions, err := s.authManager.GetSessions(s.testUserID)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), sessions, 2)
+	assert.Equal(s.T(), newer.ID, sessions[0].ID)
+	assert.Equal(s.T(), older.ID, sessions[1].ID)
+
+	cacheMock.AssertCalled(s.T(), "SRem", mock.Anything, userSessionsKey(s.testUserID), []string{expiredID.String()})
+}
+
+func (s *sessionSuite) TestTouchSession() {
+	session := newSession(s.testUserID, nil)
+	session.LastSeenAt = time.Now().Add(-SessionTouchInterval)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.TouchSession(session, s.testClient))
+	assert.WithinDuration(s.T(), time.Now(), session.LastSeenAt, time.Second)
+	assert.Equal(s.T(), s.testClient.IP, session.IP)
+
+	// Session has just been touched, so it should not be saved again.
+	assert.Nil(s.T(), s.authManager.TouchSession(session, s.testClient))
+
+	cacheMock.AssertNumberOfCalls(s.T(), "Set", 1)
+	cacheMock.AssertCalled(s.T(), "Set", mock.Anything, session.ID.String(), mock.Anything, mock.Anything)
+}
+
+func (s *sessionSuite) TestSelectWorkspace() {
+	session := newSession(s.testUserID, s.testClient)
+	workspaceID := uuid.New()
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		session.ID.String(),
+	).Return(s.getSessionCacheResponse(session))
+	cacheMock.On(
+		"Set",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.SelectWorkspace(session.ID, workspaceID))
+
+	var saved Session
+
+	assert.Nil(s.T(), json.Unmarshal([]byte(cacheMock.Calls[1].Arguments.String(2)), &saved))
+	assert.Equal(s.T(), &workspaceID, saved.WorkspaceID)
+}
+
+func (s *sessionSuite) TestLogoutAll() {
+	session := newSession(s.testUserID, s.testClient)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"SMembers",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValuesCacheResponse([]string{session.ID.String()}))
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		session.ID.String(),
+	).Return(s.getSessionCacheResponse(session))
+	cacheMock.On(
+		"Del",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+	cacheMock.On(
+		"SRem",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.authManager.cache = cacheMock
+
+	authUUIDs, err := s.authManager.LogoutAll(s.testUserID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []uuid.UUID{session.ID}, authUUIDs)
+
+	cacheMock.AssertCalled(s.T(), "Del", mock.Anything, []string{session.ID.String(), refreshTokenKey(session.ID)})
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Map of valid JWT_SIGNING_ALGORITHM env values.
+const (
+	HS256Algorithm = "HS256"
+	RS256Algorithm = "RS256"
+	EdDSAAlgorithm = "EdDSA"
+)
+
+// Minimal size of RSA keys, in bits.
+const minRSAKeySize = 2048
+
+// ErrSigningKeyUnsupported - returned when key is neither 2048+ bits RSA key, nor Ed25519 key.
+var ErrSigningKeyUnsupported = errors.New("Signing key has to be RSA (at least 2048 bits) or Ed25519 key.")
+
+// SigningKey - asymmetric key JWTs are signed or verified with. Keys used only
+// for verification have no PrivateKey.
+type SigningKey struct {
+	// ID - identifier of the key, passed in "kid" header of JWTs. It's a JWK thumbprint
+	// (RFC 7638) of the public key, so it does not have to be configured.
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// NewSigningKey - returns SigningKey for given private key. Algorithm is based on the key type.
+func NewSigningKey(privateKey crypto.Signer) (*SigningKey, error) {
+	key, err := NewVerificationKey(privateKey.Public())
+
+	if err != nil {
+		return nil, err
+	}
+
+	key.PrivateKey = privateKey
+
+	return key, nil
+}
+
+// NewVerificationKey - returns SigningKey for given public key, which can be used only
+// to verify JWTs. Algorithm is based on the key type.
+func NewVerificationKey(publicKey crypto.PublicKey) (*SigningKey, error) {
+	key := &SigningKey{
+		PublicKey: publicKey,
+	}
+
+	switch typedKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		if typedKey.N.BitLen() < minRSAKeySize {
+			return nil, ErrSigningKeyUnsupported
+		}
+
+		key.Algorithm = RS256Algorithm
+	case ed25519.PublicKey:
+		key.Algorithm = EdDSAAlgorithm
+	default:
+		return nil, ErrSigningKeyUnsupported
+	}
+
+	key.ID = jwkThumbprint(newJWK(key))
+
+	return key, nil
+}
+
+// KeySet - keys JWTs are signed and verified with. With HS256 algorithm, tokens are signed
+// with the shared API secret, but asymmetric verification keys may still be present, e.g. when
+// switching to asymmetric signing. Otherwise, tokens are signed with the signing key - multiple
+// verification keys allow to rotate it without invalidating tokens signed with previous keys.
+type KeySet struct {
+	algorithm        string
+	signingKey       *SigningKey
+	verificationKeys []*SigningKey
+}
+
+// NewHMACKeySet - returns KeySet signing JWTs with the shared API secret (HS256),
+// and verifying asymmetric ones with given keys.
+func NewHMACKeySet(verificationKeys ...*SigningKey) *KeySet {
+	return &KeySet{
+		algorithm:        HS256Algorithm,
+		verificationKeys: verificationKeys,
+	}
+}
+
+// NewKeySet - returns KeySet signing JWTs with given key. Signing key is used
+// for verification too, along with given verification keys.
+func NewKeySet(signingKey *SigningKey, verificationKeys ...*SigningKey) *KeySet {
+	keys := []*SigningKey{signingKey}
+
+	for _, key := range verificationKeys {
+		if key.ID != signingKey.ID {
+			keys = append(keys, key)
+		}
+	}
+
+	return &KeySet{
+		algorithm:        signingKey.Algorithm,
+		signingKey:       signingKey,
+		verificationKeys: keys,
+	}
+}
+
+// Algorithm - returns the algorithm new JWTs are signed with.
+func (ks *KeySet) Algorithm() string {
+	return ks.algorithm
+}
+
+// UsesSecret - returns true if JWTs are signed with the shared API secret.
+func (ks *KeySet) UsesSecret() bool {
+	return ks.algorithm == HS256Algorithm
+}
+
+// SigningKey - returns the key new JWTs are signed with, or nil if they are signed
+// with the shared API secret.
+func (ks *KeySet) SigningKey() *SigningKey {
+	return ks.signingKey
+}
+
+// VerificationKey - returns verification key with given ID.
+func (ks *KeySet) VerificationKey(keyID string) (*SigningKey, bool) {
+	for _, key := range ks.verificationKeys {
+		if key.ID == keyID {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+// JWKS - returns public verification keys as JSON Web Key Set.
+func (ks *KeySet) JWKS() *JWKS {
+	jwks := &JWKS{
+		Keys: []*JWK{},
+	}
+
+	for _, key := range ks.verificationKeys {
+		jwks.Keys = append(jwks.Keys, newJWK(key))
+	}
+
+	return jwks
+}
+
+// DefaultKeySet - KeySet used to sign and verify JWTs.
+var DefaultKeySet = NewHMACKeySet()
+
+// InitKeySet - initializes DefaultKeySet, based on JWT_SIGNING_ALGORITHM env. HS256 is used by default.
+// For "RS256" and "EdDSA", signing key is read from PEM file set by JWT_SIGNING_KEY_FILE env. Additional
+// public keys accepted during key rotation are read from PEM files set by JWT_VERIFICATION_KEY_FILES env,
+// separated by commas.
+func InitKeySet() (*KeySet, error) {
+	verificationKeys := []*SigningKey{}
+
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+
+		key, err := loadVerificationKey(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		verificationKeys = append(verificationKeys, key)
+	}
+
+	switch algorithm := os.Getenv("JWT_SIGNING_ALGORITHM"); algorithm {
+	case HS256Algorithm, "":
+		if os.Getenv("API_SECRET") == "" {
+			return nil, errors.New("API_SECRET is required for HS256 signing")
+		}
+
+		DefaultKeySet = NewHMACKeySet(verificationKeys...)
+	case RS256Algorithm, EdDSAAlgorithm:
+		signingKey, err := loadSigningKey(os.Getenv("JWT_SIGNING_KEY_FILE"))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if signingKey.Algorithm != algorithm {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY_FILE has to contain %s key", algorithm)
+		}
+
+		DefaultKeySet = NewKeySet(signingKey, verificationKeys...)
+	default:
+		return nil, fmt.Errorf("Unknown JWT signing algorithm: %s", algorithm)
+	}
+
+	return DefaultKeySet, nil
+}
+
+// ParsePrivateKeyPEM - parses PEM encoded RSA (PKCS #1 or PKCS #8) or Ed25519 (PKCS #8) private key.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, errors.New("Private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+
+	if !ok {
+		return nil, ErrSigningKeyUnsupported
+	}
+
+	return signer, nil
+}
+
+// ParsePublicKeyPEM - parses PEM encoded public key (PKIX). Private keys are accepted
+// as well - their public part is returned.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, errors.New("Public key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	signer, err := ParsePrivateKeyPEM(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Public(), nil
+}
+
+// loadSigningKey - reads SigningKey from PEM file with a private key.
+func loadSigningKey(path string) (*SigningKey, error) {
+	if path == "" {
+		return nil, errors.New("JWT_SIGNING_KEY_FILE is required for asymmetric signing")
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Loading signing key failed: %v", err)
+	}
+
+	privateKey, err := ParsePrivateKeyPEM(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Loading signing key failed: %v", err)
+	}
+
+	return NewSigningKey(privateKey)
+}
+
+// loadVerificationKey - reads verification-only SigningKey from PEM file with a public key.
+func loadVerificationKey(path string) (*SigningKey, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Loading verification key failed: %v", err)
+	}
+
+	publicKey, err := ParsePublicKeyPEM(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Loading verification key %s failed: %v", path, err)
+	}
+
+	return NewVerificationKey(publicKey)
+}
@@ -0,0 +1,310 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type signingKeysSuite struct {
+	suite.Suite
+	rsaKey       *rsa.PrivateKey
+	ed25519Key   ed25519.PrivateKey
+	testSecret   string
+	testExpires  int64
+	testAuthUUID string
+}
+
+func (s *signingKeysSuite) SetupSuite() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().Nil(err)
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().Nil(err)
+
+	s.rsaKey = rsaKey
+	s.ed25519Key = ed25519Key
+	s.testSecret = "test_secret"
+	s.testExpires = time.Now().Add(time.Minute).Unix()
+	s.testAuthUUID = "test_auth_uuid"
+}
+
+func TestSigningKeysSuite(t *testing.T) {
+	suite.Run(t, new(signingKeysSuite))
+}
+
+func (s *signingKeysSuite) newJWTManager(keys *KeySet) *JWTManager {
+	return &JWTManager{
+		tokenProvider: &tokenProvider{},
+		keys:          keys,
+	}
+}
+
+func (s *signingKeysSuite) createToken(jwtManager *JWTManager) string {
+	token, err := jwtManager.CreateToken(s.testAuthUUID, "user_id", "email", "role", s.testSecret, s.testExpires)
+	s.Require().Nil(err)
+
+	return token
+}
+
+func (s *signingKeysSuite) TestJWKThumbprint() {
+	// Examples from RFC 7638 (section 3.1) and RFC 8037 (appendix A.3).
+	rsaJWK := &JWK{
+		KeyType: "RSA",
+		Modulus: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRX" +
+			"jBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8K" +
+			"JZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniI" +
+			"qbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		Exponent: "AQAB",
+	}
+	okpJWK := &JWK{
+		KeyType: "OKP",
+		Curve:   "Ed25519",
+		X:       "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+	}
+
+	assert.Equal(s.T(), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", jwkThumbprint(rsaJWK))
+	assert.Equal(s.T(), "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", jwkThumbprint(okpJWK))
+}
+
+func (s *signingKeysSuite) TestSigningMethodEdDSA_KnownSignature() {
+	// Example from RFC 8037 (appendix A.4).
+	seed, _ := base64.RawURLEncoding.DecodeString("nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A")
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	signingString := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc"
+	expected := "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+
+	signature, err := SigningMethodEdDSA.Sign(signingString, privateKey)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), expected, signature)
+
+	publicKey := privateKey.Public()
+
+	assert.Nil(s.T(), SigningMethodEdDSA.Verify(signingString, signature, publicKey))
+	assert.Equal(s.T(), ErrEdDSAVerification, SigningMethodEdDSA.Verify(signingString+"x", signature, publicKey))
+	assert.Equal(s.T(), jwt.ErrInvalidKeyType, SigningMethodEdDSA.Verify(signingString, signature, []byte("secret")))
+}
+
+func (s *signingKeysSuite) TestNewSigningKey() {
+	rsaKey, err := NewSigningKey(s.rsaKey)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), RS256Algorithm, rsaKey.Algorithm)
+	assert.NotEmpty(s.T(), rsaKey.ID)
+
+	ed25519Key, err := NewSigningKey(s.ed25519Key)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), EdDSAAlgorithm, ed25519Key.Algorithm)
+
+	// Key ID depends only on the public key.
+	verificationKey, _ := NewVerificationKey(s.ed25519Key.Public())
+	assert.Equal(s.T(), ed25519Key.ID, verificationKey.ID)
+
+	smallKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	_, err = NewSigningKey(smallKey)
+
+	assert.Equal(s.T(), ErrSigningKeyUnsupported, err)
+}
+
+func (s *signingKeysSuite) TestCreateToken_Asymmetric() {
+	for _, privateKey := range []crypto.Signer{s.rsaKey, s.ed25519Key} {
+		signingKey, err := NewSigningKey(privateKey)
+		s.Require().Nil(err)
+
+		jwtManager := s.newJWTManager(NewKeySet(signingKey))
+
+		// Secret is not needed for asymmetric signing.
+		tokenString, err := jwtManager.CreateToken(s.testAuthUUID, "user_id", "email", "role", "", s.testExpires)
+
+		assert.Nil(s.T(), err)
+
+		token, err := jwtManager.ParseToken(tokenString, "")
+
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), signingKey.Algorithm, token.Header["alg"])
+		assert.Equal(s.T(), signingKey.ID, token.Header["kid"])
+		assert.Equal(s.T(), s.testAuthUUID, token.Claims.(jwt.MapClaims)["authUUID"])
+	}
+}
+
+func (s *signingKeysSuite) TestParseToken_Rotation() {
+	previousKey, _ := NewSigningKey(s.rsaKey)
+	currentKey, _ := NewSigningKey(s.ed25519Key)
+
+	previousToken := s.createToken(s.newJWTManager(NewKeySet(previousKey)))
+
+	// Previous key is still accepted, while it's one of verification keys.
+	verificationKey, _ := NewVerificationKey(s.rsaKey.Public())
+	rotatedManager := s.newJWTManager(NewKeySet(currentKey, verificationKey))
+
+	_, err := rotatedManager.ParseToken(previousToken, "")
+	assert.Nil(s.T(), err)
+
+	_, err = rotatedManager.ParseToken(s.createToken(rotatedManager), "")
+	assert.Nil(s.T(), err)
+
+	_, err = s.newJWTManager(NewKeySet(currentKey)).ParseToken(previousToken, "")
+	assert.NotNil(s.T(), err)
+}
+
+func (s *signingKeysSuite) TestParseToken_Rejected() {
+	signingKey, _ := NewSigningKey(s.rsaKey)
+	jwtManager := s.newJWTManager(NewKeySet(signingKey))
+
+	// HMAC tokens are rejected when asymmetric signing is used, even if they're signed
+	// with the public key (algorithm confusion).
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: mustMarshalPKIX(s.T(), s.rsaKey.Public()),
+	})
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{})
+	hmacToken.Header["kid"] = signingKey.ID
+	hmacTokenString, _ := hmacToken.SignedString(publicKeyPEM)
+
+	_, err := jwtManager.ParseToken(hmacTokenString, string(publicKeyPEM))
+	assert.NotNil(s.T(), err)
+
+	// Key cannot be used with other algorithm than its own.
+	rs512Token := jwt.NewWithClaims(jwt.SigningMethodRS512, jwt.MapClaims{})
+	rs512Token.Header["kid"] = signingKey.ID
+	rs512TokenString, _ := rs512Token.SignedString(s.rsaKey)
+
+	_, err = jwtManager.ParseToken(rs512TokenString, "")
+	assert.NotNil(s.T(), err)
+
+	// Token has to identify its key.
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	unknownKeyToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{})
+	unknownKeyTokenString, _ := unknownKeyToken.SignedString(otherKey)
+
+	_, err = jwtManager.ParseToken(unknownKeyTokenString, "")
+	assert.NotNil(s.T(), err)
+}
+
+func (s *signingKeysSuite) TestParseToken_HMACWithVerificationKeys() {
+	signingKey, _ := NewSigningKey(s.ed25519Key)
+	verificationKey, _ := NewVerificationKey(s.ed25519Key.Public())
+
+	asymmetricToken := s.createToken(s.newJWTManager(NewKeySet(signingKey)))
+
+	// Asymmetric keys can be published before switching from HS256 to them.
+	jwtManager := s.newJWTManager(NewHMACKeySet(verificationKey))
+
+	_, err := jwtManager.ParseToken(s.createToken(jwtManager), s.testSecret)
+	assert.Nil(s.T(), err)
+
+	_, err = jwtManager.ParseToken(asymmetricToken, s.testSecret)
+	assert.Nil(s.T(), err)
+}
+
+func (s *signingKeysSuite) TestJWKS() {
+	rsaKey, _ := NewSigningKey(s.rsaKey)
+	ed25519Key, _ := NewVerificationKey(s.ed25519Key.Public())
+
+	jwks := NewKeySet(rsaKey, ed25519Key, rsaKey).JWKS()
+
+	assert.Len(s.T(), jwks.Keys, 2)
+
+	rsaJWK := jwks.Keys[0]
+
+	assert.Equal(s.T(), "RSA", rsaJWK.KeyType)
+	assert.Equal(s.T(), RS256Algorithm, rsaJWK.Algorithm)
+	assert.Equal(s.T(), "sig", rsaJWK.Use)
+	assert.Equal(s.T(), rsaKey.ID, rsaJWK.KeyID)
+	assert.Equal(s.T(), "AQAB", rsaJWK.Exponent)
+
+	modulus, _ := base64.RawURLEncoding.DecodeString(rsaJWK.Modulus)
+	assert.Equal(s.T(), 0, new(big.Int).SetBytes(modulus).Cmp(s.rsaKey.N))
+
+	okpJWK := jwks.Keys[1]
+
+	assert.Equal(s.T(), "OKP", okpJWK.KeyType)
+	assert.Equal(s.T(), "Ed25519", okpJWK.Curve)
+	assert.Equal(s.T(), EdDSAAlgorithm, okpJWK.Algorithm)
+	assert.Equal(s.T(), base64.RawURLEncoding.EncodeToString(s.ed25519Key.Public().(ed25519.PublicKey)), okpJWK.X)
+
+	assert.Empty(s.T(), NewHMACKeySet().JWKS().Keys)
+}
+
+func (s *signingKeysSuite) TestInitKeySet() {
+	dir, err := ioutil.TempDir("", "gochat-keys")
+	s.Require().Nil(err)
+
+	defer os.RemoveAll(dir)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(s.ed25519Key)
+	s.Require().Nil(err)
+
+	signingKeyPath := filepath.Join(dir, "signing.pem")
+	err = ioutil.WriteFile(signingKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0600)
+	s.Require().Nil(err)
+
+	verificationKeyPath := filepath.Join(dir, "previous.pem")
+	err = ioutil.WriteFile(verificationKeyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: mustMarshalPKIX(s.T(), s.rsaKey.Public()),
+	}), 0600)
+	s.Require().Nil(err)
+
+	defaultKeySet := DefaultKeySet
+
+	defer func() {
+		DefaultKeySet = defaultKeySet
+		os.Unsetenv("JWT_SIGNING_ALGORITHM")
+		os.Unsetenv("JWT_SIGNING_KEY_FILE")
+		os.Unsetenv("JWT_VERIFICATION_KEY_FILES")
+	}()
+
+	os.Setenv("JWT_SIGNING_ALGORITHM", EdDSAAlgorithm)
+	os.Setenv("JWT_SIGNING_KEY_FILE", signingKeyPath)
+	os.Setenv("JWT_VERIFICATION_KEY_FILES", verificationKeyPath+", ")
+
+	keys, err := InitKeySet()
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DefaultKeySet, keys)
+	assert.Equal(s.T(), EdDSAAlgorithm, keys.Algorithm())
+	assert.False(s.T(), keys.UsesSecret())
+	assert.Len(s.T(), keys.JWKS().Keys, 2)
+
+	os.Setenv("JWT_SIGNING_ALGORITHM", RS256Algorithm)
+
+	_, err = InitKeySet()
+
+	assert.NotNil(s.T(), err)
+	assert.True(s.T(), strings.Contains(err.Error(), "RS256"))
+
+	os.Setenv("JWT_SIGNING_ALGORITHM", "none")
+
+	_, err = InitKeySet()
+
+	assert.NotNil(s.T(), err)
+}
+
+func mustMarshalPKIX(t *testing.T, publicKey crypto.PublicKey) []byte {
+	data, err := x509.MarshalPKIXPublicKey(publicKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
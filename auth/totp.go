@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TOTPIssuer - issuer shown by authenticator apps.
+	TOTPIssuer = "Gochat"
+
+	// TOTPPeriod - time step of TOTP codes.
+	TOTPPeriod = 30 * time.Second
+
+	// TOTPDigits - number of digits of TOTP codes.
+	TOTPDigits = 6
+
+	// Number of random bytes TOTP secret is made of (160 bits, as recommended by RFC 4226).
+	totpSecretSize = 20
+
+	// Number of time steps before and after the current one, which codes are accepted,
+	// to compensate clock drift.
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret - returns new, base32 encoded TOTP secret.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI - returns otpauth:// URI of given secret, which can be
+// encoded as QR code and scanned by authenticator apps.
+func TOTPProvisioningURI(secret, accountName string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", TOTPIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + TOTPIssuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// ValidateTOTPCode - checks given code against given secret at given time, as described
+// in RFC 6238. Returns the time step matching code has been generated for.
+func ValidateTOTPCode(secret, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil || len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	counter := at.Unix() / int64(TOTPPeriod.Seconds())
+
+	for step := counter - totpSkew; step <= counter+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode - returns HOTP code (RFC 4226) of given key and counter.
+func totpCode(key []byte, counter int64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", TOTPDigits, value%modulo)
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type totpSuite struct {
+	suite.Suite
+	authManager *AuthManager
+	testUserID  uuid.UUID
+
+	// Secret used by RFC 6238 test vectors ("12345678901234567890").
+	testSecret string
+}
+
+func (s *totpSuite) SetupTest() {
+	s.authManager = &AuthManager{
+		cache: mocks.NewRedisCacheMock(),
+		ctx:   context.Background(),
+	}
+
+	s.testUserID = uuid.New()
+	s.testSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+}
+
+func TestTOTPSuite(t *testing.T) {
+	suite.Run(t, new(totpSuite))
+}
+
+func (s *totpSuite) TestValidateTOTPCode_RFCVectors() {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for timestamp, code := range vectors {
+		step, ok := ValidateTOTPCode(s.testSecret, code, time.Unix(timestamp, 0))
+
+		assert.True(s.T(), ok, "code for %d", timestamp)
+		assert.Equal(s.T(), timestamp/30, step)
+	}
+}
+
+func (s *totpSuite) TestValidateTOTPCode_Skew() {
+	at := time.Unix(59, 0)
+
+	_, ok := ValidateTOTPCode(s.testSecret, "287082", at.Add(TOTPPeriod))
+	assert.True(s.T(), ok)
+
+	_, ok = ValidateTOTPCode(s.testSecret, "287082", at.Add(3*TOTPPeriod))
+	assert.False(s.T(), ok)
+}
+
+func (s *totpSuite) TestValidateTOTPCode_Invalid() {
+	at := time.Unix(59, 0)
+
+	_, ok := ValidateTOTPCode(s.testSecret, "000000", at)
+	assert.False(s.T(), ok)
+
+	_, ok = ValidateTOTPCode(s.testSecret, "28708", at)
+	assert.False(s.T(), ok)
+
+	_, ok = ValidateTOTPCode("not base32!", "287082", at)
+	assert.False(s.T(), ok)
+}
+
+func (s *totpSuite) TestGenerateTOTPSecret() {
+	secret, err := GenerateTOTPSecret()
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), secret, 32)
+
+	otherSecret, _ := GenerateTOTPSecret()
+	assert.NotEqual(s.T(), secret, otherSecret)
+}
+
+func (s *totpSuite) TestTOTPProvisioningURI() {
+	uri, err := url.Parse(TOTPProvisioningURI(s.testSecret, "test_email@gochat.com"))
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "otpauth", uri.Scheme)
+	assert.Equal(s.T(), "totp", uri.Host)
+	assert.Equal(s.T(), "/Gochat:test_email@gochat.com", uri.Path)
+	assert.Equal(s.T(), s.testSecret, uri.Query().Get("secret"))
+	assert.Equal(s.T(), "Gochat", uri.Query().Get("issuer"))
+	assert.Equal(s.T(), "6", uri.Query().Get("digits"))
+	assert.Equal(s.T(), "30", uri.Query().Get("period"))
+}
+
+func (s *totpSuite) TestVerifyTOTPCode() {
+	secret, _ := GenerateTOTPSecret()
+	key, _ := totpEncoding.DecodeString(secret)
+	code := totpCode(key, time.Now().Unix()/30)
+
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"SetNX",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("1")).Once()
+
+	s.authManager.cache = cacheMock
+
+	assert.Nil(s.T(), s.authManager.VerifyTOTPCode(s.testUserID, secret, code))
+
+	// Used code is remembered, therefore it cannot be used again.
+	cacheMock.On(
+		"SetNX",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetValueCacheResponse("0"))
+
+	assert.Equal(s.T(), ErrTwoFactorCodeInvalid, s.authManager.VerifyTOTPCode(s.testUserID, secret, code))
+	cacheMock.AssertNumberOfCalls(s.T(), "SetNX", 2)
+	cacheMock.AssertNotCalled(s.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func (s *totpSuite) TestGenerateRecoveryCodes() {
+	codes, hashes, err := GenerateRecoveryCodes()
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), codes, RecoveryCodesCount)
+	assert.Len(s.T(), hashes, RecoveryCodesCount)
+
+	for i, code := range codes {
+		assert.Regexp(s.T(), "^[a-z2-9]{5}-[a-z2-9]{5}$", code)
+		assert.Equal(s.T(), hashes[i], HashRecoveryCode(code))
+	}
+}
+
+func (s *totpSuite) TestHashRecoveryCode_Normalization() {
+	hash := HashRecoveryCode("abcde-fghjk")
+
+	assert.Equal(s.T(), hash, HashRecoveryCode(" ABCDE FGHJK "))
+	assert.Equal(s.T(), hash, HashRecoveryCode("abcdefghjk"))
+	assert.NotEqual(s.T(), hash, HashRecoveryCode("abcde-fghjm"))
+}
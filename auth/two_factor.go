@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// LoginChallengeTTL - lifetime of login challenge tokens.
+	LoginChallengeTTL = 5 * time.Minute
+
+	// RecoveryCodesCount - number of recovery codes generated for a user.
+	RecoveryCodesCount = 10
+
+	// Prefix of the cache keys holding hashes of login challenge tokens.
+	loginChallengeKeyPrefix = "login-challenge:"
+
+	// Prefix of the cache keys holding already used TOTP codes.
+	usedTOTPCodeKeyPrefix = "totp:used:"
+
+	// Alphabet recovery codes are made of - ambiguous characters are omitted.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// Number of characters in each of two groups recovery code is made of.
+	recoveryCodeGroupSize = 5
+)
+
+var (
+	// ErrLoginChallengeInvalid - returned when login challenge token does not exist,
+	// has expired or has already been used.
+	ErrLoginChallengeInvalid = errors.New("Login challenge is invalid or expired, please log in again.")
+
+	// ErrTwoFactorCodeInvalid - returned when TOTP or recovery code is incorrect
+	// or has already been used.
+	ErrTwoFactorCodeInvalid = errors.New("Two-factor authentication code is invalid.")
+)
+
+// CreateLoginChallenge - returns new, single-use token, which has to be exchanged
+// together with second factor to finish given user's login.
+func (am *AuthManager) CreateLoginChallenge(userID uuid.UUID) (string, error) {
+	return am.createOneTimeToken(loginChallengeKeyPrefix, userID, LoginChallengeTTL)
+}
+
+// ConsumeLoginChallenge - invalidates given login challenge token, and returns
+// the ID of the user it has been created for.
+func (am *AuthManager) ConsumeLoginChallenge(token string) (uuid.UUID, error) {
+	userID, err := am.consumeOneTimeToken(loginChallengeKeyPrefix, token)
+
+	if err == errOneTimeTokenInvalid {
+		return uuid.Nil, ErrLoginChallengeInvalid
+	}
+
+	return userID, err
+}
+
+// VerifyTOTPCode - checks given user's TOTP code. Each code can be used only once.
+func (am *AuthManager) VerifyTOTPCode(userID uuid.UUID, secret, code string) error {
+	step, ok := ValidateTOTPCode(secret, code, time.Now())
+
+	if !ok {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	key := fmt.Sprintf("%s%s:%d", usedTOTPCodeKeyPrefix, userID.String(), step)
+
+	// Code stays valid for the skew window at most, so it's enough to remember it that long.
+	ttl := time.Duration(2*totpSkew+1) * TOTPPeriod
+
+	// Code is marked as used atomically, so only one of concurrent requests can use it.
+	res := am.cache.SetNX(am.ctx, key, "1", ttl)
+
+	if res.Err() != nil {
+		return res.Err()
+	}
+
+	if res.Val() != "1" {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+// MarkSessionTwoFactorVerified - marks given session as authenticated with second factor.
+func (am *AuthManager) MarkSessionTwoFactorVerified(authUUID uuid.UUID) error {
+	session, err := am.GetSession(authUUID)
+
+	if err != nil {
+		return err
+	}
+
+	session.TwoFactorVerified = true
+
+	return am.saveSession(session)
+}
+
+// GenerateRecoveryCodes - returns new recovery codes, together with their hashes.
+func GenerateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, RecoveryCodesCount)
+	hashes := make([]string, RecoveryCodesCount)
+
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	for i := range codes {
+		random := make([]byte, 2*recoveryCodeGroupSize)
+
+		// Characters are drawn uniformly - mapping random bytes with modulo would make
+		// some of them more likely than others.
+		for j := range random {
+			n, err := rand.Int(rand.Reader, alphabetSize)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			random[j] = recoveryCodeAlphabet[n.Int64()]
+		}
+
+		codes[i] = string(random[:recoveryCodeGroupSize]) + "-" + string(random[recoveryCodeGroupSize:])
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode - returns the hash recovery code is stored as. Codes are compared
+// case-insensitively, ignoring separators and whitespaces.
+func HashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+
+	return hashToken(normalized)
+}
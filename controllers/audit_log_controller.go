@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditLogController - struct for handling audit log related requests.
+type AuditLogController struct {
+	auditLogService *services.AuditLogService
+}
+
+// NewAuditLogController - AuditLogController constructor func.
+func NewAuditLogController() *AuditLogController {
+	return &AuditLogController{
+		auditLogService: services.NewAuditLogService(),
+	}
+}
+
+// GetAuditLog - returns single page of audit log entries matching query params,
+// from the latest one by default.
+func (ac *AuditLogController) GetAuditLog(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var query schema.AuditLogQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	entries, nextCursor, err := ac.auditLogService.GetEntries(
+		getAuditLogFilter(&query),
+		query.ToPageRequest(persist.SortDescending),
+	)
+
+	if err == persist.ErrInvalidCursor {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	entryResponses := []schema.AuditLogEntryResponse{}
+
+	for _, entryModel := range entries {
+		entryResponse := schema.AuditLogEntryResponse{}
+
+		if err := entryResponse.FromModel(entryModel); err != nil {
+			return nil, api.NewInternalError(err)
+		}
+
+		entryResponses = append(entryResponses, entryResponse)
+	}
+
+	return schema.NewPageResponse(entryResponses, nextCursor), nil
+}
+
+// ExportAuditLog - writes all the audit log entries matching query params as JSON lines,
+// from the oldest one. Pagination params are ignored.
+func (ac *AuditLogController) ExportAuditLog(ctx *gin.Context, contextUser *control.ContextUser) *api.APIError {
+	var query schema.AuditLogQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	encoder := json.NewEncoder(ctx.Writer)
+	started := false
+
+	// Response is started with the first entry, so failure of loading it can be
+	// still returned as an error.
+	start := func() {
+		ctx.Header("Content-Type", "application/x-ndjson")
+		ctx.Header("Content-Disposition", `attachment; filename="audit-log.jsonl"`)
+		ctx.Status(http.StatusOK)
+		ctx.Writer.WriteHeaderNow()
+
+		started = true
+	}
+
+	err := ac.auditLogService.ExportEntries(getAuditLogFilter(&query), func(entryModel *models.AuditLogModel) error {
+		if !started {
+			start()
+		}
+
+		entryResponse := schema.AuditLogEntryResponse{}
+
+		if err := entryResponse.FromModel(entryModel); err != nil {
+			return err
+		}
+
+		// Encoder terminates every entry with a newline.
+		return encoder.Encode(&entryResponse)
+	})
+
+	if err != nil && !started {
+		return api.NewInternalError(err)
+	}
+
+	// Once the export has started, its status cannot be changed anymore.
+	if err != nil {
+		log.Printf("Exporting audit log failed: %v", err)
+	}
+
+	if !started {
+		start()
+	}
+
+	return nil
+}
+
+// getAuditLogFilter - returns AuditLogFilter matching given query params.
+func getAuditLogFilter(query *schema.AuditLogQuery) *services.AuditLogFilter {
+	filter := &services.AuditLogFilter{
+		ActorEmail:   query.ActorEmail,
+		ActionPrefix: query.Action,
+		TargetType:   query.TargetType,
+		TargetID:     query.TargetID,
+		Result:       query.Result,
+		IP:           query.IP,
+	}
+
+	if actorID, err := uuid.Parse(query.ActorID); err == nil {
+		filter.ActorID = &actorID
+	}
+
+	if !query.From.IsZero() {
+		filter.From = &query.From
+	}
+
+	if !query.To.IsZero() {
+		filter.To = &query.To
+	}
+
+	return filter
+}
@@ -2,21 +2,29 @@ package controllers
 
 import (
 	"errors"
+	"log"
+	"strconv"
 
+	"github.com/el-Mike/gochat/audit"
 	"github.com/el-Mike/gochat/auth"
 	"github.com/el-Mike/gochat/core/api"
 	"github.com/el-Mike/gochat/core/control"
-
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/realtime"
 	"github.com/el-Mike/gochat/schema"
 	"github.com/el-Mike/gochat/services"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // AuthController - struct for handling auth related requests.
 type AuthController struct {
 	authService *services.AuthService
 	userService *services.UserService
-	authManager *auth.AuthManager
+	oidcService *services.OIDCService
+	broadcaster *realtime.Broadcaster
+	cookies     *auth.CookieSessions
+	auditLogger *audit.Logger
 }
 
 // NewAuthController - AuthController constructor func.
@@ -24,7 +32,10 @@ func NewAuthController() *AuthController {
 	return &AuthController{
 		authService: services.NewAuthService(),
 		userService: services.NewUserService(),
-		authManager: auth.NewAuthManager(),
+		oidcService: services.NewOIDCService(),
+		broadcaster: realtime.DefaultBroadcaster,
+		cookies:     auth.DefaultCookieSessions,
+		auditLogger: audit.NewLogger(),
 	}
 }
 
@@ -36,34 +47,155 @@ func (ac *AuthController) Login(ctx *gin.Context) (interface{}, *api.APIError) {
 		return nil, api.NewBadRequestError(err)
 	}
 
-	userModel, err := ac.userService.GetUserByEmail(credentials.Email)
+	userModel, err := ac.authService.Authenticate(credentials.Email, credentials.Password, control.GetClientInfo(ctx))
+
+	if lockedErr, ok := err.(*auth.AccountLockedError); ok {
+		return nil, ac.recordLoginFailure(ctx, credentials.Email, newAccountLockedError(ctx, lockedErr))
+	}
+
+	if err == auth.ErrLoginCredentialsIncorrect {
+		return nil, ac.recordLoginFailure(ctx, credentials.Email, api.NewLoginCredentialsIncorrectError())
+	}
 
 	if err != nil {
-		return nil, api.NewLoginCredentialsIncorrectError()
+		return nil, api.NewInternalError(err)
 	}
 
-	err = ac.authManager.ComparePasswords(userModel.Password, []byte(credentials.Password))
+	return ac.login(ctx, userModel)
+}
+
+// StartOIDCLogin - starts OpenID Connect login, returning identity provider's URL
+// the user should be redirected to.
+func (ac *AuthController) StartOIDCLogin(ctx *gin.Context) (interface{}, *api.APIError) {
+	authorizationURL, err := ac.oidcService.StartLogin()
+
+	if err == auth.ErrOIDCNotConfigured {
+		return nil, api.NewOIDCNotConfiguredError()
+	}
 
 	if err != nil {
-		return nil, api.NewLoginCredentialsIncorrectError()
+		return nil, api.NewInternalError(err)
+	}
+
+	return &schema.OIDCLoginResponse{
+		AuthorizationURL: authorizationURL,
+	}, nil
+}
+
+// FinishOIDCLogin - finishes OpenID Connect login with the params identity provider
+// redirected the user back with, and logs the user in.
+func (ac *AuthController) FinishOIDCLogin(ctx *gin.Context) (interface{}, *api.APIError) {
+	var payload schema.OIDCCallbackPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	userModel, err := ac.oidcService.FinishLogin(payload.Code, payload.State)
+
+	if err == auth.ErrOIDCNotConfigured {
+		return nil, api.NewOIDCNotConfiguredError()
+	}
+
+	if err == auth.ErrOIDCStateInvalid {
+		return nil, api.NewOIDCStateInvalidError()
+	}
+
+	if err == auth.ErrOIDCCodeInvalid || err == auth.ErrOIDCTokenInvalid {
+		return nil, ac.recordLoginFailure(ctx, "", api.NewOIDCLoginFailedError())
 	}
 
-	token, err := ac.authService.Login(userModel)
+	if err == services.ErrExternalEmailNotVerified {
+		return nil, ac.recordLoginFailure(ctx, "", api.NewOIDCEmailNotVerifiedError())
+	}
 
 	if err != nil {
 		return nil, api.NewInternalError(err)
 	}
 
-	loginResponse := &schema.LoginResponse{}
-	err = loginResponse.FromModel(userModel)
+	return ac.login(ctx, userModel)
+}
+
+// LoginWithTwoFactor - exchanges login challenge token and TOTP or recovery code for tokens.
+func (ac *AuthController) LoginWithTwoFactor(ctx *gin.Context) (interface{}, *api.APIError) {
+	var payload schema.TwoFactorLoginPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	userModel, tokens, err := ac.authService.LoginWithTwoFactor(
+		payload.ChallengeToken,
+		payload.Code,
+		control.GetClientInfo(ctx),
+	)
+
+	// Challenge's user is not returned on failure, so failures are recorded without the email.
+	if lockedErr, ok := err.(*auth.AccountLockedError); ok {
+		return nil, ac.recordLoginFailure(ctx, "", newAccountLockedError(ctx, lockedErr))
+	}
+
+	if err == auth.ErrLoginChallengeInvalid {
+		return nil, ac.recordLoginFailure(ctx, "", api.NewLoginChallengeInvalidError())
+	}
+
+	if err == auth.ErrTwoFactorCodeInvalid {
+		return nil, ac.recordLoginFailure(ctx, "", api.NewTwoFactorCodeInvalidError())
+	}
+
+	if err == auth.ErrEmailNotVerified {
+		return nil, ac.recordLoginFailure(ctx, "", api.NewEmailNotVerifiedError())
+	}
 
 	if err != nil {
 		return nil, api.NewInternalError(err)
 	}
 
-	loginResponse.Token = token
+	return ac.newLoginResponse(ctx, userModel, tokens)
+}
 
-	return loginResponse, nil
+// Refresh - rotates given refresh token and returns new tokens. With cookie sessions,
+// refresh token is read from the cookie, if it's present.
+func (ac *AuthController) Refresh(ctx *gin.Context) (interface{}, *api.APIError) {
+	refreshToken, apiErr := ac.getRefreshToken(ctx)
+
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	tokens, err := ac.authService.Refresh(refreshToken)
+
+	if reusedErr, ok := err.(*auth.RefreshTokenReusedError); ok {
+		// Session has been revoked, therefore its real-time connections should be closed as well.
+		ac.closeRealtimeSessions(reusedErr.AuthUUID)
+
+		apiErr := api.NewRefreshTokenReusedError()
+
+		entry := control.NewAuditEntry(ctx, nil, audit.RefreshAction, models.AuditFailureResult)
+		entry.TargetType = auth.SESSION_RESOURCE
+		entry.TargetID = reusedErr.AuthUUID.String()
+		entry.Details = apiErr.Message
+
+		ac.auditLogger.Log(entry)
+
+		return nil, apiErr
+	}
+
+	if err == auth.ErrRefreshTokenInvalid {
+		return nil, api.NewRefreshTokenInvalidError()
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	refreshResponse := &schema.RefreshResponse{}
+
+	if err := ac.setSessionTokens(ctx, &refreshResponse.SessionTokensResponse, tokens); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return refreshResponse, nil
 }
 
 // Logout - logs out a user.
@@ -72,6 +204,203 @@ func (ac *AuthController) Logout(ctx *gin.Context, contextUser *control.ContextU
 		return nil, api.NewInternalError(err)
 	}
 
+	if ac.cookies != nil {
+		ac.cookies.Clear(ctx.Writer)
+	}
+
+	// Real-time connections opened with logged out token should not receive
+	// any more events.
+	ac.closeRealtimeSessions(contextUser.AuthUUID)
+
+	ac.record(ctx, contextUser, audit.LogoutAction, auth.SESSION_RESOURCE, contextUser.AuthUUID)
+
+	return nil, nil
+}
+
+// GetSessions - returns active sessions of current user, or of the user
+// with ID passed in route params.
+func (ac *AuthController) GetSessions(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	userID, apiErr := ac.getSessionsOwnerID(ctx, contextUser)
+
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	sessions, err := ac.authService.GetSessions(userID)
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	sessionResponses := []schema.SessionResponse{}
+
+	for _, session := range sessions {
+		sessionResponse := schema.SessionResponse{}
+
+		if err := sessionResponse.FromModel(session); err != nil {
+			return nil, api.NewInternalError(err)
+		}
+
+		sessionResponse.Current = session.ID == contextUser.AuthUUID
+
+		sessionResponses = append(sessionResponses, sessionResponse)
+	}
+
+	return sessionResponses, nil
+}
+
+// RevokeSession - closes a session with ID passed in route params. Session has to belong
+// to current user, or to the user with ID passed in route params.
+func (ac *AuthController) RevokeSession(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	userID, apiErr := ac.getSessionsOwnerID(ctx, contextUser)
+
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	sessionID, err := uuid.Parse(ctx.Param("sessionId"))
+
+	if sessionID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("Session ID is missing or malformed."))
+	}
+
+	err = ac.authService.RevokeSession(userID, sessionID)
+
+	if err == auth.ErrSessionNotFound {
+		return nil, api.NewNotFoundError(auth.SESSION_RESOURCE)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	ac.closeRealtimeSessions(sessionID)
+
+	ac.record(ctx, contextUser, audit.SessionRevokeAction, auth.SESSION_RESOURCE, sessionID)
+
+	return nil, nil
+}
+
+// RevokeSessions - logs out current user, or the user with ID passed
+// in route params, from all the sessions.
+func (ac *AuthController) RevokeSessions(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	userID, apiErr := ac.getSessionsOwnerID(ctx, contextUser)
+
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	sessionIDs, err := ac.authService.RevokeSessions(userID)
+
+	// Sessions closed before the failure should not receive any more events either.
+	ac.closeRealtimeSessions(sessionIDs...)
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	ac.record(ctx, contextUser, audit.SessionsRevokeAction, models.USER_RESOURCE, userID)
+
+	return nil, nil
+}
+
+// ForgotPassword - sends password reset token to given email, if it belongs to a user.
+func (ac *AuthController) ForgotPassword(ctx *gin.Context) (interface{}, *api.APIError) {
+	var payload schema.ForgotPasswordPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err := ac.authService.ForgotPassword(payload.Email); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return nil, nil
+}
+
+// ResetPassword - sets new password using password reset token. User is logged out
+// from all the sessions.
+func (ac *AuthController) ResetPassword(ctx *gin.Context) (interface{}, *api.APIError) {
+	var payload schema.ResetPasswordPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if !schema.ValidateResetPasswordConfirmation(&payload) {
+		return nil, api.NewBadRequestError(errors.New("Passwords don't match."))
+	}
+
+	sessionIDs, err := ac.authService.ResetPassword(payload.Token, payload.Password)
+
+	ac.closeRealtimeSessions(sessionIDs...)
+
+	if policyErr, ok := err.(*auth.PasswordPolicyError); ok {
+		return nil, newPasswordPolicyError(policyErr)
+	}
+
+	if err == auth.ErrPasswordResetTokenInvalid {
+		apiErr := api.NewPasswordResetTokenInvalidError()
+
+		entry := control.NewAuditEntry(ctx, nil, audit.PasswordResetAction, models.AuditFailureResult)
+		entry.Details = apiErr.Message
+
+		ac.auditLogger.Log(entry)
+
+		return nil, apiErr
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	ac.auditLogger.Log(control.NewAuditEntry(ctx, nil, audit.PasswordResetAction, models.AuditSuccessResult))
+
+	return nil, nil
+}
+
+// ChangePassword - sets new password of current user. User is logged out
+// from all the other sessions.
+func (ac *AuthController) ChangePassword(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.ChangePasswordPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if !schema.ValidateChangePasswordConfirmation(&payload) {
+		return nil, api.NewBadRequestError(errors.New("Passwords don't match."))
+	}
+
+	sessionIDs, err := ac.authService.ChangePassword(
+		contextUser.ID,
+		contextUser.AuthUUID,
+		payload.CurrentPassword,
+		payload.Password,
+		control.GetClientInfo(ctx),
+	)
+
+	ac.closeRealtimeSessions(sessionIDs...)
+
+	if policyErr, ok := err.(*auth.PasswordPolicyError); ok {
+		return nil, newPasswordPolicyError(policyErr)
+	}
+
+	if lockedErr, ok := err.(*auth.AccountLockedError); ok {
+		return nil, ac.recordPasswordChangeFailure(ctx, contextUser, newAccountLockedError(ctx, lockedErr))
+	}
+
+	if err == services.ErrCurrentPasswordIncorrect {
+		return nil, ac.recordPasswordChangeFailure(ctx, contextUser, api.NewCurrentPasswordIncorrectError())
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	ac.record(ctx, contextUser, audit.PasswordChangeAction, models.USER_RESOURCE, contextUser.ID)
+
 	return nil, nil
 }
 
@@ -93,10 +422,22 @@ func (ac *AuthController) SignUp(ctx *gin.Context) (interface{}, *api.APIError)
 
 	userModel, err := ac.authService.SignUp(credentials)
 
+	if policyErr, ok := err.(*auth.PasswordPolicyError); ok {
+		return nil, newPasswordPolicyError(policyErr)
+	}
+
 	if err != nil {
 		return nil, api.NewInternalError(err)
 	}
 
+	entry := control.NewAuditEntry(ctx, nil, audit.SignUpAction, models.AuditSuccessResult)
+	entry.ActorID = userModel.ID
+	entry.ActorEmail = userModel.Email
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = userModel.ID.String()
+
+	ac.auditLogger.Log(entry)
+
 	userResponse := &schema.UserResponse{}
 	err = userResponse.FromModel(userModel)
 
@@ -106,3 +447,283 @@ func (ac *AuthController) SignUp(ctx *gin.Context) (interface{}, *api.APIError)
 
 	return userResponse, nil
 }
+
+// UnlockAccount - resets failed login attempts of the user with ID passed in route params,
+// unlocking their account. The user has to be a member of current Workspace.
+func (ac *AuthController) UnlockAccount(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	userID, apiErr := ac.getWorkspaceUserID(ctx, contextUser)
+
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if err := ac.authService.UnlockAccount(userID); err != nil {
+		return nil, api.NewNotFoundError(models.USER_RESOURCE)
+	}
+
+	ac.record(ctx, contextUser, audit.AccountUnlockAction, models.USER_RESOURCE, userID)
+
+	return nil, nil
+}
+
+// VerifyEmail - marks user's email as verified using email verification token.
+func (ac *AuthController) VerifyEmail(ctx *gin.Context) (interface{}, *api.APIError) {
+	var query schema.VerifyEmailQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	userModel, err := ac.authService.VerifyEmail(query.Token)
+
+	if err == auth.ErrEmailVerificationTokenInvalid {
+		return nil, api.NewEmailVerificationTokenInvalidError()
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	userResponse := &schema.UserResponse{}
+
+	if err := userResponse.FromModel(userModel); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return userResponse, nil
+}
+
+// ResendVerification - sends new email verification token to given email,
+// if it belongs to a user who has not verified it yet.
+func (ac *AuthController) ResendVerification(ctx *gin.Context) (interface{}, *api.APIError) {
+	var payload schema.ResendVerificationPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err := ac.authService.ResendVerificationEmail(payload.Email); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return nil, nil
+}
+
+// createLoginChallenge - starts two-factor login of given user.
+func (ac *AuthController) createLoginChallenge(ctx *gin.Context, userModel *models.UserModel) (interface{}, *api.APIError) {
+	challengeToken, err := ac.authService.CreateLoginChallenge(userModel)
+
+	if err == auth.ErrEmailNotVerified {
+		return nil, ac.recordLoginFailure(ctx, userModel.Email, api.NewEmailNotVerifiedError())
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return &schema.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		ExpiresIn:         int(auth.LoginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// login - starts a session of authenticated user, or login challenge if user
+// has two-factor authentication enabled.
+func (ac *AuthController) login(ctx *gin.Context, userModel *models.UserModel) (interface{}, *api.APIError) {
+	// Users with two-factor authentication enabled receive tokens only
+	// after their second factor is verified.
+	if userModel.TOTPEnabled {
+		return ac.createLoginChallenge(ctx, userModel)
+	}
+
+	tokens, err := ac.authService.Login(userModel, control.GetClientInfo(ctx))
+
+	if err == auth.ErrEmailNotVerified {
+		return nil, ac.recordLoginFailure(ctx, userModel.Email, api.NewEmailNotVerifiedError())
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return ac.newLoginResponse(ctx, userModel, tokens)
+}
+
+// record - records successful action of current user, performed on given target, in the audit log.
+func (ac *AuthController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	targetType string,
+	targetID uuid.UUID,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = targetType
+	entry.TargetID = targetID.String()
+
+	ac.auditLogger.Log(entry)
+}
+
+// recordLoginFailure - records failed login of the user with given email (empty if it's
+// unknown) in the audit log. Returns given APIError, describing the failure.
+func (ac *AuthController) recordLoginFailure(ctx *gin.Context, email string, apiErr *api.APIError) *api.APIError {
+	entry := control.NewAuditEntry(ctx, nil, audit.LoginAction, models.AuditFailureResult)
+	entry.ActorEmail = email
+	entry.Details = apiErr.Message
+
+	ac.auditLogger.Log(entry)
+
+	return apiErr
+}
+
+// closeRealtimeSessions - closes real-time connections opened within given sessions.
+func (ac *AuthController) closeRealtimeSessions(authUUIDs ...uuid.UUID) {
+	for _, authUUID := range authUUIDs {
+		if err := ac.broadcaster.CloseSession(authUUID); err != nil {
+			log.Printf("Closing real-time connections failed: %v", err)
+		}
+	}
+}
+
+// recordPasswordChangeFailure - records failed password change of current user in the audit log.
+// Returns given APIError, describing the failure.
+func (ac *AuthController) recordPasswordChangeFailure(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	apiErr *api.APIError,
+) *api.APIError {
+	entry := control.NewAuditEntry(ctx, contextUser, audit.PasswordChangeAction, models.AuditFailureResult)
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = contextUser.ID.String()
+	entry.Details = apiErr.Message
+
+	ac.auditLogger.Log(entry)
+
+	return apiErr
+}
+
+// newAccountLockedError - returns APIError for locked login, setting Retry-After header as well.
+func newAccountLockedError(ctx *gin.Context, lockedErr *auth.AccountLockedError) *api.APIError {
+	retryAfter := lockedErr.RetryAfterSeconds()
+
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+
+	return api.NewAccountLockedError(retryAfter)
+}
+
+// newPasswordPolicyError - returns APIError describing password policy violations
+// as errors of "password" field.
+func newPasswordPolicyError(policyErr *auth.PasswordPolicyError) *api.APIError {
+	fields := []*api.FieldError{}
+
+	for _, violation := range policyErr.Violations {
+		fields = append(fields, &api.FieldError{
+			Field:   "password",
+			Code:    violation.Code,
+			Message: violation.Message,
+		})
+	}
+
+	return api.NewPasswordPolicyError(fields)
+}
+
+// newLoginResponse - returns login response of given user and issued tokens.
+func (ac *AuthController) newLoginResponse(
+	ctx *gin.Context,
+	userModel *models.UserModel,
+	tokens *auth.TokenPair,
+) (interface{}, *api.APIError) {
+	loginResponse := &schema.LoginResponse{}
+
+	if err := loginResponse.FromModel(userModel); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	if err := ac.setSessionTokens(ctx, &loginResponse.SessionTokensResponse, tokens); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	entry := control.NewAuditEntry(ctx, nil, audit.LoginAction, models.AuditSuccessResult)
+	entry.ActorID = userModel.ID
+	entry.ActorEmail = userModel.Email
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = userModel.ID.String()
+
+	ac.auditLogger.Log(entry)
+
+	return loginResponse, nil
+}
+
+// setSessionTokens - sets given tokens in the response. With cookie sessions, they are
+// set as cookies, so scripts cannot read them - only CSRF token is returned in the body.
+func (ac *AuthController) setSessionTokens(
+	ctx *gin.Context,
+	tokensResponse *schema.SessionTokensResponse,
+	tokens *auth.TokenPair,
+) error {
+	if ac.cookies == nil {
+		tokensResponse.Token = tokens.AccessToken
+		tokensResponse.RefreshToken = tokens.RefreshToken
+
+		return nil
+	}
+
+	csrfToken, err := ac.cookies.SetTokens(ctx.Writer, tokens)
+
+	if err != nil {
+		return err
+	}
+
+	tokensResponse.CSRFToken = csrfToken
+
+	return nil
+}
+
+// getRefreshToken - returns refresh token sent in the cookie, or in request's payload.
+// Refreshing with the cookie requires CSRF token, as any other unsafe request does.
+func (ac *AuthController) getRefreshToken(ctx *gin.Context) (string, *api.APIError) {
+	if ac.cookies != nil {
+		if refreshToken := ac.cookies.RefreshToken(ctx.Request); refreshToken != "" {
+			if err := ac.cookies.VerifyCSRFToken(ctx.Request); err != nil {
+				return "", api.NewCSRFTokenInvalidError()
+			}
+
+			return refreshToken, nil
+		}
+	}
+
+	var payload schema.RefreshPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return "", api.NewBadRequestError(err)
+	}
+
+	return payload.RefreshToken, nil
+}
+
+// getSessionsOwnerID - returns the ID of the user passed in route params,
+// or current user's ID if there is none.
+func (ac *AuthController) getSessionsOwnerID(ctx *gin.Context, contextUser *control.ContextUser) (uuid.UUID, *api.APIError) {
+	if ctx.Param("id") == "" {
+		return contextUser.ID, nil
+	}
+
+	return ac.getWorkspaceUserID(ctx, contextUser)
+}
+
+// getWorkspaceUserID - returns the ID of the user passed in route params. Users who are not
+// members of current Workspace are reported as not found, so other tenants cannot be managed.
+func (ac *AuthController) getWorkspaceUserID(ctx *gin.Context, contextUser *control.ContextUser) (uuid.UUID, *api.APIError) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+
+	if userID == uuid.Nil || err != nil {
+		return uuid.Nil, api.NewBadRequestError(errors.New("User ID is missing or malformed."))
+	}
+
+	if _, err := ac.userService.InWorkspace(contextUser.WorkspaceID).GetUserByID(userID); err != nil {
+		return uuid.Nil, api.NewNotFoundError(models.USER_RESOURCE)
+	}
+
+	return userID, nil
+}
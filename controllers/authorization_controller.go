@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorizationController - struct for handling authorization debugging related requests.
+type AuthorizationController struct {
+	authorizationService *services.AuthorizationService
+}
+
+// NewAuthorizationController - AuthorizationController constructor func.
+func NewAuthorizationController() *AuthorizationController {
+	return &AuthorizationController{
+		authorizationService: services.NewAuthorizationService(),
+	}
+}
+
+// Explain - explains whether the subject passed in the payload can perform given action
+// on given resource, and why.
+func (ac *AuthorizationController) Explain(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	var payload schema.ExplainAuthorizationPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	explanation, subject, err := ac.authorizationService.Explain(
+		&services.ExplainSubject{
+			UserID:        payload.UserID,
+			Role:          payload.Role,
+			WorkspaceID:   payload.WorkspaceID,
+			WorkspaceRole: payload.WorkspaceRole,
+		},
+		payload.Resource,
+		payload.ResourceID,
+		payload.Action,
+	)
+
+	if err != nil {
+		switch err {
+		case services.ErrExplainSubjectInvalid,
+			services.ErrExplainWorkspaceRoleInvalid,
+			services.ErrExplainWorkspaceMismatch,
+			services.ErrExplainResourceNotSupported:
+			return nil, api.NewBadRequestError(err)
+		case services.ErrExplainUserNotFound:
+			return nil, api.NewNotFoundError(models.USER_RESOURCE)
+		case services.ErrExplainWorkspaceNotFound:
+			return nil, api.NewNotFoundError(models.WORKSPACE_RESOURCE)
+		case services.ErrExplainResourceNotFound:
+			return nil, api.NewNotFoundError(payload.Resource)
+		default:
+			return nil, api.NewInternalError(err)
+		}
+	}
+
+	return &schema.AuthorizationExplanationResponse{
+		Subject: &schema.AuthorizationSubjectResponse{
+			UserID:        payload.UserID,
+			Email:         subject.Email,
+			Role:          subject.Role,
+			WorkspaceID:   subject.WorkspaceID,
+			WorkspaceRole: subject.WorkspaceRole,
+		},
+		Resource:                 payload.Resource,
+		ResourceID:               payload.ResourceID,
+		Action:                   payload.Action,
+		AuthorizationExplanation: explanation,
+	}, nil
+}
@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/realtime"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/el-Mike/restrict"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// conversationContextKey - defines the key Conversation loaded by ResourceProvider
+// will be saved under in current context.
+const conversationContextKey = "conversation"
+
+// ConversationController - struct for handling Conversations related requests.
+type ConversationController struct {
+	conversationService *services.ConversationService
+	publisher           realtime.Publisher
+	auditLogger         *audit.Logger
+}
+
+// NewConversationController - ConversationController constructor func.
+func NewConversationController() *ConversationController {
+	return &ConversationController{
+		conversationService: services.NewConversationService(),
+		publisher:           realtime.DefaultBroadcaster,
+		auditLogger:         audit.NewLogger(),
+	}
+}
+
+// GetConversationResource - AccessRule's ResourceProvider, loading Conversation
+// with ID passed in route params.
+func (cc *ConversationController) GetConversationResource(ctx *gin.Context, contextUser *control.ContextUser) restrict.Resource {
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil
+	}
+
+	return conversation
+}
+
+// GetConversations - returns all the Conversations current user participates in.
+func (cc *ConversationController) GetConversations(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	conversations, err := cc.conversations(contextUser).GetConversationsByUserID(contextUser.ID)
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	conversationResponses := []schema.ConversationResponse{}
+
+	for _, conversationModel := range conversations {
+		conversationResponse := schema.ConversationResponse{}
+
+		if err := conversationResponse.FromModel(conversationModel); err != nil {
+			return nil, api.NewInternalError(err)
+		}
+
+		conversationResponses = append(conversationResponses, conversationResponse)
+	}
+
+	return conversationResponses, nil
+}
+
+// GetConversation - returns single Conversation with given ID.
+func (cc *ConversationController) GetConversation(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	conversationResponse := schema.ConversationResponse{}
+
+	if err := conversationResponse.FromModel(conversation); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return conversationResponse, nil
+}
+
+// CreateConversation - creates a new Conversation between current user and given participants.
+func (cc *ConversationController) CreateConversation(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.ConversationPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	conversation, err := cc.conversations(contextUser).CreateConversation(contextUser.ID, payload.Name, payload.Participants)
+
+	if err == services.ErrParticipantsNotFound {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	conversationResponse := schema.ConversationResponse{}
+
+	if err := conversationResponse.FromModel(conversation); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	cc.publish(realtime.ConversationCreatedEvent, conversation.ID, conversation.GetParticipantIDs(), conversationResponse)
+
+	return conversationResponse, nil
+}
+
+// DeleteConversation - deletes a Conversation with given ID.
+func (cc *ConversationController) DeleteConversation(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	if err := cc.conversations(contextUser).DeleteConversationByID(conversation.ID); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	conversationResponse := schema.ConversationResponse{}
+
+	if err := conversationResponse.FromModel(conversation); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	cc.publish(realtime.ConversationDeletedEvent, conversation.ID, conversation.GetParticipantIDs(), conversationResponse)
+
+	return nil, nil
+}
+
+// AddParticipants - adds given users to the Conversation.
+func (cc *ConversationController) AddParticipants(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.ParticipantsPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	err = cc.conversations(contextUser).AddParticipants(conversation, payload.Participants, contextUser.ID)
+
+	if err == services.ErrParticipantsNotFound {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	conversationResponse := schema.ConversationResponse{}
+
+	if err := conversationResponse.FromModel(conversation); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	cc.publish(realtime.ParticipantsAddedEvent, conversation.ID, conversation.GetParticipantIDs(), conversationResponse)
+
+	return conversationResponse, nil
+}
+
+// RemoveParticipant - removes a user with given ID from the Conversation.
+func (cc *ConversationController) RemoveParticipant(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+
+	if userID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("User ID is missing or malformed."))
+	}
+
+	participant := conversation.GetParticipant(userID)
+
+	if participant == nil {
+		return nil, api.NewNotFoundError(models.USER_RESOURCE)
+	}
+
+	if participant.Role == models.ConversationOwnerRole {
+		return nil, api.NewBadRequestError(errors.New("Conversation's owner cannot be removed."))
+	}
+
+	// Moderators can remove members only - other moderators can be removed by the owner.
+	if participant.Role == models.ConversationModeratorRole &&
+		conversation.GetParticipantRole(contextUser.ID) != models.ConversationOwnerRole {
+		return nil, api.NewAccessDeniedError(models.CONVERSATION_RESOURCE, control.ManageRolesAction)
+	}
+
+	if err := cc.conversations(contextUser).RemoveParticipant(conversation, userID); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	conversationResponse := schema.ConversationResponse{}
+
+	if err := conversationResponse.FromModel(conversation); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	// Removed user is notified as well, so their clients can drop the Conversation.
+	recipients := append(conversation.GetParticipantIDs(), userID)
+
+	cc.publish(realtime.ParticipantRemovedEvent, conversation.ID, recipients, conversationResponse)
+
+	return conversationResponse, nil
+}
+
+// UpdateConversation - renames the Conversation.
+func (cc *ConversationController) UpdateConversation(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.UpdateConversationPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	if err := cc.conversations(contextUser).RenameConversation(conversation, payload.Name, contextUser.ID); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return cc.publishConversation(realtime.ConversationUpdatedEvent, conversation)
+}
+
+// SetParticipantRole - promotes the participant with given ID to a moderator, or demotes
+// them to a member.
+func (cc *ConversationController) SetParticipantRole(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.ParticipantRolePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+
+	if userID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("User ID is missing or malformed."))
+	}
+
+	err = cc.conversations(contextUser).SetParticipantRole(conversation, userID, payload.Role, contextUser.ID)
+
+	if apiErr := getParticipantRoleError(err); apiErr != nil {
+		return nil, apiErr
+	}
+
+	cc.record(ctx, contextUser, audit.ParticipantRoleAction, conversation.ID, userID, "role: "+payload.Role)
+
+	return cc.publishConversation(realtime.ParticipantUpdatedEvent, conversation)
+}
+
+// TransferOwnership - makes given participant the owner of the Conversation. Current
+// owner becomes a moderator.
+func (cc *ConversationController) TransferOwnership(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.TransferOwnershipPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	conversation, err := getConversation(ctx, cc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	err = cc.conversations(contextUser).TransferOwnership(conversation, payload.UserID, contextUser.ID)
+
+	if apiErr := getParticipantRoleError(err); apiErr != nil {
+		return nil, apiErr
+	}
+
+	cc.record(ctx, contextUser, audit.OwnershipTransferAction, conversation.ID, payload.UserID, "")
+
+	return cc.publishConversation(realtime.ConversationUpdatedEvent, conversation)
+}
+
+// conversations - returns ConversationService scoped to the Workspace current request is made in.
+func (cc *ConversationController) conversations(contextUser *control.ContextUser) *services.ConversationService {
+	return cc.conversationService.InWorkspace(contextUser.WorkspaceID)
+}
+
+// publishConversation - delivers an Event with the Conversation to all of its participants,
+// and returns the Conversation as the response.
+func (cc *ConversationController) publishConversation(
+	eventType realtime.EventType,
+	conversation *models.ConversationModel,
+) (interface{}, *api.APIError) {
+	conversationResponse := schema.ConversationResponse{}
+
+	if err := conversationResponse.FromModel(conversation); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	cc.publish(eventType, conversation.ID, conversation.GetParticipantIDs(), conversationResponse)
+
+	return conversationResponse, nil
+}
+
+// record - records the action current user performed on the participation of the user with
+// given ID in the audit log. Details are prefixed with the Conversation.
+func (cc *ConversationController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	details string,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = userID.String()
+	entry.Details = "conversation: " + conversationID.String()
+
+	if details != "" {
+		entry.Details += ", " + details
+	}
+
+	cc.auditLogger.Log(entry)
+}
+
+// publish - delivers an Event to given recipients.
+// Delivery failures are not propagated, as the change itself has been already saved.
+func (cc *ConversationController) publish(
+	eventType realtime.EventType,
+	conversationID uuid.UUID,
+	recipients []uuid.UUID,
+	payload interface{},
+) {
+	event := realtime.NewEvent(eventType, conversationID, recipients, payload)
+
+	if err := cc.publisher.Publish(event); err != nil {
+		log.Printf("Publishing %v event failed: %v", eventType, err)
+	}
+}
+
+// getParticipantRoleError - returns APIError matching given error of changing participant's role.
+func getParticipantRoleError(err error) *api.APIError {
+	switch err {
+	case nil:
+		return nil
+	case services.ErrParticipantNotFound:
+		return api.NewNotFoundError(models.USER_RESOURCE)
+	case services.ErrConversationRoleInvalid, services.ErrConversationOwnerRole:
+		return api.NewBadRequestError(err)
+	default:
+		return api.NewInternalError(err)
+	}
+}
+
+// getConversation - returns Conversation with ID passed in route params. If Conversation
+// has already been loaded by ResourceProvider, it's taken from current context.
+func getConversation(ctx *gin.Context, conversationService *services.ConversationService) (*models.ConversationModel, error) {
+	if value, ok := ctx.Get(conversationContextKey); ok {
+		if conversation, ok := value.(*models.ConversationModel); ok {
+			return conversation, nil
+		}
+	}
+
+	conversationID, err := uuid.Parse(ctx.Param("id"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := conversationService.GetConversationByID(conversationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Set(conversationContextKey, conversation)
+
+	return conversation, nil
+}
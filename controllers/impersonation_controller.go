@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ImpersonationController - struct for handling admin impersonation related requests.
+type ImpersonationController struct {
+	impersonationService *services.ImpersonationService
+	auditLogger          *audit.Logger
+}
+
+// NewImpersonationController - ImpersonationController constructor func.
+func NewImpersonationController() *ImpersonationController {
+	return &ImpersonationController{
+		impersonationService: services.NewImpersonationService(),
+		auditLogger:          audit.NewLogger(),
+	}
+}
+
+// Impersonate - starts a session of the user with ID passed in route params, used by
+// current user. Returned token expires after auth.ImpersonationTTL and cannot be refreshed.
+func (ic *ImpersonationController) Impersonate(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	userID, err := uuid.Parse(ctx.Param("userId"))
+
+	if userID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("User ID is missing or malformed."))
+	}
+
+	startAction := &models.ImpersonationActionModel{
+		Method: ctx.Request.Method,
+		Path:   ctx.Request.URL.Path,
+		Status: http.StatusOK,
+	}
+
+	token, session, userModel, err := ic.impersonationService.Impersonate(
+		contextUser,
+		userID,
+		startAction,
+		control.GetClientInfo(ctx),
+	)
+
+	if err == services.ErrImpersonationNotAllowed {
+		return nil, api.NewImpersonationNotAllowedError()
+	}
+
+	if err == services.ErrImpersonatedUserNotFound {
+		return nil, api.NewNotFoundError(models.USER_RESOURCE)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	ic.record(ctx, contextUser, audit.ImpersonateAction, userID, "session: "+session.ID.String())
+
+	impersonationResponse := &schema.ImpersonationResponse{
+		Token:     token,
+		SessionID: session.ID,
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	if err := impersonationResponse.UserResponse.FromModel(userModel); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return impersonationResponse, nil
+}
+
+// record - records the action current user performed on the user with given ID in the audit log.
+func (ic *ImpersonationController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	userID uuid.UUID,
+	details string,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = userID.String()
+	entry.Details = details
+
+	ic.auditLogger.Log(entry)
+}
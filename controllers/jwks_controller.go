@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSController - struct for handling requests for JWT verification keys.
+type JWKSController struct {
+	keys *auth.KeySet
+}
+
+// NewJWKSController - JWKSController constructor func.
+func NewJWKSController() *JWKSController {
+	return &JWKSController{
+		keys: auth.DefaultKeySet,
+	}
+}
+
+// GetJWKS - returns public keys Gochat's JWTs can be verified with, as JSON Web Key Set.
+func (jc *JWKSController) GetJWKS(ctx *gin.Context) (interface{}, *api.APIError) {
+	// Keys change only on rotation, which keeps previous keys published for a while,
+	// so clients can safely cache them.
+	ctx.Header("Cache-Control", "public, max-age=300")
+
+	return jc.keys.JWKS(), nil
+}
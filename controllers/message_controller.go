@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"log"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/el-Mike/gochat/realtime"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/el-Mike/restrict"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// messageContextKey - defines the key Message loaded by ResourceProvider
+// will be saved under in current context.
+const messageContextKey = "message"
+
+// MessageController - struct for handling Messages related requests.
+type MessageController struct {
+	authService         *services.AuthService
+	conversationService *services.ConversationService
+	messageService      *services.MessageService
+	publisher           realtime.Publisher
+}
+
+// NewMessageController - MessageController constructor func.
+func NewMessageController() *MessageController {
+	return &MessageController{
+		authService:         services.NewAuthService(),
+		conversationService: services.NewConversationService(),
+		messageService:      services.NewMessageService(),
+		publisher:           realtime.DefaultBroadcaster,
+	}
+}
+
+// GetNewMessageResource - AccessRule's ResourceProvider, returning not yet saved Message
+// attached to the Conversation with ID passed in route params.
+func (mc *MessageController) GetNewMessageResource(ctx *gin.Context, contextUser *control.ContextUser) restrict.Resource {
+	conversation, err := getConversation(ctx, mc.conversations(contextUser))
+
+	if err != nil {
+		return nil
+	}
+
+	return &models.MessageModel{
+		ConversationID: conversation.ID,
+		Conversation:   conversation,
+	}
+}
+
+// GetMessageResource - AccessRule's ResourceProvider, loading Message with ID passed
+// in route params, together with its Conversation.
+func (mc *MessageController) GetMessageResource(ctx *gin.Context, contextUser *control.ContextUser) restrict.Resource {
+	message, err := mc.getMessage(ctx, contextUser)
+
+	if err != nil {
+		return nil
+	}
+
+	return message
+}
+
+// GetMessages - returns single page of given Conversation's Messages.
+// By default, Messages are returned from the newest one.
+func (mc *MessageController) GetMessages(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var query schema.PageQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	conversation, err := getConversation(ctx, mc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	messages, nextCursor, err := mc.messages(contextUser).GetMessagesByConversationID(
+		conversation.ID,
+		query.ToPageRequest(persist.SortDescending),
+	)
+
+	if err == persist.ErrInvalidCursor {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	messageResponses := []schema.MessageResponse{}
+
+	for _, messageModel := range messages {
+		messageResponse := schema.MessageResponse{}
+
+		if err := messageResponse.FromModel(messageModel); err != nil {
+			return nil, api.NewInternalError(err)
+		}
+
+		messageResponses = append(messageResponses, messageResponse)
+	}
+
+	return schema.NewPageResponse(messageResponses, nextCursor), nil
+}
+
+// SendMessage - saves a new Message in given Conversation.
+func (mc *MessageController) SendMessage(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.MessagePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	err := mc.authService.CheckCanSendMessages(contextUser.ID)
+
+	if err == auth.ErrEmailNotVerified {
+		return nil, api.NewEmailNotVerifiedError()
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	conversation, err := getConversation(ctx, mc.conversations(contextUser))
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.CONVERSATION_RESOURCE)
+	}
+
+	message, err := mc.messages(contextUser).CreateMessage(conversation.ID, contextUser.ID, payload.Body)
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	messageResponse := schema.MessageResponse{}
+
+	if err := messageResponse.FromModel(message); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	mc.publish(realtime.MessageCreatedEvent, conversation, messageResponse)
+
+	return messageResponse, nil
+}
+
+// EditMessage - updates the body of a Message with given ID.
+func (mc *MessageController) EditMessage(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.MessagePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	message, err := mc.getMessage(ctx, contextUser)
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.MESSAGE_RESOURCE)
+	}
+
+	if err := mc.messages(contextUser).UpdateMessage(message, payload.Body, contextUser.ID); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	messageResponse := schema.MessageResponse{}
+
+	if err := messageResponse.FromModel(message); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	mc.publish(realtime.MessageUpdatedEvent, message.Conversation, messageResponse)
+
+	return messageResponse, nil
+}
+
+// DeleteMessage - deletes a Message with given ID.
+func (mc *MessageController) DeleteMessage(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	message, err := mc.getMessage(ctx, contextUser)
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.MESSAGE_RESOURCE)
+	}
+
+	if err := mc.messages(contextUser).DeleteMessageByID(message.ID); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	messageResponse := schema.MessageResponse{}
+
+	if err := messageResponse.FromModel(message); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	mc.publish(realtime.MessageDeletedEvent, message.Conversation, messageResponse)
+
+	return nil, nil
+}
+
+// publish - delivers an Event to all the participants of given Conversation.
+// Delivery failures are not propagated, as the change itself has been already saved.
+func (mc *MessageController) publish(eventType realtime.EventType, conversation *models.ConversationModel, payload interface{}) {
+	event := realtime.NewEvent(eventType, conversation.ID, conversation.GetParticipantIDs(), payload)
+
+	if err := mc.publisher.Publish(event); err != nil {
+		log.Printf("Publishing %v event failed: %v", eventType, err)
+	}
+}
+
+// conversations - returns ConversationService scoped to the Workspace current request is made in.
+func (mc *MessageController) conversations(contextUser *control.ContextUser) *services.ConversationService {
+	return mc.conversationService.InWorkspace(contextUser.WorkspaceID)
+}
+
+// messages - returns MessageService scoped to the Workspace current request is made in.
+func (mc *MessageController) messages(contextUser *control.ContextUser) *services.MessageService {
+	return mc.messageService.InWorkspace(contextUser.WorkspaceID)
+}
+
+// getMessage - returns Message with ID passed in route params, together with its Conversation.
+// Messages that do not belong to the Conversation from route params are treated as not found.
+// If Message has already been loaded by ResourceProvider, it's taken from current context.
+func (mc *MessageController) getMessage(ctx *gin.Context, contextUser *control.ContextUser) (*models.MessageModel, error) {
+	if value, ok := ctx.Get(messageContextKey); ok {
+		if message, ok := value.(*models.MessageModel); ok {
+			return message, nil
+		}
+	}
+
+	conversation, err := getConversation(ctx, mc.conversations(contextUser))
+
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := uuid.Parse(ctx.Param("messageId"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := mc.messages(contextUser).GetMessageByID(messageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if message.ConversationID != conversation.ID {
+		return nil, services.ErrMessageNotFound
+	}
+
+	message.Conversation = conversation
+
+	ctx.Set(messageContextKey, message)
+
+	return message, nil
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/realtime"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenController - struct for handling personal access tokens related requests.
+type PersonalAccessTokenController struct {
+	tokenService *services.PersonalAccessTokenService
+	broadcaster  *realtime.Broadcaster
+	auditLogger  *audit.Logger
+}
+
+// NewPersonalAccessTokenController - PersonalAccessTokenController constructor func.
+func NewPersonalAccessTokenController() *PersonalAccessTokenController {
+	return &PersonalAccessTokenController{
+		tokenService: services.NewPersonalAccessTokenService(),
+		broadcaster:  realtime.DefaultBroadcaster,
+		auditLogger:  audit.NewLogger(),
+	}
+}
+
+// CreateToken - creates new personal access token for current user.
+func (pc *PersonalAccessTokenController) CreateToken(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	var payload schema.CreatePersonalAccessTokenPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	var expiresAt *time.Time
+
+	if payload.ExpiresInDays > 0 {
+		expiration := time.Now().AddDate(0, 0, payload.ExpiresInDays)
+		expiresAt = &expiration
+	}
+
+	tokenModel, token, err := pc.tokenService.CreateToken(contextUser.ID, payload.Name, payload.Scopes, expiresAt)
+
+	if err == services.ErrPersonalAccessTokenScopeInvalid {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	pc.record(ctx, contextUser, audit.TokenCreateAction, tokenModel.ID, "scopes: "+strings.Join(tokenModel.GetScopes(), ", "))
+
+	tokenResponse := &schema.CreatedPersonalAccessTokenResponse{}
+
+	if err := tokenResponse.FromModel(tokenModel); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	tokenResponse.Token = token
+
+	return tokenResponse, nil
+}
+
+// GetTokens - returns personal access tokens of current user.
+func (pc *PersonalAccessTokenController) GetTokens(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	tokens, err := pc.tokenService.GetTokens(contextUser.ID)
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	tokenResponses := []schema.PersonalAccessTokenResponse{}
+
+	for _, token := range tokens {
+		tokenResponse := schema.PersonalAccessTokenResponse{}
+
+		if err := tokenResponse.FromModel(token); err != nil {
+			return nil, api.NewInternalError(err)
+		}
+
+		tokenResponses = append(tokenResponses, tokenResponse)
+	}
+
+	return tokenResponses, nil
+}
+
+// RevokeToken - revokes current user's personal access token with ID passed in route params.
+func (pc *PersonalAccessTokenController) RevokeToken(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	tokenID, err := uuid.Parse(ctx.Param("tokenId"))
+
+	if tokenID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("Token ID is missing or malformed."))
+	}
+
+	err = pc.tokenService.RevokeToken(contextUser.ID, tokenID)
+
+	if err == services.ErrPersonalAccessTokenNotFound {
+		return nil, api.NewNotFoundError(models.PERSONAL_ACCESS_TOKEN_RESOURCE)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	pc.record(ctx, contextUser, audit.TokenRevokeAction, tokenID, "")
+
+	// Real-time connections opened with the token are identified by its ID.
+	if err := pc.broadcaster.CloseSession(tokenID); err != nil {
+		log.Printf("Closing real-time connections failed: %v", err)
+	}
+
+	return nil, nil
+}
+
+// record - records the action current user performed on their personal access token with
+// given ID in the audit log.
+func (pc *PersonalAccessTokenController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	tokenID uuid.UUID,
+	details string,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = models.PERSONAL_ACCESS_TOKEN_RESOURCE
+	entry.TargetID = tokenID.String()
+	entry.Details = details
+
+	pc.auditLogger.Log(entry)
+}
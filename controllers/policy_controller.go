@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/el-Mike/restrict"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	roleResourceName             = "Role"
+	permissionPresetResourceName = "PermissionPreset"
+)
+
+// PolicyController - struct for handling RBAC policy management related requests.
+type PolicyController struct {
+	policyService *services.PolicyService
+	auditLogger   *audit.Logger
+}
+
+// NewPolicyController - PolicyController constructor func.
+func NewPolicyController() *PolicyController {
+	return &PolicyController{
+		policyService: services.NewPolicyService(),
+		auditLogger:   audit.NewLogger(),
+	}
+}
+
+// GetPolicy - returns the latest version of the policy.
+func (pc *PolicyController) GetPolicy(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	policy, version, err := pc.policyService.GetPolicy()
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	policyResponse := &schema.PolicyResponse{}
+	policyResponse.FromDefinition(policy, version)
+
+	return policyResponse, nil
+}
+
+// GetRoles - returns all the roles defined in the policy.
+func (pc *PolicyController) GetRoles(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	policy, version, err := pc.policyService.GetPolicy()
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	policyResponse := &schema.PolicyResponse{}
+	policyResponse.FromDefinition(policy, version)
+
+	return policyResponse.Roles, nil
+}
+
+// GetRole - returns the role with ID passed in route params.
+func (pc *PolicyController) GetRole(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	role, err := pc.policyService.GetRole(ctx.Param("roleId"))
+
+	return getRoleResponse(role, err)
+}
+
+// CreateRole - adds new role to the policy.
+func (pc *PolicyController) CreateRole(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	var payload schema.CreateRolePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	role, err := pc.policyService.CreateRole(&restrict.Role{
+		ID:          payload.ID,
+		Description: payload.Description,
+		Parents:     payload.Parents,
+	})
+
+	if err == nil {
+		pc.record(ctx, contextUser, audit.RoleCreateAction, roleResourceName, role.ID, describeParents(role.Parents))
+	}
+
+	return getRoleResponse(role, err)
+}
+
+// UpdateRole - updates description and parents of the role with ID passed in route params.
+func (pc *PolicyController) UpdateRole(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	var payload schema.UpdateRolePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	role, err := pc.policyService.UpdateRole(ctx.Param("roleId"), payload.Description, payload.Parents)
+
+	if err == nil {
+		pc.record(ctx, contextUser, audit.RoleUpdateAction, roleResourceName, role.ID, describeParents(role.Parents))
+	}
+
+	return getRoleResponse(role, err)
+}
+
+// SetGrants - replaces permissions the role with ID passed in route params has for
+// the resource passed in route params.
+func (pc *PolicyController) SetGrants(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	var payload schema.GrantsPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	role, err := pc.policyService.SetGrants(ctx.Param("roleId"), ctx.Param("resourceId"), payload.Permissions)
+
+	if err == nil {
+		pc.record(ctx, contextUser, audit.GrantsUpdateAction, roleResourceName, role.ID, "resource: "+ctx.Param("resourceId"))
+	}
+
+	return getRoleResponse(role, err)
+}
+
+// DeleteRole - removes the role with ID passed in route params from the policy.
+func (pc *PolicyController) DeleteRole(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	if err := pc.policyService.DeleteRole(ctx.Param("roleId")); err != nil {
+		return nil, getPolicyError(err)
+	}
+
+	pc.record(ctx, contextUser, audit.RoleDeleteAction, roleResourceName, ctx.Param("roleId"), "")
+
+	return nil, nil
+}
+
+// GetPresets - returns all the permission presets defined in the policy.
+func (pc *PolicyController) GetPresets(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	policy, _, err := pc.policyService.GetPolicy()
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	if policy.PermissionPresets == nil {
+		return restrict.PermissionPresets{}, nil
+	}
+
+	return policy.PermissionPresets, nil
+}
+
+// UpsertPreset - creates or replaces the permission preset with name passed in route params.
+func (pc *PolicyController) UpsertPreset(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	var payload schema.PermissionPresetPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	preset := &restrict.Permission{
+		Action:     payload.Action,
+		Conditions: payload.Conditions,
+	}
+
+	if err := pc.policyService.UpsertPreset(ctx.Param("presetName"), preset); err != nil {
+		return nil, getPolicyError(err)
+	}
+
+	pc.record(ctx, contextUser, audit.PresetUpdateAction, permissionPresetResourceName, ctx.Param("presetName"), "")
+
+	return preset, nil
+}
+
+// DeletePreset - removes the permission preset with name passed in route params.
+func (pc *PolicyController) DeletePreset(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+) (interface{}, *api.APIError) {
+	if err := pc.policyService.DeletePreset(ctx.Param("presetName")); err != nil {
+		return nil, getPolicyError(err)
+	}
+
+	pc.record(ctx, contextUser, audit.PresetDeleteAction, permissionPresetResourceName, ctx.Param("presetName"), "")
+
+	return nil, nil
+}
+
+// record - records the change of the policy made by current user in the audit log.
+func (pc *PolicyController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	targetType string,
+	targetID string,
+	details string,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = targetType
+	entry.TargetID = targetID
+	entry.Details = details
+
+	pc.auditLogger.Log(entry)
+}
+
+// describeParents - returns audit log details of role's parents.
+func describeParents(parents []string) string {
+	return "parents: " + strings.Join(parents, ", ")
+}
+
+func getRoleResponse(role *restrict.Role, err error) (interface{}, *api.APIError) {
+	if err != nil {
+		return nil, getPolicyError(err)
+	}
+
+	roleResponse := &schema.RoleResponse{}
+	roleResponse.FromRole(role)
+
+	return roleResponse, nil
+}
+
+// getPolicyError - returns APIError matching given policy change error.
+func getPolicyError(err error) *api.APIError {
+	if _, ok := err.(*control.PolicyInvalidError); ok {
+		return api.NewPolicyInvalidError(err)
+	}
+
+	switch err {
+	case services.ErrPolicyRoleNotFound:
+		return api.NewNotFoundError(roleResourceName)
+	case services.ErrPolicyPresetNotFound:
+		return api.NewNotFoundError(permissionPresetResourceName)
+	case services.ErrPolicyRoleExists, services.ErrPolicyRoleBuiltIn, services.ErrPolicyRoleInUse:
+		return api.NewBadRequestError(err)
+	default:
+		return api.NewInternalError(err)
+	}
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/realtime"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Period of writing comments to the event stream, in order to keep
+// the connection alive behind proxies closing idle connections.
+const streamHeartbeatPeriod = 30 * time.Second
+
+// RealtimeController - struct for handling real-time connections.
+type RealtimeController struct {
+	hub      *realtime.Hub
+	upgrader *websocket.Upgrader
+}
+
+// NewRealtimeController - RealtimeController constructor func.
+func NewRealtimeController() *RealtimeController {
+	return &RealtimeController{
+		hub:      realtime.DefaultHub,
+		upgrader: newUpgrader(os.Getenv("WS_ALLOWED_ORIGINS")),
+	}
+}
+
+// Connect - upgrades the request to WebSocket connection, which will receive
+// Events of all the Conversations current user participates in.
+func (rc *RealtimeController) Connect(ctx *gin.Context, contextUser *control.ContextUser) *api.APIError {
+	conn, err := rc.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+
+	// Upgrader responds with an HTTP error on its own.
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return nil
+	}
+
+	client := realtime.NewClient(rc.hub, conn, contextUser.ID, contextUser.AuthUUID)
+	client.PersonalAccessToken = contextUser.PersonalAccessToken
+	client.Run()
+
+	return nil
+}
+
+// Stream - opens Server-Sent Events stream, which will receive Events of all
+// the Conversations current user participates in. Events missed since the one
+// passed in Last-Event-ID header are replayed first - if that's not possible,
+// "stream.reset" Event is sent instead.
+func (rc *RealtimeController) Stream(ctx *gin.Context, contextUser *control.ContextUser) *api.APIError {
+	client := realtime.NewStreamClient(rc.hub, contextUser.ID, contextUser.AuthUUID)
+	client.PersonalAccessToken = contextUser.PersonalAccessToken
+	defer client.Close()
+
+	missed, ok := client.Subscribe(ctx.GetHeader("Last-Event-ID"))
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	if !ok {
+		ctx.Render(-1, sse.Event{
+			Event: string(realtime.StreamResetEvent),
+			Data:  realtime.NewEvent(realtime.StreamResetEvent, uuid.Nil, nil, nil),
+		})
+	}
+
+	for _, event := range missed {
+		writeStreamEvent(ctx, event)
+	}
+
+	ctx.Writer.Flush()
+
+	ticker := time.NewTicker(streamHeartbeatPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return nil
+		case <-client.Done():
+			return nil
+		case event := <-client.Events():
+			writeStreamEvent(ctx, event)
+			ctx.Writer.Flush()
+		case <-ticker.C:
+			_, _ = fmt.Fprint(ctx.Writer, ": heartbeat\n\n")
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+// writeStreamEvent - writes given Event in Server-Sent Events format.
+func writeStreamEvent(ctx *gin.Context, event *realtime.Event) {
+	ctx.Render(-1, sse.Event{
+		Id:    event.ID.String(),
+		Event: string(event.Type),
+		Data:  event,
+	})
+}
+
+// newUpgrader - returns WebSocket Upgrader accepting given, comma-separated origins.
+// When no origins are passed, only same-origin requests are accepted.
+func newUpgrader(allowedOrigins string) *websocket.Upgrader {
+	upgrader := &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+
+	if allowedOrigins == "" {
+		return upgrader
+	}
+
+	origins := make(map[string]bool)
+
+	for _, origin := range strings.Split(allowedOrigins, ",") {
+		origins[strings.TrimSpace(origin)] = true
+	}
+
+	upgrader.CheckOrigin = func(request *http.Request) bool {
+		return origins["*"] || origins[request.Header.Get("Origin")]
+	}
+
+	return upgrader
+}
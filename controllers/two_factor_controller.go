@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorController - struct for handling two-factor authentication related requests.
+type TwoFactorController struct {
+	twoFactorService *services.TwoFactorService
+	auditLogger      *audit.Logger
+}
+
+// NewTwoFactorController - TwoFactorController constructor func.
+func NewTwoFactorController() *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: services.NewTwoFactorService(),
+		auditLogger:      audit.NewLogger(),
+	}
+}
+
+// Enroll - generates new TOTP secret for current user, returning it together
+// with provisioning URI, which can be displayed as QR code.
+func (tc *TwoFactorController) Enroll(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	// Enrollment is available before it's completed, so impersonated sessions are not
+	// stopped by HandlerCreator - admins cannot set user's second factor, though.
+	if contextUser.IsImpersonated() {
+		return nil, api.NewImpersonationForbiddenError()
+	}
+
+	enrollment, err := tc.twoFactorService.Enroll(contextUser.ID)
+
+	if err == services.ErrTwoFactorAlreadyEnabled {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return &schema.TOTPEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}, nil
+}
+
+// Confirm - enables two-factor authentication of current user using TOTP code,
+// and returns recovery codes.
+func (tc *TwoFactorController) Confirm(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	if contextUser.IsImpersonated() {
+		return nil, api.NewImpersonationForbiddenError()
+	}
+
+	var payload schema.TwoFactorCodePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	recoveryCodes, err := tc.twoFactorService.Confirm(contextUser.ID, contextUser.AuthUUID, payload.Code)
+
+	if apiErr := getTwoFactorAPIError(err); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &schema.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// Disable - disables two-factor authentication of current user.
+func (tc *TwoFactorController) Disable(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.TwoFactorCodePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	err := tc.twoFactorService.Disable(contextUser.ID, payload.Code)
+
+	if apiErr := getTwoFactorAPIError(err); apiErr != nil {
+		return nil, apiErr
+	}
+
+	tc.record(ctx, contextUser, audit.TwoFactorDisableAction)
+
+	return nil, nil
+}
+
+// RegenerateRecoveryCodes - replaces recovery codes of current user.
+func (tc *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.TwoFactorCodePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	recoveryCodes, err := tc.twoFactorService.RegenerateRecoveryCodes(contextUser.ID, payload.Code)
+
+	if apiErr := getTwoFactorAPIError(err); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &schema.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// record - records the action current user performed on their own account in the audit log.
+func (tc *TwoFactorController) record(ctx *gin.Context, contextUser *control.ContextUser, action string) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = contextUser.ID.String()
+
+	tc.auditLogger.Log(entry)
+}
+
+// getTwoFactorAPIError - maps errors of two-factor authentication management to APIErrors.
+func getTwoFactorAPIError(err error) *api.APIError {
+	switch err {
+	case nil:
+		return nil
+	case auth.ErrTwoFactorCodeInvalid:
+		return api.NewTwoFactorCodeInvalidError()
+	case services.ErrTwoFactorAlreadyEnabled,
+		services.ErrTwoFactorNotEnabled,
+		services.ErrTwoFactorEnrollmentNotStarted,
+		services.ErrTwoFactorRequired:
+		return api.NewBadRequestError(err)
+	default:
+		return api.NewInternalError(err)
+	}
+}
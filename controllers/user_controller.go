@@ -3,9 +3,11 @@ package controllers
 import (
 	"errors"
 
+	"github.com/el-Mike/gochat/audit"
 	"github.com/el-Mike/gochat/core/api"
 	"github.com/el-Mike/gochat/core/control"
 	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
 	"github.com/el-Mike/gochat/schema"
 	"github.com/el-Mike/gochat/services"
 	"github.com/gin-gonic/gin"
@@ -15,12 +17,14 @@ import (
 // UserController - struct for handling Users related requests.
 type UserController struct {
 	userService *services.UserService
+	auditLogger *audit.Logger
 }
 
 // NewUserController - UserController constructor function.
 func NewUserController() *UserController {
 	return &UserController{
 		userService: services.NewUserService(),
+		auditLogger: audit.NewLogger(),
 	}
 }
 
@@ -43,15 +47,31 @@ func (uc *UserController) GetMe(ctx *gin.Context, contextUser *control.ContextUs
 	return userResponse, nil
 }
 
-// GetUsers - returns all the users.
+// GetUsers - returns single page of users, filtered by role and email or name prefix.
 func (uc *UserController) GetUsers(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
-	users, err := uc.userService.GetUsers()
+	var query schema.UsersQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	filter := &services.UserFilter{
+		Role:        query.Role,
+		EmailPrefix: query.Email,
+		NamePrefix:  query.Name,
+	}
+
+	users, nextCursor, err := uc.userService.InWorkspace(contextUser.WorkspaceID).GetUsers(filter, query.ToPageRequest(persist.SortAscending))
+
+	if err == persist.ErrInvalidCursor {
+		return nil, api.NewBadRequestError(err)
+	}
 
 	if err != nil {
 		return nil, api.NewInternalError(err)
 	}
 
-	var userResponses []schema.UserResponse
+	userResponses := []schema.UserResponse{}
 
 	for _, userModel := range users {
 		userResponse := schema.UserResponse{}
@@ -63,10 +83,10 @@ func (uc *UserController) GetUsers(ctx *gin.Context, contextUser *control.Contex
 		userResponses = append(userResponses, userResponse)
 	}
 
-	return userResponses, nil
+	return schema.NewPageResponse(userResponses, nextCursor), nil
 }
 
-// SaveUser - saves single User to DB.
+// SaveUser - saves single User to DB. New user joins the Workspace current request is made in.
 func (uc *UserController) SaveUser(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
 	var user models.UserModel
 
@@ -83,14 +103,17 @@ func (uc *UserController) SaveUser(ctx *gin.Context, contextUser *control.Contex
 		return nil, api.NewBadRequestError(errors.New("User already exists."))
 	}
 
-	if err := uc.userService.SaveUser(&user); err != nil {
+	if err := uc.userService.InWorkspace(contextUser.WorkspaceID).SaveUser(&user); err != nil {
 		return nil, api.NewInternalError(err)
 	}
 
+	uc.record(ctx, contextUser, audit.UserCreateAction, models.AuditSuccessResult, user.ID, "role: "+user.Role)
+
 	return user, nil
 }
 
-// DeleteUser - deletes a User with given ID.
+// DeleteUser - deletes a User with given ID. Only members of the Workspace current request
+// is made in can be deleted - they are removed from all the other Workspaces as well.
 func (uc *UserController) DeleteUser(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
 	paramId := ctx.Param("id")
 
@@ -106,9 +129,37 @@ func (uc *UserController) DeleteUser(ctx *gin.Context, contextUser *control.Cont
 		return nil, api.NewBadRequestError(errors.New("You cannot delete yourself."))
 	}
 
-	if err := uc.userService.DeleteUserByID(targetId); err != nil {
+	err = uc.userService.InWorkspace(contextUser.WorkspaceID).DeleteUserByID(targetId)
+
+	if err == services.ErrUserNotFound {
+		uc.record(ctx, contextUser, audit.UserDeleteAction, models.AuditFailureResult, targetId, err.Error())
+
+		return nil, api.NewNotFoundError(models.USER_RESOURCE)
+	}
+
+	if err != nil {
 		return nil, api.NewInternalError(err)
 	}
 
+	uc.record(ctx, contextUser, audit.UserDeleteAction, models.AuditSuccessResult, targetId, "")
+
 	return nil, nil
 }
+
+// record - records the action current user performed on the user with given ID, with given
+// result, in the audit log.
+func (uc *UserController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	result string,
+	userID uuid.UUID,
+	details string,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, result)
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = userID.String()
+	entry.Details = details
+
+	uc.auditLogger.Log(entry)
+}
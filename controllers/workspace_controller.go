@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/schema"
+	"github.com/el-Mike/gochat/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WorkspaceController - struct for handling Workspaces related requests.
+type WorkspaceController struct {
+	workspaceService *services.WorkspaceService
+	authService      *services.AuthService
+	auditLogger      *audit.Logger
+}
+
+// NewWorkspaceController - WorkspaceController constructor func.
+func NewWorkspaceController() *WorkspaceController {
+	return &WorkspaceController{
+		workspaceService: services.NewWorkspaceService(),
+		authService:      services.NewAuthService(),
+		auditLogger:      audit.NewLogger(),
+	}
+}
+
+// GetWorkspaces - returns all the Workspaces current user is a member of,
+// each with user's own membership only.
+func (wc *WorkspaceController) GetWorkspaces(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	workspaces, err := wc.workspaceService.GetWorkspacesByUserID(contextUser.ID)
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	workspaceResponses := []schema.WorkspaceResponse{}
+
+	for _, workspaceModel := range workspaces {
+		workspaceResponse := schema.WorkspaceResponse{}
+
+		if err := workspaceResponse.FromModel(workspaceModel); err != nil {
+			return nil, api.NewInternalError(err)
+		}
+
+		workspaceResponses = append(workspaceResponses, workspaceResponse)
+	}
+
+	return workspaceResponses, nil
+}
+
+// CreateWorkspace - creates a new Workspace, with current user as its admin.
+func (wc *WorkspaceController) CreateWorkspace(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.WorkspacePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	workspace, err := wc.workspaceService.CreateWorkspace(contextUser.ID, payload.Name, payload.Slug)
+
+	if err == services.ErrWorkspaceSlugInvalid || err == services.ErrWorkspaceSlugExists {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return getWorkspaceResponse(workspace)
+}
+
+// GetWorkspace - returns the Workspace with ID passed in route params, together with its members.
+func (wc *WorkspaceController) GetWorkspace(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	workspace, err := wc.workspaceService.GetWorkspaceByID(contextUser.WorkspaceID)
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.WORKSPACE_RESOURCE)
+	}
+
+	return getWorkspaceResponse(workspace)
+}
+
+// UpdateWorkspace - renames the Workspace.
+func (wc *WorkspaceController) UpdateWorkspace(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.UpdateWorkspacePayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	workspace, err := wc.workspaceService.GetWorkspaceByID(contextUser.WorkspaceID)
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.WORKSPACE_RESOURCE)
+	}
+
+	if err := wc.workspaceService.RenameWorkspace(workspace, payload.Name, contextUser.ID); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return getWorkspaceResponse(workspace)
+}
+
+// SetMember - adds the user with ID passed in route params to the Workspace,
+// or changes their role if they are a member already.
+func (wc *WorkspaceController) SetMember(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	var payload schema.WorkspaceMemberPayload
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		return nil, api.NewBadRequestError(err)
+	}
+
+	workspace, err := wc.workspaceService.GetWorkspaceByID(contextUser.WorkspaceID)
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.WORKSPACE_RESOURCE)
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+
+	if userID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("User ID is missing or malformed."))
+	}
+
+	if _, err := wc.workspaceService.SetMember(workspace, userID, payload.Role, contextUser.ID); err != nil {
+		return nil, getWorkspaceMemberError(err)
+	}
+
+	wc.record(ctx, contextUser, audit.WorkspaceMemberSetAction, userID, "role: "+payload.Role)
+
+	return getWorkspaceResponse(workspace)
+}
+
+// RemoveMember - removes the user with ID passed in route params from the Workspace.
+func (wc *WorkspaceController) RemoveMember(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	workspace, err := wc.workspaceService.GetWorkspaceByID(contextUser.WorkspaceID)
+
+	if err != nil {
+		return nil, api.NewNotFoundError(models.WORKSPACE_RESOURCE)
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+
+	if userID == uuid.Nil || err != nil {
+		return nil, api.NewBadRequestError(errors.New("User ID is missing or malformed."))
+	}
+
+	if err := wc.workspaceService.RemoveMember(workspace, userID); err != nil {
+		return nil, getWorkspaceMemberError(err)
+	}
+
+	wc.record(ctx, contextUser, audit.WorkspaceMemberRemoveAction, userID, "")
+
+	return getWorkspaceResponse(workspace)
+}
+
+// SelectWorkspace - makes the Workspace with ID passed in route params the one current
+// session's requests are made in, when they do not specify any. Membership has been
+// already checked when resolving the Workspace.
+func (wc *WorkspaceController) SelectWorkspace(ctx *gin.Context, contextUser *control.ContextUser) (interface{}, *api.APIError) {
+	if contextUser.PersonalAccessToken {
+		return nil, api.NewInsufficientScopeError()
+	}
+
+	err := wc.authService.SelectWorkspace(contextUser.AuthUUID, contextUser.WorkspaceID)
+
+	if err == auth.ErrSessionNotFound {
+		return nil, api.NewNotFoundError(auth.SESSION_RESOURCE)
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return nil, nil
+}
+
+// record - records the action current user performed on the membership of the user with
+// given ID in the audit log. Details are prefixed with the Workspace.
+func (wc *WorkspaceController) record(
+	ctx *gin.Context,
+	contextUser *control.ContextUser,
+	action string,
+	userID uuid.UUID,
+	details string,
+) {
+	entry := control.NewAuditEntry(ctx, contextUser, action, models.AuditSuccessResult)
+	entry.TargetType = models.USER_RESOURCE
+	entry.TargetID = userID.String()
+	entry.Details = "workspace: " + contextUser.WorkspaceID.String()
+
+	if details != "" {
+		entry.Details += ", " + details
+	}
+
+	wc.auditLogger.Log(entry)
+}
+
+// getWorkspaceResponse - returns given Workspace as the response.
+func getWorkspaceResponse(workspace *models.WorkspaceModel) (interface{}, *api.APIError) {
+	workspaceResponse := schema.WorkspaceResponse{}
+
+	if err := workspaceResponse.FromModel(workspace); err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return workspaceResponse, nil
+}
+
+// getWorkspaceMemberError - returns APIError matching given error of managing Workspace's members.
+func getWorkspaceMemberError(err error) *api.APIError {
+	switch err {
+	case services.ErrWorkspaceUserNotFound, services.ErrWorkspaceMemberNotFound:
+		return api.NewNotFoundError(models.USER_RESOURCE)
+	case services.ErrWorkspaceRoleInvalid, services.ErrWorkspaceLastAdmin:
+		return api.NewBadRequestError(err)
+	default:
+		return api.NewInternalError(err)
+	}
+}
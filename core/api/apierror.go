@@ -16,11 +16,12 @@ type ErrorCode string
 
 // Map of valid error types (ErrorType).
 const (
-	AuthorizationError  ErrorType = "AUTHORIZATION"
-	AuthenticationError ErrorType = "AUTHENTICATION"
-	NotFoundError       ErrorType = "NOT_FOUND"
-	InternalError       ErrorType = "INTERNAL"
-	BadRequestError     ErrorType = "BAD_REQUEST"
+	AuthorizationError   ErrorType = "AUTHORIZATION"
+	AuthenticationError  ErrorType = "AUTHENTICATION"
+	NotFoundError        ErrorType = "NOT_FOUND"
+	InternalError        ErrorType = "INTERNAL"
+	BadRequestError      ErrorType = "BAD_REQUEST"
+	TooManyRequestsError ErrorType = "TOO_MANY_REQUESTS"
 )
 
 // APIError holds a custom error for the application,
@@ -30,6 +31,19 @@ type APIError struct {
 	Type      ErrorType `json:"type"`
 	ErrorCode ErrorCode `json:"errorCode"`
 	Message   string    `json:"message"`
+
+	// RetryAfter - number of seconds after which the request can be retried, if it's known.
+	RetryAfter int `json:"retryAfter,omitempty"`
+
+	// Fields - problems with particular fields of the request, if there are any.
+	Fields []*FieldError `json:"fields,omitempty"`
+}
+
+// FieldError - describes a single problem with a field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 // Error() satisfies standard Error interface.
@@ -49,6 +63,8 @@ func getHttpStatusCode(errorType ErrorType) int {
 		return http.StatusInternalServerError
 	case BadRequestError:
 		return http.StatusBadRequest
+	case TooManyRequestsError:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -86,6 +102,18 @@ func NewLoginCredentialsIncorrectError() *APIError {
 	}
 }
 
+// NewAccountLockedError - returns APIError related to login being temporarily locked
+// after too many failed attempts.
+func NewAccountLockedError(retryAfter int) *APIError {
+	return &APIError{
+		Status:     getHttpStatusCode(TooManyRequestsError),
+		Type:       TooManyRequestsError,
+		ErrorCode:  "auth/account-locked",
+		Message:    fmt.Sprintf("Too many failed login attempts, try again in %d seconds.", retryAfter),
+		RetryAfter: retryAfter,
+	}
+}
+
 // NewTokenMalforedError - returns APIError related to malformed token.
 func NewTokenMalforedError() *APIError {
 	return &APIError{
@@ -96,6 +124,236 @@ func NewTokenMalforedError() *APIError {
 	}
 }
 
+// NewRefreshTokenInvalidError - returns APIError related to invalid or expired refresh token.
+func NewRefreshTokenInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/refresh-token-invalid",
+		Message:   "Refresh token is invalid or expired, please log in again.",
+	}
+}
+
+// NewRefreshTokenReusedError - returns APIError related to already used refresh token.
+func NewRefreshTokenReusedError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/refresh-token-reused",
+		Message:   "Refresh token has already been used - session has been revoked, please log in again.",
+	}
+}
+
+// NewPasswordResetTokenInvalidError - returns APIError related to invalid, expired
+// or already used password reset token.
+func NewPasswordResetTokenInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(BadRequestError),
+		Type:      BadRequestError,
+		ErrorCode: "auth/password-reset-token-invalid",
+		Message:   "Password reset token is invalid or expired.",
+	}
+}
+
+// NewPasswordPolicyError - returns APIError related to password not meeting password policy.
+// Requirements which are not met are described by given fields.
+func NewPasswordPolicyError(fields []*FieldError) *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(BadRequestError),
+		Type:      BadRequestError,
+		ErrorCode: "auth/password-policy",
+		Message:   "Password does not meet the requirements.",
+		Fields:    fields,
+	}
+}
+
+// NewCurrentPasswordIncorrectError - returns APIError related to incorrect current password,
+// while changing it.
+func NewCurrentPasswordIncorrectError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(BadRequestError),
+		Type:      BadRequestError,
+		ErrorCode: "auth/current-password-incorrect",
+		Message:   "Current password is incorrect.",
+	}
+}
+
+// NewEmailVerificationTokenInvalidError - returns APIError related to invalid, expired
+// or already used email verification token.
+func NewEmailVerificationTokenInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(BadRequestError),
+		Type:      BadRequestError,
+		ErrorCode: "auth/email-verification-token-invalid",
+		Message:   "Email verification token is invalid or expired.",
+	}
+}
+
+// NewEmailNotVerifiedError - returns APIError related to user's email not being verified,
+// while current policy requires it.
+func NewEmailNotVerifiedError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/email-not-verified",
+		Message:   "Email address has not been verified.",
+	}
+}
+
+// NewLoginChallengeInvalidError - returns APIError related to invalid, expired
+// or already used login challenge token.
+func NewLoginChallengeInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/login-challenge-invalid",
+		Message:   "Login challenge is invalid or expired, please log in again.",
+	}
+}
+
+// NewTwoFactorCodeInvalidError - returns APIError related to incorrect
+// or already used TOTP or recovery code.
+func NewTwoFactorCodeInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/two-factor-code-invalid",
+		Message:   "Two-factor authentication code is invalid.",
+	}
+}
+
+// NewTwoFactorEnrollmentRequiredError - returns APIError related to user's role
+// requiring two-factor authentication, which has not been enrolled yet.
+func NewTwoFactorEnrollmentRequiredError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/two-factor-enrollment-required",
+		Message:   "Two-factor authentication has to be enabled before using the application.",
+	}
+}
+
+// NewCSRFTokenInvalidError - returns APIError related to request authenticated with cookie
+// missing valid CSRF token.
+func NewCSRFTokenInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/csrf-token-invalid",
+		Message:   "CSRF token is missing or invalid.",
+	}
+}
+
+// NewPersonalAccessTokenInvalidError - returns APIError related to personal access token
+// which does not exist, has been revoked or has expired.
+func NewPersonalAccessTokenInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/personal-access-token-invalid",
+		Message:   "Personal access token is invalid, expired or revoked.",
+	}
+}
+
+// NewInsufficientScopeError - returns APIError related to personal access token's scopes
+// not allowing the request.
+func NewInsufficientScopeError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/insufficient-scope",
+		Message:   "Personal access token's scopes do not allow this request.",
+	}
+}
+
+// NewOIDCNotConfiguredError - returns APIError related to OIDC login being used,
+// while no identity provider is configured.
+func NewOIDCNotConfiguredError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(NotFoundError),
+		Type:      NotFoundError,
+		ErrorCode: "auth/oidc-not-configured",
+		Message:   "OpenID Connect login is not configured.",
+	}
+}
+
+// NewOIDCStateInvalidError - returns APIError related to invalid, expired
+// or already used OIDC login state.
+func NewOIDCStateInvalidError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/oidc-state-invalid",
+		Message:   "OpenID Connect login is invalid or expired, please log in again.",
+	}
+}
+
+// NewOIDCLoginFailedError - returns APIError related to authorization code or ID token
+// issued by identity provider being rejected.
+func NewOIDCLoginFailedError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthorizationError),
+		Type:      AuthorizationError,
+		ErrorCode: "auth/oidc-login-failed",
+		Message:   "Identity provider's response could not be verified, please log in again.",
+	}
+}
+
+// NewOIDCEmailNotVerifiedError - returns APIError related to identity provider
+// not confirming that user's email has been verified.
+func NewOIDCEmailNotVerifiedError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/oidc-email-not-verified",
+		Message:   "Email address has not been verified by identity provider.",
+	}
+}
+
+// NewImpersonationNotAllowedError - returns APIError related to an attempt to impersonate
+// the user who cannot be impersonated.
+func NewImpersonationNotAllowedError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/impersonation-not-allowed",
+		Message:   "This user cannot be impersonated.",
+	}
+}
+
+// NewImpersonationForbiddenError - returns APIError related to an action which cannot
+// be performed within impersonated session.
+func NewImpersonationForbiddenError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "auth/impersonation-forbidden",
+		Message:   "This action is not available while impersonating a user.",
+	}
+}
+
+// NewWorkspaceAccessDeniedError - returns APIError related to a request made in the Workspace
+// user is not a member of.
+func NewWorkspaceAccessDeniedError() *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(AuthenticationError),
+		Type:      AuthenticationError,
+		ErrorCode: "workspace/access-denied",
+		Message:   "You are not a member of this workspace.",
+	}
+}
+
+// NewPolicyInvalidError - returns APIError related to RBAC policy change, which
+// would make the policy invalid.
+func NewPolicyInvalidError(source error) *APIError {
+	return &APIError{
+		Status:    getHttpStatusCode(BadRequestError),
+		Type:      BadRequestError,
+		ErrorCode: "policy/invalid",
+		Message:   source.Error(),
+	}
+}
+
 // NewAccessDeniedError - returns APIError related to missing permissions.
 func NewAccessDeniedError(resource string, action string) *APIError {
 	return &APIError{
@@ -11,4 +11,10 @@ type AccessRule struct {
 	ResourceID       string
 	ResourceProvider func(ctx *gin.Context, user *ContextUser) restrict.Resource
 	Action           string
+
+	// Scope - personal access token scope required by the rule. It should be set only for
+	// the routes that cannot be described by ResourceID and Action (e.g. listing user's own
+	// resources), as scopes of other rules are derived from them. Rules with Scope only
+	// do not affect requests authenticated with session's JWT.
+	Scope string
 }
@@ -0,0 +1,33 @@
+package control
+
+import (
+	"github.com/el-Mike/gochat/models"
+	"github.com/gin-gonic/gin"
+)
+
+// NewAuditEntry - returns audit log entry of given action, performed within given request
+// by given user, with given result. User can be nil, if the request is not authenticated.
+func NewAuditEntry(ctx *gin.Context, contextUser *ContextUser, action, result string) *models.AuditLogModel {
+	client := GetClientInfo(ctx)
+
+	entry := &models.AuditLogModel{
+		Action:    action,
+		Result:    result,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+
+	if contextUser == nil {
+		return entry
+	}
+
+	entry.ActorID = contextUser.ID
+	entry.ActorEmail = contextUser.Email
+
+	if contextUser.IsImpersonated() {
+		impersonatorID := contextUser.Actor.ID
+		entry.ImpersonatorID = &impersonatorID
+	}
+
+	return entry
+}
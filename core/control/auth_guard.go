@@ -1,37 +1,44 @@
 package control
 
 import (
-	"context"
-	"net/http"
+	"log"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/el-Mike/gochat/auth"
 	"github.com/el-Mike/gochat/core/api"
-	"github.com/el-Mike/gochat/persist"
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-var ctx = context.Background()
-
 // AuthGuard checks if given request can be properly authenticated, by
 // veryfying the token.
 type AuthGuard struct {
-	authManager *auth.AuthManager
-	cache       persist.Cache
+	authManager   *auth.AuthManager
+	tokenVerifier *auth.PersonalAccessTokenManager
 }
 
 // NewAuthGuard - returns new AuthGuard instance.
 func NewAuthGuard() *AuthGuard {
 	return &AuthGuard{
-		authManager: auth.NewAuthManager(),
-		cache:       persist.RedisCache,
+		authManager:   auth.NewAuthManager(),
+		tokenVerifier: auth.NewPersonalAccessTokenManager(),
 	}
 }
 
 // Checks if given request contains valid token, and returns ContextUser if so.
-// Otherwise, APIError will be returned.
-func (ag *AuthGuard) CheckAuth(request *http.Request, apiSecret string) (*ContextUser, *api.APIError) {
-	token, err := ag.authManager.VerifyToken(request, apiSecret)
+// Otherwise, APIError will be returned. Session's last activity is saved as well.
+// Both session's JWTs and personal access tokens are accepted. Session's JWT can be sent
+// in a cookie as well - unsafe requests have to contain CSRF token then.
+func (ag *AuthGuard) CheckAuth(ctx *gin.Context, apiSecret string) (*ContextUser, *api.APIError) {
+	if tokenString := auth.ExtractToken(ctx.Request); auth.IsPersonalAccessToken(tokenString) {
+		return ag.checkPersonalAccessToken(ctx, tokenString)
+	}
+
+	if err := ag.authManager.VerifyCSRFToken(ctx.Request); err != nil {
+		return nil, api.NewCSRFTokenInvalidError()
+	}
+
+	token, err := ag.authManager.VerifyToken(ctx.Request, apiSecret)
 
 	if err != nil {
 		return nil, api.NewAuthorizationError(err)
@@ -50,13 +57,18 @@ func (ag *AuthGuard) CheckAuth(request *http.Request, apiSecret string) (*Contex
 		return nil, api.NewTokenMalforedError()
 	}
 
-	// If there is no authentication entry is Redis store, it means that
-	// user logged out from the application - therefore token expired,
-	// even if it's still valid time-wise.
-	if err := ag.cache.Get(ctx, authUUID.String()).Err(); err != nil {
+	// If there is no session in Redis store, it means that user logged out
+	// from the application - therefore token expired, even if it's still valid time-wise.
+	session, sessionErr := ag.authManager.GetSession(authUUID)
+
+	if sessionErr != nil {
 		return nil, api.NewTokenExpiredError()
 	}
 
+	if err := ag.authManager.TouchSession(session, GetClientInfo(ctx)); err != nil {
+		log.Printf("Saving session's activity failed: %v", err)
+	}
+
 	email := claims["email"].(string)
 	role := claims["role"].(string)
 
@@ -65,7 +77,47 @@ func (ag *AuthGuard) CheckAuth(request *http.Request, apiSecret string) (*Contex
 		AuthUUID: authUUID,
 		Email:    email,
 		Role:     role,
+
+		TwoFactorEnrollmentRequired: TwoFactorRequiredRoles[role] && !session.TwoFactorVerified,
+
+		Actor: session.Actor,
+	}
+
+	if session.WorkspaceID != nil {
+		currentUser.WorkspaceID = *session.WorkspaceID
 	}
 
 	return currentUser, nil
 }
+
+// checkPersonalAccessToken - returns ContextUser for the owner of given personal access token.
+// Token's scopes are applied on top of user's role.
+func (ag *AuthGuard) checkPersonalAccessToken(ctx *gin.Context, tokenString string) (*ContextUser, *api.APIError) {
+	token, user, err := ag.tokenVerifier.VerifyPersonalAccessToken(tokenString, GetClientInfo(ctx))
+
+	if err == auth.ErrPersonalAccessTokenInvalid {
+		return nil, api.NewPersonalAccessTokenInvalidError()
+	}
+
+	if err != nil {
+		return nil, api.NewInternalError(err)
+	}
+
+	return &ContextUser{
+		ID:       user.ID,
+		AuthUUID: token.ID,
+		Email:    user.Email,
+		Role:     user.Role,
+
+		PersonalAccessToken: true,
+		Scopes:              token.GetScopes(),
+	}, nil
+}
+
+// GetClientInfo - returns the description of the client given request has been sent from.
+func GetClientInfo(ctx *gin.Context) *auth.ClientInfo {
+	return &auth.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
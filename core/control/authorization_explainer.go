@@ -0,0 +1,186 @@
+package control
+
+import (
+	"encoding/json"
+
+	"github.com/el-Mike/restrict"
+)
+
+// AuthorizationExplanation - describes how the decision about an access request has been made.
+type AuthorizationExplanation struct {
+	// Allowed - the decision, made the same way HandlerCreator makes it.
+	Allowed bool `json:"allowed"`
+
+	// Reason - why access has been denied, if it has.
+	Reason string `json:"reason,omitempty"`
+
+	// Roles - subject's role and all the roles it inherits from, in the order they are evaluated.
+	Roles []*RoleExplanation `json:"roles"`
+}
+
+// RoleExplanation - describes evaluation of a single role's grants.
+type RoleExplanation struct {
+	Role string `json:"role"`
+
+	// Path - inheritance path leading from subject's role to this one.
+	Path []string `json:"path"`
+
+	Parents []string `json:"parents"`
+
+	// Granted - true if role's own permissions grant the access.
+	Granted bool `json:"granted"`
+
+	// Permissions - role's permissions for requested resource and action.
+	Permissions []*PermissionExplanation `json:"permissions"`
+
+	// OtherActions - other actions role's permissions for requested resource grant.
+	OtherActions []string `json:"otherActions"`
+
+	// Error - why the role could not be evaluated (e.g. it does not exist).
+	Error string `json:"error,omitempty"`
+}
+
+// PermissionExplanation - describes evaluation of a single permission.
+type PermissionExplanation struct {
+	Action     string                  `json:"action"`
+	Granted    bool                    `json:"granted"`
+	Conditions []*ConditionExplanation `json:"conditions"`
+}
+
+// ConditionExplanation - describes evaluation of a single condition.
+type ConditionExplanation struct {
+	Type      string          `json:"type"`
+	Options   json.RawMessage `json:"options"`
+	Satisfied bool            `json:"satisfied"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// AuthorizationExplainer - explains authorization decisions, so the policy can be debugged.
+type AuthorizationExplainer struct{}
+
+// NewAuthorizationExplainer - returns AuthorizationExplainer instance.
+func NewAuthorizationExplainer() *AuthorizationExplainer {
+	return &AuthorizationExplainer{}
+}
+
+// Explain - checks if given subject can perform given action on the resource, using the same
+// policy as HandlerCreator, and describes every role, permission and condition evaluated on
+// the way. Unlike the access check itself, explanation evaluates all the conditions and all
+// the parents, even if access is already granted.
+func (ae *AuthorizationExplainer) Explain(
+	subject *ContextUser,
+	resource restrict.Resource,
+	action string,
+) (*AuthorizationExplanation, error) {
+	policyManager, err := getPolicyManager()
+
+	if err != nil {
+		return nil, err
+	}
+
+	request := &restrict.AccessRequest{
+		Subject:  subject,
+		Resource: resource,
+		Actions:  []string{action},
+	}
+
+	explanation := &AuthorizationExplanation{
+		Roles: []*RoleExplanation{},
+	}
+
+	if err := restrict.NewAccessManager(policyManager).Authorize(request); err != nil {
+		explanation.Reason = err.Error()
+	} else {
+		explanation.Allowed = true
+	}
+
+	explainRole(policyManager, explanation, request, subject.GetRole(), []string{})
+
+	return explanation, nil
+}
+
+// explainRole - adds the explanation of given role, followed by its parents, to the explanation.
+func explainRole(
+	policyManager *restrict.PolicyManager,
+	explanation *AuthorizationExplanation,
+	request *restrict.AccessRequest,
+	roleID string,
+	path []string,
+) {
+	roleExplanation := &RoleExplanation{
+		Role:         roleID,
+		Path:         append(append([]string{}, path...), roleID),
+		Parents:      []string{},
+		Permissions:  []*PermissionExplanation{},
+		OtherActions: []string{},
+	}
+
+	explanation.Roles = append(explanation.Roles, roleExplanation)
+
+	for _, visitedRoleID := range path {
+		if visitedRoleID == roleID {
+			roleExplanation.Error = "Role inherits from itself."
+			return
+		}
+	}
+
+	role, err := policyManager.GetRole(roleID)
+
+	if err != nil {
+		roleExplanation.Error = err.Error()
+		return
+	}
+
+	if role.Parents != nil {
+		roleExplanation.Parents = role.Parents
+	}
+
+	action := request.Actions[0]
+
+	for _, permission := range role.Grants[request.Resource.GetResourceName()] {
+		if permission.Action != action {
+			roleExplanation.OtherActions = append(roleExplanation.OtherActions, permission.Action)
+			continue
+		}
+
+		permissionExplanation := explainPermission(permission, request)
+
+		roleExplanation.Permissions = append(roleExplanation.Permissions, permissionExplanation)
+		roleExplanation.Granted = roleExplanation.Granted || permissionExplanation.Granted
+	}
+
+	for _, parentID := range role.Parents {
+		explainRole(policyManager, explanation, request, parentID, roleExplanation.Path)
+	}
+}
+
+// explainPermission - evaluates all the conditions of given permission.
+func explainPermission(permission *restrict.Permission, request *restrict.AccessRequest) *PermissionExplanation {
+	permissionExplanation := &PermissionExplanation{
+		Action:     permission.Action,
+		Granted:    true,
+		Conditions: []*ConditionExplanation{},
+	}
+
+	for _, condition := range permission.Conditions {
+		conditionExplanation := &ConditionExplanation{
+			Type:      condition.Type(),
+			Satisfied: true,
+		}
+
+		if options, err := json.Marshal(condition); err == nil {
+			conditionExplanation.Options = options
+		}
+
+		if err := condition.Check(request); err != nil {
+			conditionExplanation.Satisfied = false
+			conditionExplanation.Reason = err.Error()
+
+			permissionExplanation.Granted = false
+		}
+
+		permissionExplanation.Conditions = append(permissionExplanation.Conditions, conditionExplanation)
+	}
+
+	return permissionExplanation
+}
@@ -0,0 +1,157 @@
+package control
+
+import (
+	"errors"
+
+	"github.com/el-Mike/restrict"
+	"github.com/google/uuid"
+)
+
+const (
+	// IsParticipantConditionType - IsParticipantCondition's type identifier.
+	IsParticipantConditionType = "IS_PARTICIPANT"
+
+	// HasConversationRoleConditionType - HasConversationRoleCondition's type identifier.
+	HasConversationRoleConditionType = "HAS_CONVERSATION_ROLE"
+
+	// HasWorkspaceRoleConditionType - HasWorkspaceRoleCondition's type identifier.
+	HasWorkspaceRoleConditionType = "HAS_WORKSPACE_ROLE"
+)
+
+// ParticipantsHolder - interface that needs to be implemented by resources
+// which access depends on Subject's participation (e.g. Conversation).
+type ParticipantsHolder interface {
+	HasParticipant(userID uuid.UUID) bool
+}
+
+// IsParticipantCondition - checks whether request's Subject participates
+// in request's Resource.
+type IsParticipantCondition struct {
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// Type - returns Condition's type.
+func (c *IsParticipantCondition) Type() string {
+	return IsParticipantConditionType
+}
+
+// Check - returns nil if Subject participates in the Resource, error otherwise.
+func (c *IsParticipantCondition) Check(request *restrict.AccessRequest) error {
+	contextUser, ok := request.Subject.(*ContextUser)
+
+	if !ok {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject is not a ContextUser"))
+	}
+
+	holder, ok := request.Resource.(ParticipantsHolder)
+
+	if !ok {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Resource does not have participants"))
+	}
+
+	if !holder.HasParticipant(contextUser.ID) {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject is not a participant"))
+	}
+
+	return nil
+}
+
+// ConversationRoleHolder - interface that needs to be implemented by resources which access
+// depends on Subject's role within a Conversation (e.g. Conversation, Message).
+type ConversationRoleHolder interface {
+	GetParticipantRole(userID uuid.UUID) string
+}
+
+// HasConversationRoleCondition - checks whether request's Subject has one of given roles
+// within request's Resource's Conversation.
+type HasConversationRoleCondition struct {
+	ID    string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Roles []string `json:"roles" yaml:"roles"`
+}
+
+// Type - returns Condition's type.
+func (c *HasConversationRoleCondition) Type() string {
+	return HasConversationRoleConditionType
+}
+
+// Check - returns nil if Subject has one of Condition's roles within the Resource, error otherwise.
+func (c *HasConversationRoleCondition) Check(request *restrict.AccessRequest) error {
+	contextUser, ok := request.Subject.(*ContextUser)
+
+	if !ok {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject is not a ContextUser"))
+	}
+
+	holder, ok := request.Resource.(ConversationRoleHolder)
+
+	if !ok {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Resource does not have conversation roles"))
+	}
+
+	participantRole := holder.GetParticipantRole(contextUser.ID)
+
+	if participantRole == "" {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject is not a participant"))
+	}
+
+	for _, role := range c.Roles {
+		if participantRole == role {
+			return nil
+		}
+	}
+
+	return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject does not have required conversation role"))
+}
+
+// HasWorkspaceRoleCondition - checks whether request's Subject has one of given roles
+// within the Workspace the request is made in.
+type HasWorkspaceRoleCondition struct {
+	ID    string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Roles []string `json:"roles" yaml:"roles"`
+}
+
+// Type - returns Condition's type.
+func (c *HasWorkspaceRoleCondition) Type() string {
+	return HasWorkspaceRoleConditionType
+}
+
+// Check - returns nil if Subject has one of Condition's roles within current Workspace, error otherwise.
+func (c *HasWorkspaceRoleCondition) Check(request *restrict.AccessRequest) error {
+	contextUser, ok := request.Subject.(*ContextUser)
+
+	if !ok {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject is not a ContextUser"))
+	}
+
+	if contextUser.WorkspaceRole == "" {
+		return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject is not a workspace member"))
+	}
+
+	for _, role := range c.Roles {
+		if contextUser.WorkspaceRole == role {
+			return nil
+		}
+	}
+
+	return restrict.NewConditionNotSatisfiedError(c, request, errors.New("Subject does not have required workspace role"))
+}
+
+func init() {
+	if err := restrict.RegisterConditionFactory(IsParticipantConditionType, func() restrict.Condition {
+		return new(IsParticipantCondition)
+	}); err != nil {
+		panic(err)
+	}
+
+	if err := restrict.RegisterConditionFactory(HasConversationRoleConditionType, func() restrict.Condition {
+		return new(HasConversationRoleCondition)
+	}); err != nil {
+		panic(err)
+	}
+
+	if err := restrict.RegisterConditionFactory(HasWorkspaceRoleConditionType, func() restrict.Condition {
+		return new(HasWorkspaceRoleCondition)
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -1,6 +1,7 @@
 package control
 
 import (
+	"github.com/el-Mike/gochat/auth"
 	"github.com/google/uuid"
 )
 
@@ -13,6 +14,46 @@ type ContextUser struct {
 	AuthUUID uuid.UUID `json:"authUUID"`
 	Email    string    `json:"email"`
 	Role     string    `json:"role"`
+
+	// TwoFactorEnrollmentRequired - true if user's role requires two-factor authentication,
+	// but current session has not been verified with it.
+	TwoFactorEnrollmentRequired bool `json:"twoFactorEnrollmentRequired"`
+
+	// PersonalAccessToken - true if user has been authenticated with personal access token,
+	// instead of session's JWT. In such case, AuthUUID holds the ID of the token.
+	PersonalAccessToken bool `json:"personalAccessToken"`
+
+	// Scopes - scopes of the personal access token user has been authenticated with.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Actor - the admin who actually acts, if user is impersonated within current session.
+	Actor *auth.Actor `json:"actor,omitempty"`
+
+	// WorkspaceID - the Workspace current request is made in. It's resolved only for the routes
+	// with AccessRules, which are not workspace-independent - otherwise, it's the Workspace
+	// selected for the session, if any.
+	WorkspaceID uuid.UUID `json:"workspaceId"`
+
+	// WorkspaceRole - user's role within the Workspace, or empty string if user is not its
+	// member (e.g. super admin managing the Workspace).
+	WorkspaceRole string `json:"workspaceRole,omitempty"`
+}
+
+// IsImpersonated - returns true if user is impersonated by an admin within current session.
+func (cu *ContextUser) IsImpersonated() bool {
+	return cu.Actor != nil
+}
+
+// HasScope - returns true if user has been authenticated with personal access token
+// with given scope.
+func (cu *ContextUser) HasScope(scope string) bool {
+	for _, userScope := range cu.Scopes {
+		if userScope == scope {
+			return true
+		}
+	}
+
+	return false
 }
 
 // GetRole - restrict's Subject implementation.
@@ -2,14 +2,22 @@ package control
 
 import (
 	"log"
+	"net/http"
 	"os"
 
+	"github.com/el-Mike/gochat/audit"
+	"github.com/el-Mike/gochat/auth"
 	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/models"
 	"github.com/el-Mike/restrict"
-	"github.com/el-Mike/restrict/adapters"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// ImpersonatorHeader - response header holding the email of the admin impersonating
+// current user, set for all the requests made within impersonated sessions.
+const ImpersonatorHeader = "X-Impersonated-By"
+
 // BasicControllerFn - controller function for unauthenticated routes.
 type BasicControllerFn func(
 	ctx *gin.Context,
@@ -21,24 +29,52 @@ type AuthenticatedControllerFn func(
 	user *ContextUser,
 ) (interface{}, *api.APIError)
 
+// StreamControllerFn - controller function for authenticated routes, that write
+// the response on their own (e.g. WebSocket, Server-Sent Events).
+type StreamControllerFn func(
+	ctx *gin.Context,
+	user *ContextUser,
+) *api.APIError
+
+// routeOptions - describes who can access the route, apart from its AccessRules.
+type routeOptions struct {
+	// allowPendingEnrollment - route is available also to the users who still have to enroll
+	// two-factor authentication required for their role.
+	allowPendingEnrollment bool
+
+	// workspaceIndependent - route is not made in any Workspace, even if it has AccessRules.
+	workspaceIndependent bool
+
+	// managesAccount - route changes user's account (e.g. password or personal access tokens),
+	// which would let the admin keep the access after impersonation ends.
+	managesAccount bool
+}
+
 // HandlerCreator - takes desired controller function and produces
 // gin's HandlerFunc. It also takes care of setting response body based on
 // controller's return values.
 type HandlerCreator struct {
-	authGuard     *AuthGuard
-	accessManager *restrict.AccessManager
+	authGuard            *AuthGuard
+	workspaceGuard       *WorkspaceGuard
+	accessManager        *restrict.AccessManager
+	impersonationAuditor *auth.ImpersonationAuditor
+	auditLogger          *audit.Logger
 }
 
-// NewHandlerCreator - returns HandlerCreator instance.
+// NewHandlerCreator - returns HandlerCreator instance, authorizing requests with
+// DefaultPolicyManager. If it has not been initialized, default Policy is used.
 func NewHandlerCreator() (*HandlerCreator, error) {
-	policyManager, err := restrict.NewPolicyManager(adapters.NewInMemoryAdapter(Policy), true)
+	policyManager, err := getPolicyManager()
 	if err != nil {
 		return nil, err
 	}
 
 	return &HandlerCreator{
-		authGuard:     NewAuthGuard(),
-		accessManager: restrict.NewAccessManager(policyManager),
+		authGuard:            NewAuthGuard(),
+		workspaceGuard:       NewWorkspaceGuard(),
+		accessManager:        restrict.NewAccessManager(policyManager),
+		impersonationAuditor: auth.NewImpersonationAuditor(),
+		auditLogger:          audit.NewLogger(),
 	}, nil
 }
 
@@ -61,54 +97,248 @@ func (hc *HandlerCreator) CreateUnauthenticated(controllerFn BasicControllerFn)
 func (hc *HandlerCreator) CreateAuthenticated(
 	controllerFn AuthenticatedControllerFn,
 	accessRules []*AccessRule,
+) gin.HandlerFunc {
+	return hc.createAuthenticated(controllerFn, accessRules, routeOptions{})
+}
+
+// CreateWorkspaceIndependent - creates authenticated route, which is not made in any Workspace
+// (e.g. listing user's Workspaces) - its AccessRules are checked without resolving one.
+func (hc *HandlerCreator) CreateWorkspaceIndependent(
+	controllerFn AuthenticatedControllerFn,
+	accessRules []*AccessRule,
+) gin.HandlerFunc {
+	return hc.createAuthenticated(controllerFn, accessRules, routeOptions{workspaceIndependent: true})
+}
+
+// CreateAccountManagement - creates authenticated route changing current user's account
+// (e.g. password, sessions or personal access tokens). It's available only with session's JWT,
+// and not within impersonated sessions.
+func (hc *HandlerCreator) CreateAccountManagement(controllerFn AuthenticatedControllerFn) gin.HandlerFunc {
+	return hc.createAuthenticated(controllerFn, []*AccessRule{}, routeOptions{managesAccount: true})
+}
+
+// CreateTwoFactorEnrollment - creates authenticated route, which is available also
+// to the users who still have to enroll two-factor authentication required for their role.
+func (hc *HandlerCreator) CreateTwoFactorEnrollment(controllerFn AuthenticatedControllerFn) gin.HandlerFunc {
+	return hc.createAuthenticated(controllerFn, []*AccessRule{}, routeOptions{allowPendingEnrollment: true})
+}
+
+// createAuthenticated - creates authenticated route with given options.
+func (hc *HandlerCreator) createAuthenticated(
+	controllerFn AuthenticatedControllerFn,
+	accessRules []*AccessRule,
+	options routeOptions,
 ) gin.HandlerFunc {
 	apiSecret := os.Getenv("API_SECRET")
 
 	return func(ctx *gin.Context) {
-		contextUser, err := hc.authGuard.CheckAuth(ctx.Request, apiSecret)
+		defer hc.recordImpersonatedAction(ctx)
+
+		contextUser, err := hc.authenticate(ctx, apiSecret, accessRules, options)
 
 		if err != nil {
 			ctx.JSON(api.ResponseFromError(err))
 			return
 		}
 
-		for _, rule := range accessRules {
+		result, err := controllerFn(ctx, contextUser)
 
-			if rule.Action == "" || rule.ResourceID == "" {
-				log.Print("Malformed AccessRule - omitting...")
-				continue
+		if err != nil {
+			ctx.JSON(api.ResponseFromError(err))
+			return
+		}
+
+		ctx.JSON(api.GetSuccessResponse(result))
+	}
+}
+
+// CreateAuthenticatedStream - creates authenticated route, which controller takes over
+// the connection (WebSocket, Server-Sent Events) and writes the response on its own.
+// As browsers cannot set headers for such connections, token can also be passed
+// with "token" query param.
+func (hc *HandlerCreator) CreateAuthenticatedStream(
+	controllerFn StreamControllerFn,
+	accessRules []*AccessRule,
+) gin.HandlerFunc {
+	apiSecret := os.Getenv("API_SECRET")
+
+	return func(ctx *gin.Context) {
+		defer hc.recordImpersonatedAction(ctx)
+
+		if token := ctx.Query("token"); token != "" && ctx.GetHeader("Authorization") == "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		contextUser, err := hc.authenticate(ctx, apiSecret, accessRules, routeOptions{})
+
+		if err != nil {
+			ctx.JSON(api.ResponseFromError(err))
+			return
+		}
+
+		if err := controllerFn(ctx, contextUser); err != nil {
+			ctx.JSON(api.ResponseFromError(err))
+		}
+	}
+}
+
+// authenticate - checks request's authentication and given AccessRules. Returns
+// ContextUser if request can be processed, APIError otherwise.
+func (hc *HandlerCreator) authenticate(
+	ctx *gin.Context,
+	apiSecret string,
+	accessRules []*AccessRule,
+	options routeOptions,
+) (*ContextUser, *api.APIError) {
+	contextUser, err := hc.authGuard.CheckAuth(ctx, apiSecret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Set(ContextUserKey, contextUser)
+
+	if contextUser.IsImpersonated() {
+		ctx.Header(ImpersonatorHeader, contextUser.Actor.Email)
+
+		if options.managesAccount {
+			return nil, api.NewImpersonationForbiddenError()
+		}
+	}
+
+	if contextUser.TwoFactorEnrollmentRequired && !options.allowPendingEnrollment {
+		return nil, api.NewTwoFactorEnrollmentRequiredError()
+	}
+
+	// Routes without any rules (e.g. listing user's sessions) are available
+	// only with session's JWT.
+	if contextUser.PersonalAccessToken && len(accessRules) == 0 {
+		return nil, api.NewInsufficientScopeError()
+	}
+
+	// Such routes are not bound to any Workspace either - others are made in the Workspace
+	// resolved here, which user has to be a member of, unless they are workspace-independent.
+	if len(accessRules) > 0 && !options.workspaceIndependent {
+		if err := hc.workspaceGuard.CheckWorkspace(ctx, contextUser); err != nil {
+			// Only denials are audited - other errors do not depend on user's access.
+			if err.Status == http.StatusForbidden {
+				hc.recordAccessDenied(ctx, contextUser, models.WORKSPACE_RESOURCE, ctx.Param(WorkspaceParam), err.Message)
 			}
 
-			var resource restrict.Resource
+			return nil, err
+		}
+	}
 
-			if rule.ResourceProvider != nil {
-				resource = rule.ResourceProvider(ctx, contextUser)
-			} else {
-				resource = restrict.UseResource(rule.ResourceID)
+	for _, rule := range accessRules {
+		if rule.Scope != "" {
+			if contextUser.PersonalAccessToken && !contextUser.HasScope(rule.Scope) {
+				return nil, api.NewInsufficientScopeError()
 			}
 
-			err := hc.accessManager.Authorize(&restrict.AccessRequest{
-				Subject:  contextUser,
-				Resource: resource,
-				Actions:  []string{rule.Action},
-			})
-
-			if _, ok := err.(*restrict.AccessDeniedError); err != nil && ok {
-				ctx.JSON(api.ResponseFromError(api.NewAccessDeniedError(rule.ResourceID, string(rule.Action))))
-				return
-			} else {
-				ctx.JSON(api.ResponseFromError(api.NewInternalError(err)))
-				return
+			if rule.Action == "" && rule.ResourceID == "" {
+				continue
 			}
 		}
 
-		result, err := controllerFn(ctx, contextUser)
+		if rule.Action == "" || rule.ResourceID == "" {
+			log.Print("Malformed AccessRule - omitting...")
+			continue
+		}
+
+		if contextUser.PersonalAccessToken && !ScopesPermit(contextUser.Scopes, rule.ResourceID, rule.Action) {
+			return nil, api.NewInsufficientScopeError()
+		}
+
+		var resource restrict.Resource
+
+		if rule.ResourceProvider != nil {
+			resource = rule.ResourceProvider(ctx, contextUser)
+
+			// ResourceProvider returns nil when requested resource cannot be found.
+			if resource == nil {
+				return nil, api.NewNotFoundError(rule.ResourceID)
+			}
+		} else {
+			resource = restrict.UseResource(rule.ResourceID)
+		}
+
+		err := hc.accessManager.Authorize(&restrict.AccessRequest{
+			Subject:  contextUser,
+			Resource: resource,
+			Actions:  []string{rule.Action},
+		})
 
 		if err != nil {
-			ctx.JSON(api.ResponseFromError(err))
-			return
+			if _, ok := err.(*restrict.AccessDeniedError); ok {
+				hc.recordAccessDenied(ctx, contextUser, rule.ResourceID, getResourceID(resource), "action: "+rule.Action)
+
+				return nil, api.NewAccessDeniedError(rule.ResourceID, string(rule.Action))
+			}
+
+			return nil, api.NewInternalError(err)
 		}
+	}
 
-		ctx.JSON(api.GetSuccessResponse(result))
+	return contextUser, nil
+}
+
+// recordImpersonatedAction - records the request, if it has been made within impersonated
+// session. Requests rejected by access rules are recorded as well.
+func (hc *HandlerCreator) recordImpersonatedAction(ctx *gin.Context) {
+	value, ok := ctx.Get(ContextUserKey)
+
+	if !ok {
+		return
 	}
+
+	contextUser, ok := value.(*ContextUser)
+
+	if !ok || !contextUser.IsImpersonated() {
+		return
+	}
+
+	client := GetClientInfo(ctx)
+
+	err := hc.impersonationAuditor.RecordAction(&models.ImpersonationActionModel{
+		SessionID:  contextUser.AuthUUID,
+		UserID:     contextUser.ID,
+		ActorID:    contextUser.Actor.ID,
+		ActorEmail: contextUser.Actor.Email,
+		Method:     ctx.Request.Method,
+		Path:       ctx.Request.URL.Path,
+		Status:     ctx.Writer.Status(),
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+	})
+
+	if err != nil {
+		log.Printf("Recording impersonated action failed: %v", err)
+	}
+}
+
+// recordAccessDenied - records the request rejected by the authorization in the audit log.
+func (hc *HandlerCreator) recordAccessDenied(
+	ctx *gin.Context,
+	contextUser *ContextUser,
+	targetType string,
+	targetID string,
+	details string,
+) {
+	entry := NewAuditEntry(ctx, contextUser, audit.AccessDeniedAction, models.AuditDeniedResult)
+
+	entry.TargetType = targetType
+	entry.TargetID = targetID
+	entry.Details = details
+
+	hc.auditLogger.Log(entry)
+}
+
+// getResourceID - returns the ID of given resource, or empty string if it's not an entity
+// (e.g. the resource has not been loaded by ResourceProvider).
+func getResourceID(resource restrict.Resource) string {
+	if entity, ok := resource.(interface{ GetID() uuid.UUID }); ok {
+		return entity.GetID().String()
+	}
+
+	return ""
 }
@@ -21,10 +21,25 @@ const (
 
 	DeleteAction    = "delete"
 	DeleteOwnAction = "deleteOwn"
+
+	ImpersonateAction = "impersonate"
+
+	// ManageParticipantsAction - adding and removing Conversation's participants.
+	ManageParticipantsAction = "manageParticipants"
+
+	// ManageRolesAction - changing roles of Conversation's participants and its ownership.
+	ManageRolesAction = "manageRoles"
+
+	// ManageMembersAction - adding and removing Workspace's members, and changing their roles.
+	ManageMembersAction = "manageMembers"
 )
 
 const (
-	AccessOwnPreset = "accessOwn"
+	AccessOwnPreset                   = "accessOwn"
+	AccessParticipantPreset           = "accessParticipant"
+	AccessConversationOwnerPreset     = "accessConversationOwner"
+	AccessConversationModeratorPreset = "accessConversationModerator"
+	AccessWorkspaceAdminPreset        = "accessWorkspaceAdmin"
 )
 
 var userRole = &restrict.Role{
@@ -32,15 +47,28 @@ var userRole = &restrict.Role{
 	Description: "User is a standard user of the application.",
 	Grants: restrict.GrantsMap{
 		models.MESSAGE_RESOURCE: {
-			&restrict.Permission{Action: CreateAction},
-			&restrict.Permission{Action: ReadAction, Preset: AccessOwnPreset},
+			&restrict.Permission{Action: CreateAction, Preset: AccessParticipantPreset},
+			&restrict.Permission{Action: ReadAction, Preset: AccessParticipantPreset},
 			&restrict.Permission{Action: UpdateOwnAction, Preset: AccessOwnPreset},
 			&restrict.Permission{Action: DeleteOwnAction, Preset: AccessOwnPreset},
+			&restrict.Permission{Action: DeleteAction, Preset: AccessOwnPreset},
+			&restrict.Permission{Action: DeleteAction, Preset: AccessConversationModeratorPreset},
 		},
 		models.CONVERSATION_RESOURCE: {
 			&restrict.Permission{Action: CreateAction},
+			&restrict.Permission{Action: ReadAction, Preset: AccessParticipantPreset},
+			&restrict.Permission{Action: UpdateAction, Preset: AccessConversationOwnerPreset},
+			&restrict.Permission{Action: DeleteAction, Preset: AccessConversationOwnerPreset},
+			&restrict.Permission{Action: ManageParticipantsAction, Preset: AccessConversationModeratorPreset},
+			&restrict.Permission{Action: ManageRolesAction, Preset: AccessConversationOwnerPreset},
+		},
+		models.WORKSPACE_RESOURCE: {
 			&restrict.Permission{Action: ReadAction},
-			&restrict.Permission{Action: DeleteAction, Preset: AccessOwnPreset},
+			&restrict.Permission{Action: UpdateAction, Preset: AccessWorkspaceAdminPreset},
+			&restrict.Permission{Action: ManageMembersAction, Preset: AccessWorkspaceAdminPreset},
+		},
+		models.USER_RESOURCE: {
+			&restrict.Permission{Action: ReadAction, Preset: AccessWorkspaceAdminPreset},
 		},
 	},
 }
@@ -62,11 +90,36 @@ var adminRole *restrict.Role = &restrict.Role{
 var superAdminRole *restrict.Role = &restrict.Role{
 	ID:          SuperAdminRole,
 	Description: "SuperAdmin can manage all entities in the system.",
-	Grants:      restrict.GrantsMap{},
-	Parents:     []string{AdminRole},
+	Grants: restrict.GrantsMap{
+		models.USER_RESOURCE: {
+			&restrict.Permission{Action: ImpersonateAction},
+		},
+		models.POLICY_RESOURCE: {
+			&restrict.Permission{Action: ReadAction},
+			&restrict.Permission{Action: UpdateAction},
+		},
+		models.WORKSPACE_RESOURCE: {
+			&restrict.Permission{Action: CreateAction},
+			&restrict.Permission{Action: UpdateAction},
+			&restrict.Permission{Action: ManageMembersAction},
+		},
+		models.AUDIT_LOG_RESOURCE: {
+			&restrict.Permission{Action: ReadAction},
+		},
+	},
+	Parents: []string{AdminRole},
 }
 
-// Policy - describes Gochat's RBAC policy definition.
+// TwoFactorRequiredRoles - roles, which users have to use two-factor authentication.
+// Until they enroll it, only the routes allowing enrollment are available to them.
+var TwoFactorRequiredRoles = map[string]bool{
+	UserRole:       false,
+	AdminRole:      true,
+	SuperAdminRole: true,
+}
+
+// Policy - describes Gochat's default RBAC policy definition. It's stored in the database
+// as the first version of the policy, which can be changed later without a restart.
 var Policy *restrict.PolicyDefinition = &restrict.PolicyDefinition{
 	PermissionPresets: restrict.PermissionPresets{
 		AccessOwnPreset: &restrict.Permission{
@@ -84,6 +137,16 @@ var Policy *restrict.PolicyDefinition = &restrict.PolicyDefinition{
 				},
 			},
 		},
+		AccessParticipantPreset: &restrict.Permission{
+			Conditions: restrict.Conditions{
+				&IsParticipantCondition{
+					ID: "isParticipant",
+				},
+			},
+		},
+		AccessConversationOwnerPreset:     newConversationOwnerPreset(),
+		AccessConversationModeratorPreset: newConversationModeratorPreset(),
+		AccessWorkspaceAdminPreset:        newWorkspaceAdminPreset(),
 	},
 	Roles: restrict.Roles{
 		UserRole:       userRole,
@@ -91,3 +154,39 @@ var Policy *restrict.PolicyDefinition = &restrict.PolicyDefinition{
 		SuperAdminRole: superAdminRole,
 	},
 }
+
+// newConversationOwnerPreset - returns preset permitting Conversation's owner only.
+func newConversationOwnerPreset() *restrict.Permission {
+	return &restrict.Permission{
+		Conditions: restrict.Conditions{
+			&HasConversationRoleCondition{
+				ID:    "isConversationOwner",
+				Roles: []string{models.ConversationOwnerRole},
+			},
+		},
+	}
+}
+
+// newConversationModeratorPreset - returns preset permitting Conversation's owner and moderators.
+func newConversationModeratorPreset() *restrict.Permission {
+	return &restrict.Permission{
+		Conditions: restrict.Conditions{
+			&HasConversationRoleCondition{
+				ID:    "isConversationModerator",
+				Roles: []string{models.ConversationOwnerRole, models.ConversationModeratorRole},
+			},
+		},
+	}
+}
+
+// newWorkspaceAdminPreset - returns preset permitting admins of current Workspace only.
+func newWorkspaceAdminPreset() *restrict.Permission {
+	return &restrict.Permission{
+		Conditions: restrict.Conditions{
+			&HasWorkspaceRoleCondition{
+				ID:    "isWorkspaceAdmin",
+				Roles: []string{models.WorkspaceAdminRole},
+			},
+		},
+	}
+}
@@ -0,0 +1,171 @@
+package control
+
+import (
+	"encoding/json"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/el-Mike/restrict"
+)
+
+// DBPolicyAdapter - restrict.StorageAdapter keeping the policy in the database. Every saved
+// policy becomes its next version, and the latest version is the one loaded.
+type DBPolicyAdapter struct {
+	broker persist.DBBroker
+
+	// Policy stored as the first version, if the database does not contain any.
+	seed *restrict.PolicyDefinition
+}
+
+// NewDBPolicyAdapter - returns DBPolicyAdapter instance, seeding the database with
+// default Policy.
+func NewDBPolicyAdapter() *DBPolicyAdapter {
+	return &DBPolicyAdapter{
+		broker: persist.GormBroker,
+		seed:   Policy,
+	}
+}
+
+// LoadPolicy - returns the latest version of the policy. Every call returns new
+// PolicyDefinition instance, so it can be modified freely.
+func (pa *DBPolicyAdapter) LoadPolicy() (*restrict.PolicyDefinition, error) {
+	policy, _, err := pa.LoadVersion()
+
+	return policy, err
+}
+
+// LoadVersion - returns the latest version of the policy together with its number.
+// If there is no policy in the database yet, the seed is saved as the first version.
+// If the latest version misses some of the policy migrations, they are applied first.
+func (pa *DBPolicyAdapter) LoadVersion() (*restrict.PolicyDefinition, int, error) {
+	policyModel, err := pa.getLatest()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if policyModel == nil {
+		if err := pa.SavePolicy(pa.seed); err != nil {
+			return nil, 0, err
+		}
+
+		return pa.LoadVersion()
+	}
+
+	policy, err := decodePolicy(policyModel)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if policyModel.Revision >= PolicyRevision() {
+		return policy, policyModel.Version, nil
+	}
+
+	return pa.migrate(policy, policyModel)
+}
+
+// SavePolicy - saves given policy as the next version, with all the policy migrations
+// applied. Saving fails if other version with the same number has been saved in the meantime.
+func (pa *DBPolicyAdapter) SavePolicy(policy *restrict.PolicyDefinition) error {
+	definition, err := json.Marshal(policy)
+
+	if err != nil {
+		return err
+	}
+
+	latest, err := pa.getLatest()
+
+	if err != nil {
+		return err
+	}
+
+	version := 1
+
+	if latest != nil {
+		version = latest.Version + 1
+	}
+
+	return pa.broker.Save(&models.PolicyModel{
+		Version:    version,
+		Definition: string(definition),
+		Revision:   PolicyRevision(),
+	}).Err()
+}
+
+// migrate - applies policy migrations missing in given stored policy, and saves the result
+// as the next version. If other instance has migrated the policy in the meantime, its
+// version is returned instead.
+func (pa *DBPolicyAdapter) migrate(
+	policy *restrict.PolicyDefinition,
+	policyModel *models.PolicyModel,
+) (*restrict.PolicyDefinition, int, error) {
+	applyPolicyMigrations(policy, policyModel.Revision)
+
+	if err := ValidatePolicy(policy); err != nil {
+		return nil, 0, err
+	}
+
+	if err := pa.SavePolicy(policy); err != nil {
+		latest, latestErr := pa.getLatest()
+
+		if latestErr != nil || latest == nil || latest.Revision < PolicyRevision() {
+			return nil, 0, err
+		}
+
+		policy, err := decodePolicy(latest)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return policy, latest.Version, nil
+	}
+
+	return policy, policyModel.Version + 1, nil
+}
+
+// getLatest - returns the latest version of the policy, or nil if there is none.
+func (pa *DBPolicyAdapter) getLatest() (*models.PolicyModel, error) {
+	var versions []*models.PolicyModel
+
+	err := pa.broker.Find(&versions, "version = (SELECT MAX(version) FROM policy_models)").Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	return versions[0], nil
+}
+
+// decodePolicy - returns the policy definition stored in given model.
+func decodePolicy(policyModel *models.PolicyModel) (*restrict.PolicyDefinition, error) {
+	policy := &restrict.PolicyDefinition{}
+
+	if err := json.Unmarshal([]byte(policyModel.Definition), policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// ClonePolicy - returns a deep copy of given policy.
+func ClonePolicy(policy *restrict.PolicyDefinition) (*restrict.PolicyDefinition, error) {
+	data, err := json.Marshal(policy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &restrict.PolicyDefinition{}
+
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
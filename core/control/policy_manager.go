@@ -0,0 +1,106 @@
+package control
+
+import (
+	"context"
+	"log"
+
+	"github.com/el-Mike/gochat/persist"
+	"github.com/el-Mike/restrict"
+	"github.com/el-Mike/restrict/adapters"
+)
+
+// PolicyChannel - PubSub channel policy changes are announced with.
+const PolicyChannel = "gochat:policy"
+
+// policyLoader - interface for an entity holding the policy, which can be reloaded
+// from its storage.
+type policyLoader interface {
+	LoadPolicy() error
+}
+
+// DefaultPolicyManager - PolicyManager shared by all the HandlerCreators. Nil until
+// InitPolicyManager is called.
+var DefaultPolicyManager *restrict.PolicyManager
+
+// PolicyReloader - reloads the policy of PolicyManager on all the application's instances,
+// after it has been changed in the database.
+type PolicyReloader struct {
+	manager policyLoader
+	pubSub  persist.PubSub
+	ctx     context.Context
+}
+
+// DefaultPolicyReloader - PolicyReloader of DefaultPolicyManager.
+var DefaultPolicyReloader *PolicyReloader
+
+// NewPolicyReloader - returns new PolicyReloader instance.
+func NewPolicyReloader(manager policyLoader, pubSub persist.PubSub) *PolicyReloader {
+	return &PolicyReloader{
+		manager: manager,
+		pubSub:  pubSub,
+		ctx:     context.Background(),
+	}
+}
+
+// InitPolicyManager - initializes DefaultPolicyManager with the latest policy stored
+// in the database, and starts reloading it whenever any instance changes it.
+func InitPolicyManager() (*restrict.PolicyManager, error) {
+	if DefaultPolicyManager != nil {
+		return DefaultPolicyManager, nil
+	}
+
+	// Policy is changed only by saving its new version, so PolicyManager never saves it.
+	policyManager, err := restrict.NewPolicyManager(NewDBPolicyAdapter(), false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	DefaultPolicyManager = policyManager
+	DefaultPolicyReloader = NewPolicyReloader(policyManager, persist.RedisCache)
+
+	go DefaultPolicyReloader.Listen()
+
+	return DefaultPolicyManager, nil
+}
+
+// getPolicyManager - returns DefaultPolicyManager, or new PolicyManager of default Policy,
+// if it has not been initialized.
+func getPolicyManager() (*restrict.PolicyManager, error) {
+	if DefaultPolicyManager != nil {
+		return DefaultPolicyManager, nil
+	}
+
+	policy, err := ClonePolicy(Policy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return restrict.NewPolicyManager(adapters.NewInMemoryAdapter(policy), false)
+}
+
+// Reload - reloads the policy on current instance, and tells other instances to do the same.
+func (pr *PolicyReloader) Reload() error {
+	if err := pr.manager.LoadPolicy(); err != nil {
+		return err
+	}
+
+	return pr.pubSub.Publish(pr.ctx, PolicyChannel, []byte{}).Err()
+}
+
+// Listen - reloads the policy every time any instance announces a change.
+// It blocks until the subscription is closed.
+func (pr *PolicyReloader) Listen() {
+	subscription := pr.pubSub.Subscribe(pr.ctx, PolicyChannel)
+
+	pr.listen(subscription)
+}
+
+func (pr *PolicyReloader) listen(subscription persist.Subscription) {
+	for range subscription.Channel() {
+		if err := pr.manager.LoadPolicy(); err != nil {
+			log.Printf("Reloading policy failed: %v", err)
+		}
+	}
+}
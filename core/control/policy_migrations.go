@@ -0,0 +1,127 @@
+package control
+
+import (
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/restrict"
+)
+
+// policyMigration - change of default Policy, which has to be applied to the policies
+// stored before it was introduced.
+type policyMigration func(policy *restrict.PolicyDefinition)
+
+// policyMigrations - changes of default Policy introduced after storing the policy in
+// the database, in the order they were made. New changes have to be appended.
+var policyMigrations = []policyMigration{
+	migrateConversationRoles,
+	migrateWorkspaces,
+	migrateAuditLog,
+}
+
+// PolicyRevision - returns the number of policy migrations default Policy includes.
+func PolicyRevision() int {
+	return len(policyMigrations)
+}
+
+// applyPolicyMigrations - applies the migrations introduced after given revision to the policy.
+func applyPolicyMigrations(policy *restrict.PolicyDefinition, revision int) {
+	for _, migration := range policyMigrations[revision:] {
+		migration(policy)
+	}
+}
+
+// migrateConversationRoles - adds conversation role presets, and grants USER role access to
+// Conversations and Messages based on them. Conversation's permissions which depended on
+// being its creator depend on being its owner instead.
+func migrateConversationRoles(policy *restrict.PolicyDefinition) {
+	if policy.PermissionPresets == nil {
+		policy.PermissionPresets = restrict.PermissionPresets{}
+	}
+
+	if policy.PermissionPresets[AccessConversationOwnerPreset] == nil {
+		policy.PermissionPresets[AccessConversationOwnerPreset] = newConversationOwnerPreset()
+	}
+
+	if policy.PermissionPresets[AccessConversationModeratorPreset] == nil {
+		policy.PermissionPresets[AccessConversationModeratorPreset] = newConversationModeratorPreset()
+	}
+
+	role := policy.Roles[UserRole]
+
+	if role == nil {
+		return
+	}
+
+	if role.Grants == nil {
+		role.Grants = restrict.GrantsMap{}
+	}
+
+	for _, permission := range role.Grants[models.CONVERSATION_RESOURCE] {
+		if permission.Preset == AccessOwnPreset && (permission.Action == UpdateAction || permission.Action == DeleteAction) {
+			permission.Preset = AccessConversationOwnerPreset
+		}
+	}
+
+	addMissingPermission(role, models.CONVERSATION_RESOURCE, ManageParticipantsAction, AccessConversationModeratorPreset)
+	addMissingPermission(role, models.CONVERSATION_RESOURCE, ManageRolesAction, AccessConversationOwnerPreset)
+	addMissingPermission(role, models.MESSAGE_RESOURCE, DeleteAction, AccessOwnPreset)
+	addMissingPermission(role, models.MESSAGE_RESOURCE, DeleteAction, AccessConversationModeratorPreset)
+}
+
+// migrateWorkspaces - adds Workspace admin preset, grants USER role access to Workspaces
+// and lets Workspace admins read users. SUPER_ADMIN role can manage all the Workspaces.
+func migrateWorkspaces(policy *restrict.PolicyDefinition) {
+	if policy.PermissionPresets == nil {
+		policy.PermissionPresets = restrict.PermissionPresets{}
+	}
+
+	if policy.PermissionPresets[AccessWorkspaceAdminPreset] == nil {
+		policy.PermissionPresets[AccessWorkspaceAdminPreset] = newWorkspaceAdminPreset()
+	}
+
+	if role := policy.Roles[UserRole]; role != nil {
+		if role.Grants == nil {
+			role.Grants = restrict.GrantsMap{}
+		}
+
+		addMissingPermission(role, models.WORKSPACE_RESOURCE, ReadAction, "")
+		addMissingPermission(role, models.WORKSPACE_RESOURCE, UpdateAction, AccessWorkspaceAdminPreset)
+		addMissingPermission(role, models.WORKSPACE_RESOURCE, ManageMembersAction, AccessWorkspaceAdminPreset)
+		addMissingPermission(role, models.USER_RESOURCE, ReadAction, AccessWorkspaceAdminPreset)
+	}
+
+	if role := policy.Roles[SuperAdminRole]; role != nil {
+		if role.Grants == nil {
+			role.Grants = restrict.GrantsMap{}
+		}
+
+		addMissingPermission(role, models.WORKSPACE_RESOURCE, CreateAction, "")
+		addMissingPermission(role, models.WORKSPACE_RESOURCE, UpdateAction, "")
+		addMissingPermission(role, models.WORKSPACE_RESOURCE, ManageMembersAction, "")
+	}
+}
+
+// migrateAuditLog - lets SUPER_ADMIN role read the audit log.
+func migrateAuditLog(policy *restrict.PolicyDefinition) {
+	if role := policy.Roles[SuperAdminRole]; role != nil {
+		if role.Grants == nil {
+			role.Grants = restrict.GrantsMap{}
+		}
+
+		addMissingPermission(role, models.AUDIT_LOG_RESOURCE, ReadAction, "")
+	}
+}
+
+// addMissingPermission - grants given action with given preset to the role, unless
+// it's already granted.
+func addMissingPermission(role *restrict.Role, resourceID, action, preset string) {
+	for _, permission := range role.Grants[resourceID] {
+		if permission.Action == action && permission.Preset == preset {
+			return
+		}
+	}
+
+	role.Grants[resourceID] = append(role.Grants[resourceID], &restrict.Permission{
+		Action: action,
+		Preset: preset,
+	})
+}
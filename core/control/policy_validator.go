@@ -0,0 +1,189 @@
+package control
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/restrict"
+	"github.com/el-Mike/restrict/adapters"
+)
+
+// BuiltInRoles - roles the application relies on, which cannot be removed from the policy.
+var BuiltInRoles = []string{SuperAdminRole, AdminRole, UserRole}
+
+// PolicyInvalidError - returned when policy definition cannot be applied.
+type PolicyInvalidError struct {
+	Reason string
+}
+
+// Error - satisfies standard Error interface.
+func (e *PolicyInvalidError) Error() string {
+	return e.Reason
+}
+
+func newPolicyInvalidError(format string, args ...interface{}) *PolicyInvalidError {
+	return &PolicyInvalidError{
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// ValidatePolicy - checks if given policy definition can be safely applied. Built-in roles
+// have to exist, referenced parents and presets have to be defined, roles cannot inherit
+// from themselves, and SuperAdminRole has to keep unconditional access to the policy,
+// so it can always be fixed. Returns *PolicyInvalidError describing the first problem found.
+func ValidatePolicy(policy *restrict.PolicyDefinition) error {
+	for _, roleID := range BuiltInRoles {
+		if policy.Roles[roleID] == nil {
+			return newPolicyInvalidError("Built-in role %v is missing.", roleID)
+		}
+	}
+
+	for name, preset := range policy.PermissionPresets {
+		if preset == nil {
+			return newPolicyInvalidError("Preset %v is empty.", name)
+		}
+
+		if preset.Preset != "" {
+			return newPolicyInvalidError("Preset %v cannot extend other preset.", name)
+		}
+	}
+
+	for _, roleID := range sortedRoleIDs(policy.Roles) {
+		if err := validateRole(policy, roleID); err != nil {
+			return err
+		}
+	}
+
+	for _, roleID := range sortedRoleIDs(policy.Roles) {
+		if hasInheritanceCycle(policy.Roles, roleID, map[string]bool{}) {
+			return newPolicyInvalidError("Role %v inherits from itself.", roleID)
+		}
+	}
+
+	return validatePolicyAccess(policy)
+}
+
+// ValidateGroupRoles - checks if external groups are mapped only to roles defined by Policy,
+// so mistyped role does not silently demote users logging in with given groups.
+func ValidateGroupRoles(groupRoles map[string]string) error {
+	groups := make([]string, 0, len(groupRoles))
+
+	for group := range groupRoles {
+		groups = append(groups, group)
+	}
+
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		if role := groupRoles[group]; Policy.Roles[role] == nil {
+			return fmt.Errorf("Group \"%s\" is mapped to unknown role %s", group, role)
+		}
+	}
+
+	return nil
+}
+
+// validateRole - checks if parents and presets referenced by given role are defined,
+// and all of its permissions have an action.
+func validateRole(policy *restrict.PolicyDefinition, roleID string) error {
+	role := policy.Roles[roleID]
+
+	if role == nil || roleID == "" || role.ID != roleID {
+		return newPolicyInvalidError("Role %v is malformed.", roleID)
+	}
+
+	for _, parentID := range role.Parents {
+		if policy.Roles[parentID] == nil {
+			return newPolicyInvalidError("Parent %v of role %v does not exist.", parentID, roleID)
+		}
+	}
+
+	for resourceID, permissions := range role.Grants {
+		for _, permission := range permissions {
+			if permission == nil {
+				return newPolicyInvalidError("Role %v has empty permission for %v.", roleID, resourceID)
+			}
+
+			action := permission.Action
+
+			if permission.Preset != "" {
+				preset := policy.PermissionPresets[permission.Preset]
+
+				if preset == nil {
+					return newPolicyInvalidError("Preset %v used by role %v does not exist.", permission.Preset, roleID)
+				}
+
+				if action == "" {
+					action = preset.Action
+				}
+			}
+
+			if action == "" {
+				return newPolicyInvalidError("Role %v has permission for %v without an action.", roleID, resourceID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasInheritanceCycle - returns true if given role can be reached again by following its parents.
+func hasInheritanceCycle(roles restrict.Roles, roleID string, visited map[string]bool) bool {
+	if visited[roleID] {
+		return true
+	}
+
+	visited[roleID] = true
+	defer delete(visited, roleID)
+
+	for _, parentID := range roles[roleID].Parents {
+		if hasInheritanceCycle(roles, parentID, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validatePolicyAccess - checks, using a copy of given policy with presets applied, whether
+// SuperAdminRole can read and update the policy without any conditions.
+func validatePolicyAccess(policy *restrict.PolicyDefinition) error {
+	policyCopy, err := ClonePolicy(policy)
+
+	if err != nil {
+		return newPolicyInvalidError("Policy cannot be serialized: %v", err)
+	}
+
+	policyManager, err := restrict.NewPolicyManager(adapters.NewInMemoryAdapter(policyCopy), false)
+
+	if err != nil {
+		return newPolicyInvalidError("Policy cannot be loaded: %v", err)
+	}
+
+	err = restrict.NewAccessManager(policyManager).Authorize(&restrict.AccessRequest{
+		Subject:  &ContextUser{Role: SuperAdminRole},
+		Resource: restrict.UseResource(models.POLICY_RESOURCE),
+		Actions:  []string{ReadAction, UpdateAction},
+	})
+
+	if err != nil {
+		return newPolicyInvalidError("Role %v has to be able to read and update the policy.", SuperAdminRole)
+	}
+
+	return nil
+}
+
+// sortedRoleIDs - returns IDs of given roles in alphabetical order, so validation
+// reports problems deterministically.
+func sortedRoleIDs(roles restrict.Roles) []string {
+	roleIDs := make([]string, 0, len(roles))
+
+	for roleID := range roles {
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	sort.Strings(roleIDs)
+
+	return roleIDs
+}
@@ -0,0 +1,69 @@
+package control
+
+import (
+	"github.com/el-Mike/gochat/models"
+)
+
+// Map of valid personal access token scopes.
+const (
+	MessagesReadScope       = "messages:read"
+	MessagesWriteScope      = "messages:write"
+	ConversationsReadScope  = "conversations:read"
+	ConversationsWriteScope = "conversations:write"
+	UsersReadScope          = "users:read"
+	UsersWriteScope         = "users:write"
+	WorkspacesReadScope     = "workspaces:read"
+)
+
+// ScopeGrants - actions on resources permitted by each of the scopes. Scopes only narrow
+// what user's role permits - they never grant anything the role does not.
+var ScopeGrants = map[string]map[string][]string{
+	MessagesReadScope: {
+		models.MESSAGE_RESOURCE: {ReadAction},
+	},
+	MessagesWriteScope: {
+		models.MESSAGE_RESOURCE: {ReadAction, CreateAction, UpdateAction, UpdateOwnAction, DeleteAction, DeleteOwnAction},
+	},
+	ConversationsReadScope: {
+		models.CONVERSATION_RESOURCE: {ReadAction},
+	},
+	ConversationsWriteScope: {
+		models.CONVERSATION_RESOURCE: {
+			ReadAction,
+			CreateAction,
+			UpdateAction,
+			DeleteAction,
+			ManageParticipantsAction,
+			ManageRolesAction,
+		},
+	},
+	UsersReadScope: {
+		models.USER_RESOURCE: {ReadAction},
+	},
+	UsersWriteScope: {
+		models.USER_RESOURCE: {ReadAction, CreateAction, UpdateAction, DeleteAction},
+	},
+	WorkspacesReadScope: {
+		models.WORKSPACE_RESOURCE: {ReadAction},
+	},
+}
+
+// IsValidScope - returns true if given scope exists.
+func IsValidScope(scope string) bool {
+	_, ok := ScopeGrants[scope]
+
+	return ok
+}
+
+// ScopesPermit - returns true if any of given scopes permits given action on given resource.
+func ScopesPermit(scopes []string, resourceID string, action string) bool {
+	for _, scope := range scopes {
+		for _, permittedAction := range ScopeGrants[scope][resourceID] {
+			if permittedAction == action {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package control
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/core/api"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WorkspaceParam - route param holding the ID of the Workspace request is made in.
+const WorkspaceParam = "workspaceId"
+
+// WorkspaceGuard - resolves the Workspace given request is made in, and checks if
+// user can access it.
+type WorkspaceGuard struct {
+	broker persist.DBBroker
+}
+
+// NewWorkspaceGuard - returns new WorkspaceGuard instance.
+func NewWorkspaceGuard() *WorkspaceGuard {
+	return &WorkspaceGuard{
+		broker: persist.GormBroker,
+	}
+}
+
+// CheckWorkspace - sets the Workspace request is made in, together with user's role within it,
+// on ContextUser. Workspace passed in route params takes precedence over the one selected for
+// the session - if there is none, the default Workspace is used. Only Workspace's members
+// can access it, except for super admins.
+func (wg *WorkspaceGuard) CheckWorkspace(ctx *gin.Context, contextUser *ContextUser) *api.APIError {
+	workspaceID, apiErr := wg.getWorkspaceID(ctx, contextUser)
+
+	if apiErr != nil {
+		return apiErr
+	}
+
+	member := &models.WorkspaceMemberModel{}
+
+	err := wg.broker.FirstWhere(member, &models.WorkspaceMemberModel{
+		WorkspaceID: workspaceID,
+		UserID:      contextUser.ID,
+	}).Err()
+
+	if err == nil {
+		contextUser.WorkspaceID = workspaceID
+		contextUser.WorkspaceRole = member.Role
+
+		return nil
+	}
+
+	if contextUser.Role != SuperAdminRole {
+		return api.NewWorkspaceAccessDeniedError()
+	}
+
+	if err := wg.broker.First(&models.WorkspaceModel{}, workspaceID).Err(); err != nil {
+		return api.NewNotFoundError(models.WORKSPACE_RESOURCE)
+	}
+
+	contextUser.WorkspaceID = workspaceID
+	contextUser.WorkspaceRole = ""
+
+	return nil
+}
+
+// getWorkspaceID - returns the ID of the Workspace request is made in.
+func (wg *WorkspaceGuard) getWorkspaceID(ctx *gin.Context, contextUser *ContextUser) (uuid.UUID, *api.APIError) {
+	if param := ctx.Param(WorkspaceParam); param != "" {
+		workspaceID, err := uuid.Parse(param)
+
+		if workspaceID == uuid.Nil || err != nil {
+			return uuid.Nil, api.NewBadRequestError(errors.New("Workspace ID is missing or malformed."))
+		}
+
+		return workspaceID, nil
+	}
+
+	if contextUser.WorkspaceID != uuid.Nil {
+		return contextUser.WorkspaceID, nil
+	}
+
+	workspace := &models.WorkspaceModel{}
+
+	err := wg.broker.FirstWhere(workspace, &models.WorkspaceModel{Slug: models.DefaultWorkspaceSlug}).Err()
+
+	if err != nil {
+		return uuid.Nil, api.NewInternalError(err)
+	}
+
+	return workspace.ID, nil
+}
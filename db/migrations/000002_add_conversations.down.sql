@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS conversation_participant_models;
+
+DROP TABLE IF EXISTS conversation_models;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS conversation_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "name" TEXT
+);
+
+CREATE TABLE IF NOT EXISTS conversation_participant_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "conversation_id" UUID REFERENCES conversation_models ("id") ON DELETE CASCADE,
+    "user_id" UUID REFERENCES user_models ("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversation_participant
+ON conversation_participant_models ("conversation_id", "user_id");
+
+CREATE INDEX IF NOT EXISTS idx_conversation_participant_models_user_id
+ON conversation_participant_models ("user_id");
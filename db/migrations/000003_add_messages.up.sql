@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS message_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID REFERENCES user_models ("id") ON DELETE CASCADE,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ
+);
+
+ALTER TABLE message_models
+ADD COLUMN IF NOT EXISTS "body" TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS "conversation_id" UUID NOT NULL REFERENCES conversation_models ("id") ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_message_conversation_created
+ON message_models ("conversation_id", "created_at");
@@ -0,0 +1,3 @@
+ALTER TABLE user_models
+DROP COLUMN IF EXISTS "email_verified_at",
+DROP COLUMN IF EXISTS "email_verified";
@@ -0,0 +1,8 @@
+-- Users registered before email verification was introduced are treated as verified,
+-- so enabling verification policy does not lock them out.
+ALTER TABLE user_models
+ADD COLUMN IF NOT EXISTS "email_verified" BOOLEAN NOT NULL DEFAULT true,
+ADD COLUMN IF NOT EXISTS "email_verified_at" TIMESTAMPTZ;
+
+ALTER TABLE user_models
+ALTER COLUMN "email_verified" SET DEFAULT false;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS totp_recovery_code_models;
+
+ALTER TABLE user_models
+DROP COLUMN IF EXISTS "totp_enabled",
+DROP COLUMN IF EXISTS "totp_secret";
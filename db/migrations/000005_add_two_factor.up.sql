@@ -0,0 +1,17 @@
+ALTER TABLE user_models
+ADD COLUMN IF NOT EXISTS "totp_secret" TEXT,
+ADD COLUMN IF NOT EXISTS "totp_enabled" BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS totp_recovery_code_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "user_id" UUID REFERENCES user_models ("id") ON DELETE CASCADE,
+    "code_hash" TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_totp_recovery_code_models_user_id
+ON totp_recovery_code_models ("user_id");
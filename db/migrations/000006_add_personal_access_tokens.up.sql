@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS personal_access_token_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "user_id" UUID REFERENCES user_models ("id") ON DELETE CASCADE,
+    "name" TEXT,
+    "token_hash" TEXT,
+    "prefix" TEXT,
+    "scopes" TEXT,
+    "expires_at" TIMESTAMPTZ,
+    "last_used_at" TIMESTAMPTZ,
+    "last_used_ip" TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_access_token_models_token_hash
+ON personal_access_token_models ("token_hash");
+
+CREATE INDEX IF NOT EXISTS idx_personal_access_token_models_user_id
+ON personal_access_token_models ("user_id");
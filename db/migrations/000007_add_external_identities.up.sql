@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS external_identity_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "user_id" UUID REFERENCES user_models ("id") ON DELETE CASCADE,
+    "issuer" TEXT,
+    "subject" TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_external_identity_subject
+ON external_identity_models ("issuer", "subject");
+
+CREATE INDEX IF NOT EXISTS idx_external_identity_models_user_id
+ON external_identity_models ("user_id");
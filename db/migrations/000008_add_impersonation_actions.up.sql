@@ -0,0 +1,28 @@
+-- Actions are not linked to users with foreign keys, so they outlive both the user
+-- and the admin who impersonated them.
+CREATE TABLE IF NOT EXISTS impersonation_action_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "session_id" UUID,
+    "user_id" UUID,
+    "actor_id" UUID,
+    "actor_email" TEXT,
+    "method" TEXT,
+    "path" TEXT,
+    "status" BIGINT,
+    "ip" TEXT,
+    "user_agent" TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_action_models_session_id
+ON impersonation_action_models ("session_id");
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_action_models_user_id
+ON impersonation_action_models ("user_id");
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_action_models_actor_id
+ON impersonation_action_models ("actor_id");
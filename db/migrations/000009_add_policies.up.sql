@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS policy_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "version" BIGINT,
+    "definition" TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_models_version
+ON policy_models ("version");
@@ -0,0 +1,5 @@
+ALTER TABLE policy_models
+DROP COLUMN IF EXISTS "revision";
+
+ALTER TABLE conversation_participant_models
+DROP COLUMN IF EXISTS "role";
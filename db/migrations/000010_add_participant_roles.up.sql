@@ -0,0 +1,18 @@
+-- Participants added before conversation roles were introduced become members,
+-- except for the creators, who become owners of their conversations.
+ALTER TABLE conversation_participant_models
+ADD COLUMN IF NOT EXISTS "role" TEXT NOT NULL DEFAULT 'member';
+
+UPDATE conversation_participant_models
+SET "role" = 'owner'
+WHERE "role" = 'member'
+AND "user_id" = (SELECT "created_by" FROM conversation_models WHERE "id" = "conversation_id")
+AND NOT EXISTS (
+    SELECT 1 FROM conversation_participant_models owners
+    WHERE owners."conversation_id" = conversation_participant_models."conversation_id"
+    AND owners."role" = 'owner'
+);
+
+-- Number of application's policy migrations already applied to stored policy definition.
+ALTER TABLE policy_models
+ADD COLUMN IF NOT EXISTS "revision" BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+ALTER TABLE message_models
+DROP COLUMN IF EXISTS "workspace_id";
+
+ALTER TABLE conversation_models
+DROP COLUMN IF EXISTS "workspace_id";
+
+DROP TABLE IF EXISTS workspace_member_models;
+
+DROP TABLE IF EXISTS workspace_models;
@@ -0,0 +1,65 @@
+-- gen_random_uuid() is built into PostgreSQL 13+ only - older versions need pgcrypto.
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE IF NOT EXISTS workspace_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "name" TEXT NOT NULL,
+    "slug" TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_models_slug
+ON workspace_models ("slug");
+
+CREATE TABLE IF NOT EXISTS workspace_member_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "workspace_id" UUID REFERENCES workspace_models ("id") ON DELETE CASCADE,
+    "user_id" UUID REFERENCES user_models ("id") ON DELETE CASCADE,
+    "role" TEXT NOT NULL DEFAULT 'member'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_member
+ON workspace_member_models ("workspace_id", "user_id");
+
+CREATE INDEX IF NOT EXISTS idx_workspace_member_models_user_id
+ON workspace_member_models ("user_id");
+
+ALTER TABLE conversation_models
+ADD COLUMN IF NOT EXISTS "workspace_id" UUID;
+
+CREATE INDEX IF NOT EXISTS idx_conversation_models_workspace_id
+ON conversation_models ("workspace_id");
+
+ALTER TABLE message_models
+ADD COLUMN IF NOT EXISTS "workspace_id" UUID;
+
+CREATE INDEX IF NOT EXISTS idx_message_models_workspace_id
+ON message_models ("workspace_id");
+
+-- Existing data is moved to the default workspace, which all the existing users join.
+INSERT INTO workspace_models ("id", "created_at", "updated_at", "name", "slug")
+VALUES (gen_random_uuid(), NOW(), NOW(), 'Default', 'default')
+ON CONFLICT ("slug") DO NOTHING;
+
+UPDATE conversation_models
+SET "workspace_id" = (SELECT "id" FROM workspace_models WHERE "slug" = 'default')
+WHERE "workspace_id" IS NULL;
+
+UPDATE message_models
+SET "workspace_id" = (SELECT "id" FROM workspace_models WHERE "slug" = 'default')
+WHERE "workspace_id" IS NULL;
+
+INSERT INTO workspace_member_models ("id", "created_by", "updated_by", "created_at", "updated_at", "workspace_id", "user_id", "role")
+SELECT gen_random_uuid(), u."id", u."id", NOW(), NOW(), w."id", u."id", 'member'
+FROM user_models u, workspace_models w
+WHERE w."slug" = 'default'
+ON CONFLICT ("workspace_id", "user_id") DO NOTHING;
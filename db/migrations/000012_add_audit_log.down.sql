@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log_models;
+
+DROP FUNCTION IF EXISTS reject_audit_log_change();
+
+DROP TABLE IF EXISTS audit_log_models;
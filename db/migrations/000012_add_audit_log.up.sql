@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS audit_log_models (
+    "id" UUID PRIMARY KEY,
+    "created_by" UUID,
+    "updated_by" UUID,
+    "created_at" TIMESTAMPTZ,
+    "updated_at" TIMESTAMPTZ,
+    "deleted_at" TIMESTAMPTZ,
+    "actor_id" UUID,
+    "actor_email" TEXT,
+    "impersonator_id" UUID,
+    "action" TEXT NOT NULL,
+    "target_type" TEXT,
+    "target_id" TEXT,
+    "result" TEXT NOT NULL,
+    "details" TEXT,
+    "ip" TEXT,
+    "user_agent" TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_actor_id
+ON audit_log_models ("actor_id");
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_actor_email
+ON audit_log_models ("actor_email");
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_action
+ON audit_log_models ("action");
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_target
+ON audit_log_models ("target_type", "target_id");
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_result
+ON audit_log_models ("result");
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log entries cannot be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log_models;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log_models
+FOR EACH STATEMENT EXECUTE PROCEDURE reject_audit_log_change();
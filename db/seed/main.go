@@ -31,6 +31,10 @@ func main() {
 	adminPassword := os.Getenv("GOCHAT_ADMIN_PASSWORD")
 	adminEmail := os.Getenv("GOCHAT_ADMIN_EMAIL")
 
+	if _, err := auth.InitPasswordHasher(); err != nil {
+		log.Fatal(err)
+	}
+
 	_, err := persist.InitDatabase(pgUser, pgPassword, pgDBname, pgHost, pgPort)
 
 	us := services.NewUserService()
@@ -40,6 +44,11 @@ func main() {
 		log.Fatal("Database connection failed")
 	}
 
+	// Admin joins the default Workspace, which is created with the schema.
+	if err := persist.AutoMigrate(); err != nil {
+		log.Fatal(err)
+	}
+
 	hashedPassword, err := am.HashAndSalt([]byte(adminPassword))
 
 	if err != nil {
@@ -47,11 +56,12 @@ func main() {
 	}
 
 	adminUser := &models.UserModel{
-		Password:  hashedPassword,
-		Email:     adminEmail,
-		FirstName: "John",
-		LastName:  "Doe",
-		Role:      control.SuperAdminRole,
+		Password:      hashedPassword,
+		Email:         adminEmail,
+		FirstName:     "John",
+		LastName:      "Doe",
+		Role:          control.SuperAdminRole,
+		EmailVerified: true,
 	}
 
 	err = us.SaveUser(adminUser)
@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer - Mailer writing messages to given writer instead of delivering them.
+// Meant for local development and tests.
+type LogMailer struct {
+	writer io.Writer
+
+	sync.Mutex
+}
+
+// NewLogMailer - returns new LogMailer instance.
+func NewLogMailer(writer io.Writer) *LogMailer {
+	return &LogMailer{
+		writer: writer,
+	}
+}
+
+// NewFileMailer - returns new LogMailer appending messages to the file under given path.
+func NewFileMailer(path string) (*LogMailer, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogMailer(file), nil
+}
+
+// Send - writes given message.
+func (lm *LogMailer) Send(message *Message) error {
+	lm.Lock()
+	defer lm.Unlock()
+
+	_, err := fmt.Fprintf(
+		lm.writer,
+		"--- %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339),
+		message.To,
+		message.Subject,
+		message.Body,
+	)
+
+	return err
+}
@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"log"
+	"os"
+)
+
+// Map of valid MAILER env values.
+const (
+	SMTPMailerType = "smtp"
+	LogMailerType  = "log"
+)
+
+// Message - single email message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer - interface for an entity delivering email messages.
+type Mailer interface {
+	// Send - delivers given message to its recipient.
+	Send(message *Message) error
+}
+
+// DefaultMailer - Mailer shared by the whole application.
+var DefaultMailer Mailer
+
+// InitMailer - initializes DefaultMailer, based on MAILER env. SMTPMailer is used for "smtp",
+// LogMailer otherwise - writing to MAIL_LOG_FILE if it's set, or to the standard logger.
+func InitMailer() Mailer {
+	if DefaultMailer != nil {
+		return DefaultMailer
+	}
+
+	if os.Getenv("MAILER") == SMTPMailerType {
+		DefaultMailer = NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+
+		return DefaultMailer
+	}
+
+	if path := os.Getenv("MAIL_LOG_FILE"); path != "" {
+		fileMailer, err := NewFileMailer(path)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		DefaultMailer = fileMailer
+
+		return DefaultMailer
+	}
+
+	DefaultMailer = NewLogMailer(log.Writer())
+
+	return DefaultMailer
+}
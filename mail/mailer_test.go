@@ -0,0 +1,95 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type mailerSuite struct {
+	suite.Suite
+	testMessage *Message
+}
+
+func (s *mailerSuite) SetupSuite() {
+	s.testMessage = &Message{
+		To:      "test_email@gochat.com",
+		Subject: "test_subject",
+		Body:    "test_body\nsecond_line",
+	}
+}
+
+func TestMailerSuite(t *testing.T) {
+	suite.Run(t, new(mailerSuite))
+}
+
+func (s *mailerSuite) TestLogMailer() {
+	var buffer bytes.Buffer
+
+	mailer := NewLogMailer(&buffer)
+
+	assert.Nil(s.T(), mailer.Send(s.testMessage))
+	assert.Contains(s.T(), buffer.String(), "To: test_email@gochat.com")
+	assert.Contains(s.T(), buffer.String(), "Subject: test_subject")
+	assert.Contains(s.T(), buffer.String(), "test_body\nsecond_line")
+}
+
+func (s *mailerSuite) TestFileMailer() {
+	dir, err := ioutil.TempDir("", "gochat")
+	s.Require().Nil(err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mails.log")
+
+	mailer, err := NewFileMailer(path)
+	s.Require().Nil(err)
+
+	assert.Nil(s.T(), mailer.Send(s.testMessage))
+	assert.Nil(s.T(), mailer.Send(s.testMessage))
+
+	content, err := ioutil.ReadFile(path)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, bytes.Count(content, []byte("Subject: test_subject")))
+}
+
+func (s *mailerSuite) TestSMTPMailer() {
+	mailer := NewSMTPMailer("localhost", "25", "test_user", "test_password", "gochat@gochat.com")
+
+	var sentAddr, sentFrom string
+	var sentTo []string
+	var sentMsg []byte
+
+	mailer.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		sentAddr, sentFrom, sentTo, sentMsg = addr, from, to, msg
+
+		return nil
+	}
+
+	assert.Nil(s.T(), mailer.Send(s.testMessage))
+	assert.Equal(s.T(), "localhost:25", sentAddr)
+	assert.Equal(s.T(), "gochat@gochat.com", sentFrom)
+	assert.Equal(s.T(), []string{"test_email@gochat.com"}, sentTo)
+	assert.Contains(s.T(), string(sentMsg), "Subject: test_subject\r\n")
+	assert.Contains(s.T(), string(sentMsg), "\r\n\r\ntest_body\r\nsecond_line")
+	assert.NotNil(s.T(), mailer.auth)
+}
+
+func (s *mailerSuite) TestSMTPMailer_Error() {
+	mailer := NewSMTPMailer("localhost", "25", "", "", "gochat@gochat.com")
+
+	mailer.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		return errors.New("smtp_error")
+	}
+
+	assert.NotNil(s.T(), mailer.Send(s.testMessage))
+	assert.Nil(s.T(), mailer.auth)
+}
@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type sendMailFn func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// SMTPMailer - Mailer delivering messages via SMTP server.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+
+	sendMail sendMailFn
+}
+
+// NewSMTPMailer - returns new SMTPMailer instance. When username is empty,
+// messages are sent without authentication.
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	mailer := &SMTPMailer{
+		addr:     net.JoinHostPort(host, port),
+		from:     from,
+		sendMail: smtp.SendMail,
+	}
+
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return mailer
+}
+
+// Send - delivers given message to its recipient.
+func (sm *SMTPMailer) Send(message *Message) error {
+	return sm.sendMail(sm.addr, sm.auth, sm.from, []string{message.To}, sm.format(message))
+}
+
+// format - returns given message in RFC 5322 format.
+func (sm *SMTPMailer) format(message *Message) []byte {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "From: %s\r\n", sm.from)
+	fmt.Fprintf(&builder, "To: %s\r\n", message.To)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", message.Subject)
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(builder.String())
+}
@@ -4,6 +4,10 @@ import (
 	"log"
 	"os"
 
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mail"
+	"github.com/el-Mike/gochat/realtime"
 	"github.com/el-Mike/gochat/routing"
 
 	"github.com/el-Mike/gochat/persist"
@@ -48,9 +52,54 @@ func main() {
 		log.Fatal(err)
 	}
 
+	_, err = control.InitPolicyManager()
+
 	if err != nil {
 		log.Fatal("RBAC initialization failed")
 	}
 
+	if _, err := auth.InitKeySet(); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := auth.InitPasswordHasher(); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := auth.InitPasswordPolicy(); err != nil {
+		log.Fatal(err)
+	}
+
+	oidcProvider, err := auth.InitOIDCProvider()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if oidcProvider != nil {
+		if err := control.ValidateGroupRoles(oidcProvider.GroupRoles()); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ldapDirectory, err := auth.InitLDAPDirectory()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if ldapDirectory != nil {
+		if err := control.ValidateGroupRoles(ldapDirectory.GroupRoles()); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if _, err := auth.InitCookieSessions(); err != nil {
+		log.Fatal(err)
+	}
+
+	realtime.InitBroadcaster()
+	mail.InitMailer()
+
 	routing.InitRouting()
 }
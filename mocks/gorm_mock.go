@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -58,6 +59,17 @@ func (gm *GormMock) Find(dest interface{}, conds ...interface{}) *persist.DBResp
 	return args.Get(0).(*persist.DBResponse)
 }
 
+// FindPage - FindPage method mock implementation.
+func (gm *GormMock) FindPage(dest interface{}, page *persist.PageRequest, query interface{}, queryArgs ...interface{}) *persist.DBResponse {
+	args := gm.Called(dest, page, query, queryArgs)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.DBResponse)
+}
+
 // First - Save method mock implementation.
 func (gm *GormMock) Save(value interface{}) *persist.DBResponse {
 	args := gm.Called(value)
@@ -79,10 +91,37 @@ func (gm *GormMock) DeleteByID(target interface{}, id interface{}) *persist.DBRe
 	return args.Get(0).(*persist.DBResponse)
 }
 
+// InWorkspace - InWorkspace method mock implementation.
+func (gm *GormMock) InWorkspace(workspaceID uuid.UUID) persist.DBBroker {
+	args := gm.Called(workspaceID)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(persist.DBBroker)
+}
+
+// Transaction - Transaction method mock implementation. Given function is run with the mock
+// itself, so the calls made within the transaction can be mocked as usual.
+func (gm *GormMock) Transaction(fn func(broker persist.DBBroker) error) error {
+	gm.Called(fn)
+
+	return fn(gm)
+}
+
 func GetDefaultDBResponse() *persist.DBResponse {
 	return persist.NewDBResponse()
 }
 
+// GetRowsAffectedDBResponse - returns DBResponse with given number of affected records.
+func GetRowsAffectedDBResponse(rowsAffected int64) *persist.DBResponse {
+	res := persist.NewDBResponse()
+	res.SetRowsAffected(rowsAffected)
+
+	return res
+}
+
 func GetErrorDBResponse(err error) *persist.DBResponse {
 	res := persist.NewDBResponse()
 	res.SetErr(err)
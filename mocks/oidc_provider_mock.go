@@ -0,0 +1,277 @@
+package mocks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// OIDCProviderMock - local OpenID Connect identity provider, serving discovery, authorization,
+// token and JWKS endpoints. It implements authorization code flow with PKCE (S256 only),
+// and logs in every authorization request as the user described by Claims.
+type OIDCProviderMock struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+	Key          *rsa.PrivateKey
+	KeyID        string
+
+	// Claims - claims of ID tokens issued for next logins, besides iss, aud, iat, exp and nonce.
+	Claims map[string]interface{}
+
+	mutex          sync.Mutex
+	authorizations map[string]*oidcAuthorizationMock
+}
+
+// oidcAuthorizationMock - authorization request an authorization code has been issued for.
+type oidcAuthorizationMock struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	claims        map[string]interface{}
+}
+
+// NewOIDCProviderMock - starts OIDCProviderMock with registered client with given
+// credentials. Client secret can be empty for public clients.
+func NewOIDCProviderMock(clientID, clientSecret string) *OIDCProviderMock {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		panic(err)
+	}
+
+	pm := &OIDCProviderMock{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		Key:            key,
+		KeyID:          "mock-key",
+		Claims:         map[string]interface{}{},
+		authorizations: map[string]*oidcAuthorizationMock{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", pm.handleDiscovery)
+	mux.HandleFunc("/authorize", pm.handleAuthorize)
+	mux.HandleFunc("/token", pm.handleToken)
+	mux.HandleFunc("/jwks", pm.handleJWKS)
+
+	pm.Server = httptest.NewServer(mux)
+
+	return pm
+}
+
+// Issuer - returns provider's issuer URL.
+func (pm *OIDCProviderMock) Issuer() string {
+	return pm.Server.URL
+}
+
+// Close - shuts the provider down.
+func (pm *OIDCProviderMock) Close() {
+	pm.Server.Close()
+}
+
+// Authorize - simulates user's visit at given authorization URL, and returns the code
+// and state the user would be redirected back with.
+func (pm *OIDCProviderMock) Authorize(authorizationURL string) (string, string, error) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	response, err := client.Get(authorizationURL)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return "", "", errors.New("Authorization request has been rejected")
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+// SignIDToken - returns given claims signed with provider's key.
+func (pm *OIDCProviderMock) SignIDToken(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = pm.KeyID
+
+	return token.SignedString(pm.Key)
+}
+
+func (pm *OIDCProviderMock) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSONMock(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                pm.Issuer(),
+		"authorization_endpoint":                pm.Issuer() + "/authorize",
+		"token_endpoint":                        pm.Issuer() + "/token",
+		"jwks_uri":                              pm.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (pm *OIDCProviderMock) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("response_type") != "code" ||
+		query.Get("client_id") != pm.ClientID ||
+		query.Get("redirect_uri") == "" ||
+		query.Get("code_challenge") == "" ||
+		query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+
+		return
+	}
+
+	code := randomMock()
+
+	pm.mutex.Lock()
+	pm.authorizations[code] = &oidcAuthorizationMock{
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		claims:        pm.Claims,
+	}
+	pm.mutex.Unlock()
+
+	redirect := url.Values{
+		"code":  {code},
+		"state": {query.Get("state")},
+	}
+
+	http.Redirect(w, r, query.Get("redirect_uri")+"?"+redirect.Encode(), http.StatusFound)
+}
+
+func (pm *OIDCProviderMock) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSONMock(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+
+		return
+	}
+
+	if !pm.authenticateClient(r) {
+		writeJSONMock(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+
+		return
+	}
+
+	// Codes can be used only once.
+	pm.mutex.Lock()
+	authorization, ok := pm.authorizations[r.PostForm.Get("code")]
+	delete(pm.authorizations, r.PostForm.Get("code"))
+	pm.mutex.Unlock()
+
+	verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+	if !ok ||
+		authorization.redirectURI != r.PostForm.Get("redirect_uri") ||
+		authorization.codeChallenge != base64.RawURLEncoding.EncodeToString(verifierHash[:]) {
+		writeJSONMock(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+
+		return
+	}
+
+	now := time.Now()
+
+	claims := jwt.MapClaims{}
+
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+
+	claims["iss"] = pm.Issuer()
+	claims["aud"] = pm.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(5 * time.Minute).Unix()
+
+	if authorization.nonce != "" {
+		claims["nonce"] = authorization.nonce
+	}
+
+	idToken, err := pm.SignIDToken(claims)
+
+	if err != nil {
+		writeJSONMock(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+
+		return
+	}
+
+	writeJSONMock(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomMock(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (pm *OIDCProviderMock) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSONMock(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": pm.KeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(pm.Key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pm.Key.PublicKey.E)).Bytes()),
+			},
+		},
+	})
+}
+
+// authenticateClient - returns true if request has been sent by registered client.
+// Public clients only have to pass their ID.
+func (pm *OIDCProviderMock) authenticateClient(r *http.Request) bool {
+	if pm.ClientSecret == "" {
+		return r.PostForm.Get("client_id") == pm.ClientID
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+
+	if !ok {
+		return false
+	}
+
+	clientID, _ = url.QueryUnescape(clientID)
+	clientSecret, _ = url.QueryUnescape(clientSecret)
+
+	return clientID == pm.ClientID && clientSecret == pm.ClientSecret
+}
+
+func writeJSONMock(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func randomMock() string {
+	value := make([]byte, 16)
+
+	if _, err := rand.Read(value); err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(value)
+}
@@ -47,6 +47,17 @@ func (rc *RedisCacheMock) Set(
 	return args.Get(0).(*persist.CacheResponse)
 }
 
+// SetNX - SetNX method mock implementation.
+func (rc *RedisCacheMock) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *persist.CacheResponse {
+	args := rc.Called(ctx, key, value, expiration)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
 // Del - Del method mock implementation.
 func (rc *RedisCacheMock) Del(ctx context.Context, keys ...string) *persist.CacheResponse {
 	args := rc.Called(ctx, keys)
@@ -58,11 +69,99 @@ func (rc *RedisCacheMock) Del(ctx context.Context, keys ...string) *persist.Cach
 	return args.Get(0).(*persist.CacheResponse)
 }
 
+// SAdd - SAdd method mock implementation.
+func (rc *RedisCacheMock) SAdd(ctx context.Context, key string, members ...string) *persist.CacheResponse {
+	args := rc.Called(ctx, key, members)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
+// SRem - SRem method mock implementation.
+func (rc *RedisCacheMock) SRem(ctx context.Context, key string, members ...string) *persist.CacheResponse {
+	args := rc.Called(ctx, key, members)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
+// SMembers - SMembers method mock implementation.
+func (rc *RedisCacheMock) SMembers(ctx context.Context, key string) *persist.CacheResponse {
+	args := rc.Called(ctx, key)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
+// CompareAndSet - CompareAndSet method mock implementation.
+func (rc *RedisCacheMock) CompareAndSet(
+	ctx context.Context,
+	key string,
+	expected string,
+	value interface{},
+	expiration time.Duration,
+) *persist.CacheResponse {
+	args := rc.Called(ctx, key, expected, value, expiration)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
+// Incr - Incr method mock implementation.
+func (rc *RedisCacheMock) Incr(ctx context.Context, key string) *persist.CacheResponse {
+	args := rc.Called(ctx, key)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
+// Expire - Expire method mock implementation.
+func (rc *RedisCacheMock) Expire(ctx context.Context, key string, expiration time.Duration) *persist.CacheResponse {
+	args := rc.Called(ctx, key, expiration)
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).(*persist.CacheResponse)
+}
+
 // GetDefaultCacheResponse - returns default, empty CacheResponse.
 func GetDefaultCacheResponse() *persist.CacheResponse {
 	return persist.NewCacheResponse()
 }
 
+// GetValueCacheResponse - returns CacheResponse with given value.
+func GetValueCacheResponse(val string) *persist.CacheResponse {
+	res := persist.NewCacheResponse()
+	res.SetVal(val)
+
+	return res
+}
+
+// GetValuesCacheResponse - returns CacheResponse with given values.
+func GetValuesCacheResponse(vals []string) *persist.CacheResponse {
+	res := persist.NewCacheResponse()
+	res.SetVals(vals)
+
+	return res
+}
+
 // GetErrorCacheResponse - returns CacheResponse with given error.
 func GetErrorCacheResponse(err error) *persist.CacheResponse {
 	res := persist.NewCacheResponse()
@@ -0,0 +1,43 @@
+package models
+
+import "github.com/google/uuid"
+
+// AUDIT_LOG_RESOURCE - name of AuditLog resource.
+const AUDIT_LOG_RESOURCE = "AuditLog"
+
+// Map of valid audit log entry results.
+const (
+	AuditSuccessResult = "success"
+	AuditFailureResult = "failure"
+	AuditDeniedResult  = "denied"
+)
+
+// AuditLogModel - security relevant action (e.g. login or deleting a user), recorded together
+// with its actor and outcome. Entries are never updated nor deleted, and are not linked
+// to users with foreign keys, so they outlive both actors and targets. Entry's CreatedAt
+// is the time the action has been performed at.
+type AuditLogModel struct {
+	BaseModel
+
+	// ActorID - the user performing the action, or uuid.Nil if they are unknown
+	// (e.g. failed login with unregistered email).
+	ActorID    uuid.UUID `gorm:"type:uuid;index" json:"actorId"`
+	ActorEmail string    `gorm:"index" json:"actorEmail"`
+
+	// ImpersonatorID - the admin who actually performs the action, if actor is impersonated.
+	ImpersonatorID *uuid.UUID `gorm:"type:uuid" json:"impersonatorId,omitempty"`
+
+	Action string `gorm:"index;not null" json:"action"`
+
+	// TargetType and TargetID - the entity the action is performed on, if any.
+	TargetType string `gorm:"index:idx_audit_log_target" json:"targetType"`
+	TargetID   string `gorm:"index:idx_audit_log_target" json:"targetId"`
+
+	Result string `gorm:"index;not null" json:"result"`
+
+	// Details - action's outcome description, e.g. why it has failed or what has been changed.
+	Details string `json:"details"`
+
+	IP        string `json:"ip"`
+	UserAgent string `json:"userAgent"`
+}
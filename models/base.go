@@ -23,3 +23,13 @@ func (base *BaseModel) BeforeCreate(tx *gorm.DB) (err error) {
 
 	return nil
 }
+
+// GetID - returns entity's ID.
+func (base BaseModel) GetID() uuid.UUID {
+	return base.ID
+}
+
+// GetCreatedAt - returns entity's creation time.
+func (base BaseModel) GetCreatedAt() time.Time {
+	return base.CreatedAt
+}
@@ -8,11 +8,82 @@ const CONVERSATION_RESOURCE = "Conversation"
 // ConversationModel - Conversation DB model.
 type ConversationModel struct {
 	BaseModel
-	Users    []uuid.UUID     `json:"users"`
-	Messages []*MessageModel `json:"messages"`
+	Name         string                          `json:"name"`
+	WorkspaceID  uuid.UUID                       `gorm:"type:uuid;index" json:"workspaceId"`
+	Participants []*ConversationParticipantModel `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"participants"`
 }
 
-// ResourceName - returns the name of Conversation resource.
-func (cm *ConversationModel) ResourceName() string {
+// GetResourceName - returns the name of Conversation resource.
+func (cm *ConversationModel) GetResourceName() string {
 	return CONVERSATION_RESOURCE
 }
+
+// WorkspaceScope - returns SQL condition matching Conversations of a Workspace.
+func (cm *ConversationModel) WorkspaceScope() string {
+	return "workspace_id = ?"
+}
+
+// GetWorkspaceID - returns the ID of the Workspace the Conversation belongs to.
+func (cm *ConversationModel) GetWorkspaceID() uuid.UUID {
+	return cm.WorkspaceID
+}
+
+// SetWorkspaceID - sets the ID of the Workspace the Conversation belongs to.
+func (cm *ConversationModel) SetWorkspaceID(id uuid.UUID) {
+	cm.WorkspaceID = id
+}
+
+// HasParticipant - returns true if user with given ID participates in the Conversation.
+func (cm *ConversationModel) HasParticipant(userID uuid.UUID) bool {
+	for _, participant := range cm.Participants {
+		if participant.UserID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetParticipant - returns participation of the user with given ID, or nil if the user
+// does not participate in the Conversation.
+func (cm *ConversationModel) GetParticipant(userID uuid.UUID) *ConversationParticipantModel {
+	for _, participant := range cm.Participants {
+		if participant.UserID == userID {
+			return participant
+		}
+	}
+
+	return nil
+}
+
+// GetParticipantRole - returns the role of the user with given ID within the Conversation,
+// or empty string if the user does not participate in it.
+func (cm *ConversationModel) GetParticipantRole(userID uuid.UUID) string {
+	if participant := cm.GetParticipant(userID); participant != nil {
+		return participant.Role
+	}
+
+	return ""
+}
+
+// GetOwner - returns participation of the Conversation's owner, or nil if it cannot be found.
+func (cm *ConversationModel) GetOwner() *ConversationParticipantModel {
+	for _, participant := range cm.Participants {
+		if participant.Role == ConversationOwnerRole {
+			return participant
+		}
+	}
+
+	return nil
+}
+
+// GetParticipantIDs - returns IDs of all the users participating in the Conversation.
+func (cm *ConversationModel) GetParticipantIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(cm.Participants))
+
+	for _, participant := range cm.Participants {
+		ids = append(ids, participant.UserID)
+	}
+
+	return ids
+}
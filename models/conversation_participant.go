@@ -0,0 +1,31 @@
+package models
+
+import "github.com/google/uuid"
+
+// Map of valid participant's roles within a Conversation.
+const (
+	// ConversationOwnerRole - role of the participant owning the Conversation. Every
+	// Conversation has exactly one owner.
+	ConversationOwnerRole = "owner"
+
+	// ConversationModeratorRole - role of the participant managing other participants
+	// and their messages.
+	ConversationModeratorRole = "moderator"
+
+	// ConversationMemberRole - role of a standard participant.
+	ConversationMemberRole = "member"
+)
+
+// ConversationParticipantModel - join model between Conversation and User.
+type ConversationParticipantModel struct {
+	BaseModel
+	ConversationID uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_conversation_participant" json:"conversationId"`
+	UserID         uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_conversation_participant;index" json:"userId"`
+	User           *UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Role           string     `gorm:"not null;default:member" json:"role"`
+}
+
+// WorkspaceScope - returns SQL condition matching participants of a Workspace's Conversations.
+func (cpm *ConversationParticipantModel) WorkspaceScope() string {
+	return "conversation_id IN (SELECT id FROM conversation_models WHERE workspace_id = ?)"
+}
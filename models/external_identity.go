@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+// ExternalIdentityModel - links a user to their account in external identity provider,
+// so they are recognized by provider's subject even if their email changes.
+type ExternalIdentityModel struct {
+	BaseModel
+	UserID  uuid.UUID  `gorm:"type:uuid;index" json:"userId"`
+	User    *UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Issuer  string     `gorm:"uniqueIndex:idx_external_identity_subject" json:"issuer"`
+	Subject string     `gorm:"uniqueIndex:idx_external_identity_subject" json:"subject"`
+}
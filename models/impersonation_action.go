@@ -0,0 +1,19 @@
+package models
+
+import "github.com/google/uuid"
+
+// ImpersonationActionModel - request made within impersonated session, recorded together
+// with the admin who actually made it. It's not linked to users with foreign keys,
+// so the record outlives both of them.
+type ImpersonationActionModel struct {
+	BaseModel
+	SessionID  uuid.UUID `gorm:"type:uuid;index" json:"sessionId"`
+	UserID     uuid.UUID `gorm:"type:uuid;index" json:"userId"`
+	ActorID    uuid.UUID `gorm:"type:uuid;index" json:"actorId"`
+	ActorEmail string    `json:"actorEmail"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+}
@@ -1,14 +1,56 @@
 package models
 
+import "github.com/google/uuid"
+
 // MESSAGE_RESOURCE - name of Message resource.
 const MESSAGE_RESOURCE = "Message"
 
-// MessageModel - Message DB model.
+// MessageModel - Message DB model. Message's author is kept in CreatedBy field.
 type MessageModel struct {
 	BaseModel
+	Body           string             `gorm:"type:text;not null" json:"body"`
+	ConversationID uuid.UUID          `gorm:"type:uuid;not null;index:idx_message_conversation_created" json:"conversationId"`
+	Conversation   *ConversationModel `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"-"`
+	WorkspaceID    uuid.UUID          `gorm:"type:uuid;index" json:"workspaceId"`
+	Author         *UserModel         `gorm:"foreignKey:CreatedBy;constraint:OnDelete:CASCADE" json:"-"`
 }
 
-// ResourceName - returns the name of Message resource.
-func (mr *MessageModel) ResourceName() string {
+// GetResourceName - returns the name of Message resource.
+func (mr *MessageModel) GetResourceName() string {
 	return MESSAGE_RESOURCE
 }
+
+// WorkspaceScope - returns SQL condition matching Messages of a Workspace.
+func (mr *MessageModel) WorkspaceScope() string {
+	return "workspace_id = ?"
+}
+
+// GetWorkspaceID - returns the ID of the Workspace Message's Conversation belongs to.
+func (mr *MessageModel) GetWorkspaceID() uuid.UUID {
+	return mr.WorkspaceID
+}
+
+// SetWorkspaceID - sets the ID of the Workspace Message's Conversation belongs to.
+func (mr *MessageModel) SetWorkspaceID(id uuid.UUID) {
+	mr.WorkspaceID = id
+}
+
+// HasParticipant - returns true if user with given ID participates in Message's Conversation.
+// Conversation needs to be loaded beforehand, otherwise false is returned.
+func (mr *MessageModel) HasParticipant(userID uuid.UUID) bool {
+	if mr.Conversation == nil {
+		return false
+	}
+
+	return mr.Conversation.HasParticipant(userID)
+}
+
+// GetParticipantRole - returns the role of the user with given ID within Message's Conversation.
+// Conversation needs to be loaded beforehand, otherwise empty string is returned.
+func (mr *MessageModel) GetParticipantRole(userID uuid.UUID) string {
+	if mr.Conversation == nil {
+		return ""
+	}
+
+	return mr.Conversation.GetParticipantRole(userID)
+}
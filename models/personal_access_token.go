@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PERSONAL_ACCESS_TOKEN_RESOURCE - name of PersonalAccessToken resource.
+const PERSONAL_ACCESS_TOKEN_RESOURCE = "PersonalAccessToken"
+
+// PersonalAccessTokenModel - long-lived, scoped credential used by scripts and bots
+// instead of user's password. Only the hash of the token is stored.
+type PersonalAccessTokenModel struct {
+	BaseModel
+	UserID    uuid.UUID  `gorm:"type:uuid;index" json:"userId"`
+	User      *UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Name      string     `json:"name"`
+	TokenHash string     `gorm:"uniqueIndex" json:"-"`
+
+	// Prefix - beginning of the token, allowing users to recognize it.
+	Prefix string `json:"prefix"`
+
+	// Scopes - space-separated scopes, limiting what the token can be used for.
+	Scopes string `json:"scopes"`
+
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	LastUsedIP string     `json:"lastUsedIp"`
+}
+
+// GetResourceName - returns the name of PersonalAccessToken resource.
+func (pm *PersonalAccessTokenModel) GetResourceName() string {
+	return PERSONAL_ACCESS_TOKEN_RESOURCE
+}
+
+// GetScopes - returns token's scopes.
+func (pm *PersonalAccessTokenModel) GetScopes() []string {
+	return strings.Fields(pm.Scopes)
+}
+
+// IsExpired - returns true if token has an expiration time, which has passed.
+func (pm *PersonalAccessTokenModel) IsExpired(now time.Time) bool {
+	return pm.ExpiresAt != nil && !now.Before(*pm.ExpiresAt)
+}
@@ -0,0 +1,15 @@
+package models
+
+// POLICY_RESOURCE - name of RBAC Policy resource.
+const POLICY_RESOURCE = "Policy"
+
+// PolicyModel - a single version of RBAC policy definition, stored as JSON. Versions are
+// never modified - every change of the policy is saved as the next version.
+type PolicyModel struct {
+	BaseModel
+	Version    int    `gorm:"uniqueIndex" json:"version"`
+	Definition string `gorm:"type:text" json:"-"`
+
+	// Revision - number of application's policy migrations already applied to the definition.
+	Revision int `gorm:"not null;default:0" json:"revision"`
+}
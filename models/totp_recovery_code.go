@@ -0,0 +1,11 @@
+package models
+
+import "github.com/google/uuid"
+
+// TOTPRecoveryCodeModel - hashed, single-use code which can be used instead of TOTP code.
+type TOTPRecoveryCodeModel struct {
+	BaseModel
+	UserID   uuid.UUID  `gorm:"type:uuid;index" json:"userId"`
+	User     *UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	CodeHash string     `json:"-"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // USER_RESOURCE -name of User resource.
 const USER_RESOURCE = "User"
 
@@ -11,9 +13,23 @@ type UserModel struct {
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 	Role      string `json:"role"`
+
+	EmailVerified   bool       `gorm:"not null;default:false" json:"emailVerified"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
+
+	// TOTPSecret is set when enrollment starts, but it's used only after
+	// the enrollment has been confirmed (TOTPEnabled).
+	TOTPSecret  string `gorm:"column:totp_secret" json:"-"`
+	TOTPEnabled bool   `gorm:"column:totp_enabled;not null;default:false" json:"totpEnabled"`
 }
 
-// ResourceName - returns the name of User resource.
+// GetResourceName - returns the name of User resource.
 func (um *UserModel) GetResourceName() string {
 	return USER_RESOURCE
 }
+
+// WorkspaceScope - returns SQL condition matching members of a Workspace. Users are not
+// owned by Workspaces - a user can be a member of several of them.
+func (um *UserModel) WorkspaceScope() string {
+	return "id IN (SELECT user_id FROM workspace_member_models WHERE workspace_id = ?)"
+}
@@ -0,0 +1,66 @@
+package models
+
+import "github.com/google/uuid"
+
+// WORKSPACE_RESOURCE - name of Workspace resource.
+const WORKSPACE_RESOURCE = "Workspace"
+
+// DefaultWorkspaceSlug - slug of the Workspace created together with the database. Users
+// signing up join it, and requests are made in it unless other Workspace is selected.
+const DefaultWorkspaceSlug = "default"
+
+// WorkspaceModel - Workspace DB model. Workspace isolates its members, Conversations
+// and Messages from other Workspaces hosted by the same deployment.
+type WorkspaceModel struct {
+	BaseModel
+	Name    string                  `gorm:"not null" json:"name"`
+	Slug    string                  `gorm:"uniqueIndex;not null" json:"slug"`
+	Members []*WorkspaceMemberModel `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// GetResourceName - returns the name of Workspace resource.
+func (wm *WorkspaceModel) GetResourceName() string {
+	return WORKSPACE_RESOURCE
+}
+
+// GetMember - returns membership of the user with given ID, or nil if the user
+// is not a member of the Workspace.
+func (wm *WorkspaceModel) GetMember(userID uuid.UUID) *WorkspaceMemberModel {
+	for _, member := range wm.Members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+
+	return nil
+}
+
+// CountAdmins - returns the number of Workspace's admins.
+func (wm *WorkspaceModel) CountAdmins() int {
+	count := 0
+
+	for _, member := range wm.Members {
+		if member.Role == WorkspaceAdminRole {
+			count++
+		}
+	}
+
+	return count
+}
+
+// WorkspaceScoped - interface that needs to be implemented by models which records are
+// visible only within a Workspace. DBBroker scoped to a Workspace filters them with it.
+type WorkspaceScoped interface {
+	// WorkspaceScope - returns SQL condition matching the records of a Workspace,
+	// which ID is its only argument.
+	WorkspaceScope() string
+}
+
+// WorkspaceResource - interface that needs to be implemented by models which records
+// belong to exactly one Workspace, kept in their WorkspaceID field.
+type WorkspaceResource interface {
+	WorkspaceScoped
+
+	GetWorkspaceID() uuid.UUID
+	SetWorkspaceID(id uuid.UUID)
+}
@@ -0,0 +1,28 @@
+package models
+
+import "github.com/google/uuid"
+
+// Map of valid member's roles within a Workspace. They are layered over users' global
+// roles - e.g. Workspace's admin can manage its members, even if they are a standard user.
+const (
+	// WorkspaceAdminRole - role of the member managing the Workspace and its members.
+	WorkspaceAdminRole = "admin"
+
+	// WorkspaceMemberRole - role of a standard member.
+	WorkspaceMemberRole = "member"
+)
+
+// WorkspaceMemberModel - join model between Workspace and User.
+type WorkspaceMemberModel struct {
+	BaseModel
+	WorkspaceID uuid.UUID       `gorm:"type:uuid;uniqueIndex:idx_workspace_member" json:"workspaceId"`
+	Workspace   *WorkspaceModel `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID      uuid.UUID       `gorm:"type:uuid;uniqueIndex:idx_workspace_member;index" json:"userId"`
+	User        *UserModel      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Role        string          `gorm:"not null;default:member" json:"role"`
+}
+
+// WorkspaceScope - returns SQL condition matching memberships of a Workspace.
+func (wm *WorkspaceMemberModel) WorkspaceScope() string {
+	return "workspace_id = ?"
+}
@@ -13,13 +13,40 @@ type Cache interface {
 	// Set - set given key to the passed value.
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *CacheResponse
 
-	// Del - remove value under given key.
+	// SetNX - atomically set given key to the passed value, only if it does not exist yet.
+	// Response's value is "1" if the key has been set, "0" otherwise.
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *CacheResponse
+
+	// Del - remove values under given keys. Number of removed keys is returned
+	// as the response's value.
 	Del(ctx context.Context, keys ...string) *CacheResponse
+
+	// Incr - increment the integer value under given key, starting from 0 if it does not exist.
+	// New value is returned as the response's value.
+	Incr(ctx context.Context, key string) *CacheResponse
+
+	// Expire - set the expiration of given key.
+	Expire(ctx context.Context, key string, expiration time.Duration) *CacheResponse
+
+	// SAdd - add given members to the set under given key.
+	SAdd(ctx context.Context, key string, members ...string) *CacheResponse
+
+	// SRem - remove given members from the set under given key.
+	SRem(ctx context.Context, key string, members ...string) *CacheResponse
+
+	// SMembers - get all the members of the set under given key.
+	SMembers(ctx context.Context, key string) *CacheResponse
+
+	// CompareAndSet - atomically set given key to the passed value, only if its current value
+	// equals the expected one. Response's value is "1" if the key has been set, "0" otherwise.
+	CompareAndSet(ctx context.Context, key string, expected string, value interface{}, expiration time.Duration) *CacheResponse
 }
 
 // CacheResponse - basic cache response.
 type CacheResponse struct {
-	err error
+	err  error
+	val  string
+	vals []string
 }
 
 // NewCacheResponse - returns CacheResponse instance.
@@ -36,3 +63,23 @@ func (cr *CacheResponse) Err() error {
 func (cr *CacheResponse) SetErr(err error) {
 	cr.err = err
 }
+
+// Val - returns the value returned by cache operation.
+func (cr *CacheResponse) Val() string {
+	return cr.val
+}
+
+// SetVal - sets given value on CacheResponse instance.
+func (cr *CacheResponse) SetVal(val string) {
+	cr.val = val
+}
+
+// Vals - returns the values returned by cache operation.
+func (cr *CacheResponse) Vals() []string {
+	return cr.vals
+}
+
+// SetVals - sets given values on CacheResponse instance.
+func (cr *CacheResponse) SetVals(vals []string) {
+	cr.vals = vals
+}
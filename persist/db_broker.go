@@ -1,5 +1,7 @@
 package persist
 
+import "github.com/google/uuid"
+
 // DBBroker - basic, common database broker interface.
 type DBBroker interface {
 	// First - finds first record that match given conditions.
@@ -14,16 +16,33 @@ type DBBroker interface {
 	// Find - returns records that match given conditions.
 	Find(dest interface{}, conds ...interface{}) *DBResponse
 
+	// FindPage - returns a single page of records that match given query.
+	// Cursor of the next page is available in DBResponse.
+	FindPage(dest interface{}, page *PageRequest, query interface{}, args ...interface{}) *DBResponse
+
 	// Save - update value in the DB or create if it does not exist.
 	Save(value interface{}) *DBResponse
 
-	// DeleteByID - deletes a record of given type by passed ID.
+	// DeleteByID - deletes a record of given type by passed ID. Number of deleted records
+	// is available in DBResponse.
 	DeleteByID(target interface{}, id interface{}) *DBResponse
+
+	// InWorkspace - returns a broker, which finds and deletes only the records of given
+	// Workspace (models.WorkspaceScoped), and saves new records (models.WorkspaceResource)
+	// in it. Saving records of other Workspaces fails with ErrWorkspaceMismatch.
+	InWorkspace(workspaceID uuid.UUID) DBBroker
+
+	// Transaction - runs given function in a database transaction, passing it a broker bound
+	// to the transaction (and scoped like the current one). Transaction is committed if
+	// the function returns nil, and rolled back otherwise.
+	Transaction(fn func(broker DBBroker) error) error
 }
 
 // DBResponse - basic, unified database response.
 type DBResponse struct {
-	err error
+	err          error
+	nextCursor   string
+	rowsAffected int64
 }
 
 // NewDBResponse - returns DBResponse instance.
@@ -40,3 +59,24 @@ func (dr *DBResponse) Err() error {
 func (dr *DBResponse) SetErr(err error) {
 	dr.err = err
 }
+
+// NextCursor - returns the cursor of the next page, or empty string
+// if there are no more records.
+func (dr *DBResponse) NextCursor() string {
+	return dr.nextCursor
+}
+
+// SetNextCursor - sets the cursor of the next page on DBResponse instance.
+func (dr *DBResponse) SetNextCursor(cursor string) {
+	dr.nextCursor = cursor
+}
+
+// RowsAffected - returns the number of records affected by DB operation.
+func (dr *DBResponse) RowsAffected() int64 {
+	return dr.rowsAffected
+}
+
+// SetRowsAffected - sets the number of affected records on DBResponse instance.
+func (dr *DBResponse) SetRowsAffected(rowsAffected int64) {
+	dr.rowsAffected = rowsAffected
+}
@@ -6,12 +6,16 @@ import (
 	"log"
 
 	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 type gormWrapper struct {
 	db *gorm.DB
+
+	// workspaceID - the Workspace broker is scoped to, or nil if it's not scoped.
+	workspaceID *uuid.UUID
 }
 
 // GormBroker - database broker based on Gorm.
@@ -19,7 +23,7 @@ var GormBroker *gormWrapper
 
 // First - wrapper for Gorm's First method.
 func (gm *gormWrapper) First(dest interface{}, conds ...interface{}) *DBResponse {
-	res := gm.db.First(dest, conds...)
+	res := gm.scope(dest).First(dest, conds...)
 
 	return dbResponseFromGormResult(res)
 }
@@ -33,31 +37,101 @@ func (gm *gormWrapper) Where(query interface{}, args ...interface{}) *DBResponse
 
 // FirstWhere - returns first record that matches given criteria.
 func (gm *gormWrapper) FirstWhere(dest interface{}, query interface{}, args ...interface{}) *DBResponse {
-	res := gm.db.Where(query, args...).First(dest)
+	res := gm.scope(dest).Where(query, args...).First(dest)
 
 	return dbResponseFromGormResult(res)
 }
 
 // Find - wrapper for Gorm's Find method.
 func (gm *gormWrapper) Find(dest interface{}, conds ...interface{}) *DBResponse {
-	res := gm.db.Find(dest, conds...)
+	res := gm.scope(dest).Find(dest, conds...)
 
 	return dbResponseFromGormResult(res)
 }
 
-// Save - wrapper for Gorm's Save method.
+// FindPage - returns records that match given query, sorted by creation time and ID,
+// starting after the record PageRequest's cursor points to.
+func (gm *gormWrapper) FindPage(dest interface{}, page *PageRequest, query interface{}, args ...interface{}) *DBResponse {
+	db := gm.scope(dest)
+
+	if query != nil {
+		db = db.Where(query, args...)
+	}
+
+	operator, order := ">", "ASC"
+
+	if page.Direction == SortDescending {
+		operator, order = "<", "DESC"
+	}
+
+	if page.Cursor != "" {
+		after, err := decodeCursor(page.Cursor)
+
+		if err != nil {
+			res := NewDBResponse()
+			res.SetErr(err)
+
+			return res
+		}
+
+		db = db.Where(fmt.Sprintf("(created_at, id) %s (?, ?)", operator), after.CreatedAt, after.ID)
+	}
+
+	// One additional record is fetched to determine if the next page exists.
+	result := db.
+		Order("created_at " + order).
+		Order("id " + order).
+		Limit(page.Limit + 1).
+		Find(dest)
+
+	res := dbResponseFromGormResult(result)
+
+	if result.Error == nil {
+		res.SetNextCursor(trimPage(dest, page.Limit))
+	}
+
+	return res
+}
+
+// Save - wrapper for Gorm's Save method. If the broker is scoped, new records are saved
+// in its Workspace.
 func (gm *gormWrapper) Save(value interface{}) *DBResponse {
+	if err := gm.assignWorkspace(value); err != nil {
+		res := NewDBResponse()
+		res.SetErr(err)
+
+		return res
+	}
+
 	res := gm.db.Save(value)
 
 	return dbResponseFromGormResult(res)
 }
 
 func (gm *gormWrapper) DeleteByID(target interface{}, id interface{}) *DBResponse {
-	res := gm.db.Delete(target, id)
+	res := gm.scope(target).Delete(target, id)
 
 	return dbResponseFromGormResult(res)
 }
 
+// InWorkspace - returns a broker scoped to given Workspace, sharing the connection.
+func (gm *gormWrapper) InWorkspace(workspaceID uuid.UUID) DBBroker {
+	return &gormWrapper{
+		db:          gm.db,
+		workspaceID: &workspaceID,
+	}
+}
+
+// Transaction - wrapper for Gorm's Transaction method, keeping broker's Workspace scope.
+func (gm *gormWrapper) Transaction(fn func(broker DBBroker) error) error {
+	return gm.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormWrapper{
+			db:          tx,
+			workspaceID: gm.workspaceID,
+		})
+	})
+}
+
 func dbResponseFromGormResult(result *gorm.DB) *DBResponse {
 	res := NewDBResponse()
 
@@ -65,6 +139,8 @@ func dbResponseFromGormResult(result *gorm.DB) *DBResponse {
 		res.SetErr(result.Error)
 	}
 
+	res.SetRowsAffected(result.RowsAffected)
+
 	return res
 }
 
@@ -100,13 +176,154 @@ func AutoMigrate() error {
 		return errors.New("Database has not been initialized")
 	}
 
+	// Users registered before email verification was introduced are treated
+	// as verified, so enabling verification policy does not lock them out.
+	verifyExistingUsers := GormBroker.db.Migrator().HasTable(&models.UserModel{}) &&
+		!GormBroker.db.Migrator().HasColumn(&models.UserModel{}, "EmailVerified")
+
+	// Participants added before conversation roles were introduced become members,
+	// except for the creators, who become owners of their conversations.
+	assignConversationOwners := GormBroker.db.Migrator().HasTable(&models.ConversationParticipantModel{}) &&
+		!GormBroker.db.Migrator().HasColumn(&models.ConversationParticipantModel{}, "Role")
+
+	// Records created before workspaces were introduced are moved to the default Workspace,
+	// which all the users join.
+	assignDefaultWorkspace := GormBroker.db.Migrator().HasTable(&models.UserModel{}) &&
+		!GormBroker.db.Migrator().HasTable(&models.WorkspaceModel{})
+
 	err := GormBroker.db.AutoMigrate(
 		&models.UserModel{},
+		&models.ConversationModel{},
+		&models.ConversationParticipantModel{},
+		&models.MessageModel{},
+		&models.TOTPRecoveryCodeModel{},
+		&models.PersonalAccessTokenModel{},
+		&models.ExternalIdentityModel{},
+		&models.ImpersonationActionModel{},
+		&models.PolicyModel{},
+		&models.WorkspaceModel{},
+		&models.WorkspaceMemberModel{},
+		&models.AuditLogModel{},
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if verifyExistingUsers {
+		err = GormBroker.db.
+			Model(&models.UserModel{}).
+			Where("email_verified = ?", false).
+			Update("email_verified", true).
+			Error
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if assignConversationOwners {
+		err = GormBroker.db.
+			Model(&models.ConversationParticipantModel{}).
+			Where("user_id = (SELECT created_by FROM conversation_models WHERE id = conversation_id)").
+			Update("role", models.ConversationOwnerRole).
+			Error
+
+		if err != nil {
+			return err
+		}
+	}
+
+	defaultWorkspace, err := ensureDefaultWorkspace()
+
+	if err != nil {
+		return err
+	}
+
+	if assignDefaultWorkspace {
+		if err := moveToWorkspace(defaultWorkspace); err != nil {
+			return err
+		}
+	}
+
+	return protectAuditLog()
+}
+
+// protectAuditLog - makes the database reject any change or removal of audit log entries,
+// so the log stays append-only even if the application is compromised.
+func protectAuditLog() error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit log entries cannot be changed';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log_models`,
+		`CREATE TRIGGER audit_log_append_only
+		BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log_models
+		FOR EACH STATEMENT EXECUTE PROCEDURE reject_audit_log_change()`,
+	}
+
+	for _, statement := range statements {
+		if err := GormBroker.db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureDefaultWorkspace - returns the default Workspace, creating it if it does not exist.
+func ensureDefaultWorkspace() (*models.WorkspaceModel, error) {
+	workspace := &models.WorkspaceModel{}
+
+	err := GormBroker.db.
+		Where(&models.WorkspaceModel{Slug: models.DefaultWorkspaceSlug}).
+		FirstOrCreate(workspace, &models.WorkspaceModel{
+			Name: "Default",
+			Slug: models.DefaultWorkspaceSlug,
+		}).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// moveToWorkspace - moves all the Conversations and Messages to given Workspace, and makes
+// all the users its members.
+func moveToWorkspace(workspace *models.WorkspaceModel) error {
+	for _, model := range []interface{}{&models.ConversationModel{}, &models.MessageModel{}} {
+		err := GormBroker.db.
+			Model(model).
+			Where("workspace_id IS NULL").
+			Update("workspace_id", workspace.ID).
+			Error
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var users []*models.UserModel
+
+	if err := GormBroker.db.Find(&users).Error; err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		member := &models.WorkspaceMemberModel{
+			WorkspaceID: workspace.ID,
+			UserID:      user.ID,
+			Role:        models.WorkspaceMemberRole,
+		}
+
+		if err := GormBroker.db.Create(member).Error; err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
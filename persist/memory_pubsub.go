@@ -0,0 +1,108 @@
+package persist
+
+import (
+	"context"
+	"sync"
+)
+
+// Number of messages buffered for a single in-memory subscription.
+const memorySubscriptionBufferSize = 100
+
+// InMemoryPubSub - PubSub implementation delivering messages within current process.
+// Useful for tests and single instance deployments.
+type InMemoryPubSub struct {
+	subscriptions map[string]map[*memorySubscription]bool
+
+	sync.RWMutex
+}
+
+// NewInMemoryPubSub - returns new InMemoryPubSub instance.
+func NewInMemoryPubSub() *InMemoryPubSub {
+	return &InMemoryPubSub{
+		subscriptions: make(map[string]map[*memorySubscription]bool),
+	}
+}
+
+// Publish - delivers given message to all subscribers of passed channel.
+// Similarly to Redis, message is dropped for subscribers that cannot keep up.
+func (ps *InMemoryPubSub) Publish(ctx context.Context, channel string, message []byte) *CacheResponse {
+	ps.RLock()
+	defer ps.RUnlock()
+
+	for subscription := range ps.subscriptions[channel] {
+		subscription.deliver(&PubSubMessage{
+			Channel: channel,
+			Payload: message,
+		})
+	}
+
+	return NewCacheResponse()
+}
+
+// Subscribe - subscribes to given channels.
+func (ps *InMemoryPubSub) Subscribe(ctx context.Context, channels ...string) Subscription {
+	ps.Lock()
+	defer ps.Unlock()
+
+	subscription := &memorySubscription{
+		pubSub:   ps,
+		channels: channels,
+		messages: make(chan *PubSubMessage, memorySubscriptionBufferSize),
+	}
+
+	for _, channel := range channels {
+		if ps.subscriptions[channel] == nil {
+			ps.subscriptions[channel] = make(map[*memorySubscription]bool)
+		}
+
+		ps.subscriptions[channel][subscription] = true
+	}
+
+	return subscription
+}
+
+// unsubscribe - removes given subscription from all of its channels.
+func (ps *InMemoryPubSub) unsubscribe(subscription *memorySubscription) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	for _, channel := range subscription.channels {
+		delete(ps.subscriptions[channel], subscription)
+
+		if len(ps.subscriptions[channel]) == 0 {
+			delete(ps.subscriptions, channel)
+		}
+	}
+}
+
+type memorySubscription struct {
+	pubSub   *InMemoryPubSub
+	channels []string
+	messages chan *PubSubMessage
+
+	closeOnce sync.Once
+}
+
+// Channel - Subscription interface implementation.
+func (ms *memorySubscription) Channel() <-chan *PubSubMessage {
+	return ms.messages
+}
+
+// Close - Subscription interface implementation.
+func (ms *memorySubscription) Close() error {
+	ms.closeOnce.Do(func() {
+		// Unsubscribing first guarantees that no message will be delivered
+		// to already closed Go channel.
+		ms.pubSub.unsubscribe(ms)
+		close(ms.messages)
+	})
+
+	return nil
+}
+
+func (ms *memorySubscription) deliver(message *PubSubMessage) {
+	select {
+	case ms.messages <- message:
+	default:
+	}
+}
@@ -0,0 +1,80 @@
+package persist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type inMemoryPubSubSuite struct {
+	suite.Suite
+	pubSub      *InMemoryPubSub
+	ctx         context.Context
+	testChannel string
+	testMessage []byte
+}
+
+func (s *inMemoryPubSubSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.testChannel = "test_channel"
+	s.testMessage = []byte("test_message")
+}
+
+func (s *inMemoryPubSubSuite) SetupTest() {
+	s.pubSub = NewInMemoryPubSub()
+}
+
+func TestInMemoryPubSubSuite(t *testing.T) {
+	suite.Run(t, new(inMemoryPubSubSuite))
+}
+
+func (s *inMemoryPubSubSuite) receive(subscription Subscription) *PubSubMessage {
+	select {
+	case message := <-subscription.Channel():
+		return message
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+}
+
+func (s *inMemoryPubSubSuite) TestPublish() {
+	first := s.pubSub.Subscribe(s.ctx, s.testChannel)
+	second := s.pubSub.Subscribe(s.ctx, s.testChannel, "other_channel")
+
+	err := s.pubSub.Publish(s.ctx, s.testChannel, s.testMessage).Err()
+
+	assert.Nil(s.T(), err)
+
+	for _, subscription := range []Subscription{first, second} {
+		message := s.receive(subscription)
+
+		assert.NotNil(s.T(), message)
+		assert.Equal(s.T(), s.testChannel, message.Channel)
+		assert.Equal(s.T(), s.testMessage, message.Payload)
+	}
+}
+
+func (s *inMemoryPubSubSuite) TestPublish_OtherChannel() {
+	subscription := s.pubSub.Subscribe(s.ctx, s.testChannel)
+
+	s.pubSub.Publish(s.ctx, "other_channel", s.testMessage)
+
+	assert.Nil(s.T(), s.receive(subscription))
+}
+
+func (s *inMemoryPubSubSuite) TestClose() {
+	subscription := s.pubSub.Subscribe(s.ctx, s.testChannel)
+
+	assert.Nil(s.T(), subscription.Close())
+	assert.Nil(s.T(), subscription.Close())
+
+	s.pubSub.Publish(s.ctx, s.testChannel, s.testMessage)
+
+	_, ok := <-subscription.Channel()
+
+	assert.False(s.T(), ok)
+	assert.Empty(s.T(), s.pubSub.subscriptions)
+}
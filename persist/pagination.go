@@ -0,0 +1,121 @@
+package persist
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SortDirection - direction records of a page are sorted in.
+type SortDirection string
+
+// Map of valid sort directions (SortDirection).
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+const (
+	// DefaultPageLimit - number of records returned when no limit is specified.
+	DefaultPageLimit = 20
+
+	// MaxPageLimit - maximum number of records returned in a single page.
+	MaxPageLimit = 100
+)
+
+// ErrInvalidCursor - returned when passed cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("Cursor is malformed.")
+
+// Pageable - interface for records that can be paginated with a cursor.
+type Pageable interface {
+	GetID() uuid.UUID
+	GetCreatedAt() time.Time
+}
+
+// PageRequest - describes a single page of records to be fetched. Records are
+// sorted by creation time and ID, and Cursor points to the last record of
+// the previous page.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	Direction SortDirection
+}
+
+// NewPageRequest - returns new PageRequest instance, with defaults applied
+// to missing or out of range values.
+func NewPageRequest(limit int, cursor string, direction SortDirection) *PageRequest {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	if direction != SortDescending {
+		direction = SortAscending
+	}
+
+	return &PageRequest{
+		Limit:     limit,
+		Cursor:    cursor,
+		Direction: direction,
+	}
+}
+
+// cursor - decoded representation of an opaque cursor.
+type cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// EncodeCursor - returns an opaque cursor pointing to given record.
+func EncodeCursor(record Pageable) string {
+	data, _ := json.Marshal(&cursor{
+		CreatedAt: record.GetCreatedAt(),
+		ID:        record.GetID(),
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor - returns cursor decoded from its opaque representation.
+func decodeCursor(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var decoded cursor
+
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &decoded, nil
+}
+
+// trimPage - cuts off the additional record fetched in order to check if the next page
+// exists. Returns the cursor of the next page, or empty string if there is none.
+func trimPage(dest interface{}, limit int) string {
+	records := reflect.Indirect(reflect.ValueOf(dest))
+
+	if records.Kind() != reflect.Slice || records.Len() <= limit {
+		return ""
+	}
+
+	records.Set(records.Slice(0, limit))
+
+	last, ok := records.Index(limit - 1).Interface().(Pageable)
+
+	if !ok {
+		return ""
+	}
+
+	return EncodeCursor(last)
+}
@@ -0,0 +1,93 @@
+package persist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type pageableRecord struct {
+	id        uuid.UUID
+	createdAt time.Time
+}
+
+func (pr *pageableRecord) GetID() uuid.UUID {
+	return pr.id
+}
+
+func (pr *pageableRecord) GetCreatedAt() time.Time {
+	return pr.createdAt
+}
+
+type paginationSuite struct {
+	suite.Suite
+}
+
+func TestPaginationSuite(t *testing.T) {
+	suite.Run(t, new(paginationSuite))
+}
+
+func (s *paginationSuite) TestNewPageRequest() {
+	page := NewPageRequest(0, "test_cursor", "")
+
+	assert.Equal(s.T(), DefaultPageLimit, page.Limit)
+	assert.Equal(s.T(), "test_cursor", page.Cursor)
+	assert.Equal(s.T(), SortAscending, page.Direction)
+
+	page = NewPageRequest(MaxPageLimit+1, "", SortDescending)
+
+	assert.Equal(s.T(), MaxPageLimit, page.Limit)
+	assert.Equal(s.T(), SortDescending, page.Direction)
+}
+
+func (s *paginationSuite) TestCursor() {
+	record := &pageableRecord{
+		id:        uuid.New(),
+		createdAt: time.Now().UTC(),
+	}
+
+	decoded, err := decodeCursor(EncodeCursor(record))
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), record.id, decoded.ID)
+	assert.True(s.T(), record.createdAt.Equal(decoded.CreatedAt))
+}
+
+func (s *paginationSuite) TestCursor_Malformed() {
+	for _, encoded := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+		decoded, err := decodeCursor(encoded)
+
+		assert.Nil(s.T(), decoded)
+		assert.Equal(s.T(), ErrInvalidCursor, err)
+	}
+}
+
+func (s *paginationSuite) TestTrimPage() {
+	records := []*pageableRecord{
+		{id: uuid.New()},
+		{id: uuid.New()},
+		{id: uuid.New()},
+	}
+
+	nextCursor := trimPage(&records, 2)
+
+	assert.Len(s.T(), records, 2)
+
+	decoded, err := decodeCursor(nextCursor)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), records[1].id, decoded.ID)
+}
+
+func (s *paginationSuite) TestTrimPage_LastPage() {
+	records := []*pageableRecord{
+		{id: uuid.New()},
+		{id: uuid.New()},
+	}
+
+	assert.Empty(s.T(), trimPage(&records, 2))
+	assert.Len(s.T(), records, 2)
+}
@@ -0,0 +1,30 @@
+package persist
+
+import (
+	"context"
+)
+
+// PubSub - basic, common publish/subscribe interface.
+type PubSub interface {
+	// Publish - publish given message on passed channel.
+	Publish(ctx context.Context, channel string, message []byte) *CacheResponse
+
+	// Subscribe - subscribe to given channels.
+	Subscribe(ctx context.Context, channels ...string) Subscription
+}
+
+// Subscription - basic subscription interface.
+type Subscription interface {
+	// Channel - returns Go channel receiving published messages. It is closed
+	// together with the Subscription.
+	Channel() <-chan *PubSubMessage
+
+	// Close - unsubscribes from all the channels.
+	Close() error
+}
+
+// PubSubMessage - message received from a subscribed channel.
+type PubSubMessage struct {
+	Channel string
+	Payload []byte
+}
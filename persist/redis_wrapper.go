@@ -3,11 +3,22 @@ package persist
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// compareAndSetScript - sets KEYS[1] to ARGV[2] with ARGV[3] milliseconds expiration,
+// if its current value equals ARGV[1].
+const compareAndSetScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`
+
 type redisWrapper struct {
 	redis *redis.Client
 }
@@ -29,6 +40,13 @@ func (rc *redisWrapper) Set(ctx context.Context, key string, value interface{},
 	return cacheResponseFromStatusCmd(cmd)
 }
 
+// SetNX - wrapper for Redis' SetNX method.
+func (rc *redisWrapper) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *CacheResponse {
+	cmd := rc.redis.SetNX(ctx, key, value, expiration)
+
+	return cacheResponseFromBoolCmd(cmd)
+}
+
 // Del - wrapper for Redis' Del method.
 func (rc *redisWrapper) Del(ctx context.Context, keys ...string) *CacheResponse {
 	cmd := rc.redis.Del(ctx, keys...)
@@ -36,6 +54,69 @@ func (rc *redisWrapper) Del(ctx context.Context, keys ...string) *CacheResponse
 	return cacheResponseFromIntCmd(cmd)
 }
 
+// Incr - wrapper for Redis' Incr method.
+func (rc *redisWrapper) Incr(ctx context.Context, key string) *CacheResponse {
+	cmd := rc.redis.Incr(ctx, key)
+
+	return cacheResponseFromIntCmd(cmd)
+}
+
+// Expire - wrapper for Redis' Expire method.
+func (rc *redisWrapper) Expire(ctx context.Context, key string, expiration time.Duration) *CacheResponse {
+	cmd := rc.redis.Expire(ctx, key, expiration)
+
+	return cacheResponseFromBoolCmd(cmd)
+}
+
+// SAdd - wrapper for Redis' SAdd method.
+func (rc *redisWrapper) SAdd(ctx context.Context, key string, members ...string) *CacheResponse {
+	cmd := rc.redis.SAdd(ctx, key, toInterfaces(members)...)
+
+	return cacheResponseFromIntCmd(cmd)
+}
+
+// SRem - wrapper for Redis' SRem method.
+func (rc *redisWrapper) SRem(ctx context.Context, key string, members ...string) *CacheResponse {
+	cmd := rc.redis.SRem(ctx, key, toInterfaces(members)...)
+
+	return cacheResponseFromIntCmd(cmd)
+}
+
+// SMembers - wrapper for Redis' SMembers method.
+func (rc *redisWrapper) SMembers(ctx context.Context, key string) *CacheResponse {
+	cmd := rc.redis.SMembers(ctx, key)
+
+	return cacheResponseFromStringSliceCmd(cmd)
+}
+
+// CompareAndSet - sets given key with Lua script, so the comparison and the update
+// cannot be interleaved with other clients' commands.
+func (rc *redisWrapper) CompareAndSet(
+	ctx context.Context,
+	key string,
+	expected string,
+	value interface{},
+	expiration time.Duration,
+) *CacheResponse {
+	cmd := rc.redis.Eval(ctx, compareAndSetScript, []string{key}, expected, value, expiration.Milliseconds())
+
+	return cacheResponseFromCmd(cmd)
+}
+
+// Publish - wrapper for Redis' Publish method.
+func (rc *redisWrapper) Publish(ctx context.Context, channel string, message []byte) *CacheResponse {
+	cmd := rc.redis.Publish(ctx, channel, message)
+
+	return cacheResponseFromIntCmd(cmd)
+}
+
+// Subscribe - wrapper for Redis' Subscribe method.
+func (rc *redisWrapper) Subscribe(ctx context.Context, channels ...string) Subscription {
+	pubSub := rc.redis.Subscribe(ctx, channels...)
+
+	return newRedisSubscription(pubSub)
+}
+
 // InitRedisClient - initializes Redis storage driver.
 func InitRedisCache(host, port, password string) *redisWrapper {
 	if RedisCache != nil {
@@ -75,6 +156,20 @@ func cacheResponseFromStringCmd(cmd *redis.StringCmd) *CacheResponse {
 		res.SetErr(cmd.Err())
 	}
 
+	res.SetVal(cmd.Val())
+
+	return res
+}
+
+func cacheResponseFromStringSliceCmd(cmd *redis.StringSliceCmd) *CacheResponse {
+	res := NewCacheResponse()
+
+	if cmd.Err() != nil {
+		res.SetErr(cmd.Err())
+	}
+
+	res.SetVals(cmd.Val())
+
 	return res
 }
 
@@ -85,5 +180,84 @@ func cacheResponseFromIntCmd(cmd *redis.IntCmd) *CacheResponse {
 		res.SetErr(cmd.Err())
 	}
 
+	res.SetVal(strconv.FormatInt(cmd.Val(), 10))
+
+	return res
+}
+
+func cacheResponseFromBoolCmd(cmd *redis.BoolCmd) *CacheResponse {
+	res := NewCacheResponse()
+
+	if cmd.Err() != nil {
+		res.SetErr(cmd.Err())
+	}
+
+	if cmd.Val() {
+		res.SetVal("1")
+	} else {
+		res.SetVal("0")
+	}
+
+	return res
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+
+	for i, value := range values {
+		result[i] = value
+	}
+
+	return result
+}
+
+func cacheResponseFromCmd(cmd *redis.Cmd) *CacheResponse {
+	res := NewCacheResponse()
+
+	val, err := cmd.Int64()
+
+	if err != nil {
+		res.SetErr(err)
+	}
+
+	res.SetVal(strconv.FormatInt(val, 10))
+
 	return res
 }
+
+type redisSubscription struct {
+	pubSub   *redis.PubSub
+	messages chan *PubSubMessage
+}
+
+func newRedisSubscription(pubSub *redis.PubSub) *redisSubscription {
+	subscription := &redisSubscription{
+		pubSub:   pubSub,
+		messages: make(chan *PubSubMessage),
+	}
+
+	// Redis' channel is closed together with PubSub, which ends the loop
+	// and closes subscription's channel as well.
+	go func() {
+		defer close(subscription.messages)
+
+		for message := range pubSub.Channel() {
+			subscription.messages <- &PubSubMessage{
+				Channel: message.Channel,
+				Payload: []byte(message.Payload),
+			}
+		}
+	}()
+
+	return subscription
+}
+
+// Channel - Subscription interface implementation.
+func (rs *redisSubscription) Channel() <-chan *PubSubMessage {
+	return rs.messages
+}
+
+// Close - Subscription interface implementation.
+func (rs *redisSubscription) Close() error {
+	return rs.pubSub.Close()
+}
@@ -0,0 +1,78 @@
+package persist
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrWorkspaceMismatch - returned when a broker scoped to a Workspace saves a record
+// belonging to other Workspace.
+var ErrWorkspaceMismatch = errors.New("Record belongs to other Workspace.")
+
+// scope - returns DB instance limited to the records of broker's Workspace, if the broker
+// is scoped and records of given destination's type are visible only within a Workspace.
+func (gm *gormWrapper) scope(dest interface{}) *gorm.DB {
+	if gm.workspaceID == nil {
+		return gm.db
+	}
+
+	scoped, ok := newModel(dest).(models.WorkspaceScoped)
+
+	if !ok {
+		return gm.db
+	}
+
+	return gm.db.Where(scoped.WorkspaceScope(), *gm.workspaceID)
+}
+
+// assignWorkspace - sets broker's Workspace on given record, if the broker is scoped
+// and the record belongs to a Workspace. Returns ErrWorkspaceMismatch if the record
+// belongs to other Workspace already.
+func (gm *gormWrapper) assignWorkspace(value interface{}) error {
+	if gm.workspaceID == nil {
+		return nil
+	}
+
+	resource, ok := value.(models.WorkspaceResource)
+
+	if !ok {
+		return nil
+	}
+
+	workspaceID := resource.GetWorkspaceID()
+
+	if workspaceID == uuid.Nil {
+		resource.SetWorkspaceID(*gm.workspaceID)
+
+		return nil
+	}
+
+	if workspaceID != *gm.workspaceID {
+		return ErrWorkspaceMismatch
+	}
+
+	return nil
+}
+
+// newModel - returns new instance of the model given destination (a model, a pointer
+// or a slice of them) holds, or nil if it does not hold a struct.
+func newModel(dest interface{}) interface{} {
+	modelType := reflect.TypeOf(dest)
+
+	for modelType != nil {
+		switch modelType.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			modelType = modelType.Elem()
+		case reflect.Struct:
+			return reflect.New(modelType).Interface()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package persist
+
+import (
+	"testing"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type workspaceScopeSuite struct {
+	suite.Suite
+	testWorkspaceID uuid.UUID
+	broker          *gormWrapper
+}
+
+func (s *workspaceScopeSuite) SetupTest() {
+	s.testWorkspaceID = uuid.New()
+	s.broker = &gormWrapper{workspaceID: &s.testWorkspaceID}
+}
+
+func TestWorkspaceScopeSuite(t *testing.T) {
+	suite.Run(t, new(workspaceScopeSuite))
+}
+
+func (s *workspaceScopeSuite) TestNewModel() {
+	var conversations []*models.ConversationModel
+
+	assert.IsType(s.T(), &models.ConversationModel{}, newModel(&conversations))
+	assert.IsType(s.T(), &models.MessageModel{}, newModel(&models.MessageModel{}))
+	assert.IsType(s.T(), &models.UserModel{}, newModel(models.UserModel{}))
+	assert.Nil(s.T(), newModel(nil))
+	assert.Nil(s.T(), newModel("test"))
+}
+
+func (s *workspaceScopeSuite) TestAssignWorkspace() {
+	conversation := &models.ConversationModel{}
+
+	err := s.broker.assignWorkspace(conversation)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testWorkspaceID, conversation.WorkspaceID)
+}
+
+func (s *workspaceScopeSuite) TestAssignWorkspace_Mismatch() {
+	conversation := &models.ConversationModel{WorkspaceID: uuid.New()}
+
+	err := s.broker.assignWorkspace(conversation)
+
+	assert.Equal(s.T(), ErrWorkspaceMismatch, err)
+}
+
+func (s *workspaceScopeSuite) TestAssignWorkspace_NotScoped() {
+	conversation := &models.ConversationModel{}
+
+	err := (&gormWrapper{}).assignWorkspace(conversation)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), uuid.Nil, conversation.WorkspaceID)
+}
@@ -0,0 +1,130 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+const (
+	// EventsChannel - PubSub channel Events are distributed with.
+	EventsChannel = "gochat:events"
+
+	// SessionsChannel - PubSub channel closed sessions are distributed with.
+	SessionsChannel = "gochat:sessions"
+)
+
+// localDeliverer - interface for an entity delivering Events to clients connected
+// to current instance.
+type localDeliverer interface {
+	Publish(event *Event) error
+	CloseSession(authUUID uuid.UUID)
+}
+
+// eventEnvelope - wraps an Event together with its recipients, as recipients
+// are not exposed to the clients.
+type eventEnvelope struct {
+	Event      *Event      `json:"event"`
+	Recipients []uuid.UUID `json:"recipients"`
+}
+
+// Broadcaster - Publisher distributing Events across all the application's instances
+// via PubSub. Every instance delivers received Events to the clients connected to it.
+type Broadcaster struct {
+	hub    localDeliverer
+	pubSub persist.PubSub
+	ctx    context.Context
+}
+
+// DefaultBroadcaster - Broadcaster shared by the whole application.
+var DefaultBroadcaster *Broadcaster
+
+// NewBroadcaster - returns new Broadcaster instance.
+func NewBroadcaster(hub localDeliverer, pubSub persist.PubSub) *Broadcaster {
+	return &Broadcaster{
+		hub:    hub,
+		pubSub: pubSub,
+		ctx:    context.Background(),
+	}
+}
+
+// InitBroadcaster - initializes DefaultBroadcaster, based on DefaultHub and Redis,
+// and starts listening for Events published by other instances.
+func InitBroadcaster() *Broadcaster {
+	if DefaultBroadcaster != nil {
+		return DefaultBroadcaster
+	}
+
+	DefaultBroadcaster = NewBroadcaster(InitHub(), persist.RedisCache)
+
+	go DefaultBroadcaster.Listen()
+
+	return DefaultBroadcaster
+}
+
+// Publish - publishes given Event to all the instances.
+func (b *Broadcaster) Publish(event *Event) error {
+	data, err := json.Marshal(&eventEnvelope{
+		Event:      event,
+		Recipients: event.Recipients,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return b.pubSub.Publish(b.ctx, EventsChannel, data).Err()
+}
+
+// CloseSession - closes connections opened with given authorization on all the instances.
+func (b *Broadcaster) CloseSession(authUUID uuid.UUID) error {
+	return b.pubSub.Publish(b.ctx, SessionsChannel, []byte(authUUID.String())).Err()
+}
+
+// Listen - delivers messages published by any instance to local clients.
+// It blocks until the subscription is closed.
+func (b *Broadcaster) Listen() {
+	subscription := b.pubSub.Subscribe(b.ctx, EventsChannel, SessionsChannel)
+
+	b.listen(subscription)
+}
+
+func (b *Broadcaster) listen(subscription persist.Subscription) {
+	for message := range subscription.Channel() {
+		switch message.Channel {
+		case EventsChannel:
+			b.deliverEvent(message.Payload)
+		case SessionsChannel:
+			b.closeSession(message.Payload)
+		}
+	}
+}
+
+func (b *Broadcaster) deliverEvent(payload []byte) {
+	var envelope eventEnvelope
+
+	if err := json.Unmarshal(payload, &envelope); err != nil || envelope.Event == nil {
+		log.Printf("Malformed event received - omitting...")
+		return
+	}
+
+	envelope.Event.Recipients = envelope.Recipients
+
+	if err := b.hub.Publish(envelope.Event); err != nil {
+		log.Printf("Delivering %v event failed: %v", envelope.Event.Type, err)
+	}
+}
+
+func (b *Broadcaster) closeSession(payload []byte) {
+	authUUID, err := uuid.ParseBytes(payload)
+
+	if err != nil {
+		log.Printf("Malformed session received - omitting...")
+		return
+	}
+
+	b.hub.CloseSession(authUUID)
+}
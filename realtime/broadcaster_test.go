@@ -0,0 +1,124 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type localDelivererMock struct {
+	mock.Mock
+}
+
+func (ld *localDelivererMock) Publish(event *Event) error {
+	args := ld.Called(event)
+
+	return args.Error(0)
+}
+
+func (ld *localDelivererMock) CloseSession(authUUID uuid.UUID) {
+	ld.Called(authUUID)
+}
+
+type broadcasterSuite struct {
+	suite.Suite
+	pubSub         *persist.InMemoryPubSub
+	testRecipients []uuid.UUID
+	testAuthUUID   uuid.UUID
+}
+
+func (s *broadcasterSuite) SetupSuite() {
+	s.testRecipients = []uuid.UUID{uuid.New(), uuid.New()}
+	s.testAuthUUID = uuid.New()
+}
+
+func (s *broadcasterSuite) SetupTest() {
+	s.pubSub = persist.NewInMemoryPubSub()
+}
+
+func TestBroadcasterSuite(t *testing.T) {
+	suite.Run(t, new(broadcasterSuite))
+}
+
+// startListening - returns Broadcaster listening to PubSub messages with given hub,
+// together with a function stopping it.
+func (s *broadcasterSuite) startListening(hub localDeliverer) (*Broadcaster, func()) {
+	broadcaster := NewBroadcaster(hub, s.pubSub)
+	subscription := s.pubSub.Subscribe(broadcaster.ctx, EventsChannel, SessionsChannel)
+	done := make(chan struct{})
+
+	go func() {
+		broadcaster.listen(subscription)
+		close(done)
+	}()
+
+	return broadcaster, func() {
+		subscription.Close()
+		<-done
+	}
+}
+
+func (s *broadcasterSuite) TestPublish() {
+	publisherHub := new(localDelivererMock)
+	receiverHub := new(localDelivererMock)
+	receiverHub.On(
+		"Publish",
+		mock.Anything,
+	).Return(nil)
+
+	publisher := NewBroadcaster(publisherHub, s.pubSub)
+	_, stop := s.startListening(receiverHub)
+
+	event := NewEvent(MessageCreatedEvent, uuid.New(), s.testRecipients, "test_payload")
+
+	assert.Nil(s.T(), publisher.Publish(event))
+
+	stop()
+
+	receiverHub.AssertNumberOfCalls(s.T(), "Publish", 1)
+	publisherHub.AssertNumberOfCalls(s.T(), "Publish", 0)
+
+	received := receiverHub.Calls[0].Arguments.Get(0).(*Event)
+
+	assert.Equal(s.T(), event.ID, received.ID)
+	assert.Equal(s.T(), event.Type, received.Type)
+	assert.Equal(s.T(), event.ConversationID, received.ConversationID)
+	assert.Equal(s.T(), s.testRecipients, received.Recipients)
+	assert.WithinDuration(s.T(), event.CreatedAt, received.CreatedAt, time.Millisecond)
+}
+
+func (s *broadcasterSuite) TestPublish_MalformedEvent() {
+	receiverHub := new(localDelivererMock)
+
+	_, stop := s.startListening(receiverHub)
+
+	s.pubSub.Publish(context.Background(), EventsChannel, []byte("malformed"))
+
+	stop()
+
+	receiverHub.AssertNumberOfCalls(s.T(), "Publish", 0)
+}
+
+func (s *broadcasterSuite) TestCloseSession() {
+	receiverHub := new(localDelivererMock)
+	receiverHub.On(
+		"CloseSession",
+		mock.Anything,
+	).Return()
+
+	publisher := NewBroadcaster(new(localDelivererMock), s.pubSub)
+	_, stop := s.startListening(receiverHub)
+
+	assert.Nil(s.T(), publisher.CloseSession(s.testAuthUUID))
+
+	stop()
+
+	receiverHub.AssertNumberOfCalls(s.T(), "CloseSession", 1)
+	receiverHub.AssertCalled(s.T(), "CloseSession", s.testAuthUUID)
+}
@@ -0,0 +1,158 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum message size allowed from peer.
+	maxMessageSize = 512
+
+	// Number of messages buffered for a single client.
+	sendBufferSize = 256
+)
+
+// Client - single WebSocket connection of a user.
+type Client struct {
+	UserID   uuid.UUID
+	AuthUUID uuid.UUID
+
+	// PersonalAccessToken - true if the connection has been opened with personal access token,
+	// instead of session's JWT. In such case, AuthUUID holds the ID of the token.
+	PersonalAccessToken bool
+
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+	done chan struct{}
+
+	closeOnce sync.Once
+}
+
+// NewClient - returns new Client instance.
+func NewClient(hub *Hub, conn *websocket.Conn, userID, authUUID uuid.UUID) *Client {
+	return &Client{
+		UserID:   userID,
+		AuthUUID: authUUID,
+		hub:      hub,
+		conn:     conn,
+		send:     make(chan []byte, sendBufferSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run - registers the Client in its Hub and pumps messages until connection is closed.
+// It blocks until the connection is closed.
+func (c *Client) Run() {
+	c.hub.Register(c)
+
+	go c.writePump()
+
+	c.readPump()
+}
+
+// GetUserID - returns the ID of connected user.
+func (c *Client) GetUserID() uuid.UUID {
+	return c.UserID
+}
+
+// GetAuthUUID - returns the authorization the connection has been opened with.
+func (c *Client) GetAuthUUID() uuid.UUID {
+	return c.AuthUUID
+}
+
+// Deliver - schedules Event's data to be sent to the peer. Returns false if client's buffer is full.
+func (c *Client) Deliver(event *Event, data []byte) bool {
+	select {
+	case <-c.done:
+		return true
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close - unregisters the Client and signals its connection to be closed.
+// Safe to call multiple times.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		c.hub.Unregister(c)
+		close(c.done)
+	})
+}
+
+// readPump - reads messages from the peer. Gochat clients don't send anything
+// over the socket, therefore incoming messages are discarded - reading is needed
+// only to process control frames (pong, close).
+func (c *Client) readPump() {
+	defer c.Close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump - writes queued messages and pings to the peer. On every ping,
+// client's authorization is checked as well - if user logged out (possibly
+// on another instance), the connection is closed.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+
+	defer func() {
+		ticker.Stop()
+		c.Close()
+
+		_ = c.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			_ = c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(writeWait),
+			)
+			return
+		case data := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if !c.hub.isSessionActive(c.AuthUUID, c.PersonalAccessToken) {
+				return
+			}
+
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
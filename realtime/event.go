@@ -0,0 +1,70 @@
+package realtime
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType - type of the event pushed to connected clients.
+type EventType string
+
+// Map of valid event types (EventType).
+const (
+	MessageCreatedEvent EventType = "message.created"
+	MessageUpdatedEvent EventType = "message.updated"
+	MessageDeletedEvent EventType = "message.deleted"
+
+	ConversationCreatedEvent EventType = "conversation.created"
+	ConversationUpdatedEvent EventType = "conversation.updated"
+	ConversationDeletedEvent EventType = "conversation.deleted"
+	ParticipantsAddedEvent   EventType = "participants.added"
+	ParticipantUpdatedEvent  EventType = "participant.updated"
+	ParticipantRemovedEvent  EventType = "participant.removed"
+
+	// StreamResetEvent - sent to a resuming stream, when the Events it missed
+	// cannot be replayed anymore. Client should reload its state.
+	StreamResetEvent EventType = "stream.reset"
+)
+
+// Event - describes a change in a Conversation, that should be delivered
+// to all of its participants.
+type Event struct {
+	ID             uuid.UUID   `json:"id"`
+	Type           EventType   `json:"type"`
+	ConversationID uuid.UUID   `json:"conversationId"`
+	Payload        interface{} `json:"payload"`
+	CreatedAt      time.Time   `json:"createdAt"`
+
+	// Recipients - IDs of the users the event should be delivered to.
+	Recipients []uuid.UUID `json:"-"`
+}
+
+// NewEvent - returns new Event instance.
+func NewEvent(eventType EventType, conversationID uuid.UUID, recipients []uuid.UUID, payload interface{}) *Event {
+	return &Event{
+		ID:             uuid.New(),
+		Type:           eventType,
+		ConversationID: conversationID,
+		Payload:        payload,
+		CreatedAt:      time.Now(),
+		Recipients:     recipients,
+	}
+}
+
+// isAddressedTo - returns true if user with given ID is one of Event's recipients.
+func (e *Event) isAddressedTo(userID uuid.UUID) bool {
+	for _, recipientID := range e.Recipients {
+		if recipientID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Publisher - interface for an entity able to deliver Events to their recipients.
+type Publisher interface {
+	// Publish - delivers given Event to its recipients.
+	Publish(event *Event) error
+}
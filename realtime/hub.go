@@ -0,0 +1,227 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+// Number of the most recent Events kept by the Hub, in order to allow
+// reconnecting clients to receive what they missed.
+const historySize = 1000
+
+// Subscriber - single connection (WebSocket, Server-Sent Events stream) receiving
+// Events addressed to its user.
+type Subscriber interface {
+	// GetUserID - returns the ID of connected user.
+	GetUserID() uuid.UUID
+
+	// GetAuthUUID - returns the authorization the connection has been opened with.
+	GetAuthUUID() uuid.UUID
+
+	// Deliver - schedules Event (and its JSON representation) to be sent to the peer.
+	// Returns false if Subscriber cannot keep up with incoming Events.
+	Deliver(event *Event, data []byte) bool
+
+	// Close - closes the connection. Has to be safe to call multiple times.
+	Close()
+}
+
+// Hub - keeps track of all the subscribers connected to current instance, grouped by user,
+// and delivers Events to them.
+type Hub struct {
+	subscribers map[uuid.UUID]map[Subscriber]bool
+	history     []*Event
+	cache       persist.Cache
+	broker      persist.DBBroker
+	ctx         context.Context
+
+	sync.RWMutex
+}
+
+// DefaultHub - Hub shared by the whole application.
+var DefaultHub *Hub
+
+// NewHub - returns new Hub instance, checking sessions in given cache, and personal
+// access tokens with given broker.
+func NewHub(cache persist.Cache, broker persist.DBBroker) *Hub {
+	return &Hub{
+		subscribers: make(map[uuid.UUID]map[Subscriber]bool),
+		history:     make([]*Event, 0, historySize),
+		cache:       cache,
+		broker:      broker,
+		ctx:         context.Background(),
+	}
+}
+
+// InitHub - initializes DefaultHub.
+func InitHub() *Hub {
+	if DefaultHub != nil {
+		return DefaultHub
+	}
+
+	DefaultHub = NewHub(persist.RedisCache, persist.GormBroker)
+
+	return DefaultHub
+}
+
+// Register - adds given subscriber to the Hub.
+func (h *Hub) Register(subscriber Subscriber) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.register(subscriber)
+}
+
+// RegisterWithReplay - adds given subscriber to the Hub, and returns all the Events
+// addressed to its user, published after the Event with given ID. Second return
+// value is false when given Event is no longer (or has never been) known to the Hub -
+// in such case, subscriber is registered, but nothing can be replayed.
+// Registration and replay are atomic, therefore no Event can be missed in between.
+func (h *Hub) RegisterWithReplay(subscriber Subscriber, lastEventID uuid.UUID) ([]*Event, bool) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.register(subscriber)
+
+	for i, event := range h.history {
+		if event.ID != lastEventID {
+			continue
+		}
+
+		var missed []*Event
+
+		for _, next := range h.history[i+1:] {
+			if next.isAddressedTo(subscriber.GetUserID()) {
+				missed = append(missed, next)
+			}
+		}
+
+		return missed, true
+	}
+
+	return nil, false
+}
+
+// Unregister - removes given subscriber from the Hub.
+func (h *Hub) Unregister(subscriber Subscriber) {
+	h.Lock()
+	defer h.Unlock()
+
+	userID := subscriber.GetUserID()
+	userSubscribers := h.subscribers[userID]
+
+	if userSubscribers == nil {
+		return
+	}
+
+	delete(userSubscribers, subscriber)
+
+	if len(userSubscribers) == 0 {
+		delete(h.subscribers, userID)
+	}
+}
+
+// Publish - delivers given Event to all the recipients' subscribers connected to the Hub.
+func (h *Hub) Publish(event *Event) error {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	if len(h.history) == historySize {
+		h.history = append(h.history[:0], h.history[1:]...)
+	}
+
+	h.history = append(h.history, event)
+
+	for _, userID := range event.Recipients {
+		for subscriber := range h.subscribers[userID] {
+			if !subscriber.Deliver(event, data) {
+				log.Printf("Subscriber of user %v is too slow - closing connection...", userID)
+				go subscriber.Close()
+			}
+		}
+	}
+
+	return nil
+}
+
+// CloseSession - closes all the subscribers opened with given authorization.
+func (h *Hub) CloseSession(authUUID uuid.UUID) {
+	h.RLock()
+
+	var subscribers []Subscriber
+
+	for _, userSubscribers := range h.subscribers {
+		for subscriber := range userSubscribers {
+			if subscriber.GetAuthUUID() == authUUID {
+				subscribers = append(subscribers, subscriber)
+			}
+		}
+	}
+
+	h.RUnlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.Close()
+	}
+}
+
+// isSessionActive - returns true if authorization entry still exists in the cache. Connections
+// opened with personal access tokens have no session - the token itself is checked instead.
+func (h *Hub) isSessionActive(authUUID uuid.UUID, personalAccessToken bool) bool {
+	if personalAccessToken {
+		return h.isTokenActive(authUUID)
+	}
+
+	if h.cache == nil {
+		return true
+	}
+
+	return h.cache.Get(h.ctx, authUUID.String()).Err() == nil
+}
+
+// isTokenActive - returns true if personal access token with given ID has been neither
+// revoked nor expired.
+func (h *Hub) isTokenActive(tokenID uuid.UUID) bool {
+	if h.broker == nil {
+		return true
+	}
+
+	token := &models.PersonalAccessTokenModel{}
+
+	if err := h.broker.First(token, tokenID).Err(); err != nil {
+		return false
+	}
+
+	return !token.IsExpired(time.Now())
+}
+
+// countSubscribers - returns the number of subscribers connected by given user.
+func (h *Hub) countSubscribers(userID uuid.UUID) int {
+	h.RLock()
+	defer h.RUnlock()
+
+	return len(h.subscribers[userID])
+}
+
+func (h *Hub) register(subscriber Subscriber) {
+	userID := subscriber.GetUserID()
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[Subscriber]bool)
+	}
+
+	h.subscribers[userID][subscriber] = true
+}
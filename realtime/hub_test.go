@@ -0,0 +1,208 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type hubSuite struct {
+	suite.Suite
+	hub          *Hub
+	server       *httptest.Server
+	testUserID   uuid.UUID
+	testAuthUUID uuid.UUID
+}
+
+func (s *hubSuite) SetupTest() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.hub = NewHub(cacheMock, nil)
+	s.testUserID = uuid.New()
+	s.testAuthUUID = uuid.New()
+
+	upgrader := &websocket.Upgrader{}
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+
+		if err != nil {
+			return
+		}
+
+		NewClient(s.hub, conn, s.testUserID, s.testAuthUUID).Run()
+	}))
+}
+
+func (s *hubSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestHubSuite(t *testing.T) {
+	suite.Run(t, new(hubSuite))
+}
+
+func (s *hubSuite) dial() *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(s.server.URL, "http")
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	s.Require().Nil(err)
+
+	// Wait for the Client to be registered.
+	s.Eventually(func() bool {
+		return s.hub.countSubscribers(s.testUserID) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	return conn
+}
+
+func (s *hubSuite) TestPublish() {
+	conn := s.dial()
+	defer conn.Close()
+
+	event := NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{s.testUserID}, "test_payload")
+
+	assert.Nil(s.T(), s.hub.Publish(event))
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := conn.ReadMessage()
+
+	assert.Nil(s.T(), err)
+
+	var received Event
+
+	assert.Nil(s.T(), json.Unmarshal(data, &received))
+	assert.Equal(s.T(), event.ID, received.ID)
+	assert.Equal(s.T(), MessageCreatedEvent, received.Type)
+	assert.Equal(s.T(), "test_payload", received.Payload)
+}
+
+func (s *hubSuite) TestPublish_OtherRecipients() {
+	conn := s.dial()
+	defer conn.Close()
+
+	event := NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{uuid.New()}, "test_payload")
+
+	assert.Nil(s.T(), s.hub.Publish(event))
+
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err := conn.ReadMessage()
+
+	assert.NotNil(s.T(), err)
+}
+
+func (s *hubSuite) TestCloseSession() {
+	conn := s.dial()
+	defer conn.Close()
+
+	s.hub.CloseSession(s.testAuthUUID)
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+
+	assert.True(s.T(), websocket.IsCloseError(err, websocket.CloseNormalClosure))
+	assert.Equal(s.T(), 0, s.hub.countSubscribers(s.testUserID))
+}
+
+func (s *hubSuite) TestIsSessionActive() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorCacheResponse(errors.New("redis: nil")))
+
+	hub := NewHub(cacheMock, nil)
+
+	assert.False(s.T(), hub.isSessionActive(s.testAuthUUID, false))
+	cacheMock.AssertCalled(s.T(), "Get", mock.Anything, s.testAuthUUID.String())
+}
+
+func (s *hubSuite) TestIsSessionActive_PersonalAccessToken() {
+	cacheMock := new(mocks.RedisCacheMock)
+
+	brokerMock := new(mocks.GormMock)
+	brokerMock.On(
+		"First",
+		mock.AnythingOfType("*models.PersonalAccessTokenModel"),
+		[]interface{}{s.testAuthUUID},
+	).Return(mocks.GetDefaultDBResponse())
+
+	hub := NewHub(cacheMock, brokerMock)
+
+	assert.True(s.T(), hub.isSessionActive(s.testAuthUUID, true))
+	cacheMock.AssertNotCalled(s.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func (s *hubSuite) TestIsSessionActive_PersonalAccessTokenRevoked() {
+	brokerMock := new(mocks.GormMock)
+	brokerMock.On(
+		"First",
+		mock.AnythingOfType("*models.PersonalAccessTokenModel"),
+		[]interface{}{s.testAuthUUID},
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+
+	hub := NewHub(new(mocks.RedisCacheMock), brokerMock)
+
+	assert.False(s.T(), hub.isSessionActive(s.testAuthUUID, true))
+}
+
+func (s *hubSuite) TestRegisterWithReplay() {
+	otherUserID := uuid.New()
+	conversationID := uuid.New()
+
+	first := NewEvent(MessageCreatedEvent, conversationID, []uuid.UUID{s.testUserID}, "first")
+	other := NewEvent(MessageCreatedEvent, conversationID, []uuid.UUID{otherUserID}, "other")
+	second := NewEvent(MessageUpdatedEvent, conversationID, []uuid.UUID{s.testUserID, otherUserID}, "second")
+
+	for _, event := range []*Event{first, other, second} {
+		s.Require().Nil(s.hub.Publish(event))
+	}
+
+	client := NewStreamClient(s.hub, s.testUserID, s.testAuthUUID)
+	defer client.Close()
+
+	missed, ok := s.hub.RegisterWithReplay(client, first.ID)
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), []*Event{second}, missed)
+	assert.Equal(s.T(), 1, s.hub.countSubscribers(s.testUserID))
+}
+
+func (s *hubSuite) TestRegisterWithReplay_UnknownEvent() {
+	s.Require().Nil(s.hub.Publish(NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{s.testUserID}, "test_payload")))
+
+	client := NewStreamClient(s.hub, s.testUserID, s.testAuthUUID)
+	defer client.Close()
+
+	missed, ok := s.hub.RegisterWithReplay(client, uuid.New())
+
+	assert.False(s.T(), ok)
+	assert.Empty(s.T(), missed)
+	assert.Equal(s.T(), 1, s.hub.countSubscribers(s.testUserID))
+}
+
+func (s *hubSuite) TestPublish_HistoryLimit() {
+	for i := 0; i < historySize+1; i++ {
+		s.Require().Nil(s.hub.Publish(NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{s.testUserID}, i)))
+	}
+
+	assert.Len(s.T(), s.hub.history, historySize)
+	assert.Equal(s.T(), 1, s.hub.history[0].Payload)
+}
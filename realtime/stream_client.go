@@ -0,0 +1,121 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StreamClient - single, one-way stream of Events (e.g. Server-Sent Events) of a user.
+// Writing Events to the peer is up to the owner of the StreamClient.
+type StreamClient struct {
+	UserID   uuid.UUID
+	AuthUUID uuid.UUID
+
+	// PersonalAccessToken - true if the stream has been opened with personal access token,
+	// instead of session's JWT. In such case, AuthUUID holds the ID of the token.
+	PersonalAccessToken bool
+
+	hub    *Hub
+	events chan *Event
+	done   chan struct{}
+
+	closeOnce sync.Once
+}
+
+// NewStreamClient - returns new StreamClient instance.
+func NewStreamClient(hub *Hub, userID, authUUID uuid.UUID) *StreamClient {
+	return &StreamClient{
+		UserID:   userID,
+		AuthUUID: authUUID,
+		hub:      hub,
+		events:   make(chan *Event, sendBufferSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// Subscribe - registers the StreamClient in its Hub. If lastEventID is not empty,
+// Events published after it are returned, so they can be written before any new Event.
+// Second return value is false if missed Events cannot be replayed.
+func (sc *StreamClient) Subscribe(lastEventID string) ([]*Event, bool) {
+	defer func() {
+		go sc.watchSession()
+	}()
+
+	if lastEventID == "" {
+		sc.hub.Register(sc)
+
+		return nil, true
+	}
+
+	eventID, err := uuid.Parse(lastEventID)
+
+	if err != nil {
+		sc.hub.Register(sc)
+
+		return nil, false
+	}
+
+	return sc.hub.RegisterWithReplay(sc, eventID)
+}
+
+// Events - returns a channel of Events to be written to the peer.
+func (sc *StreamClient) Events() <-chan *Event {
+	return sc.events
+}
+
+// Done - returns a channel closed when the StreamClient gets closed.
+func (sc *StreamClient) Done() <-chan struct{} {
+	return sc.done
+}
+
+// GetUserID - returns the ID of connected user.
+func (sc *StreamClient) GetUserID() uuid.UUID {
+	return sc.UserID
+}
+
+// GetAuthUUID - returns the authorization the stream has been opened with.
+func (sc *StreamClient) GetAuthUUID() uuid.UUID {
+	return sc.AuthUUID
+}
+
+// Deliver - schedules Event to be written to the peer. Returns false if client's buffer is full.
+func (sc *StreamClient) Deliver(event *Event, data []byte) bool {
+	select {
+	case <-sc.done:
+		return true
+	case sc.events <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close - unregisters the StreamClient and signals its stream to be closed.
+// Safe to call multiple times.
+func (sc *StreamClient) Close() {
+	sc.closeOnce.Do(func() {
+		sc.hub.Unregister(sc)
+		close(sc.done)
+	})
+}
+
+// watchSession - periodically checks client's authorization - if user logged out
+// (possibly on another instance), the stream is closed.
+func (sc *StreamClient) watchSession() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sc.done:
+			return
+		case <-ticker.C:
+			if !sc.hub.isSessionActive(sc.AuthUUID, sc.PersonalAccessToken) {
+				sc.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type streamClientSuite struct {
+	suite.Suite
+	hub          *Hub
+	testUserID   uuid.UUID
+	testAuthUUID uuid.UUID
+}
+
+func (s *streamClientSuite) SetupTest() {
+	cacheMock := new(mocks.RedisCacheMock)
+	cacheMock.On(
+		"Get",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultCacheResponse())
+
+	s.hub = NewHub(cacheMock, nil)
+	s.testUserID = uuid.New()
+	s.testAuthUUID = uuid.New()
+}
+
+func TestStreamClientSuite(t *testing.T) {
+	suite.Run(t, new(streamClientSuite))
+}
+
+func (s *streamClientSuite) TestSubscribe() {
+	client := NewStreamClient(s.hub, s.testUserID, s.testAuthUUID)
+	defer client.Close()
+
+	missed, ok := client.Subscribe("")
+
+	assert.True(s.T(), ok)
+	assert.Empty(s.T(), missed)
+
+	event := NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{s.testUserID}, "test_payload")
+	s.Require().Nil(s.hub.Publish(event))
+
+	select {
+	case received := <-client.Events():
+		assert.Equal(s.T(), event, received)
+	case <-time.After(time.Second):
+		s.Fail("Event has not been delivered")
+	}
+}
+
+func (s *streamClientSuite) TestSubscribe_Resume() {
+	first := NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{s.testUserID}, "first")
+	second := NewEvent(MessageCreatedEvent, uuid.New(), []uuid.UUID{s.testUserID}, "second")
+
+	s.Require().Nil(s.hub.Publish(first))
+	s.Require().Nil(s.hub.Publish(second))
+
+	client := NewStreamClient(s.hub, s.testUserID, s.testAuthUUID)
+	defer client.Close()
+
+	missed, ok := client.Subscribe(first.ID.String())
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), []*Event{second}, missed)
+}
+
+func (s *streamClientSuite) TestSubscribe_MalformedLastEventID() {
+	client := NewStreamClient(s.hub, s.testUserID, s.testAuthUUID)
+	defer client.Close()
+
+	missed, ok := client.Subscribe("malformed")
+
+	assert.False(s.T(), ok)
+	assert.Empty(s.T(), missed)
+	assert.Equal(s.T(), 1, s.hub.countSubscribers(s.testUserID))
+}
+
+func (s *streamClientSuite) TestCloseSession() {
+	client := NewStreamClient(s.hub, s.testUserID, s.testAuthUUID)
+	client.Subscribe("")
+
+	s.hub.CloseSession(s.testAuthUUID)
+
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second):
+		s.Fail("Stream has not been closed")
+	}
+
+	assert.Equal(s.T(), 0, s.hub.countSubscribers(s.testUserID))
+}
@@ -0,0 +1,108 @@
+package routing
+
+import (
+	"github.com/el-Mike/gochat/controllers"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/gin-gonic/gin"
+)
+
+// DefineAdminRoutes - registers admin routes.
+func DefineAdminRoutes(router *gin.RouterGroup) {
+	handlerCreator, err := control.NewHandlerCreator()
+	if err != nil {
+		panic(err)
+	}
+
+	impersonationController := controllers.NewImpersonationController()
+	policyController := controllers.NewPolicyController()
+	authorizationController := controllers.NewAuthorizationController()
+	auditLogController := controllers.NewAuditLogController()
+
+	readPolicyRules := []*control.AccessRule{
+		{
+			ResourceID: models.POLICY_RESOURCE,
+			Action:     control.ReadAction,
+		},
+	}
+	updatePolicyRules := []*control.AccessRule{
+		{
+			ResourceID: models.POLICY_RESOURCE,
+			Action:     control.UpdateAction,
+		},
+	}
+	readAuditLogRules := []*control.AccessRule{
+		{
+			ResourceID: models.AUDIT_LOG_RESOURCE,
+			Action:     control.ReadAction,
+		},
+	}
+
+	// Authenticated routes
+	router.POST("/impersonate/:userId", handlerCreator.CreateAuthenticated(
+		impersonationController.Impersonate,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.USER_RESOURCE,
+				Action:     control.ImpersonateAction,
+			},
+		},
+	))
+
+	router.GET("/policy", handlerCreator.CreateAuthenticated(
+		policyController.GetPolicy,
+		readPolicyRules,
+	))
+
+	router.GET("/policy/roles", handlerCreator.CreateAuthenticated(
+		policyController.GetRoles,
+		readPolicyRules,
+	))
+	router.GET("/policy/roles/:roleId", handlerCreator.CreateAuthenticated(
+		policyController.GetRole,
+		readPolicyRules,
+	))
+	router.POST("/policy/roles", handlerCreator.CreateAuthenticated(
+		policyController.CreateRole,
+		updatePolicyRules,
+	))
+	router.PUT("/policy/roles/:roleId", handlerCreator.CreateAuthenticated(
+		policyController.UpdateRole,
+		updatePolicyRules,
+	))
+	router.DELETE("/policy/roles/:roleId", handlerCreator.CreateAuthenticated(
+		policyController.DeleteRole,
+		updatePolicyRules,
+	))
+	router.PUT("/policy/roles/:roleId/grants/:resourceId", handlerCreator.CreateAuthenticated(
+		policyController.SetGrants,
+		updatePolicyRules,
+	))
+
+	router.GET("/policy/presets", handlerCreator.CreateAuthenticated(
+		policyController.GetPresets,
+		readPolicyRules,
+	))
+	router.PUT("/policy/presets/:presetName", handlerCreator.CreateAuthenticated(
+		policyController.UpsertPreset,
+		updatePolicyRules,
+	))
+	router.DELETE("/policy/presets/:presetName", handlerCreator.CreateAuthenticated(
+		policyController.DeletePreset,
+		updatePolicyRules,
+	))
+
+	router.POST("/authorization/explain", handlerCreator.CreateAuthenticated(
+		authorizationController.Explain,
+		readPolicyRules,
+	))
+
+	router.GET("/audit-log", handlerCreator.CreateAuthenticated(
+		auditLogController.GetAuditLog,
+		readAuditLogRules,
+	))
+	router.GET("/audit-log/export", handlerCreator.CreateAuthenticatedStream(
+		auditLogController.ExportAuditLog,
+		readAuditLogRules,
+	))
+}
@@ -3,6 +3,7 @@ package routing
 import (
 	"github.com/el-Mike/gochat/controllers"
 	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,14 +15,83 @@ func DefineAuthRoutes(router *gin.RouterGroup) {
 	}
 
 	authController := controllers.NewAuthController()
+	twoFactorController := controllers.NewTwoFactorController()
+	tokenController := controllers.NewPersonalAccessTokenController()
 
 	// Unauthenticated routes
 	router.POST("/signup", handlerCreator.CreateUnauthenticated(authController.SignUp))
 	router.POST("/login", handlerCreator.CreateUnauthenticated(authController.Login))
+	router.POST("/login/2fa", handlerCreator.CreateUnauthenticated(authController.LoginWithTwoFactor))
+	router.GET("/oidc/login", handlerCreator.CreateUnauthenticated(authController.StartOIDCLogin))
+	router.POST("/oidc/callback", handlerCreator.CreateUnauthenticated(authController.FinishOIDCLogin))
+	router.POST("/refresh", handlerCreator.CreateUnauthenticated(authController.Refresh))
+	router.POST("/password/forgot", handlerCreator.CreateUnauthenticated(authController.ForgotPassword))
+	router.POST("/password/reset", handlerCreator.CreateUnauthenticated(authController.ResetPassword))
+	router.GET("/verify", handlerCreator.CreateUnauthenticated(authController.VerifyEmail))
+	router.POST("/verify/resend", handlerCreator.CreateUnauthenticated(authController.ResendVerification))
+
+	// Authenticated routes, available also before enrolling required two-factor authentication
+	router.POST("/logout", handlerCreator.CreateTwoFactorEnrollment(authController.Logout))
+	router.POST("/2fa/enroll", handlerCreator.CreateTwoFactorEnrollment(twoFactorController.Enroll))
+	router.POST("/2fa/confirm", handlerCreator.CreateTwoFactorEnrollment(twoFactorController.Confirm))
 
 	// Authenticated routes
-	router.POST("/logout", handlerCreator.CreateAuthenticated(
-		authController.Logout,
+	router.GET("/sessions", handlerCreator.CreateAuthenticated(
+		authController.GetSessions,
+		[]*control.AccessRule{},
+	))
+	router.GET("/tokens", handlerCreator.CreateAuthenticated(
+		tokenController.GetTokens,
 		[]*control.AccessRule{},
 	))
+
+	// Authenticated routes changing user's account, not available within impersonated sessions
+	router.DELETE("/2fa", handlerCreator.CreateAccountManagement(twoFactorController.Disable))
+	router.POST("/2fa/recovery-codes", handlerCreator.CreateAccountManagement(twoFactorController.RegenerateRecoveryCodes))
+
+	router.POST("/password/change", handlerCreator.CreateAccountManagement(authController.ChangePassword))
+
+	router.DELETE("/sessions", handlerCreator.CreateAccountManagement(authController.RevokeSessions))
+	router.DELETE("/sessions/:sessionId", handlerCreator.CreateAccountManagement(authController.RevokeSession))
+
+	router.POST("/tokens", handlerCreator.CreateAccountManagement(tokenController.CreateToken))
+	router.DELETE("/tokens/:tokenId", handlerCreator.CreateAccountManagement(tokenController.RevokeToken))
+
+	// Managing other users' accounts and sessions
+	router.POST("/users/:id/unlock", handlerCreator.CreateAuthenticated(
+		authController.UnlockAccount,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.USER_RESOURCE,
+				Action:     control.UpdateAction,
+			},
+		},
+	))
+	router.GET("/users/:id/sessions", handlerCreator.CreateAuthenticated(
+		authController.GetSessions,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.USER_RESOURCE,
+				Action:     control.UpdateAction,
+			},
+		},
+	))
+	router.DELETE("/users/:id/sessions", handlerCreator.CreateAuthenticated(
+		authController.RevokeSessions,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.USER_RESOURCE,
+				Action:     control.UpdateAction,
+			},
+		},
+	))
+	router.DELETE("/users/:id/sessions/:sessionId", handlerCreator.CreateAuthenticated(
+		authController.RevokeSession,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.USER_RESOURCE,
+				Action:     control.UpdateAction,
+			},
+		},
+	))
 }
@@ -0,0 +1,105 @@
+package routing
+
+import (
+	"github.com/el-Mike/gochat/controllers"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/gin-gonic/gin"
+)
+
+// DefineConversationRoutes - registers conversation routes.
+func DefineConversationRoutes(router *gin.RouterGroup) {
+	handlerCreator, err := control.NewHandlerCreator()
+	if err != nil {
+		panic(err)
+	}
+
+	conversationController := controllers.NewConversationController()
+
+	// Authenticated routes
+	router.GET("/", handlerCreator.CreateAuthenticated(
+		conversationController.GetConversations,
+		[]*control.AccessRule{
+			{Scope: control.ConversationsReadScope},
+		},
+	))
+	router.POST("/", handlerCreator.CreateAuthenticated(
+		conversationController.CreateConversation,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.CONVERSATION_RESOURCE,
+				Action:     control.CreateAction,
+			},
+		},
+	))
+	router.GET("/:id", handlerCreator.CreateAuthenticated(
+		conversationController.GetConversation,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.ReadAction,
+			},
+		},
+	))
+	router.PUT("/:id", handlerCreator.CreateAuthenticated(
+		conversationController.UpdateConversation,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.UpdateAction,
+			},
+		},
+	))
+	router.DELETE("/:id", handlerCreator.CreateAuthenticated(
+		conversationController.DeleteConversation,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.DeleteAction,
+			},
+		},
+	))
+	router.POST("/:id/participants", handlerCreator.CreateAuthenticated(
+		conversationController.AddParticipants,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.ManageParticipantsAction,
+			},
+		},
+	))
+	router.DELETE("/:id/participants/:userId", handlerCreator.CreateAuthenticated(
+		conversationController.RemoveParticipant,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.ManageParticipantsAction,
+			},
+		},
+	))
+	router.PUT("/:id/participants/:userId/role", handlerCreator.CreateAuthenticated(
+		conversationController.SetParticipantRole,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.ManageRolesAction,
+			},
+		},
+	))
+	router.POST("/:id/owner", handlerCreator.CreateAuthenticated(
+		conversationController.TransferOwnership,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.CONVERSATION_RESOURCE,
+				ResourceProvider: conversationController.GetConversationResource,
+				Action:           control.ManageRolesAction,
+			},
+		},
+	))
+}
@@ -0,0 +1,70 @@
+package routing
+
+import (
+	"github.com/el-Mike/gochat/controllers"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/gin-gonic/gin"
+)
+
+// DefineMessageRoutes - registers message routes, nested under a Conversation.
+func DefineMessageRoutes(router *gin.RouterGroup) {
+	handlerCreator, err := control.NewHandlerCreator()
+	if err != nil {
+		panic(err)
+	}
+
+	messageController := controllers.NewMessageController()
+
+	// Authenticated routes
+	router.GET("/", handlerCreator.CreateAuthenticated(
+		messageController.GetMessages,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.MESSAGE_RESOURCE,
+				ResourceProvider: messageController.GetNewMessageResource,
+				Action:           control.ReadAction,
+			},
+		},
+	))
+	router.POST("/", handlerCreator.CreateAuthenticated(
+		messageController.SendMessage,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.MESSAGE_RESOURCE,
+				ResourceProvider: messageController.GetNewMessageResource,
+				Action:           control.CreateAction,
+			},
+		},
+	))
+	router.PUT("/:messageId", handlerCreator.CreateAuthenticated(
+		messageController.EditMessage,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.MESSAGE_RESOURCE,
+				ResourceProvider: messageController.GetMessageResource,
+				Action:           control.ReadAction,
+			},
+			{
+				ResourceID:       models.MESSAGE_RESOURCE,
+				ResourceProvider: messageController.GetMessageResource,
+				Action:           control.UpdateOwnAction,
+			},
+		},
+	))
+	router.DELETE("/:messageId", handlerCreator.CreateAuthenticated(
+		messageController.DeleteMessage,
+		[]*control.AccessRule{
+			{
+				ResourceID:       models.MESSAGE_RESOURCE,
+				ResourceProvider: messageController.GetMessageResource,
+				Action:           control.ReadAction,
+			},
+			{
+				ResourceID:       models.MESSAGE_RESOURCE,
+				ResourceProvider: messageController.GetMessageResource,
+				Action:           control.DeleteAction,
+			},
+		},
+	))
+}
@@ -0,0 +1,32 @@
+package routing
+
+import (
+	"github.com/el-Mike/gochat/controllers"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/gin-gonic/gin"
+)
+
+// DefineRealtimeRoutes - registers real-time routes.
+func DefineRealtimeRoutes(router *gin.RouterGroup) {
+	handlerCreator, err := control.NewHandlerCreator()
+	if err != nil {
+		panic(err)
+	}
+
+	realtimeController := controllers.NewRealtimeController()
+
+	// Authenticated routes
+	router.GET("/ws", handlerCreator.CreateAuthenticatedStream(
+		realtimeController.Connect,
+		[]*control.AccessRule{
+			{Scope: control.MessagesReadScope},
+		},
+	))
+
+	router.GET("/events", handlerCreator.CreateAuthenticatedStream(
+		realtimeController.Stream,
+		[]*control.AccessRule{
+			{Scope: control.MessagesReadScope},
+		},
+	))
+}
@@ -20,6 +20,20 @@ func InitRouting() {
 
 	DefineAuthRoutes(v1.Group("/auth"))
 	DefineUserRoutes(v1.Group("/users"))
+	DefineConversationRoutes(v1.Group("/conversations"))
+	DefineMessageRoutes(v1.Group("/conversations/:id/messages"))
+	DefineWorkspaceRoutes(v1.Group("/workspaces"))
+
+	// Workspace-bound routes can be made in a Workspace other than the one selected
+	// for the session, by prefixing them with its ID.
+	DefineUserRoutes(v1.Group("/workspaces/:workspaceId/users"))
+	DefineConversationRoutes(v1.Group("/workspaces/:workspaceId/conversations"))
+	DefineMessageRoutes(v1.Group("/workspaces/:workspaceId/conversations/:id/messages"))
+
+	DefineAdminRoutes(v1.Group("/admin"))
+	DefineRealtimeRoutes(v1)
+
+	DefineWellKnownRoutes(router.Group("/.well-known"))
 
 	if err := router.Run(); err != nil {
 		log.Fatal(err)
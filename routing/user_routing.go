@@ -19,7 +19,9 @@ func DefineUserRoutes(router *gin.RouterGroup) {
 	// Authenticated routes
 	router.GET("/me", handlerCreator.CreateAuthenticated(
 		userController.GetMe,
-		[]*control.AccessRule{},
+		[]*control.AccessRule{
+			{Scope: control.UsersReadScope},
+		},
 	))
 
 	router.GET("/", handlerCreator.CreateAuthenticated(
@@ -0,0 +1,20 @@
+package routing
+
+import (
+	"github.com/el-Mike/gochat/controllers"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/gin-gonic/gin"
+)
+
+// DefineWellKnownRoutes - registers well-known routes (RFC 8615).
+func DefineWellKnownRoutes(router *gin.RouterGroup) {
+	handlerCreator, err := control.NewHandlerCreator()
+	if err != nil {
+		panic(err)
+	}
+
+	jwksController := controllers.NewJWKSController()
+
+	// Unauthenticated routes
+	router.GET("/jwks.json", handlerCreator.CreateUnauthenticated(jwksController.GetJWKS))
+}
@@ -0,0 +1,80 @@
+package routing
+
+import (
+	"github.com/el-Mike/gochat/controllers"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/gin-gonic/gin"
+)
+
+// DefineWorkspaceRoutes - registers workspace routes.
+func DefineWorkspaceRoutes(router *gin.RouterGroup) {
+	handlerCreator, err := control.NewHandlerCreator()
+	if err != nil {
+		panic(err)
+	}
+
+	workspaceController := controllers.NewWorkspaceController()
+
+	// Authenticated routes
+	router.GET("/", handlerCreator.CreateWorkspaceIndependent(
+		workspaceController.GetWorkspaces,
+		[]*control.AccessRule{
+			{Scope: control.WorkspacesReadScope},
+		},
+	))
+	router.POST("/", handlerCreator.CreateAuthenticated(
+		workspaceController.CreateWorkspace,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.WORKSPACE_RESOURCE,
+				Action:     control.CreateAction,
+			},
+		},
+	))
+	router.GET("/:workspaceId", handlerCreator.CreateAuthenticated(
+		workspaceController.GetWorkspace,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.WORKSPACE_RESOURCE,
+				Action:     control.ReadAction,
+			},
+		},
+	))
+	router.PUT("/:workspaceId", handlerCreator.CreateAuthenticated(
+		workspaceController.UpdateWorkspace,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.WORKSPACE_RESOURCE,
+				Action:     control.UpdateAction,
+			},
+		},
+	))
+	router.POST("/:workspaceId/select", handlerCreator.CreateAuthenticated(
+		workspaceController.SelectWorkspace,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.WORKSPACE_RESOURCE,
+				Action:     control.ReadAction,
+			},
+		},
+	))
+	router.PUT("/:workspaceId/members/:userId", handlerCreator.CreateAuthenticated(
+		workspaceController.SetMember,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.WORKSPACE_RESOURCE,
+				Action:     control.ManageMembersAction,
+			},
+		},
+	))
+	router.DELETE("/:workspaceId/members/:userId", handlerCreator.CreateAuthenticated(
+		workspaceController.RemoveMember,
+		[]*control.AccessRule{
+			{
+				ResourceID: models.WORKSPACE_RESOURCE,
+				Action:     control.ManageMembersAction,
+			},
+		},
+	))
+}
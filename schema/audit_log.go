@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+)
+
+// AuditLogQuery - query params of audit log list and export requests.
+type AuditLogQuery struct {
+	PageQuery
+	ActorID    string    `form:"actorId" binding:"omitempty,uuid"`
+	ActorEmail string    `form:"actorEmail"`
+	Action     string    `form:"action"`
+	TargetType string    `form:"targetType"`
+	TargetID   string    `form:"targetId"`
+	Result     string    `form:"result" binding:"omitempty,oneof=success failure denied"`
+	IP         string    `form:"ip"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// AuditLogEntryResponse - response for AuditLog entity.
+type AuditLogEntryResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Timestamp      time.Time  `json:"timestamp"`
+	ActorID        *uuid.UUID `json:"actorId"`
+	ActorEmail     string     `json:"actorEmail,omitempty"`
+	ImpersonatorID *uuid.UUID `json:"impersonatorId,omitempty"`
+	Action         string     `json:"action"`
+	TargetType     string     `json:"targetType,omitempty"`
+	TargetID       string     `json:"targetId,omitempty"`
+	Result         string     `json:"result"`
+	Details        string     `json:"details,omitempty"`
+	IP             string     `json:"ip"`
+	UserAgent      string     `json:"userAgent"`
+}
+
+// FromModel - creates AuditLogEntryResponse from AuditLogModel.
+func (entry *AuditLogEntryResponse) FromModel(model *models.AuditLogModel) error {
+	entry.ID = model.ID
+	entry.Timestamp = model.CreatedAt
+
+	// Unknown actor is returned as null, rather than as nil UUID.
+	if model.ActorID != uuid.Nil {
+		actorID := model.ActorID
+		entry.ActorID = &actorID
+	}
+
+	entry.ActorEmail = model.ActorEmail
+	entry.ImpersonatorID = model.ImpersonatorID
+	entry.Action = model.Action
+	entry.TargetType = model.TargetType
+	entry.TargetID = model.TargetID
+	entry.Result = model.Result
+	entry.Details = model.Details
+	entry.IP = model.IP
+	entry.UserAgent = model.UserAgent
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/google/uuid"
+)
+
+// ExplainAuthorizationPayload - schema for explaining authorization decision. Subject is given
+// either by user ID or by role - in the latter case, its role within the Workspace can be given
+// as well.
+type ExplainAuthorizationPayload struct {
+	UserID        *uuid.UUID `json:"userId"`
+	Role          string     `json:"role"`
+	WorkspaceID   *uuid.UUID `json:"workspaceId"`
+	WorkspaceRole string     `json:"workspaceRole"`
+	Resource      string     `json:"resource" binding:"required"`
+	ResourceID    *uuid.UUID `json:"resourceId"`
+	Action        string     `json:"action" binding:"required"`
+}
+
+// AuthorizationSubjectResponse - schema for the subject of explained authorization decision.
+type AuthorizationSubjectResponse struct {
+	UserID        *uuid.UUID `json:"userId"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	WorkspaceID   uuid.UUID  `json:"workspaceId"`
+	WorkspaceRole string     `json:"workspaceRole"`
+}
+
+// AuthorizationExplanationResponse - schema for authorization decision explanation response.
+type AuthorizationExplanationResponse struct {
+	Subject    *AuthorizationSubjectResponse `json:"subject"`
+	Resource   string                        `json:"resource"`
+	ResourceID *uuid.UUID                    `json:"resourceId"`
+	Action     string                        `json:"action"`
+
+	*control.AuthorizationExplanation
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+)
+
+// ConversationPayload - schema for creating a Conversation.
+type ConversationPayload struct {
+	Name         string      `json:"name" binding:"max=255"`
+	Participants []uuid.UUID `json:"participants" binding:"required,min=1"`
+}
+
+// ParticipantsPayload - schema for adding participants to a Conversation.
+type ParticipantsPayload struct {
+	Participants []uuid.UUID `json:"participants" binding:"required,min=1"`
+}
+
+// UpdateConversationPayload - schema for updating a Conversation.
+type UpdateConversationPayload struct {
+	Name string `json:"name" binding:"max=255"`
+}
+
+// ParticipantRolePayload - schema for changing participant's role within a Conversation.
+type ParticipantRolePayload struct {
+	Role string `json:"role" binding:"required,oneof=moderator member"`
+}
+
+// TransferOwnershipPayload - schema for transferring Conversation's ownership.
+type TransferOwnershipPayload struct {
+	UserID uuid.UUID `json:"userId" binding:"required"`
+}
+
+// ConversationMemberResponse - response for participant of a Conversation, with their role.
+type ConversationMemberResponse struct {
+	UserID uuid.UUID `json:"userId"`
+	Role   string    `json:"role"`
+}
+
+// ConversationResponse - response for Conversation entity.
+type ConversationResponse struct {
+	BaseEntityResponse
+	Name         string                       `json:"name"`
+	WorkspaceID  uuid.UUID                    `json:"workspaceId"`
+	CreatedBy    uuid.UUID                    `json:"createdBy"`
+	Participants []uuid.UUID                  `json:"participants"`
+	Members      []ConversationMemberResponse `json:"members"`
+}
+
+// FromModel - creates ConversationResponse from ConversationModel.
+func (conversation *ConversationResponse) FromModel(model *models.ConversationModel) error {
+	conversation.ID = model.ID
+	conversation.CreatedAt = model.CreatedAt
+	conversation.UpdatedAt = model.UpdatedAt
+
+	conversation.Name = model.Name
+	conversation.WorkspaceID = model.WorkspaceID
+	conversation.CreatedBy = model.CreatedBy
+	conversation.Participants = model.GetParticipantIDs()
+	conversation.Members = []ConversationMemberResponse{}
+
+	for _, participant := range model.Participants {
+		conversation.Members = append(conversation.Members, ConversationMemberResponse{
+			UserID: participant.UserID,
+			Role:   participant.Role,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package schema
+
+// VerifyEmailQuery - query params of email verification request.
+type VerifyEmailQuery struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// ResendVerificationPayload - schema for verification email resend request payload.
+type ResendVerificationPayload struct {
+	Email string `json:"email" binding:"required,email"`
+}
@@ -0,0 +1,15 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonationResponse - schema for started impersonation response.
+type ImpersonationResponse struct {
+	UserResponse
+	Token     string    `json:"token"`
+	SessionID uuid.UUID `json:"sessionId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -6,8 +6,26 @@ type LoginCredentials struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse - schema for login response.
+// LoginResponse - schema for login response. With cookie sessions, tokens are set
+// as cookies instead, and CSRF token is returned.
 type LoginResponse struct {
 	UserResponse
-	Token string `json:"token"`
+	SessionTokensResponse
+}
+
+// RefreshPayload - schema for token refresh payload.
+type RefreshPayload struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// RefreshResponse - schema for token refresh response.
+type RefreshResponse struct {
+	SessionTokensResponse
+}
+
+// SessionTokensResponse - schema for session's tokens.
+type SessionTokensResponse struct {
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	CSRFToken    string `json:"csrfToken,omitempty"`
 }
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+)
+
+// MessagePayload - schema for sending or editing a Message.
+type MessagePayload struct {
+	Body string `json:"body" binding:"required,max=4096"`
+}
+
+// MessageResponse - response for Message entity.
+type MessageResponse struct {
+	BaseEntityResponse
+	ConversationID uuid.UUID `json:"conversationId"`
+	AuthorID       uuid.UUID `json:"authorId"`
+	Body           string    `json:"body"`
+}
+
+// FromModel - creates MessageResponse from MessageModel.
+func (message *MessageResponse) FromModel(model *models.MessageModel) error {
+	message.ID = model.ID
+	message.CreatedAt = model.CreatedAt
+	message.UpdatedAt = model.UpdatedAt
+
+	message.ConversationID = model.ConversationID
+	message.AuthorID = model.CreatedBy
+	message.Body = model.Body
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package schema
+
+// OIDCLoginResponse - schema for OIDC login start response.
+type OIDCLoginResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+}
+
+// OIDCCallbackPayload - schema for OIDC login callback payload, carrying the params
+// identity provider redirected the user back with.
+type OIDCCallbackPayload struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"github.com/el-Mike/gochat/persist"
+)
+
+// PageQuery - query params of paginated list requests.
+type PageQuery struct {
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor    string `form:"cursor"`
+	Direction string `form:"direction" binding:"omitempty,oneof=asc desc"`
+}
+
+// ToPageRequest - creates PageRequest from PageQuery. Given direction
+// is used when none has been passed.
+func (query *PageQuery) ToPageRequest(defaultDirection persist.SortDirection) *persist.PageRequest {
+	direction := persist.SortDirection(query.Direction)
+
+	if direction == "" {
+		direction = defaultDirection
+	}
+
+	return persist.NewPageRequest(query.Limit, query.Cursor, direction)
+}
+
+// PageResponse - envelope for paginated lists. NextCursor is null
+// when there are no more items.
+type PageResponse struct {
+	Items      interface{} `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
+}
+
+// NewPageResponse - returns new PageResponse instance.
+func NewPageResponse(items interface{}, nextCursor string) *PageResponse {
+	page := &PageResponse{
+		Items: items,
+	}
+
+	if nextCursor != "" {
+		page.NextCursor = &nextCursor
+	}
+
+	return page
+}
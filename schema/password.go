@@ -0,0 +1,40 @@
+package schema
+
+// ForgotPasswordPayload - schema for password reset request payload.
+type ForgotPasswordPayload struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordPayload - schema for password reset payload.
+type ResetPasswordPayload struct {
+	Token             string `json:"token" binding:"required"`
+	Password          string `json:"password" binding:"required"`
+	ConfirmedPassword string `json:"confirmedPassword" binding:"required"`
+}
+
+// ValidateResetPasswordConfirmation - returns true when Password and ConfirmedPassword
+// are equal, false otherwise.
+func ValidateResetPasswordConfirmation(resetPayload *ResetPasswordPayload) bool {
+	if resetPayload == nil {
+		return false
+	}
+
+	return passwordsMatch(resetPayload.Password, resetPayload.ConfirmedPassword)
+}
+
+// ChangePasswordPayload - schema for password change payload.
+type ChangePasswordPayload struct {
+	CurrentPassword   string `json:"currentPassword" binding:"required"`
+	Password          string `json:"password" binding:"required"`
+	ConfirmedPassword string `json:"confirmedPassword" binding:"required"`
+}
+
+// ValidateChangePasswordConfirmation - returns true when Password and ConfirmedPassword
+// are equal, false otherwise.
+func ValidateChangePasswordConfirmation(changePayload *ChangePasswordPayload) bool {
+	if changePayload == nil {
+		return false
+	}
+
+	return passwordsMatch(changePayload.Password, changePayload.ConfirmedPassword)
+}
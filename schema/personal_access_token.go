@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/el-Mike/gochat/models"
+)
+
+// CreatePersonalAccessTokenPayload - schema for personal access token creation payload.
+// Token does not expire if ExpiresInDays is not set.
+type CreatePersonalAccessTokenPayload struct {
+	Name          string   `json:"name" binding:"required,max=255"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expiresInDays" binding:"omitempty,min=1,max=3650"`
+}
+
+// PersonalAccessTokenResponse - response for PersonalAccessToken entity.
+type PersonalAccessTokenResponse struct {
+	BaseEntityResponse
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	LastUsedIP string     `json:"lastUsedIp"`
+}
+
+// FromModel - creates PersonalAccessTokenResponse from PersonalAccessTokenModel.
+func (token *PersonalAccessTokenResponse) FromModel(model *models.PersonalAccessTokenModel) error {
+	token.ID = model.ID
+	token.CreatedAt = model.CreatedAt
+	token.UpdatedAt = model.UpdatedAt
+
+	token.Name = model.Name
+	token.Prefix = model.Prefix
+	token.Scopes = model.GetScopes()
+	token.ExpiresAt = model.ExpiresAt
+	token.LastUsedAt = model.LastUsedAt
+	token.LastUsedIP = model.LastUsedIP
+
+	return nil
+}
+
+// CreatedPersonalAccessTokenResponse - response for newly created personal access token.
+// It's the only response containing the token itself.
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
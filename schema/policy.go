@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"sort"
+
+	"github.com/el-Mike/restrict"
+)
+
+// PolicyResponse - schema for RBAC policy response.
+type PolicyResponse struct {
+	Version           int                        `json:"version"`
+	PermissionPresets restrict.PermissionPresets `json:"permissionPresets"`
+	Roles             []*RoleResponse            `json:"roles"`
+}
+
+// FromDefinition - fills PolicyResponse with given policy definition. Roles are sorted by ID.
+func (pr *PolicyResponse) FromDefinition(policy *restrict.PolicyDefinition, version int) {
+	pr.Version = version
+	pr.PermissionPresets = policy.PermissionPresets
+	pr.Roles = []*RoleResponse{}
+
+	for _, role := range policy.Roles {
+		roleResponse := &RoleResponse{}
+		roleResponse.FromRole(role)
+
+		pr.Roles = append(pr.Roles, roleResponse)
+	}
+
+	sort.Slice(pr.Roles, func(i, j int) bool {
+		return pr.Roles[i].ID < pr.Roles[j].ID
+	})
+}
+
+// RoleResponse - schema for RBAC role response.
+type RoleResponse struct {
+	ID          string             `json:"id"`
+	Description string             `json:"description"`
+	Grants      restrict.GrantsMap `json:"grants"`
+	Parents     []string           `json:"parents"`
+}
+
+// FromRole - fills RoleResponse with given role.
+func (rr *RoleResponse) FromRole(role *restrict.Role) {
+	rr.ID = role.ID
+	rr.Description = role.Description
+	rr.Grants = role.Grants
+	rr.Parents = role.Parents
+}
+
+// CreateRolePayload - schema for creating RBAC role.
+type CreateRolePayload struct {
+	ID          string   `json:"id" binding:"required"`
+	Description string   `json:"description"`
+	Parents     []string `json:"parents"`
+}
+
+// UpdateRolePayload - schema for updating RBAC role.
+type UpdateRolePayload struct {
+	Description string   `json:"description"`
+	Parents     []string `json:"parents"`
+}
+
+// GrantsPayload - schema for setting role's permissions for a resource.
+type GrantsPayload struct {
+	Permissions restrict.Permissions `json:"permissions"`
+}
+
+// PermissionPresetPayload - schema for creating or updating permission preset.
+type PermissionPresetPayload struct {
+	Action     string              `json:"action"`
+	Conditions restrict.Conditions `json:"conditions"`
+}
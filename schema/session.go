@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/google/uuid"
+)
+
+// SessionResponse - response for user's Session.
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+
+	// ImpersonatedBy - email of the admin impersonating the user within the session.
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+}
+
+// FromModel - creates SessionResponse from Session.
+func (session *SessionResponse) FromModel(model *auth.Session) error {
+	session.ID = model.ID
+	session.IP = model.IP
+	session.UserAgent = model.UserAgent
+	session.CreatedAt = model.CreatedAt
+	session.LastSeenAt = model.LastSeenAt
+	session.ExpiresAt = model.ExpiresAt
+
+	if model.Actor != nil {
+		session.ImpersonatedBy = model.Actor.Email
+	}
+
+	return nil
+}
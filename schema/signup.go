@@ -3,8 +3,8 @@ package schema
 // SignupPayload - schema for signup payload
 type SignupPayload struct {
 	Email             string `json:"email" binding:"required,email"`
-	Password          string `json:"password" binding:"required,min=8,max=32"`
-	ConfirmedPassword string `json:"confirmedPassword" binding:"required,min=8,max=32"`
+	Password          string `json:"password" binding:"required"`
+	ConfirmedPassword string `json:"confirmedPassword" binding:"required"`
 	FirstName         string `json:"firstName" binding:"required,max=255"`
 	LastName          string `json:"lastName" binding:"required,max=255"`
 }
@@ -12,11 +12,18 @@ type SignupPayload struct {
 // ValidatePasswordConfirmation - returns true when Password and ConfirmedPassword are equal,
 // false otherwise.
 func ValidatePasswordConfirmation(signupPayload *SignupPayload) bool {
-	if signupPayload == nil ||
-		signupPayload.Password == "" ||
-		signupPayload.ConfirmedPassword == "" {
+	if signupPayload == nil {
 		return false
 	}
 
-	return signupPayload.Password == signupPayload.ConfirmedPassword
+	return passwordsMatch(signupPayload.Password, signupPayload.ConfirmedPassword)
+}
+
+// passwordsMatch - returns true when both passwords are non-empty and equal.
+func passwordsMatch(password, confirmedPassword string) bool {
+	if password == "" || confirmedPassword == "" {
+		return false
+	}
+
+	return password == confirmedPassword
 }
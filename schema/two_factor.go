@@ -0,0 +1,31 @@
+package schema
+
+// TwoFactorChallengeResponse - schema for login response of users with two-factor
+// authentication enabled.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken"`
+	ExpiresIn         int    `json:"expiresIn"`
+}
+
+// TwoFactorLoginPayload - schema for second step of two-factor login payload.
+type TwoFactorLoginPayload struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorCodePayload - schema for payloads confirmed with TOTP or recovery code.
+type TwoFactorCodePayload struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TOTPEnrollmentResponse - schema for TOTP enrollment response.
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// RecoveryCodesResponse - schema for recovery codes response.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
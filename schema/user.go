@@ -4,12 +4,23 @@ import (
 	"github.com/el-Mike/gochat/models"
 )
 
+// UsersQuery - query params of Users list request.
+type UsersQuery struct {
+	PageQuery
+	Role  string `form:"role"`
+	Email string `form:"email"`
+	Name  string `form:"name"`
+}
+
 // UserResponse - response for User entity.
 type UserResponse struct {
 	BaseEntityResponse
 	Email     string `json:"email"`
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
+
+	EmailVerified    bool `json:"emailVerified"`
+	TwoFactorEnabled bool `json:"twoFactorEnabled"`
 }
 
 // FromModel - creates UserResponse from UserModel.
@@ -21,6 +32,8 @@ func (user *UserResponse) FromModel(model *models.UserModel) error {
 	user.Email = model.Email
 	user.FirstName = model.FirstName
 	user.LastName = model.LastName
+	user.EmailVerified = model.EmailVerified
+	user.TwoFactorEnabled = model.TOTPEnabled
 
 	return nil
 }
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+)
+
+// WorkspacePayload - schema for creating a Workspace.
+type WorkspacePayload struct {
+	Name string `json:"name" binding:"required,max=255"`
+	Slug string `json:"slug" binding:"required,max=63"`
+}
+
+// UpdateWorkspacePayload - schema for updating a Workspace.
+type UpdateWorkspacePayload struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// WorkspaceMemberPayload - schema for adding a member to a Workspace, or changing their role.
+type WorkspaceMemberPayload struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// WorkspaceMemberResponse - response for member of a Workspace, with their role.
+type WorkspaceMemberResponse struct {
+	UserID uuid.UUID `json:"userId"`
+	Role   string    `json:"role"`
+}
+
+// FromModel - creates WorkspaceMemberResponse from WorkspaceMemberModel.
+func (member *WorkspaceMemberResponse) FromModel(model *models.WorkspaceMemberModel) error {
+	member.UserID = model.UserID
+	member.Role = model.Role
+
+	return nil
+}
+
+// WorkspaceResponse - response for Workspace entity.
+type WorkspaceResponse struct {
+	BaseEntityResponse
+	Name    string                    `json:"name"`
+	Slug    string                    `json:"slug"`
+	Members []WorkspaceMemberResponse `json:"members"`
+}
+
+// FromModel - creates WorkspaceResponse from WorkspaceModel.
+func (workspace *WorkspaceResponse) FromModel(model *models.WorkspaceModel) error {
+	workspace.ID = model.ID
+	workspace.CreatedAt = model.CreatedAt
+	workspace.UpdatedAt = model.UpdatedAt
+
+	workspace.Name = model.Name
+	workspace.Slug = model.Slug
+	workspace.Members = []WorkspaceMemberResponse{}
+
+	for _, memberModel := range model.Members {
+		member := WorkspaceMemberResponse{}
+
+		if err := member.FromModel(memberModel); err != nil {
+			return err
+		}
+
+		workspace.Members = append(workspace.Members, member)
+	}
+
+	return nil
+}
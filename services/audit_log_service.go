@@ -0,0 +1,143 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+// AuditLogFilter - criteria audit log entries can be filtered by. Empty criteria are omitted.
+type AuditLogFilter struct {
+	ActorID      *uuid.UUID
+	ActorEmail   string
+	ActionPrefix string
+	TargetType   string
+	TargetID     string
+	Result       string
+	IP           string
+	From         *time.Time
+	To           *time.Time
+}
+
+// AuditLogService - struct for handling audit log related logic.
+type AuditLogService struct {
+	broker persist.DBBroker
+}
+
+// NewAuditLogService - AuditLogService constructor func.
+func NewAuditLogService() *AuditLogService {
+	return &AuditLogService{
+		broker: persist.GormBroker,
+	}
+}
+
+// GetEntries - returns single page of audit log entries matching given filter, together with
+// the cursor of the next page.
+func (as *AuditLogService) GetEntries(
+	filter *AuditLogFilter,
+	page *persist.PageRequest,
+) ([]*models.AuditLogModel, string, error) {
+	var entries []*models.AuditLogModel
+
+	query, args := filter.toQuery()
+
+	res := as.broker.FindPage(&entries, page, query, args...)
+	if err := res.Err(); err != nil {
+		return nil, "", err
+	}
+
+	return entries, res.NextCursor(), nil
+}
+
+// ExportEntries - passes all the audit log entries matching given filter to given function,
+// from the oldest one. Entries are loaded page by page, so the log is never loaded at once.
+// Export stops at the first error returned by the function.
+func (as *AuditLogService) ExportEntries(filter *AuditLogFilter, write func(entry *models.AuditLogModel) error) error {
+	page := persist.NewPageRequest(persist.MaxPageLimit, "", persist.SortAscending)
+
+	for {
+		entries, nextCursor, err := as.GetEntries(filter, page)
+
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := write(entry); err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+
+		page.Cursor = nextCursor
+	}
+}
+
+// toQuery - returns query (and its arguments) matching the filter,
+// or nil query if there are no criteria.
+func (af *AuditLogFilter) toQuery() (interface{}, []interface{}) {
+	if af == nil {
+		return nil, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+
+	if af.ActorID != nil {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, *af.ActorID)
+	}
+
+	if af.ActorEmail != "" {
+		conditions = append(conditions, "LOWER(actor_email) = ?")
+		args = append(args, strings.ToLower(af.ActorEmail))
+	}
+
+	// Prefix lets whole groups of actions (e.g. "auth." or "policy.role.") be filtered.
+	if af.ActionPrefix != "" {
+		conditions = append(conditions, "LOWER(action) LIKE ?")
+		args = append(args, likePrefix(af.ActionPrefix))
+	}
+
+	if af.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, af.TargetType)
+	}
+
+	if af.TargetID != "" {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, af.TargetID)
+	}
+
+	if af.Result != "" {
+		conditions = append(conditions, "result = ?")
+		args = append(args, af.Result)
+	}
+
+	if af.IP != "" {
+		conditions = append(conditions, "ip = ?")
+		args = append(args, af.IP)
+	}
+
+	if af.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *af.From)
+	}
+
+	if af.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *af.To)
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
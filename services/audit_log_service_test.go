@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditLogServiceSuite struct {
+	suite.Suite
+	auditLogService *AuditLogService
+}
+
+func (s *auditLogServiceSuite) SetupTest() {
+	s.auditLogService = &AuditLogService{
+		broker: mocks.NewGormMock(),
+	}
+}
+
+func TestAuditLogServiceSuite(t *testing.T) {
+	suite.Run(t, new(auditLogServiceSuite))
+}
+
+// fillEntries - returns mock's Run function, populating entries slice with given number of entries.
+func fillEntries(count int) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		if entries, ok := args.Get(0).(*[]*models.AuditLogModel); ok {
+			for i := 0; i < count; i++ {
+				*entries = append(*entries, &models.AuditLogModel{})
+			}
+		}
+	}
+}
+
+// pageResponse - returns DBResponse with given next page cursor.
+func pageResponse(nextCursor string) *persist.DBResponse {
+	res := mocks.GetDefaultDBResponse()
+	res.SetNextCursor(nextCursor)
+
+	return res
+}
+
+func (s *auditLogServiceSuite) TestNewAuditLogService() {
+	auditLogService := NewAuditLogService()
+
+	assert.NotNil(s.T(), auditLogService)
+}
+
+func (s *auditLogServiceSuite) TestGetEntries() {
+	auditLogService := s.auditLogService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Run(fillEntries(2)).Return(pageResponse("next_cursor"))
+
+	auditLogService.broker = gormMock
+
+	actorID := uuid.New()
+	from := time.Now().Add(-time.Hour)
+
+	entries, nextCursor, err := auditLogService.GetEntries(&AuditLogFilter{
+		ActorID:      &actorID,
+		ActionPrefix: "auth.",
+		Result:       models.AuditFailureResult,
+		From:         &from,
+	}, persist.NewPageRequest(0, "", persist.SortDescending))
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), entries, 2)
+	assert.Equal(s.T(), "next_cursor", nextCursor)
+
+	gormMock.AssertCalled(
+		s.T(),
+		"FindPage",
+		mock.Anything,
+		mock.Anything,
+		"actor_id = ? AND LOWER(action) LIKE ? AND result = ? AND created_at >= ?",
+		[]interface{}{actorID, "auth.%", models.AuditFailureResult, from},
+	)
+}
+
+func (s *auditLogServiceSuite) TestGetEntries_NoFilter() {
+	auditLogService := s.auditLogService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	auditLogService.broker = gormMock
+
+	_, _, err := auditLogService.GetEntries(nil, persist.NewPageRequest(0, "", persist.SortDescending))
+
+	assert.Nil(s.T(), err)
+
+	gormMock.AssertCalled(s.T(), "FindPage", mock.Anything, mock.Anything, nil, []interface{}(nil))
+}
+
+func (s *auditLogServiceSuite) TestExportEntries() {
+	auditLogService := s.auditLogService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		mock.MatchedBy(func(page *persist.PageRequest) bool { return page.Cursor == "" }),
+		mock.Anything,
+		mock.Anything,
+	).Run(fillEntries(persist.MaxPageLimit)).Return(pageResponse("next_cursor")).Once()
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		mock.MatchedBy(func(page *persist.PageRequest) bool { return page.Cursor == "next_cursor" }),
+		mock.Anything,
+		mock.Anything,
+	).Run(fillEntries(1)).Return(mocks.GetDefaultDBResponse()).Once()
+
+	auditLogService.broker = gormMock
+
+	exported := 0
+
+	err := auditLogService.ExportEntries(nil, func(entry *models.AuditLogModel) error {
+		exported++
+
+		return nil
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), persist.MaxPageLimit+1, exported)
+
+	gormMock.AssertNumberOfCalls(s.T(), "FindPage", 2)
+}
+
+func (s *auditLogServiceSuite) TestExportEntries_WriteError() {
+	auditLogService := s.auditLogService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Run(fillEntries(3)).Return(pageResponse("next_cursor"))
+
+	auditLogService.broker = gormMock
+
+	writeErr := errors.New("connection closed")
+
+	err := auditLogService.ExportEntries(nil, func(entry *models.AuditLogModel) error {
+		return writeErr
+	})
+
+	assert.Equal(s.T(), writeErr, err)
+
+	gormMock.AssertNumberOfCalls(s.T(), "FindPage", 1)
+}
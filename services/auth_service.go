@@ -2,56 +2,265 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"os"
+	"time"
 
 	"github.com/el-Mike/gochat/auth"
 	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mail"
 	"github.com/el-Mike/gochat/models"
 	"github.com/el-Mike/gochat/persist"
 	"github.com/el-Mike/gochat/schema"
+	"github.com/google/uuid"
 )
 
+// ErrCurrentPasswordIncorrect - returned when current password given to change it is incorrect.
+var ErrCurrentPasswordIncorrect = errors.New("Current password is incorrect.")
+
 type userService interface {
+	GetUserByID(id uuid.UUID) (*models.UserModel, error)
+	GetUserByEmail(email string) (*models.UserModel, error)
 	SaveUser(*models.UserModel) error
 }
 
 type authManager interface {
-	Login(user *models.UserModel, apiSecret string) (string, error)
+	Login(user *models.UserModel, client *auth.ClientInfo, apiSecret string) (*auth.TokenPair, error)
+	VerifyRefreshToken(refreshToken string) (*auth.RefreshSession, error)
+	Refresh(session *auth.RefreshSession, user *models.UserModel, apiSecret string) (*auth.TokenPair, error)
 	Logout(authUUID string) error
+	SelectWorkspace(authUUID uuid.UUID, workspaceID uuid.UUID) error
+	LogoutAll(userID uuid.UUID) ([]uuid.UUID, error)
+	GetSession(authUUID uuid.UUID) (*auth.Session, error)
+	GetSessions(userID uuid.UUID) ([]*auth.Session, error)
+	CreatePasswordResetToken(userID uuid.UUID) (string, error)
+	VerifyPasswordResetToken(token string) (uuid.UUID, error)
+	ConsumePasswordResetToken(token string) (uuid.UUID, error)
+	CreateEmailVerificationToken(userID uuid.UUID) (string, error)
+	ConsumeEmailVerificationToken(token string) (uuid.UUID, error)
+	CreateLoginChallenge(userID uuid.UUID) (string, error)
+	ConsumeLoginChallenge(token string) (uuid.UUID, error)
+	CheckLoginAllowed(email, ip string) error
+	RegisterLoginFailure(email, ip string) error
+	ResetLoginFailures(email string) error
 	HashAndSalt(password []byte) (string, error)
+	ComparePasswords(hashedPassword string, plainPassword []byte) error
+	PasswordNeedsRehash(hashedPassword string) bool
+}
+
+type passwordValidator interface {
+	Validate(password string, user *models.UserModel) error
+}
+
+type twoFactorVerifier interface {
+	VerifyCode(user *models.UserModel, code string) error
 }
 
 // AuthService - struct for handling auth related logic.
 type AuthService struct {
-	broker      persist.DBBroker
-	userService userService
-	authManager authManager
+	broker           persist.DBBroker
+	userService      userService
+	authManager      authManager
+	twoFactorService twoFactorVerifier
+	passwordPolicy   passwordValidator
+	mailer           mail.Mailer
+
+	credentialVerifiers []CredentialVerifier
 }
 
 // NewAuthService - AuthService constructor func.
 func NewAuthService() *AuthService {
-	return &AuthService{
-		broker:      persist.GormBroker,
-		userService: NewUserService(),
-		authManager: auth.NewAuthManager(),
+	authService := &AuthService{
+		broker:           persist.GormBroker,
+		userService:      NewUserService(),
+		authManager:      auth.NewAuthManager(),
+		twoFactorService: NewTwoFactorService(),
+		passwordPolicy:   auth.DefaultPasswordPolicy,
+		mailer:           mail.DefaultMailer,
+	}
+
+	authService.credentialVerifiers = newCredentialVerifiers(authService)
+
+	return authService
+}
+
+// Authenticate - returns the user with given credentials, verified by the credential verifier
+// chain. Failed attempts are counted for the account and client's IP address - once there
+// are too many of them, login is temporarily locked and auth.AccountLockedError is returned.
+func (as *AuthService) Authenticate(email, password string, client *auth.ClientInfo) (*models.UserModel, error) {
+	if err := as.authManager.CheckLoginAllowed(email, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := as.verifyCredentials(email, password)
+
+	if err == auth.ErrLoginCredentialsIncorrect {
+		if err := as.authManager.RegisterLoginFailure(email, client.IP); err != nil {
+			return nil, err
+		}
+
+		return nil, auth.ErrLoginCredentialsIncorrect
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Password alone does not prove the identity of users with two-factor authentication
+	// enabled - their failures are reset only after second factor is verified.
+	if !user.TOTPEnabled {
+		if err := as.authManager.ResetLoginFailures(email); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// verifyCredentials - returns the user given credentials belong to, according to the first
+// verifier of the chain accepting them.
+func (as *AuthService) verifyCredentials(email, password string) (*models.UserModel, error) {
+	for _, verifier := range as.credentialVerifiers {
+		user, err := verifier.VerifyCredentials(email, password)
+
+		if err != auth.ErrLoginCredentialsIncorrect {
+			return user, err
+		}
+	}
+
+	return nil, auth.ErrLoginCredentialsIncorrect
+}
+
+// verifyLocalCredentials - verifies given credentials against the password stored in Gochat,
+// upgrading its hash if needed.
+func (as *AuthService) verifyLocalCredentials(email, password string) (*models.UserModel, error) {
+	user, err := as.userService.GetUserByEmail(email)
+
+	if err == nil {
+		err = as.authManager.ComparePasswords(user.Password, []byte(password))
 	}
+
+	if err != nil {
+		return nil, auth.ErrLoginCredentialsIncorrect
+	}
+
+	as.upgradePasswordHash(user, password)
+
+	return user, nil
+}
+
+// UnlockAccount - resets failed login attempts of the user with given ID, unlocking their account.
+func (as *AuthService) UnlockAccount(userID uuid.UUID) error {
+	user, err := as.userService.GetUserByID(userID)
+
+	if err != nil {
+		return err
+	}
+
+	return as.authManager.ResetLoginFailures(user.Email)
 }
 
-// Login - logs in a user.
-func (as *AuthService) Login(user *models.UserModel) (string, error) {
+// Login - logs in a user, using given client. Returns auth.ErrEmailNotVerified
+// if current policy requires verified email to log in.
+func (as *AuthService) Login(user *models.UserModel, client *auth.ClientInfo) (*auth.TokenPair, error) {
 	apiSecret := os.Getenv("API_SECRET")
 
-	if apiSecret == "" {
-		return "", errors.New("Missing API Secret!")
+	if apiSecret == "" && auth.DefaultKeySet.UsesSecret() {
+		return nil, errors.New("Missing API Secret!")
+	}
+
+	if err := checkCanLogin(user); err != nil {
+		return nil, err
 	}
 
-	token, err := as.authManager.Login(user, apiSecret)
+	tokens, err := as.authManager.Login(user, client, apiSecret)
 
 	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// CreateLoginChallenge - starts the login of a user with two-factor authentication enabled.
+// Returned challenge token has to be exchanged together with second factor using LoginWithTwoFactor.
+func (as *AuthService) CreateLoginChallenge(user *models.UserModel) (string, error) {
+	if err := checkCanLogin(user); err != nil {
 		return "", err
 	}
 
-	return token, nil
+	return as.authManager.CreateLoginChallenge(user.ID)
+}
+
+// LoginWithTwoFactor - finishes the login started with CreateLoginChallenge, if given code
+// is user's correct second factor. Challenge can be used only once, regardless of the result.
+func (as *AuthService) LoginWithTwoFactor(
+	challengeToken string,
+	code string,
+	client *auth.ClientInfo,
+) (*models.UserModel, *auth.TokenPair, error) {
+	userID, err := as.authManager.ConsumeLoginChallenge(challengeToken)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := as.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, nil, auth.ErrLoginChallengeInvalid
+	}
+
+	if err := as.authManager.CheckLoginAllowed(user.Email, client.IP); err != nil {
+		return nil, nil, err
+	}
+
+	if err := as.twoFactorService.VerifyCode(user, code); err != nil {
+		if err == auth.ErrTwoFactorCodeInvalid {
+			if err := as.authManager.RegisterLoginFailure(user.Email, client.IP); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return nil, nil, err
+	}
+
+	if err := as.authManager.ResetLoginFailures(user.Email); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := as.Login(user, client)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Refresh - rotates given refresh token and issues new tokens for its session.
+func (as *AuthService) Refresh(refreshToken string) (*auth.TokenPair, error) {
+	apiSecret := os.Getenv("API_SECRET")
+
+	if apiSecret == "" && auth.DefaultKeySet.UsesSecret() {
+		return nil, errors.New("Missing API Secret!")
+	}
+
+	session, err := as.authManager.VerifyRefreshToken(refreshToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// User is loaded again, so the new access token reflects their current data (e.g. role).
+	user, err := as.userService.GetUserByID(session.UserID)
+
+	if err != nil {
+		return nil, auth.ErrRefreshTokenInvalid
+	}
+
+	return as.authManager.Refresh(session, user, apiSecret)
 }
 
 // Logout - logs out a user.
@@ -59,26 +268,365 @@ func (as *AuthService) Logout(userContext *control.ContextUser) error {
 	return as.authManager.Logout(userContext.AuthUUID.String())
 }
 
-// SignUp - registers a new user, and saves it to DB.
-func (as *AuthService) SignUp(credentials schema.SignupPayload) (*models.UserModel, error) {
-	hashedPassword, err := as.authManager.HashAndSalt([]byte(credentials.Password))
+// GetSessions - returns active sessions of given user.
+func (as *AuthService) GetSessions(userID uuid.UUID) ([]*auth.Session, error) {
+	return as.authManager.GetSessions(userID)
+}
+
+// RevokeSession - closes given user's session with given ID.
+func (as *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := as.authManager.GetSession(sessionID)
+
+	if err != nil || session.UserID != userID {
+		return auth.ErrSessionNotFound
+	}
+
+	return as.authManager.Logout(session.ID.String())
+}
+
+// SelectWorkspace - makes given workspace the current one in the session with given ID.
+func (as *AuthService) SelectWorkspace(authUUID, workspaceID uuid.UUID) error {
+	return as.authManager.SelectWorkspace(authUUID, workspaceID)
+}
+
+// RevokeSessions - closes all the sessions of given user. Returns IDs of closed sessions.
+func (as *AuthService) RevokeSessions(userID uuid.UUID) ([]uuid.UUID, error) {
+	return as.authManager.LogoutAll(userID)
+}
+
+// ForgotPassword - sends password reset token to the user with given email.
+// Nothing is sent when such user does not exist, but no error is returned,
+// so registered emails cannot be discovered this way.
+func (as *AuthService) ForgotPassword(email string) error {
+	user, err := as.userService.GetUserByEmail(email)
+
+	if err != nil {
+		return nil
+	}
+
+	token, err := as.authManager.CreatePasswordResetToken(user.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return as.mailer.Send(newPasswordResetMessage(user, token))
+}
+
+// ResetPassword - sets new password for the user given token has been created for.
+// All the sessions of the user are closed - their IDs are returned. If the password
+// violates password policy, auth.PasswordPolicyError is returned and the token can be used again.
+func (as *AuthService) ResetPassword(token, password string) ([]uuid.UUID, error) {
+	userID, err := as.authManager.VerifyPasswordResetToken(token)
 
 	if err != nil {
 		return nil, err
 	}
 
+	user, err := as.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, auth.ErrPasswordResetTokenInvalid
+	}
+
+	if err := as.passwordPolicy.Validate(password, user); err != nil {
+		return nil, err
+	}
+
+	// Token is consumed only after the password has been validated, so the user can retry
+	// with another password. Consuming fails if concurrent request has already used it.
+	if _, err := as.authManager.ConsumePasswordResetToken(token); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := as.authManager.HashAndSalt([]byte(password))
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedBy = user.ID
+
+	// Receiving the token proves the ownership of the email as well.
+	if !user.EmailVerified {
+		setEmailVerified(user)
+	}
+
+	if err := as.userService.SaveUser(user); err != nil {
+		return nil, err
+	}
+
+	return as.authManager.LogoutAll(user.ID)
+}
+
+// ChangePassword - sets new password for given user, if current one is correct. All the other
+// sessions of the user are closed - their IDs are returned. If the password violates password
+// policy, auth.PasswordPolicyError is returned. Incorrect current passwords count as failed
+// logins, so once there are too many of them, auth.AccountLockedError is returned.
+func (as *AuthService) ChangePassword(
+	userID uuid.UUID,
+	authUUID uuid.UUID,
+	currentPassword string,
+	password string,
+	client *auth.ClientInfo,
+) ([]uuid.UUID, error) {
+	user, err := as.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.authManager.CheckLoginAllowed(user.Email, client.IP); err != nil {
+		return nil, err
+	}
+
+	if err := as.authManager.ComparePasswords(user.Password, []byte(currentPassword)); err != nil {
+		if err := as.authManager.RegisterLoginFailure(user.Email, client.IP); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrCurrentPasswordIncorrect
+	}
+
+	if err := as.authManager.ResetLoginFailures(user.Email); err != nil {
+		return nil, err
+	}
+
+	if err := as.passwordPolicy.Validate(password, user); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := as.authManager.HashAndSalt([]byte(password))
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedBy = user.ID
+
+	if err := as.userService.SaveUser(user); err != nil {
+		return nil, err
+	}
+
+	return as.logoutOtherSessions(user.ID, authUUID)
+}
+
+// SignUp - registers a new user, and saves it to DB. If the password violates
+// password policy, auth.PasswordPolicyError is returned.
+func (as *AuthService) SignUp(credentials schema.SignupPayload) (*models.UserModel, error) {
 	userModel := &models.UserModel{
-		Password:  hashedPassword,
 		Email:     credentials.Email,
 		FirstName: credentials.FirstName,
 		LastName:  credentials.LastName,
 	}
 
+	if err := as.passwordPolicy.Validate(credentials.Password, userModel); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := as.authManager.HashAndSalt([]byte(credentials.Password))
+
+	if err != nil {
+		return nil, err
+	}
+
+	userModel.Password = hashedPassword
+
 	err = as.userService.SaveUser(userModel)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// User is already registered at this point - if sending fails,
+	// verification email can be requested again.
+	if err := as.sendVerificationEmail(userModel); err != nil {
+		log.Printf("Sending verification email failed: %v", err)
+	}
+
 	return userModel, nil
 }
+
+// VerifyEmail - marks the email of the user given token has been created for as verified.
+func (as *AuthService) VerifyEmail(token string) (*models.UserModel, error) {
+	userID, err := as.authManager.ConsumeEmailVerificationToken(token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := as.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, auth.ErrEmailVerificationTokenInvalid
+	}
+
+	if user.EmailVerified {
+		return user, nil
+	}
+
+	setEmailVerified(user)
+	user.UpdatedBy = user.ID
+
+	if err := as.userService.SaveUser(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ResendVerificationEmail - sends new verification token to the user with given email.
+// Nothing is sent when such user does not exist or is already verified, but no error
+// is returned, so registered emails cannot be discovered this way.
+func (as *AuthService) ResendVerificationEmail(email string) error {
+	user, err := as.userService.GetUserByEmail(email)
+
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+
+	return as.sendVerificationEmail(user)
+}
+
+// CheckCanSendMessages - returns auth.ErrEmailNotVerified if given user is not allowed
+// to send messages until their email is verified.
+func (as *AuthService) CheckCanSendMessages(userID uuid.UUID) error {
+	if !auth.GetEmailVerificationPolicy().BlocksMessages() {
+		return nil
+	}
+
+	user, err := as.userService.GetUserByID(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !user.EmailVerified {
+		return auth.ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// sendVerificationEmail - creates new email verification token for given user and sends it.
+func (as *AuthService) sendVerificationEmail(user *models.UserModel) error {
+	token, err := as.authManager.CreateEmailVerificationToken(user.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return as.mailer.Send(newEmailVerificationMessage(user, token))
+}
+
+// logoutOtherSessions - closes all the sessions of given user, except the one with given ID.
+// Returns IDs of closed sessions.
+func (as *AuthService) logoutOtherSessions(userID, authUUID uuid.UUID) ([]uuid.UUID, error) {
+	sessions, err := as.authManager.GetSessions(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	closedIDs := []uuid.UUID{}
+
+	for _, session := range sessions {
+		if session.ID == authUUID {
+			continue
+		}
+
+		if err := as.authManager.Logout(session.ID.String()); err != nil {
+			return closedIDs, err
+		}
+
+		closedIDs = append(closedIDs, session.ID)
+	}
+
+	return closedIDs, nil
+}
+
+// upgradePasswordHash - replaces given user's password hash, if it has been created with
+// outdated algorithm or parameters. Login is not affected by failures - hash will be
+// upgraded on the next one.
+func (as *AuthService) upgradePasswordHash(user *models.UserModel, password string) {
+	if !as.authManager.PasswordNeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := as.authManager.HashAndSalt([]byte(password))
+
+	if err != nil {
+		log.Printf("Upgrading password hash failed: %v", err)
+		return
+	}
+
+	user.Password = hashedPassword
+
+	if err := as.userService.SaveUser(user); err != nil {
+		log.Printf("Upgrading password hash failed: %v", err)
+	}
+}
+
+// checkCanLogin - returns auth.ErrEmailNotVerified if current policy does not allow
+// given user to log in until their email is verified.
+func checkCanLogin(user *models.UserModel) error {
+	if !user.EmailVerified && auth.GetEmailVerificationPolicy().BlocksLogin() {
+		return auth.ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// setEmailVerified - marks given user's email as verified.
+func setEmailVerified(user *models.UserModel) {
+	verifiedAt := time.Now()
+
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &verifiedAt
+}
+
+// newPasswordResetMessage - returns the message delivering password reset token to given user.
+// If PASSWORD_RESET_URL is set, token is passed as a "token" query param of that URL.
+func newPasswordResetMessage(user *models.UserModel, token string) *mail.Message {
+	link := token
+
+	if resetURL := os.Getenv("PASSWORD_RESET_URL"); resetURL != "" {
+		link = fmt.Sprintf("%s?token=%s", resetURL, token)
+	}
+
+	return &mail.Message{
+		To:      user.Email,
+		Subject: "Gochat password reset",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the following link to reset your Gochat password:\n\n%s\n\n"+
+				"The link expires in %d minutes. If you did not request a password reset, please ignore this message.",
+			user.FirstName,
+			link,
+			int(auth.PasswordResetTokenTTL.Minutes()),
+		),
+	}
+}
+
+// newEmailVerificationMessage - returns the message delivering email verification token to given user.
+// If EMAIL_VERIFICATION_URL is set, token is passed as a "token" query param of that URL.
+func newEmailVerificationMessage(user *models.UserModel, token string) *mail.Message {
+	link := token
+
+	if verificationURL := os.Getenv("EMAIL_VERIFICATION_URL"); verificationURL != "" {
+		link = fmt.Sprintf("%s?token=%s", verificationURL, token)
+	}
+
+	return &mail.Message{
+		To:      user.Email,
+		Subject: "Gochat email verification",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the following link to verify your Gochat email address:\n\n%s\n\n"+
+				"The link expires in %d hours. If you did not create a Gochat account, please ignore this message.",
+			user.FirstName,
+			link,
+			int(auth.EmailVerificationTokenTTL.Hours()),
+		),
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/el-Mike/gochat/auth"
 	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mail"
 	"github.com/el-Mike/gochat/mocks"
 	"github.com/el-Mike/gochat/models"
 	"github.com/el-Mike/gochat/schema"
@@ -19,6 +22,26 @@ type userServiceMock struct {
 	mock.Mock
 }
 
+func (us *userServiceMock) GetUserByID(id uuid.UUID) (*models.UserModel, error) {
+	args := us.Called(id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.UserModel), args.Error(1)
+}
+
+func (us *userServiceMock) GetUserByEmail(email string) (*models.UserModel, error) {
+	args := us.Called(email)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.UserModel), args.Error(1)
+}
+
 func (us *userServiceMock) SaveUser(user *models.UserModel) error {
 	args := us.Called(user)
 
@@ -33,10 +56,38 @@ type authManagerMock struct {
 	mock.Mock
 }
 
-func (am *authManagerMock) Login(user *models.UserModel, apiSecret string) (string, error) {
-	args := am.Called(user, apiSecret)
+func (am *authManagerMock) Login(user *models.UserModel, client *auth.ClientInfo, apiSecret string) (*auth.TokenPair, error) {
+	args := am.Called(user, client, apiSecret)
 
-	return args.String(0), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.TokenPair), args.Error(1)
+}
+
+func (am *authManagerMock) VerifyRefreshToken(refreshToken string) (*auth.RefreshSession, error) {
+	args := am.Called(refreshToken)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.RefreshSession), args.Error(1)
+}
+
+func (am *authManagerMock) Refresh(
+	session *auth.RefreshSession,
+	user *models.UserModel,
+	apiSecret string,
+) (*auth.TokenPair, error) {
+	args := am.Called(session, user, apiSecret)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.TokenPair), args.Error(1)
 }
 
 func (am *authManagerMock) Logout(authUUID string) error {
@@ -45,12 +96,140 @@ func (am *authManagerMock) Logout(authUUID string) error {
 	return args.Error(0)
 }
 
+func (am *authManagerMock) SelectWorkspace(authUUID uuid.UUID, workspaceID uuid.UUID) error {
+	args := am.Called(authUUID, workspaceID)
+
+	return args.Error(0)
+}
+
+func (am *authManagerMock) LogoutAll(userID uuid.UUID) ([]uuid.UUID, error) {
+	args := am.Called(userID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (am *authManagerMock) GetSession(authUUID uuid.UUID) (*auth.Session, error) {
+	args := am.Called(authUUID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.Session), args.Error(1)
+}
+
+func (am *authManagerMock) GetSessions(userID uuid.UUID) ([]*auth.Session, error) {
+	args := am.Called(userID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*auth.Session), args.Error(1)
+}
+
+func (am *authManagerMock) CreatePasswordResetToken(userID uuid.UUID) (string, error) {
+	args := am.Called(userID)
+
+	return args.String(0), args.Error(1)
+}
+
+func (am *authManagerMock) VerifyPasswordResetToken(token string) (uuid.UUID, error) {
+	args := am.Called(token)
+
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (am *authManagerMock) ConsumePasswordResetToken(token string) (uuid.UUID, error) {
+	args := am.Called(token)
+
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (am *authManagerMock) CreateEmailVerificationToken(userID uuid.UUID) (string, error) {
+	args := am.Called(userID)
+
+	return args.String(0), args.Error(1)
+}
+
+func (am *authManagerMock) ConsumeEmailVerificationToken(token string) (uuid.UUID, error) {
+	args := am.Called(token)
+
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (am *authManagerMock) CreateLoginChallenge(userID uuid.UUID) (string, error) {
+	args := am.Called(userID)
+
+	return args.String(0), args.Error(1)
+}
+
+func (am *authManagerMock) ConsumeLoginChallenge(token string) (uuid.UUID, error) {
+	args := am.Called(token)
+
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (am *authManagerMock) CheckLoginAllowed(email, ip string) error {
+	args := am.Called(email, ip)
+
+	return args.Error(0)
+}
+
+func (am *authManagerMock) RegisterLoginFailure(email, ip string) error {
+	args := am.Called(email, ip)
+
+	return args.Error(0)
+}
+
+func (am *authManagerMock) ResetLoginFailures(email string) error {
+	args := am.Called(email)
+
+	return args.Error(0)
+}
+
+func (am *authManagerMock) ComparePasswords(hashedPassword string, plainPassword []byte) error {
+	args := am.Called(hashedPassword, plainPassword)
+
+	return args.Error(0)
+}
+
+func (am *authManagerMock) PasswordNeedsRehash(hashedPassword string) bool {
+	args := am.Called(hashedPassword)
+
+	return args.Bool(0)
+}
+
 func (am *authManagerMock) HashAndSalt(password []byte) (string, error) {
 	args := am.Called(password)
 
 	return args.String(0), args.Error(1)
 }
 
+type twoFactorVerifierMock struct {
+	mock.Mock
+}
+
+func (tv *twoFactorVerifierMock) VerifyCode(user *models.UserModel, code string) error {
+	args := tv.Called(user, code)
+
+	return args.Error(0)
+}
+
+type mailerMock struct {
+	mock.Mock
+}
+
+func (mm *mailerMock) Send(message *mail.Message) error {
+	args := mm.Called(message)
+
+	return args.Error(0)
+}
+
 type authServiceSuite struct {
 	suite.Suite
 	authService     *AuthService
@@ -60,7 +239,7 @@ type authServiceSuite struct {
 	testUser        *models.UserModel
 	testContextUser *control.ContextUser
 	testSecret      string
-	testToken       string
+	testTokens      *auth.TokenPair
 	testPassword    string
 	testCredentials *schema.SignupPayload
 }
@@ -86,7 +265,10 @@ func (s *authServiceSuite) SetupSuite() {
 	s.testContextUser = &control.ContextUser{}
 
 	s.testSecret = "test_api_secret"
-	s.testToken = "test_token"
+	s.testTokens = &auth.TokenPair{
+		AccessToken:  "test_token",
+		RefreshToken: "test_refresh_token",
+	}
 
 	s.testPassword = "test_password"
 
@@ -100,9 +282,16 @@ func (s *authServiceSuite) SetupSuite() {
 
 func (s *authServiceSuite) SetupTest() {
 	s.authService = &AuthService{
-		broker:      mocks.NewGormMock(),
-		userService: &userServiceMock{},
-		authManager: &authManagerMock{},
+		broker:           mocks.NewGormMock(),
+		userService:      &userServiceMock{},
+		authManager:      &authManagerMock{},
+		twoFactorService: &twoFactorVerifierMock{},
+		passwordPolicy:   auth.NewPasswordPolicy(),
+		mailer:           &mailerMock{},
+	}
+
+	s.authService.credentialVerifiers = []CredentialVerifier{
+		CredentialVerifierFunc(s.authService.verifyLocalCredentials),
 	}
 }
 
@@ -120,11 +309,12 @@ func (s *authServiceSuite) TestLogin() {
 		"Login",
 		mock.Anything,
 		mock.Anything,
-	).Return(s.testToken, nil)
+		mock.Anything,
+	).Return(s.testTokens, nil)
 
 	authService.authManager = authManagerMock
 
-	token, err := authService.Login(s.testUser)
+	token, err := authService.Login(s.testUser, &auth.ClientInfo{})
 
 	authManagerMock.AssertNumberOfCalls(s.T(), "Login", 1)
 
@@ -142,11 +332,12 @@ func (s *authServiceSuite) TestLogin_MissingAPISecret() {
 		"Login",
 		mock.Anything,
 		mock.Anything,
-	).Return(s.testToken, nil)
+		mock.Anything,
+	).Return(s.testTokens, nil)
 
 	authService.authManager = authManagerMock
 
-	token, err := authService.Login(s.testUser)
+	token, err := authService.Login(s.testUser, &auth.ClientInfo{})
 
 	authManagerMock.AssertNumberOfCalls(s.T(), "Login", 0)
 
@@ -164,11 +355,12 @@ func (s *authServiceSuite) TestLogin_Error() {
 		"Login",
 		mock.Anything,
 		mock.Anything,
-	).Return("", errors.New("LoginError"))
+		mock.Anything,
+	).Return(nil, errors.New("LoginError"))
 
 	authService.authManager = authManagerMock
 
-	token, err := authService.Login(s.testUser)
+	token, err := authService.Login(s.testUser, &auth.ClientInfo{})
 
 	authManagerMock.AssertNumberOfCalls(s.T(), "Login", 1)
 
@@ -176,53 +368,1075 @@ func (s *authServiceSuite) TestLogin_Error() {
 	assert.NotNil(s.T(), err)
 }
 
-func (s *authServiceSuite) TestLogout() {
+func (s *authServiceSuite) TestLogin_EmailNotVerified() {
 	authService := s.authService
 
+	os.Setenv("API_SECRET", s.testSecret)
+	os.Setenv("EMAIL_VERIFICATION_POLICY", string(auth.EmailVerificationRequiredForLogin))
+	defer os.Unsetenv("EMAIL_VERIFICATION_POLICY")
+
 	authManagerMock := new(authManagerMock)
-	authManagerMock.On(
-		"Logout",
-		mock.Anything,
-	).Return(nil)
 
 	authService.authManager = authManagerMock
 
-	err := authService.Logout(s.testContextUser)
+	token, err := authService.Login(s.testUser, &auth.ClientInfo{})
 
-	authManagerMock.AssertNumberOfCalls(s.T(), "Logout", 1)
+	authManagerMock.AssertNotCalled(s.T(), "Login", mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Nil(s.T(), token)
+	assert.Equal(s.T(), auth.ErrEmailNotVerified, err)
+}
+
+func (s *authServiceSuite) TestAuthenticate() {
+	authService := s.authService
+	client := &auth.ClientInfo{IP: "127.0.0.1"}
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Email:     s.testEmail,
+		Password:  "test_hash",
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", s.testEmail, client.IP).Return(nil)
+	authManagerMock.On("ComparePasswords", "test_hash", []byte(s.testPassword)).Return(nil)
+	authManagerMock.On("PasswordNeedsRehash", "test_hash").Return(false)
+	authManagerMock.On("ResetLoginFailures", s.testEmail).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", s.testEmail).Return(testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, client)
 
 	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testUser, user)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "ResetLoginFailures", 1)
+	authManagerMock.AssertNotCalled(s.T(), "RegisterLoginFailure", mock.Anything, mock.Anything)
 }
 
-func (s *authServiceSuite) TestSignUp() {
+func (s *authServiceSuite) TestAuthenticate_RehashPassword() {
 	authService := s.authService
 
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Email:     s.testEmail,
+		Password:  "outdated_hash",
+	}
+
 	authManagerMock := new(authManagerMock)
-	authManagerMock.On(
-		"HashAndSalt",
-		mock.Anything,
-	).Return(s.testPassword, nil)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("ComparePasswords", "outdated_hash", []byte(s.testPassword)).Return(nil)
+	authManagerMock.On("PasswordNeedsRehash", "outdated_hash").Return(true)
+	authManagerMock.On("HashAndSalt", []byte(s.testPassword)).Return("current_hash", nil)
+	authManagerMock.On("ResetLoginFailures", mock.Anything).Return(nil)
 
 	userServiceMock := new(userServiceMock)
-	userServiceMock.On(
-		"SaveUser",
-		mock.Anything,
-	).Return(nil)
+	userServiceMock.On("GetUserByEmail", s.testEmail).Return(testUser, nil)
+	userServiceMock.On("SaveUser", testUser).Return(nil)
 
 	authService.authManager = authManagerMock
 	authService.userService = userServiceMock
 
-	user, err := authService.SignUp(*s.testCredentials)
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, &auth.ClientInfo{})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "current_hash", user.Password)
 
-	authManagerMock.AssertNumberOfCalls(s.T(), "HashAndSalt", 1)
 	userServiceMock.AssertNumberOfCalls(s.T(), "SaveUser", 1)
+}
+
+func (s *authServiceSuite) TestAuthenticate_RehashPasswordError() {
+	authService := s.authService
 
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Email:     s.testEmail,
+		Password:  "outdated_hash",
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("ComparePasswords", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("PasswordNeedsRehash", mock.Anything).Return(true)
+	authManagerMock.On("HashAndSalt", mock.Anything).Return("current_hash", nil)
+	authManagerMock.On("ResetLoginFailures", mock.Anything).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", s.testEmail).Return(testUser, nil)
+	userServiceMock.On("SaveUser", mock.Anything).Return(errors.New("SaveUserError"))
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, &auth.ClientInfo{})
+
+	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), user)
+}
+
+func (s *authServiceSuite) TestAuthenticate_TwoFactorEnabled() {
+	authService := s.authService
+
+	testUser := &models.UserModel{
+		BaseModel:   models.BaseModel{ID: s.testUserID},
+		Email:       s.testEmail,
+		Password:    "test_hash",
+		TOTPEnabled: true,
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("ComparePasswords", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("PasswordNeedsRehash", mock.Anything).Return(false)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", s.testEmail).Return(testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, &auth.ClientInfo{})
+
 	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testUser, user)
 
-	assert.Equal(s.T(), s.testCredentials.Email, user.Email)
-	assert.Equal(s.T(), s.testCredentials.FirstName, user.FirstName)
-	assert.Equal(s.T(), s.testCredentials.LastName, user.LastName)
+	authManagerMock.AssertNotCalled(s.T(), "ResetLoginFailures", mock.Anything)
+}
+
+func (s *authServiceSuite) TestAuthenticate_IncorrectPassword() {
+	authService := s.authService
+	client := &auth.ClientInfo{IP: "127.0.0.1"}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("ComparePasswords", mock.Anything, mock.Anything).Return(errors.New("MismatchError"))
+	authManagerMock.On("RegisterLoginFailure", s.testEmail, client.IP).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", s.testEmail).Return(s.testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, client)
+
+	assert.Nil(s.T(), user)
+	assert.Equal(s.T(), auth.ErrLoginCredentialsIncorrect, err)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "RegisterLoginFailure", 1)
+	authManagerMock.AssertNotCalled(s.T(), "ResetLoginFailures", mock.Anything)
+}
+
+func (s *authServiceSuite) TestAuthenticate_UnknownEmail() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("RegisterLoginFailure", "unknown@gochat.com", mock.Anything).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", mock.Anything).Return(nil, errors.New("GormError"))
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.Authenticate("unknown@gochat.com", s.testPassword, &auth.ClientInfo{})
+
+	assert.Nil(s.T(), user)
+	assert.Equal(s.T(), auth.ErrLoginCredentialsIncorrect, err)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "RegisterLoginFailure", 1)
+}
+
+func (s *authServiceSuite) TestAuthenticate_Locked() {
+	authService := s.authService
+
+	lockedErr := &auth.AccountLockedError{RetryAfter: time.Minute}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(lockedErr)
+
+	userServiceMock := new(userServiceMock)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, &auth.ClientInfo{})
+
+	assert.Nil(s.T(), user)
+	assert.Equal(s.T(), lockedErr, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByEmail", mock.Anything)
+}
+
+func (s *authServiceSuite) TestAuthenticate_NextVerifier() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+	authManagerMock.On("ResetLoginFailures", s.testEmail).Return(nil)
+
+	directoryUser := &models.UserModel{Email: s.testEmail}
+
+	authService.authManager = authManagerMock
+	authService.credentialVerifiers = []CredentialVerifier{
+		CredentialVerifierFunc(func(email, password string) (*models.UserModel, error) {
+			return nil, auth.ErrLoginCredentialsIncorrect
+		}),
+		CredentialVerifierFunc(func(email, password string) (*models.UserModel, error) {
+			return directoryUser, nil
+		}),
+	}
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, &auth.ClientInfo{})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), directoryUser, user)
+
+	authManagerMock.AssertNotCalled(s.T(), "RegisterLoginFailure", mock.Anything, mock.Anything)
+}
+
+func (s *authServiceSuite) TestAuthenticate_VerifierError() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", mock.Anything, mock.Anything).Return(nil)
+
+	verifierErr := errors.New("LDAPError")
+	nextVerifierCalled := false
+
+	authService.authManager = authManagerMock
+	authService.credentialVerifiers = []CredentialVerifier{
+		CredentialVerifierFunc(func(email, password string) (*models.UserModel, error) {
+			return nil, verifierErr
+		}),
+		CredentialVerifierFunc(func(email, password string) (*models.UserModel, error) {
+			nextVerifierCalled = true
+
+			return s.testUser, nil
+		}),
+	}
+
+	user, err := authService.Authenticate(s.testEmail, s.testPassword, &auth.ClientInfo{})
+
+	assert.Nil(s.T(), user)
+	assert.Equal(s.T(), verifierErr, err)
+	assert.False(s.T(), nextVerifierCalled)
+
+	// Unavailable directory is not user's mistake, so it does not count towards the lockout.
+	authManagerMock.AssertNotCalled(s.T(), "RegisterLoginFailure", mock.Anything, mock.Anything)
+}
+
+func (s *authServiceSuite) TestUnlockAccount() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("ResetLoginFailures", s.testEmail).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(s.testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	assert.Nil(s.T(), authService.UnlockAccount(s.testUserID))
+	authManagerMock.AssertNumberOfCalls(s.T(), "ResetLoginFailures", 1)
+}
+
+func (s *authServiceSuite) TestLoginWithTwoFactor() {
+	authService := s.authService
+
+	os.Setenv("API_SECRET", s.testSecret)
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"ConsumeLoginChallenge",
+		"test_challenge_token",
+	).Return(s.testUserID, nil)
+	authManagerMock.On(
+		"CheckLoginAllowed",
+		s.testEmail,
+		mock.Anything,
+	).Return(nil)
+	authManagerMock.On(
+		"ResetLoginFailures",
+		s.testEmail,
+	).Return(nil)
+	authManagerMock.On(
+		"Login",
+		s.testUser,
+		mock.Anything,
+		s.testSecret,
+	).Return(s.testTokens, nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(s.testUser, nil)
+
+	twoFactorMock := new(twoFactorVerifierMock)
+	twoFactorMock.On(
+		"VerifyCode",
+		s.testUser,
+		"123456",
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.twoFactorService = twoFactorMock
+
+	user, tokens, err := authService.LoginWithTwoFactor("test_challenge_token", "123456", &auth.ClientInfo{})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testUser, user)
+	assert.Equal(s.T(), s.testTokens, tokens)
+}
+
+func (s *authServiceSuite) TestLoginWithTwoFactor_InvalidCode() {
+	authService := s.authService
+
+	os.Setenv("API_SECRET", s.testSecret)
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"ConsumeLoginChallenge",
+		mock.Anything,
+	).Return(s.testUserID, nil)
+	authManagerMock.On(
+		"CheckLoginAllowed",
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+	authManagerMock.On(
+		"RegisterLoginFailure",
+		s.testEmail,
+		"127.0.0.1",
+	).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(s.testUser, nil)
+
+	twoFactorMock := new(twoFactorVerifierMock)
+	twoFactorMock.On(
+		"VerifyCode",
+		mock.Anything,
+		mock.Anything,
+	).Return(auth.ErrTwoFactorCodeInvalid)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.twoFactorService = twoFactorMock
+
+	user, tokens, err := authService.LoginWithTwoFactor("test_challenge_token", "000000", &auth.ClientInfo{IP: "127.0.0.1"})
+
+	assert.Nil(s.T(), user)
+	assert.Nil(s.T(), tokens)
+	assert.Equal(s.T(), auth.ErrTwoFactorCodeInvalid, err)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "RegisterLoginFailure", 1)
+
+	authManagerMock.AssertNotCalled(s.T(), "Login", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *authServiceSuite) TestLoginWithTwoFactor_InvalidChallenge() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"ConsumeLoginChallenge",
+		mock.Anything,
+	).Return(uuid.Nil, auth.ErrLoginChallengeInvalid)
+
+	twoFactorMock := new(twoFactorVerifierMock)
+
+	authService.authManager = authManagerMock
+	authService.twoFactorService = twoFactorMock
+
+	_, _, err := authService.LoginWithTwoFactor("test_challenge_token", "123456", &auth.ClientInfo{})
+
+	assert.Equal(s.T(), auth.ErrLoginChallengeInvalid, err)
+	twoFactorMock.AssertNotCalled(s.T(), "VerifyCode", mock.Anything, mock.Anything)
+}
+
+func (s *authServiceSuite) TestRefresh() {
+	authService := s.authService
+
+	os.Setenv("API_SECRET", s.testSecret)
+
+	session := &auth.RefreshSession{
+		AuthUUID: uuid.New(),
+		UserID:   s.testUserID,
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"VerifyRefreshToken",
+		s.testTokens.RefreshToken,
+	).Return(session, nil)
+	authManagerMock.On(
+		"Refresh",
+		session,
+		s.testUser,
+		s.testSecret,
+	).Return(s.testTokens, nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(s.testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	tokens, err := authService.Refresh(s.testTokens.RefreshToken)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "Refresh", 1)
+
+	assert.Equal(s.T(), s.testTokens, tokens)
+	assert.Nil(s.T(), err)
+}
+
+func (s *authServiceSuite) TestRefresh_InvalidToken() {
+	authService := s.authService
+
+	os.Setenv("API_SECRET", s.testSecret)
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"VerifyRefreshToken",
+		mock.Anything,
+	).Return(nil, auth.ErrRefreshTokenInvalid)
+
+	authService.authManager = authManagerMock
+
+	tokens, err := authService.Refresh(s.testTokens.RefreshToken)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "Refresh", 0)
+
+	assert.Nil(s.T(), tokens)
+	assert.Equal(s.T(), auth.ErrRefreshTokenInvalid, err)
+}
+
+func (s *authServiceSuite) TestRefresh_UserNotFound() {
+	authService := s.authService
+
+	os.Setenv("API_SECRET", s.testSecret)
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"VerifyRefreshToken",
+		mock.Anything,
+	).Return(&auth.RefreshSession{UserID: s.testUserID}, nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		mock.Anything,
+	).Return(nil, errors.New("GormError"))
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	tokens, err := authService.Refresh(s.testTokens.RefreshToken)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "Refresh", 0)
+
+	assert.Nil(s.T(), tokens)
+	assert.Equal(s.T(), auth.ErrRefreshTokenInvalid, err)
+}
+
+func (s *authServiceSuite) TestLogout() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"Logout",
+		mock.Anything,
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+
+	err := authService.Logout(s.testContextUser)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "Logout", 1)
+
+	assert.Nil(s.T(), err)
+}
+
+func (s *authServiceSuite) TestRevokeSession() {
+	authService := s.authService
+
+	session := &auth.Session{
+		ID:     uuid.New(),
+		UserID: s.testUserID,
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"GetSession",
+		session.ID,
+	).Return(session, nil)
+	authManagerMock.On(
+		"Logout",
+		session.ID.String(),
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+
+	err := authService.RevokeSession(s.testUserID, session.ID)
+
+	assert.Nil(s.T(), err)
+	authManagerMock.AssertNumberOfCalls(s.T(), "Logout", 1)
+}
+
+func (s *authServiceSuite) TestRevokeSession_OtherUser() {
+	authService := s.authService
+
+	session := &auth.Session{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"GetSession",
+		session.ID,
+	).Return(session, nil)
+
+	authService.authManager = authManagerMock
+
+	err := authService.RevokeSession(s.testUserID, session.ID)
+
+	assert.Equal(s.T(), auth.ErrSessionNotFound, err)
+	authManagerMock.AssertNotCalled(s.T(), "Logout", mock.Anything)
+}
+
+func (s *authServiceSuite) TestRevokeSessions() {
+	authService := s.authService
+
+	sessionIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"LogoutAll",
+		s.testUserID,
+	).Return(sessionIDs, nil)
+
+	authService.authManager = authManagerMock
+
+	result, err := authService.RevokeSessions(s.testUserID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), sessionIDs, result)
+}
+
+func (s *authServiceSuite) TestForgotPassword() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"CreatePasswordResetToken",
+		s.testUserID,
+	).Return("test_reset_token", nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByEmail",
+		s.testEmail,
+	).Return(s.testUser, nil)
+
+	mailerMock := new(mailerMock)
+	mailerMock.On(
+		"Send",
+		mock.Anything,
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.mailer = mailerMock
+
+	err := authService.ForgotPassword(s.testEmail)
+
+	assert.Nil(s.T(), err)
+
+	message := mailerMock.Calls[0].Arguments.Get(0).(*mail.Message)
+
+	assert.Equal(s.T(), s.testEmail, message.To)
+	assert.Contains(s.T(), message.Body, "test_reset_token")
+}
+
+func (s *authServiceSuite) TestForgotPassword_UnknownEmail() {
+	authService := s.authService
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByEmail",
+		mock.Anything,
+	).Return(nil, errors.New("GormError"))
+
+	mailerMock := new(mailerMock)
+
+	authService.userService = userServiceMock
+	authService.mailer = mailerMock
+
+	err := authService.ForgotPassword(s.testEmail)
+
+	assert.Nil(s.T(), err)
+	mailerMock.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *authServiceSuite) TestResetPassword() {
+	authService := s.authService
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Password:  "old_hash",
+	}
+
+	sessionIDs := []uuid.UUID{uuid.New()}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"VerifyPasswordResetToken",
+		"test_reset_token",
+	).Return(s.testUserID, nil)
+	authManagerMock.On(
+		"ConsumePasswordResetToken",
+		"test_reset_token",
+	).Return(s.testUserID, nil)
+	authManagerMock.On(
+		"HashAndSalt",
+		[]byte(s.testPassword),
+	).Return("new_hash", nil)
+	authManagerMock.On(
+		"LogoutAll",
+		s.testUserID,
+	).Return(sessionIDs, nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(testUser, nil)
+	userServiceMock.On(
+		"SaveUser",
+		testUser,
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	result, err := authService.ResetPassword("test_reset_token", s.testPassword)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), sessionIDs, result)
+	assert.Equal(s.T(), "new_hash", testUser.Password)
+
+	userServiceMock.AssertNumberOfCalls(s.T(), "SaveUser", 1)
+	authManagerMock.AssertNumberOfCalls(s.T(), "LogoutAll", 1)
+}
+
+func (s *authServiceSuite) TestResetPassword_InvalidToken() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"VerifyPasswordResetToken",
+		mock.Anything,
+	).Return(uuid.Nil, auth.ErrPasswordResetTokenInvalid)
+
+	userServiceMock := new(userServiceMock)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	result, err := authService.ResetPassword("test_reset_token", s.testPassword)
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), auth.ErrPasswordResetTokenInvalid, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *authServiceSuite) TestResetPassword_PasswordPolicy() {
+	authService := s.authService
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Password:  "old_hash",
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"VerifyPasswordResetToken",
+		"test_reset_token",
+	).Return(s.testUserID, nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.passwordPolicy = auth.NewPasswordPolicy(auth.NewPasswordLengthRule(20, 128))
+
+	result, err := authService.ResetPassword("test_reset_token", s.testPassword)
+
+	assert.Nil(s.T(), result)
+	assert.IsType(s.T(), &auth.PasswordPolicyError{}, err)
+	assert.Equal(s.T(), "old_hash", testUser.Password)
+
+	// Token stays valid, so password can be reset with a better one.
+	authManagerMock.AssertNotCalled(s.T(), "ConsumePasswordResetToken", mock.Anything)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *authServiceSuite) TestChangePassword() {
+	authService := s.authService
+	client := &auth.ClientInfo{IP: "127.0.0.1"}
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Email:     s.testEmail,
+		Password:  "old_hash",
+	}
+
+	currentSessionID := uuid.New()
+	otherSessionID := uuid.New()
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", s.testEmail, client.IP).Return(nil)
+	authManagerMock.On("ResetLoginFailures", s.testEmail).Return(nil)
+	authManagerMock.On(
+		"ComparePasswords",
+		"old_hash",
+		[]byte("current_password"),
+	).Return(nil)
+	authManagerMock.On(
+		"HashAndSalt",
+		[]byte(s.testPassword),
+	).Return("new_hash", nil)
+	authManagerMock.On(
+		"GetSessions",
+		s.testUserID,
+	).Return([]*auth.Session{{ID: currentSessionID}, {ID: otherSessionID}}, nil)
+	authManagerMock.On(
+		"Logout",
+		otherSessionID.String(),
+	).Return(nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(testUser, nil)
+	userServiceMock.On(
+		"SaveUser",
+		testUser,
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	result, err := authService.ChangePassword(s.testUserID, currentSessionID, "current_password", s.testPassword, client)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []uuid.UUID{otherSessionID}, result)
+	assert.Equal(s.T(), "new_hash", testUser.Password)
+
+	userServiceMock.AssertNumberOfCalls(s.T(), "SaveUser", 1)
+	authManagerMock.AssertNumberOfCalls(s.T(), "ResetLoginFailures", 1)
+	authManagerMock.AssertNotCalled(s.T(), "Logout", currentSessionID.String())
+}
+
+func (s *authServiceSuite) TestChangePassword_IncorrectCurrentPassword() {
+	authService := s.authService
+	client := &auth.ClientInfo{IP: "127.0.0.1"}
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Email:     s.testEmail,
+		Password:  "old_hash",
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", s.testEmail, client.IP).Return(nil)
+	authManagerMock.On("RegisterLoginFailure", s.testEmail, client.IP).Return(nil)
+	authManagerMock.On(
+		"ComparePasswords",
+		mock.Anything,
+		mock.Anything,
+	).Return(auth.ErrPasswordMismatch)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	result, err := authService.ChangePassword(s.testUserID, uuid.New(), "wrong_password", s.testPassword, client)
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), ErrCurrentPasswordIncorrect, err)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "RegisterLoginFailure", 1)
+	authManagerMock.AssertNotCalled(s.T(), "HashAndSalt", mock.Anything)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *authServiceSuite) TestChangePassword_Locked() {
+	authService := s.authService
+	client := &auth.ClientInfo{IP: "127.0.0.1"}
+	lockedErr := &auth.AccountLockedError{RetryAfter: time.Minute}
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+		Email:     s.testEmail,
+		Password:  "old_hash",
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On("CheckLoginAllowed", s.testEmail, client.IP).Return(lockedErr)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(testUser, nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	result, err := authService.ChangePassword(s.testUserID, uuid.New(), "current_password", s.testPassword, client)
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), lockedErr, err)
+
+	authManagerMock.AssertNotCalled(s.T(), "ComparePasswords", mock.Anything, mock.Anything)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *authServiceSuite) TestSignUp_PasswordPolicy() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	userServiceMock := new(userServiceMock)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.passwordPolicy = auth.NewPasswordPolicy(auth.NewPersonalInfoRule())
+
+	credentials := *s.testCredentials
+	credentials.Password = "First_Name_2024"
+
+	user, err := authService.SignUp(credentials)
+
+	assert.Nil(s.T(), user)
+
+	policyErr, ok := err.(*auth.PasswordPolicyError)
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), auth.PasswordPersonalInfoViolation, policyErr.Violations[0].Code)
+
+	authManagerMock.AssertNotCalled(s.T(), "HashAndSalt", mock.Anything)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *authServiceSuite) TestSignUp() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"HashAndSalt",
+		mock.Anything,
+	).Return(s.testPassword, nil)
+
+	authManagerMock.On(
+		"CreateEmailVerificationToken",
+		mock.Anything,
+	).Return("test_verification_token", nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"SaveUser",
+		mock.Anything,
+	).Return(nil)
+
+	mailerMock := new(mailerMock)
+	mailerMock.On(
+		"Send",
+		mock.Anything,
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.mailer = mailerMock
+
+	user, err := authService.SignUp(*s.testCredentials)
+
+	authManagerMock.AssertNumberOfCalls(s.T(), "HashAndSalt", 1)
+	userServiceMock.AssertNumberOfCalls(s.T(), "SaveUser", 1)
+	mailerMock.AssertNumberOfCalls(s.T(), "Send", 1)
+
+	assert.NotNil(s.T(), user)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), user.EmailVerified)
+
+	assert.Equal(s.T(), s.testCredentials.Email, user.Email)
+	assert.Equal(s.T(), s.testCredentials.FirstName, user.FirstName)
+	assert.Equal(s.T(), s.testCredentials.LastName, user.LastName)
+}
+
+func (s *authServiceSuite) TestSignUp_MailerError() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"HashAndSalt",
+		mock.Anything,
+	).Return(s.testPassword, nil)
+	authManagerMock.On(
+		"CreateEmailVerificationToken",
+		mock.Anything,
+	).Return("test_verification_token", nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"SaveUser",
+		mock.Anything,
+	).Return(nil)
+
+	mailerMock := new(mailerMock)
+	mailerMock.On(
+		"Send",
+		mock.Anything,
+	).Return(errors.New("MailerError"))
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+	authService.mailer = mailerMock
+
+	user, err := authService.SignUp(*s.testCredentials)
+
+	assert.NotNil(s.T(), user)
+	assert.Nil(s.T(), err)
+}
+
+func (s *authServiceSuite) TestVerifyEmail() {
+	authService := s.authService
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: s.testUserID},
+	}
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"ConsumeEmailVerificationToken",
+		"test_verification_token",
+	).Return(s.testUserID, nil)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(testUser, nil)
+	userServiceMock.On(
+		"SaveUser",
+		testUser,
+	).Return(nil)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.VerifyEmail("test_verification_token")
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), user.EmailVerified)
+	assert.NotNil(s.T(), user.EmailVerifiedAt)
+
+	userServiceMock.AssertNumberOfCalls(s.T(), "SaveUser", 1)
+}
+
+func (s *authServiceSuite) TestVerifyEmail_InvalidToken() {
+	authService := s.authService
+
+	authManagerMock := new(authManagerMock)
+	authManagerMock.On(
+		"ConsumeEmailVerificationToken",
+		mock.Anything,
+	).Return(uuid.Nil, auth.ErrEmailVerificationTokenInvalid)
+
+	userServiceMock := new(userServiceMock)
+
+	authService.authManager = authManagerMock
+	authService.userService = userServiceMock
+
+	user, err := authService.VerifyEmail("test_verification_token")
+
+	assert.Nil(s.T(), user)
+	assert.Equal(s.T(), auth.ErrEmailVerificationTokenInvalid, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *authServiceSuite) TestResendVerificationEmail_AlreadyVerified() {
+	authService := s.authService
+
+	testUser := &models.UserModel{
+		BaseModel:     models.BaseModel{ID: s.testUserID},
+		Email:         s.testEmail,
+		EmailVerified: true,
+	}
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByEmail",
+		s.testEmail,
+	).Return(testUser, nil)
+
+	mailerMock := new(mailerMock)
+
+	authService.userService = userServiceMock
+	authService.mailer = mailerMock
+
+	err := authService.ResendVerificationEmail(s.testEmail)
+
+	assert.Nil(s.T(), err)
+	mailerMock.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *authServiceSuite) TestCheckCanSendMessages() {
+	authService := s.authService
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On(
+		"GetUserByID",
+		s.testUserID,
+	).Return(s.testUser, nil)
+
+	authService.userService = userServiceMock
+
+	assert.Nil(s.T(), authService.CheckCanSendMessages(s.testUserID))
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByID", mock.Anything)
+
+	os.Setenv("EMAIL_VERIFICATION_POLICY", string(auth.EmailVerificationRequiredForMessages))
+	defer os.Unsetenv("EMAIL_VERIFICATION_POLICY")
+
+	assert.Equal(s.T(), auth.ErrEmailNotVerified, authService.CheckCanSendMessages(s.testUserID))
 }
 
 func (s *authServiceSuite) TestSignUp_PasswordError() {
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/el-Mike/restrict"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrExplainSubjectInvalid - returned when explained subject is given by both user ID
+	// and role, or by none of them.
+	ErrExplainSubjectInvalid = errors.New("Either user ID or role has to be given.")
+
+	// ErrExplainWorkspaceRoleInvalid - returned when Workspace role is given for the subject
+	// given by user ID, or it's neither admin nor member.
+	ErrExplainWorkspaceRoleInvalid = errors.New("Workspace role can be given only together with role, and has to be admin or member.")
+
+	// ErrExplainWorkspaceNotFound - returned when explained request's Workspace does not exist.
+	ErrExplainWorkspaceNotFound = errors.New("Workspace not found.")
+
+	// ErrExplainWorkspaceMismatch - returned when explained resource is a Workspace other than
+	// the one the request is made in.
+	ErrExplainWorkspaceMismatch = errors.New("Workspace resource has to be the Workspace the request is made in.")
+
+	// ErrExplainWorkspaceAccessDenied - reason of denying the access to the subject, who cannot
+	// access explained request's Workspace.
+	ErrExplainWorkspaceAccessDenied = errors.New("Subject is not a member of the Workspace.")
+
+	// ErrExplainUserNotFound - returned when explained subject's user does not exist.
+	ErrExplainUserNotFound = errors.New("User not found.")
+
+	// ErrExplainResourceNotFound - returned when explained resource does not exist.
+	ErrExplainResourceNotFound = errors.New("Resource not found.")
+
+	// ErrExplainResourceNotSupported - returned when explained resource of given type
+	// cannot be loaded by its ID.
+	ErrExplainResourceNotSupported = errors.New("Resource of this type cannot be loaded by ID.")
+)
+
+// ExplainSubject - the subject explained access is checked for - either the user with given ID,
+// or any user with given role.
+type ExplainSubject struct {
+	UserID *uuid.UUID
+	Role   string
+
+	// WorkspaceID - the Workspace explained request is made in. If it's nil, the default
+	// Workspace is used, unless the resource is a Workspace itself.
+	WorkspaceID *uuid.UUID
+
+	// WorkspaceRole - role within the Workspace assumed for the subject given by role. If it's
+	// empty, such subject is not a member of the Workspace.
+	WorkspaceRole string
+}
+
+type authorizationExplainer interface {
+	Explain(subject *control.ContextUser, resource restrict.Resource, action string) (*control.AuthorizationExplanation, error)
+}
+
+// AuthorizationService - struct for handling authorization debugging related logic.
+type AuthorizationService struct {
+	broker              persist.DBBroker
+	conversationService *ConversationService
+	explainer           authorizationExplainer
+}
+
+// NewAuthorizationService - AuthorizationService constructor func.
+func NewAuthorizationService() *AuthorizationService {
+	return &AuthorizationService{
+		broker:              persist.GormBroker,
+		conversationService: NewConversationService(),
+		explainer:           control.NewAuthorizationExplainer(),
+	}
+}
+
+// Explain - explains whether given action on the resource of given type is permitted to
+// given subject. If resource ID is nil, the resource is not loaded, so conditions depending
+// on its fields cannot be satisfied. Access is checked within a Workspace, the same way
+// HandlerCreator checks it - subjects who cannot access the Workspace are denied, whatever
+// their roles grant. Returns the subject the access has been checked for as well.
+func (as *AuthorizationService) Explain(
+	explainSubject *ExplainSubject,
+	resourceType string,
+	resourceID *uuid.UUID,
+	action string,
+) (*control.AuthorizationExplanation, *control.ContextUser, error) {
+	subject, err := as.getSubject(explainSubject)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workspaceID := explainSubject.WorkspaceID
+
+	// Workspace routes are made in the Workspace they manage.
+	if resourceType == models.WORKSPACE_RESOURCE && resourceID != nil {
+		if workspaceID != nil && *workspaceID != *resourceID {
+			return nil, nil, ErrExplainWorkspaceMismatch
+		}
+
+		workspaceID = resourceID
+	}
+
+	canAccessWorkspace, err := as.resolveWorkspace(subject, workspaceID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resource restrict.Resource = restrict.UseResource(resourceType)
+
+	if resourceID != nil {
+		if resource, err = as.loadResource(subject.WorkspaceID, resourceType, *resourceID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	explanation, err := as.explainer.Explain(subject, resource, action)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !canAccessWorkspace {
+		explanation.Allowed = false
+		explanation.Reason = ErrExplainWorkspaceAccessDenied.Error()
+	}
+
+	return explanation, subject, nil
+}
+
+// getSubject - returns ContextUser of the user with given ID, or of a user with given role.
+func (as *AuthorizationService) getSubject(explainSubject *ExplainSubject) (*control.ContextUser, error) {
+	userID, role, workspaceRole := explainSubject.UserID, explainSubject.Role, explainSubject.WorkspaceRole
+
+	if (userID == nil) == (role == "") {
+		return nil, ErrExplainSubjectInvalid
+	}
+
+	if workspaceRole != "" && userID != nil {
+		return nil, ErrExplainWorkspaceRoleInvalid
+	}
+
+	if workspaceRole != "" && workspaceRole != models.WorkspaceAdminRole && workspaceRole != models.WorkspaceMemberRole {
+		return nil, ErrExplainWorkspaceRoleInvalid
+	}
+
+	if userID == nil {
+		return &control.ContextUser{Role: role, WorkspaceRole: workspaceRole}, nil
+	}
+
+	user := &models.UserModel{}
+
+	if err := as.broker.First(user, *userID).Err(); err != nil {
+		return nil, ErrExplainUserNotFound
+	}
+
+	return &control.ContextUser{
+		ID:    user.ID,
+		Email: user.Email,
+		Role:  user.Role,
+	}, nil
+}
+
+// resolveWorkspace - sets the Workspace with given ID (or the default one, if ID is nil) on
+// the subject, together with subject's role within it, the same way WorkspaceGuard does.
+// Returns false if the subject cannot access the Workspace.
+func (as *AuthorizationService) resolveWorkspace(subject *control.ContextUser, workspaceID *uuid.UUID) (bool, error) {
+	workspace := &models.WorkspaceModel{}
+
+	if workspaceID == nil {
+		err := as.broker.FirstWhere(workspace, &models.WorkspaceModel{Slug: models.DefaultWorkspaceSlug}).Err()
+
+		if err != nil {
+			return false, err
+		}
+	} else if err := as.broker.First(workspace, *workspaceID).Err(); err != nil {
+		return false, ErrExplainWorkspaceNotFound
+	}
+
+	subject.WorkspaceID = workspace.ID
+
+	if subject.ID != uuid.Nil {
+		member := &models.WorkspaceMemberModel{}
+
+		err := as.broker.FirstWhere(member, &models.WorkspaceMemberModel{
+			WorkspaceID: workspace.ID,
+			UserID:      subject.ID,
+		}).Err()
+
+		if err == nil {
+			subject.WorkspaceRole = member.Role
+		}
+	}
+
+	return subject.WorkspaceRole != "" || subject.Role == control.SuperAdminRole, nil
+}
+
+// loadResource - returns the resource of given type with given ID, loaded from given Workspace
+// the same way routes' ResourceProviders load it.
+func (as *AuthorizationService) loadResource(
+	workspaceID uuid.UUID,
+	resourceType string,
+	resourceID uuid.UUID,
+) (restrict.Resource, error) {
+	broker := as.broker.InWorkspace(workspaceID)
+	conversationService := as.conversationService.InWorkspace(workspaceID)
+
+	switch resourceType {
+	case models.CONVERSATION_RESOURCE:
+		conversation, err := conversationService.GetConversationByID(resourceID)
+
+		if err != nil {
+			return nil, ErrExplainResourceNotFound
+		}
+
+		return conversation, nil
+	case models.MESSAGE_RESOURCE:
+		message := &models.MessageModel{}
+
+		if err := broker.First(message, resourceID).Err(); err != nil {
+			return nil, ErrExplainResourceNotFound
+		}
+
+		conversation, err := conversationService.GetConversationByID(message.ConversationID)
+
+		if err != nil {
+			return nil, ErrExplainResourceNotFound
+		}
+
+		message.Conversation = conversation
+
+		return message, nil
+	case models.USER_RESOURCE:
+		user := &models.UserModel{}
+
+		if err := broker.First(user, resourceID).Err(); err != nil {
+			return nil, ErrExplainResourceNotFound
+		}
+
+		return user, nil
+	case models.PERSONAL_ACCESS_TOKEN_RESOURCE:
+		token := &models.PersonalAccessTokenModel{}
+
+		if err := as.broker.First(token, resourceID).Err(); err != nil {
+			return nil, ErrExplainResourceNotFound
+		}
+
+		return token, nil
+	case models.WORKSPACE_RESOURCE:
+		workspace := &models.WorkspaceModel{}
+
+		if err := as.broker.First(workspace, resourceID).Err(); err != nil {
+			return nil, ErrExplainResourceNotFound
+		}
+
+		return workspace, nil
+	default:
+		return nil, ErrExplainResourceNotSupported
+	}
+}
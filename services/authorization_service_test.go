@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type authorizationServiceSuite struct {
+	suite.Suite
+	authorizationService *AuthorizationService
+	testUserID           uuid.UUID
+	testMessageID        uuid.UUID
+	testConversationID   uuid.UUID
+	testWorkspaceID      uuid.UUID
+}
+
+func (s *authorizationServiceSuite) SetupSuite() {
+	s.testUserID = uuid.New()
+	s.testMessageID = uuid.New()
+	s.testConversationID = uuid.New()
+	s.testWorkspaceID = uuid.New()
+}
+
+func (s *authorizationServiceSuite) SetupTest() {
+	gormMock := mocks.NewGormMock()
+	s.mockWorkspace(gormMock, "")
+
+	s.authorizationService = &AuthorizationService{
+		broker:              gormMock,
+		conversationService: &ConversationService{broker: gormMock},
+		explainer:           control.NewAuthorizationExplainer(),
+	}
+}
+
+func TestAuthorizationServiceSuite(t *testing.T) {
+	suite.Run(t, new(authorizationServiceSuite))
+}
+
+// mockWorkspace - makes given broker return test Workspace as the default one, with test user
+// as its member with given role, or not a member at all if the role is empty.
+func (s *authorizationServiceSuite) mockWorkspace(gormMock *mocks.GormMock, memberRole string) {
+	gormMock.On("InWorkspace", s.testWorkspaceID).Return(gormMock)
+	gormMock.On(
+		"FirstWhere",
+		mock.AnythingOfType("*models.WorkspaceModel"),
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.WorkspaceModel).ID = s.testWorkspaceID
+	})
+
+	memberResponse := mocks.GetErrorDBResponse(errors.New("record not found"))
+
+	if memberRole != "" {
+		memberResponse = mocks.GetDefaultDBResponse()
+	}
+
+	gormMock.On(
+		"FirstWhere",
+		mock.AnythingOfType("*models.WorkspaceMemberModel"),
+		mock.Anything,
+		mock.Anything,
+	).Return(memberResponse).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.WorkspaceMemberModel).Role = memberRole
+	})
+}
+
+// setupMessage - makes the broker return test user, with USER role, and test message created
+// by them, in a conversation test user is a member of.
+func (s *authorizationServiceSuite) setupMessage() *mocks.GormMock {
+	gormMock := new(mocks.GormMock)
+	gormMock.On("First", mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse()).Run(
+		func(args mock.Arguments) {
+			switch dest := args.Get(0).(type) {
+			case *models.UserModel:
+				dest.ID = s.testUserID
+				dest.Role = control.UserRole
+			case *models.MessageModel:
+				dest.ID = s.testMessageID
+				dest.CreatedBy = s.testUserID
+				dest.ConversationID = s.testConversationID
+			case *models.ConversationModel:
+				dest.ID = s.testConversationID
+			case *models.WorkspaceModel:
+				dest.ID = s.testWorkspaceID
+			}
+		},
+	)
+	gormMock.On("Find", mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse()).Run(
+		func(args mock.Arguments) {
+			participants := args.Get(0).(*[]*models.ConversationParticipantModel)
+
+			*participants = append(*participants, &models.ConversationParticipantModel{
+				ConversationID: s.testConversationID,
+				UserID:         s.testUserID,
+				Role:           models.ConversationMemberRole,
+			})
+		},
+	)
+
+	s.mockWorkspace(gormMock, models.WorkspaceMemberRole)
+
+	s.authorizationService.broker = gormMock
+	s.authorizationService.conversationService.broker = gormMock
+
+	return gormMock
+}
+
+func (s *authorizationServiceSuite) TestNewAuthorizationService() {
+	authorizationService := NewAuthorizationService()
+
+	assert.NotNil(s.T(), authorizationService)
+}
+
+func (s *authorizationServiceSuite) TestExplain_Role() {
+	explanation, subject, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: control.SuperAdminRole},
+		models.USER_RESOURCE,
+		nil,
+		control.DeleteAction,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), control.SuperAdminRole, subject.Role)
+	assert.True(s.T(), explanation.Allowed)
+
+	// Access is granted by the parent, which is reached through the inheritance path.
+	assert.Len(s.T(), explanation.Roles, 3)
+	assert.False(s.T(), explanation.Roles[0].Granted)
+	assert.Equal(s.T(), []string{control.ImpersonateAction}, explanation.Roles[0].OtherActions)
+	assert.True(s.T(), explanation.Roles[1].Granted)
+	assert.Equal(s.T(), []string{control.SuperAdminRole, control.AdminRole}, explanation.Roles[1].Path)
+	assert.Equal(s.T(), []string{control.UserRole}, explanation.Roles[1].Parents)
+}
+
+func (s *authorizationServiceSuite) TestExplain_Denied() {
+	explanation, _, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: control.UserRole},
+		models.POLICY_RESOURCE,
+		nil,
+		control.ReadAction,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), explanation.Allowed)
+	assert.NotEmpty(s.T(), explanation.Reason)
+	assert.Len(s.T(), explanation.Roles, 1)
+	assert.Empty(s.T(), explanation.Roles[0].Permissions)
+}
+
+func (s *authorizationServiceSuite) TestExplain_UnknownRole() {
+	explanation, _, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: "UNKNOWN"},
+		models.USER_RESOURCE,
+		nil,
+		control.ReadAction,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), explanation.Allowed)
+	assert.NotEmpty(s.T(), explanation.Roles[0].Error)
+}
+
+func (s *authorizationServiceSuite) TestExplain_Conditions() {
+	s.setupMessage()
+
+	explanation, subject, err := s.authorizationService.Explain(
+		&ExplainSubject{UserID: &s.testUserID},
+		models.MESSAGE_RESOURCE,
+		&s.testMessageID,
+		control.DeleteAction,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testUserID, subject.ID)
+	assert.True(s.T(), explanation.Allowed)
+
+	permissions := explanation.Roles[0].Permissions
+
+	// Message can be deleted by its creator or by conversation's moderator - test user
+	// is only the creator.
+	assert.Len(s.T(), permissions, 2)
+	assert.True(s.T(), permissions[0].Granted)
+	assert.True(s.T(), permissions[0].Conditions[0].Satisfied)
+	assert.Contains(s.T(), string(permissions[0].Conditions[0].Options), "isOwner")
+	assert.False(s.T(), permissions[1].Granted)
+	assert.False(s.T(), permissions[1].Conditions[0].Satisfied)
+	assert.NotEmpty(s.T(), permissions[1].Conditions[0].Reason)
+}
+
+func (s *authorizationServiceSuite) TestExplain_SubjectInvalid() {
+	_, _, err := s.authorizationService.Explain(&ExplainSubject{}, models.USER_RESOURCE, nil, control.ReadAction)
+
+	assert.Equal(s.T(), ErrExplainSubjectInvalid, err)
+
+	_, _, err = s.authorizationService.Explain(
+		&ExplainSubject{UserID: &s.testUserID, Role: control.UserRole},
+		models.USER_RESOURCE,
+		nil,
+		control.ReadAction,
+	)
+
+	assert.Equal(s.T(), ErrExplainSubjectInvalid, err)
+}
+
+func (s *authorizationServiceSuite) TestExplain_UserNotFound() {
+	gormMock := new(mocks.GormMock)
+	gormMock.On("First", mock.Anything, mock.Anything).Return(
+		mocks.GetErrorDBResponse(errors.New("record not found")),
+	)
+
+	s.authorizationService.broker = gormMock
+
+	_, _, err := s.authorizationService.Explain(
+		&ExplainSubject{UserID: &s.testUserID},
+		models.USER_RESOURCE,
+		nil,
+		control.ReadAction,
+	)
+
+	assert.Equal(s.T(), ErrExplainUserNotFound, err)
+}
+
+func (s *authorizationServiceSuite) TestExplain_ResourceNotSupported() {
+	_, _, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: control.UserRole},
+		models.POLICY_RESOURCE,
+		&s.testMessageID,
+		control.ReadAction,
+	)
+
+	assert.Equal(s.T(), ErrExplainResourceNotSupported, err)
+}
+
+func (s *authorizationServiceSuite) TestExplain_WorkspaceAdmin() {
+	gormMock := new(mocks.GormMock)
+	gormMock.On("First", mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse()).Run(
+		func(args mock.Arguments) {
+			switch dest := args.Get(0).(type) {
+			case *models.UserModel:
+				dest.ID = s.testUserID
+				dest.Role = control.UserRole
+			case *models.WorkspaceModel:
+				dest.ID = s.testWorkspaceID
+			}
+		},
+	)
+	s.mockWorkspace(gormMock, models.WorkspaceAdminRole)
+
+	s.authorizationService.broker = gormMock
+
+	explanation, subject, err := s.authorizationService.Explain(
+		&ExplainSubject{UserID: &s.testUserID},
+		models.WORKSPACE_RESOURCE,
+		&s.testWorkspaceID,
+		control.UpdateAction,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testWorkspaceID, subject.WorkspaceID)
+	assert.Equal(s.T(), models.WorkspaceAdminRole, subject.WorkspaceRole)
+	assert.True(s.T(), explanation.Allowed)
+	assert.True(s.T(), explanation.Roles[0].Permissions[0].Conditions[0].Satisfied)
+}
+
+func (s *authorizationServiceSuite) TestExplain_WorkspaceRole() {
+	explanation, subject, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: control.UserRole, WorkspaceRole: models.WorkspaceAdminRole},
+		models.USER_RESOURCE,
+		nil,
+		control.ReadAction,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testWorkspaceID, subject.WorkspaceID)
+	assert.True(s.T(), explanation.Allowed)
+
+	_, _, err = s.authorizationService.Explain(
+		&ExplainSubject{UserID: &s.testUserID, WorkspaceRole: models.WorkspaceAdminRole},
+		models.USER_RESOURCE,
+		nil,
+		control.ReadAction,
+	)
+
+	assert.Equal(s.T(), ErrExplainWorkspaceRoleInvalid, err)
+}
+
+func (s *authorizationServiceSuite) TestExplain_WorkspaceAccessDenied() {
+	explanation, _, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: control.UserRole},
+		models.CONVERSATION_RESOURCE,
+		nil,
+		control.CreateAction,
+	)
+
+	// Role grants the action, but the subject is not a member of the Workspace.
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), explanation.Allowed)
+	assert.Equal(s.T(), ErrExplainWorkspaceAccessDenied.Error(), explanation.Reason)
+	assert.True(s.T(), explanation.Roles[0].Granted)
+}
+
+func (s *authorizationServiceSuite) TestExplain_WorkspaceMismatch() {
+	otherWorkspaceID := uuid.New()
+
+	_, _, err := s.authorizationService.Explain(
+		&ExplainSubject{Role: control.UserRole, WorkspaceID: &otherWorkspaceID},
+		models.WORKSPACE_RESOURCE,
+		&s.testWorkspaceID,
+		control.ReadAction,
+	)
+
+	assert.Equal(s.T(), ErrExplainWorkspaceMismatch, err)
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrParticipantsNotFound - returned when some of the given participants do not exist.
+	ErrParticipantsNotFound = errors.New("Some of the participants do not exist.")
+
+	// ErrParticipantNotFound - returned when given user does not participate in the Conversation.
+	ErrParticipantNotFound = errors.New("User does not participate in the Conversation.")
+
+	// ErrConversationRoleInvalid - returned when assigning a role other than moderator or member.
+	ErrConversationRoleInvalid = errors.New("Participant's role has to be moderator or member.")
+
+	// ErrConversationOwnerRole - returned when changing the role of Conversation's owner,
+	// which is possible only by transferring the ownership.
+	ErrConversationOwnerRole = errors.New("Owner's role can be changed only by transferring the ownership.")
+)
+
+// ConversationService - struct for handling Conversation related logic.
+type ConversationService struct {
+	broker persist.DBBroker
+}
+
+// NewConversationService - ConversationService constructor func.
+func NewConversationService() *ConversationService {
+	return &ConversationService{
+		broker: persist.GormBroker,
+	}
+}
+
+// InWorkspace - returns ConversationService, which finds and saves only the Conversations
+// of given Workspace. Users outside of the Workspace cannot participate in them.
+func (cs *ConversationService) InWorkspace(workspaceID uuid.UUID) *ConversationService {
+	return &ConversationService{
+		broker: cs.broker.InWorkspace(workspaceID),
+	}
+}
+
+// GetConversationByID - returns single Conversation with given ID, together with its participants.
+func (cs *ConversationService) GetConversationByID(id uuid.UUID) (*models.ConversationModel, error) {
+	model := &models.ConversationModel{}
+
+	if err := cs.broker.First(model, id).Err(); err != nil {
+		return nil, err
+	}
+
+	var participants []*models.ConversationParticipantModel
+
+	if err := cs.broker.Find(&participants, &models.ConversationParticipantModel{ConversationID: id}).Err(); err != nil {
+		return nil, err
+	}
+
+	model.Participants = participants
+
+	return model, nil
+}
+
+// GetConversationsByUserID - returns all the Conversations given user participates in.
+func (cs *ConversationService) GetConversationsByUserID(userID uuid.UUID) ([]*models.ConversationModel, error) {
+	var memberships []*models.ConversationParticipantModel
+
+	if err := cs.broker.Find(&memberships, &models.ConversationParticipantModel{UserID: userID}).Err(); err != nil {
+		return nil, err
+	}
+
+	if len(memberships) == 0 {
+		return []*models.ConversationModel{}, nil
+	}
+
+	conversationIDs := make([]uuid.UUID, 0, len(memberships))
+
+	for _, membership := range memberships {
+		conversationIDs = append(conversationIDs, membership.ConversationID)
+	}
+
+	var conversations []*models.ConversationModel
+
+	if err := cs.broker.Find(&conversations, "id IN ?", conversationIDs).Err(); err != nil {
+		return nil, err
+	}
+
+	var participants []*models.ConversationParticipantModel
+
+	if err := cs.broker.Find(&participants, "conversation_id IN ?", conversationIDs).Err(); err != nil {
+		return nil, err
+	}
+
+	participantsByConversation := make(map[uuid.UUID][]*models.ConversationParticipantModel)
+
+	for _, participant := range participants {
+		participantsByConversation[participant.ConversationID] = append(
+			participantsByConversation[participant.ConversationID],
+			participant,
+		)
+	}
+
+	for _, conversation := range conversations {
+		conversation.Participants = participantsByConversation[conversation.ID]
+	}
+
+	return conversations, nil
+}
+
+// CreateConversation - creates a new Conversation between its creator and given participants.
+func (cs *ConversationService) CreateConversation(
+	creatorID uuid.UUID,
+	name string,
+	participantIDs []uuid.UUID,
+) (*models.ConversationModel, error) {
+	userIDs := uniqueIDs(append([]uuid.UUID{creatorID}, participantIDs...))
+
+	if err := cs.ensureUsersExist(userIDs); err != nil {
+		return nil, err
+	}
+
+	conversation := &models.ConversationModel{
+		Name: name,
+	}
+
+	conversation.CreatedBy = creatorID
+	conversation.UpdatedBy = creatorID
+
+	for _, userID := range userIDs {
+		role := models.ConversationMemberRole
+
+		if userID == creatorID {
+			role = models.ConversationOwnerRole
+		}
+
+		conversation.Participants = append(conversation.Participants, newParticipant(userID, role, creatorID))
+	}
+
+	if err := cs.broker.Save(conversation).Err(); err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// AddParticipants - adds given users to the Conversation. Users already participating
+// are omitted.
+func (cs *ConversationService) AddParticipants(
+	conversation *models.ConversationModel,
+	userIDs []uuid.UUID,
+	addedBy uuid.UUID,
+) error {
+	var newUserIDs []uuid.UUID
+
+	for _, userID := range uniqueIDs(userIDs) {
+		if !conversation.HasParticipant(userID) {
+			newUserIDs = append(newUserIDs, userID)
+		}
+	}
+
+	if len(newUserIDs) == 0 {
+		return nil
+	}
+
+	if err := cs.ensureUsersExist(newUserIDs); err != nil {
+		return err
+	}
+
+	for _, userID := range newUserIDs {
+		participant := newParticipant(userID, models.ConversationMemberRole, addedBy)
+		participant.ConversationID = conversation.ID
+
+		if err := cs.broker.Save(participant).Err(); err != nil {
+			return err
+		}
+
+		conversation.Participants = append(conversation.Participants, participant)
+	}
+
+	return nil
+}
+
+// RemoveParticipant - removes given user from the Conversation.
+func (cs *ConversationService) RemoveParticipant(conversation *models.ConversationModel, userID uuid.UUID) error {
+	for i, participant := range conversation.Participants {
+		if participant.UserID != userID {
+			continue
+		}
+
+		if err := cs.broker.DeleteByID(&models.ConversationParticipantModel{}, participant.ID).Err(); err != nil {
+			return err
+		}
+
+		conversation.Participants = append(conversation.Participants[:i], conversation.Participants[i+1:]...)
+
+		return nil
+	}
+
+	return nil
+}
+
+// RenameConversation - sets the name of the Conversation.
+func (cs *ConversationService) RenameConversation(conversation *models.ConversationModel, name string, updatedBy uuid.UUID) error {
+	conversation.Name = name
+	conversation.UpdatedBy = updatedBy
+
+	return cs.broker.Save(conversation).Err()
+}
+
+// SetParticipantRole - sets the role of given participant to moderator or member.
+// Owner's role cannot be changed this way.
+func (cs *ConversationService) SetParticipantRole(
+	conversation *models.ConversationModel,
+	userID uuid.UUID,
+	role string,
+	updatedBy uuid.UUID,
+) error {
+	if role != models.ConversationModeratorRole && role != models.ConversationMemberRole {
+		return ErrConversationRoleInvalid
+	}
+
+	participant := conversation.GetParticipant(userID)
+
+	if participant == nil {
+		return ErrParticipantNotFound
+	}
+
+	if participant.Role == models.ConversationOwnerRole {
+		return ErrConversationOwnerRole
+	}
+
+	return saveParticipantRole(cs.broker, participant, role, updatedBy)
+}
+
+// TransferOwnership - makes given participant the owner of the Conversation. Previous owner
+// becomes a moderator. Both roles are saved in a single transaction, so the Conversation never
+// ends up with two owners, nor without one.
+func (cs *ConversationService) TransferOwnership(
+	conversation *models.ConversationModel,
+	userID uuid.UUID,
+	updatedBy uuid.UUID,
+) error {
+	participant := conversation.GetParticipant(userID)
+
+	if participant == nil {
+		return ErrParticipantNotFound
+	}
+
+	owner := conversation.GetOwner()
+
+	if owner == participant {
+		return nil
+	}
+
+	participantRole := participant.Role
+
+	err := cs.broker.Transaction(func(broker persist.DBBroker) error {
+		if err := saveParticipantRole(broker, participant, models.ConversationOwnerRole, updatedBy); err != nil {
+			return err
+		}
+
+		if owner == nil {
+			return nil
+		}
+
+		return saveParticipantRole(broker, owner, models.ConversationModeratorRole, updatedBy)
+	})
+
+	// Rolled back new owner cannot stay the owner of loaded Conversation either.
+	if err != nil {
+		participant.Role = participantRole
+	}
+
+	return err
+}
+
+// DeleteConversationByID - deletes a Conversation with given ID. Participants
+// are removed by the database cascade.
+func (cs *ConversationService) DeleteConversationByID(id uuid.UUID) error {
+	return cs.broker.DeleteByID(&models.ConversationModel{}, id).Err()
+}
+
+// ensureUsersExist - returns ErrParticipantsNotFound if any of the given users does not exist.
+func (cs *ConversationService) ensureUsersExist(userIDs []uuid.UUID) error {
+	var users []*models.UserModel
+
+	if err := cs.broker.Find(&users, "id IN ?", userIDs).Err(); err != nil {
+		return err
+	}
+
+	if len(users) != len(userIDs) {
+		return ErrParticipantsNotFound
+	}
+
+	return nil
+}
+
+// saveParticipantRole - sets given role of the participant, using given broker.
+func saveParticipantRole(
+	broker persist.DBBroker,
+	participant *models.ConversationParticipantModel,
+	role string,
+	updatedBy uuid.UUID,
+) error {
+	previousRole := participant.Role
+
+	participant.Role = role
+	participant.UpdatedBy = updatedBy
+
+	if err := broker.Save(participant).Err(); err != nil {
+		participant.Role = previousRole
+
+		return err
+	}
+
+	return nil
+}
+
+func newParticipant(userID uuid.UUID, role string, createdBy uuid.UUID) *models.ConversationParticipantModel {
+	participant := &models.ConversationParticipantModel{
+		UserID: userID,
+		Role:   role,
+	}
+
+	participant.CreatedBy = createdBy
+	participant.UpdatedBy = createdBy
+
+	return participant
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		result = append(result, id)
+	}
+
+	return result
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type conversationServiceSuite struct {
+	suite.Suite
+	conversationService *ConversationService
+	testConversationID  uuid.UUID
+	testCreatorID       uuid.UUID
+	testParticipantID   uuid.UUID
+}
+
+func (s *conversationServiceSuite) SetupSuite() {
+	s.testConversationID = uuid.New()
+	s.testCreatorID = uuid.New()
+	s.testParticipantID = uuid.New()
+}
+
+func (s *conversationServiceSuite) SetupTest() {
+	s.conversationService = &ConversationService{
+		broker: mocks.NewGormMock(),
+	}
+}
+
+func TestConversationServiceSuite(t *testing.T) {
+	suite.Run(t, new(conversationServiceSuite))
+}
+
+func (s *conversationServiceSuite) getTestConversation() *models.ConversationModel {
+	conversation := &models.ConversationModel{
+		BaseModel: models.BaseModel{ID: s.testConversationID, CreatedBy: s.testCreatorID},
+	}
+
+	conversation.Participants = []*models.ConversationParticipantModel{
+		{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			ConversationID: s.testConversationID,
+			UserID:         s.testCreatorID,
+			Role:           models.ConversationOwnerRole,
+		},
+		{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			ConversationID: s.testConversationID,
+			UserID:         s.testParticipantID,
+			Role:           models.ConversationMemberRole,
+		},
+	}
+
+	return conversation
+}
+
+// fillUsers - returns mock's Run function, populating users slice with given number of entries.
+func fillUsers(count int) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		if users, ok := args.Get(0).(*[]*models.UserModel); ok {
+			for i := 0; i < count; i++ {
+				*users = append(*users, &models.UserModel{})
+			}
+		}
+	}
+}
+
+func (s *conversationServiceSuite) TestNewConversationService() {
+	conversationService := NewConversationService()
+
+	assert.NotNil(s.T(), conversationService)
+}
+
+func (s *conversationServiceSuite) TestGetConversationByID() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"First",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	conversation, err := conversationService.GetConversationByID(s.testConversationID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "First", 1)
+	gormMock.AssertNumberOfCalls(s.T(), "Find", 1)
+
+	assert.NotNil(s.T(), conversation)
+	assert.Nil(s.T(), err)
+}
+
+func (s *conversationServiceSuite) TestGetConversationByID_Error() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"First",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
+
+	conversationService.broker = gormMock
+
+	conversation, err := conversationService.GetConversationByID(s.testConversationID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "First", 1)
+	gormMock.AssertNumberOfCalls(s.T(), "Find", 0)
+
+	assert.Nil(s.T(), conversation)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *conversationServiceSuite) TestGetConversationsByUserID_NoMemberships() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	conversations, err := conversationService.GetConversationsByUserID(s.testCreatorID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Find", 1)
+
+	assert.Empty(s.T(), conversations)
+	assert.Nil(s.T(), err)
+}
+
+func (s *conversationServiceSuite) TestGetConversationsByUserID_Error() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
+
+	conversationService.broker = gormMock
+
+	conversations, err := conversationService.GetConversationsByUserID(s.testCreatorID)
+
+	assert.Nil(s.T(), conversations)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *conversationServiceSuite) TestCreateConversation() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Run(fillUsers(2)).Return(mocks.GetDefaultDBResponse())
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	conversation, err := conversationService.CreateConversation(
+		s.testCreatorID,
+		"test_conversation",
+		[]uuid.UUID{s.testParticipantID, s.testCreatorID},
+	)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Find", 1)
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), conversation)
+	assert.Equal(s.T(), s.testCreatorID, conversation.CreatedBy)
+	assert.ElementsMatch(s.T(), []uuid.UUID{s.testCreatorID, s.testParticipantID}, conversation.GetParticipantIDs())
+	assert.Equal(s.T(), models.ConversationOwnerRole, conversation.GetParticipantRole(s.testCreatorID))
+	assert.Equal(s.T(), models.ConversationMemberRole, conversation.GetParticipantRole(s.testParticipantID))
+}
+
+func (s *conversationServiceSuite) TestCreateConversation_MissingParticipants() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Run(fillUsers(1)).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	conversation, err := conversationService.CreateConversation(
+		s.testCreatorID,
+		"test_conversation",
+		[]uuid.UUID{s.testParticipantID},
+	)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 0)
+
+	assert.Nil(s.T(), conversation)
+	assert.Equal(s.T(), ErrParticipantsNotFound, err)
+}
+
+func (s *conversationServiceSuite) TestAddParticipants() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+	newUserID := uuid.New()
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Run(fillUsers(1)).Return(mocks.GetDefaultDBResponse())
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	err := conversationService.AddParticipants(
+		conversation,
+		[]uuid.UUID{s.testParticipantID, newUserID},
+		s.testCreatorID,
+	)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), conversation.HasParticipant(newUserID))
+	assert.Equal(s.T(), models.ConversationMemberRole, conversation.GetParticipantRole(newUserID))
+	assert.Len(s.T(), conversation.Participants, 3)
+}
+
+func (s *conversationServiceSuite) TestAddParticipants_AlreadyParticipating() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+
+	gormMock := new(mocks.GormMock)
+
+	conversationService.broker = gormMock
+
+	err := conversationService.AddParticipants(conversation, []uuid.UUID{s.testParticipantID}, s.testCreatorID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Find", 0)
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 0)
+
+	assert.Nil(s.T(), err)
+}
+
+func (s *conversationServiceSuite) TestRemoveParticipant() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+	participantEntryID := conversation.Participants[1].ID
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"DeleteByID",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	err := conversationService.RemoveParticipant(conversation, s.testParticipantID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+	gormMock.AssertCalled(s.T(), "DeleteByID", mock.Anything, participantEntryID)
+
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), conversation.HasParticipant(s.testParticipantID))
+}
+
+func (s *conversationServiceSuite) TestRenameConversation() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	err := conversationService.RenameConversation(conversation, "renamed_conversation", s.testCreatorID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "renamed_conversation", conversation.Name)
+
+	gormMock.AssertCalled(s.T(), "Save", conversation)
+}
+
+func (s *conversationServiceSuite) TestSetParticipantRole() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	err := conversationService.SetParticipantRole(
+		conversation,
+		s.testParticipantID,
+		models.ConversationModeratorRole,
+		s.testCreatorID,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), models.ConversationModeratorRole, conversation.GetParticipantRole(s.testParticipantID))
+
+	gormMock.AssertCalled(s.T(), "Save", conversation.Participants[1])
+}
+
+func (s *conversationServiceSuite) TestSetParticipantRole_Invalid() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+
+	gormMock := new(mocks.GormMock)
+
+	conversationService.broker = gormMock
+
+	err := conversationService.SetParticipantRole(
+		conversation,
+		s.testParticipantID,
+		models.ConversationOwnerRole,
+		s.testCreatorID,
+	)
+
+	assert.Equal(s.T(), ErrConversationRoleInvalid, err)
+
+	err = conversationService.SetParticipantRole(
+		conversation,
+		s.testCreatorID,
+		models.ConversationMemberRole,
+		s.testCreatorID,
+	)
+
+	assert.Equal(s.T(), ErrConversationOwnerRole, err)
+
+	err = conversationService.SetParticipantRole(conversation, uuid.New(), models.ConversationMemberRole, s.testCreatorID)
+
+	assert.Equal(s.T(), ErrParticipantNotFound, err)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 0)
+}
+
+func (s *conversationServiceSuite) TestTransferOwnership() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On("Transaction", mock.Anything).Return()
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	err := conversationService.TransferOwnership(conversation, s.testParticipantID, s.testCreatorID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), models.ConversationOwnerRole, conversation.GetParticipantRole(s.testParticipantID))
+	assert.Equal(s.T(), models.ConversationModeratorRole, conversation.GetParticipantRole(s.testCreatorID))
+	assert.Equal(s.T(), s.testParticipantID, conversation.GetOwner().UserID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Transaction", 1)
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 2)
+}
+
+func (s *conversationServiceSuite) TestTransferOwnership_SaveFailed() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+	saveErr := errors.New("save failed")
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On("Transaction", mock.Anything).Return()
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse()).Once()
+	gormMock.On("Save", mock.Anything).Return(mocks.GetErrorDBResponse(saveErr))
+
+	conversationService.broker = gormMock
+
+	err := conversationService.TransferOwnership(conversation, s.testParticipantID, s.testCreatorID)
+
+	// Transaction is rolled back, so previous owner stays the only one.
+	assert.Equal(s.T(), saveErr, err)
+	assert.Equal(s.T(), models.ConversationOwnerRole, conversation.GetParticipantRole(s.testCreatorID))
+	assert.NotEqual(s.T(), models.ConversationOwnerRole, conversation.GetParticipantRole(s.testParticipantID))
+	assert.Equal(s.T(), s.testCreatorID, conversation.GetOwner().UserID)
+}
+
+func (s *conversationServiceSuite) TestTransferOwnership_NotParticipant() {
+	conversationService := s.conversationService
+	conversation := s.getTestConversation()
+
+	gormMock := new(mocks.GormMock)
+
+	conversationService.broker = gormMock
+
+	err := conversationService.TransferOwnership(conversation, uuid.New(), s.testCreatorID)
+
+	assert.Equal(s.T(), ErrParticipantNotFound, err)
+	assert.Equal(s.T(), s.testCreatorID, conversation.GetOwner().UserID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 0)
+}
+
+func (s *conversationServiceSuite) TestDeleteConversationByID() {
+	conversationService := s.conversationService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"DeleteByID",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	conversationService.broker = gormMock
+
+	err := conversationService.DeleteConversationByID(s.testConversationID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+	gormMock.AssertCalled(s.T(), "DeleteByID", mock.Anything, s.testConversationID)
+
+	assert.Nil(s.T(), err)
+}
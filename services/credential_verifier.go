@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+)
+
+// CredentialVerifier - verifies user's login credentials. Returns the user they belong to,
+// or auth.ErrLoginCredentialsIncorrect if they are not valid for this verifier - the next
+// verifier of the chain is tried then.
+type CredentialVerifier interface {
+	VerifyCredentials(email, password string) (*models.UserModel, error)
+}
+
+// CredentialVerifierFunc - allows using ordinary functions as CredentialVerifiers.
+type CredentialVerifierFunc func(email, password string) (*models.UserModel, error)
+
+// VerifyCredentials - CredentialVerifier implementation.
+func (f CredentialVerifierFunc) VerifyCredentials(email, password string) (*models.UserModel, error) {
+	return f(email, password)
+}
+
+type ldapDirectory interface {
+	Authenticate(login, password string) (*auth.LDAPEntry, error)
+	URL() string
+	GroupRoles() map[string]string
+}
+
+// LDAPCredentialVerifier - verifies credentials with LDAP simple bind. Users are linked
+// with their directory entries, or provisioned from them, on the first login.
+type LDAPCredentialVerifier struct {
+	broker      persist.DBBroker
+	userService userService
+	directory   ldapDirectory
+}
+
+// NewLDAPCredentialVerifier - LDAPCredentialVerifier constructor func.
+func NewLDAPCredentialVerifier(directory *auth.LDAPDirectory) *LDAPCredentialVerifier {
+	return &LDAPCredentialVerifier{
+		broker:      persist.GormBroker,
+		userService: NewUserService(),
+		directory:   directory,
+	}
+}
+
+// VerifyCredentials - CredentialVerifier implementation.
+func (lv *LDAPCredentialVerifier) VerifyCredentials(email, password string) (*models.UserModel, error) {
+	entry, err := lv.directory.Authenticate(email, password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Email == "" {
+		return nil, fmt.Errorf("LDAP entry %s has no mail attribute", entry.DN)
+	}
+
+	identity := &externalIdentity{
+		Issuer:  lv.directory.URL(),
+		Subject: entry.DN,
+		Email:   entry.Email,
+		// Directory is managed by the organization, so its emails are trusted.
+		EmailVerified: true,
+		FirstName:     entry.FirstName,
+		LastName:      entry.LastName,
+		Groups:        entry.Groups,
+	}
+
+	return resolveExternalUser(lv.broker, lv.userService, identity, lv.directory.GroupRoles())
+}
+
+// newCredentialVerifiers - returns default credential verifier chain - Gochat's own passwords
+// are verified first, then LDAP directory's, if it's configured.
+func newCredentialVerifiers(authService *AuthService) []CredentialVerifier {
+	credentialVerifiers := []CredentialVerifier{
+		CredentialVerifierFunc(authService.verifyLocalCredentials),
+	}
+
+	if auth.DefaultLDAPDirectory != nil {
+		credentialVerifiers = append(credentialVerifiers, NewLDAPCredentialVerifier(auth.DefaultLDAPDirectory))
+	}
+
+	return credentialVerifiers
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ldapDirectoryMock struct {
+	mock.Mock
+}
+
+func (dm *ldapDirectoryMock) Authenticate(login, password string) (*auth.LDAPEntry, error) {
+	args := dm.Called(login, password)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.LDAPEntry), args.Error(1)
+}
+
+func (dm *ldapDirectoryMock) URL() string {
+	args := dm.Called()
+
+	return args.String(0)
+}
+
+func (dm *ldapDirectoryMock) GroupRoles() map[string]string {
+	args := dm.Called()
+
+	return args.Get(0).(map[string]string)
+}
+
+type credentialVerifierSuite struct {
+	suite.Suite
+	ldapVerifier *LDAPCredentialVerifier
+	testEntry    *auth.LDAPEntry
+}
+
+func (s *credentialVerifierSuite) SetupTest() {
+	s.ldapVerifier = &LDAPCredentialVerifier{
+		broker:      mocks.NewGormMock(),
+		userService: &userServiceMock{},
+		directory:   &ldapDirectoryMock{},
+	}
+
+	s.testEntry = &auth.LDAPEntry{
+		DN:        "uid=jdoe,ou=people,dc=example,dc=com",
+		Email:     "jdoe@example.com",
+		FirstName: "John",
+		LastName:  "Doe",
+		Groups:    []string{"gochat-admins"},
+	}
+}
+
+func TestCredentialVerifierSuite(t *testing.T) {
+	suite.Run(t, new(credentialVerifierSuite))
+}
+
+func (s *credentialVerifierSuite) TestLDAPVerifyCredentials() {
+	directoryMock := new(ldapDirectoryMock)
+	directoryMock.On("Authenticate", "jdoe@example.com", "test_password").Return(s.testEntry, nil)
+	directoryMock.On("URL").Return("ldap://ldap.example.com:389")
+	directoryMock.On("GroupRoles").Return(map[string]string{"gochat-admins": control.AdminRole})
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", "jdoe@example.com").Return(nil, errors.New("record not found"))
+	userServiceMock.On("SaveUser", mock.Anything).Return(nil)
+
+	s.ldapVerifier.broker = gormMock
+	s.ldapVerifier.userService = userServiceMock
+	s.ldapVerifier.directory = directoryMock
+
+	user, err := s.ldapVerifier.VerifyCredentials("jdoe@example.com", "test_password")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "jdoe@example.com", user.Email)
+	assert.Equal(s.T(), "John", user.FirstName)
+	assert.Equal(s.T(), control.AdminRole, user.Role)
+	assert.Empty(s.T(), user.Password)
+	assert.True(s.T(), user.EmailVerified)
+
+	gormMock.AssertCalled(s.T(), "Save", mock.MatchedBy(func(identity *models.ExternalIdentityModel) bool {
+		return identity.Issuer == "ldap://ldap.example.com:389" && identity.Subject == s.testEntry.DN
+	}))
+}
+
+func (s *credentialVerifierSuite) TestLDAPVerifyCredentials_Incorrect() {
+	directoryMock := new(ldapDirectoryMock)
+	directoryMock.On("Authenticate", mock.Anything, mock.Anything).Return(nil, auth.ErrLoginCredentialsIncorrect)
+
+	userServiceMock := new(userServiceMock)
+
+	s.ldapVerifier.userService = userServiceMock
+	s.ldapVerifier.directory = directoryMock
+
+	_, err := s.ldapVerifier.VerifyCredentials("jdoe@example.com", "wrong_password")
+
+	assert.Equal(s.T(), auth.ErrLoginCredentialsIncorrect, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByEmail", mock.Anything)
+}
+
+func (s *credentialVerifierSuite) TestLDAPVerifyCredentials_NoMail() {
+	s.testEntry.Email = ""
+
+	directoryMock := new(ldapDirectoryMock)
+	directoryMock.On("Authenticate", mock.Anything, mock.Anything).Return(s.testEntry, nil)
+
+	userServiceMock := new(userServiceMock)
+
+	s.ldapVerifier.userService = userServiceMock
+	s.ldapVerifier.directory = directoryMock
+
+	_, err := s.ldapVerifier.VerifyCredentials("jdoe", "test_password")
+
+	assert.NotNil(s.T(), err)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByEmail", mock.Anything)
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+)
+
+// ErrExternalEmailNotVerified - returned when external identity provider has not verified
+// user's email, so it cannot be used to link or provision Gochat account.
+var ErrExternalEmailNotVerified = errors.New("Email address has not been verified by identity provider.")
+
+// externalRoles - roles external groups can be mapped to, from the most privileged one.
+var externalRoles = []string{control.SuperAdminRole, control.AdminRole, control.UserRole}
+
+// externalIdentity - user's identity, confirmed by external identity provider or directory.
+type externalIdentity struct {
+	// Issuer and Subject - identify the user within given identity provider.
+	Issuer  string
+	Subject string
+
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+	Groups        []string
+}
+
+// resolveExternalUser - returns the user linked with given external identity. On the first login,
+// identity is linked to the user with the same, verified email, or the user is provisioned.
+// User's role is updated according to their groups, if groupRoles are not empty.
+func resolveExternalUser(
+	broker persist.DBBroker,
+	userService userService,
+	identity *externalIdentity,
+	groupRoles map[string]string,
+) (*models.UserModel, error) {
+	identityModel := &models.ExternalIdentityModel{}
+
+	err := broker.FirstWhere(identityModel, &models.ExternalIdentityModel{
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	}).Err()
+
+	if err == nil {
+		user, err := userService.GetUserByID(identityModel.UserID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if applyGroupRole(user, groupRoles, identity.Groups) {
+			if err := userService.SaveUser(user); err != nil {
+				return nil, err
+			}
+		}
+
+		return user, nil
+	}
+
+	// Unverified email could belong to anyone, so it cannot be trusted to link accounts.
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrExternalEmailNotVerified
+	}
+
+	user, err := userService.GetUserByEmail(identity.Email)
+
+	if err != nil {
+		user = &models.UserModel{
+			Email:     identity.Email,
+			FirstName: identity.FirstName,
+			LastName:  identity.LastName,
+			Role:      control.UserRole,
+		}
+	} else if !user.EmailVerified {
+		// Account with unverified email could have been registered by someone else,
+		// therefore its password cannot be trusted anymore.
+		user.Password = ""
+	}
+
+	if !user.EmailVerified {
+		setEmailVerified(user)
+	}
+
+	applyGroupRole(user, groupRoles, identity.Groups)
+
+	if err := userService.SaveUser(user); err != nil {
+		return nil, err
+	}
+
+	identityModel = &models.ExternalIdentityModel{
+		UserID:  user.ID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	}
+
+	identityModel.CreatedBy = user.ID
+	identityModel.UpdatedBy = user.ID
+
+	if err := broker.Save(identityModel).Err(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// applyGroupRole - sets user's role according to given groups. Role is left intact
+// if groups are not mapped to roles. Returns true if role has changed.
+func applyGroupRole(user *models.UserModel, groupRoles map[string]string, groups []string) bool {
+	if len(groupRoles) == 0 {
+		return false
+	}
+
+	role := mapGroupRole(groupRoles, groups)
+
+	if user.Role == role {
+		return false
+	}
+
+	user.Role = role
+
+	return true
+}
+
+// mapGroupRole - returns the most privileged role given groups are mapped to,
+// or control.UserRole if none of them is.
+func mapGroupRole(groupRoles map[string]string, groups []string) string {
+	mappedRoles := map[string]bool{}
+
+	for _, group := range groups {
+		if role, ok := groupRoles[group]; ok {
+			mappedRoles[role] = true
+		}
+	}
+
+	for _, role := range externalRoles {
+		if mappedRoles[role] {
+			return role
+		}
+	}
+
+	return control.UserRole
+}
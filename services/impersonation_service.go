@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"os"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrImpersonationNotAllowed - returned when given user cannot be impersonated by given actor.
+	ErrImpersonationNotAllowed = errors.New("User cannot be impersonated.")
+
+	// ErrImpersonatedUserNotFound - returned when the user to impersonate does not exist.
+	ErrImpersonatedUserNotFound = errors.New("User to impersonate not found.")
+)
+
+type impersonationManager interface {
+	Impersonate(user *models.UserModel, actor *auth.Actor, client *auth.ClientInfo, apiSecret string) (string, *auth.Session, error)
+}
+
+type impersonationAuditor interface {
+	RecordAction(action *models.ImpersonationActionModel) error
+}
+
+// ImpersonationService - struct for handling admin impersonation related logic.
+type ImpersonationService struct {
+	userService userService
+	authManager impersonationManager
+	auditor     impersonationAuditor
+}
+
+// NewImpersonationService - ImpersonationService constructor func.
+func NewImpersonationService() *ImpersonationService {
+	return &ImpersonationService{
+		userService: NewUserService(),
+		authManager: auth.NewAuthManager(),
+		auditor:     auth.NewImpersonationAuditor(),
+	}
+}
+
+// Impersonate - starts a session of the user with given ID, used by given actor. Returns the
+// access token of the session and the impersonated user. Super admins cannot be impersonated,
+// and impersonated sessions cannot start another impersonation. Given startAction, describing
+// the request starting the session, is recorded as its first action.
+func (is *ImpersonationService) Impersonate(
+	actor *control.ContextUser,
+	userID uuid.UUID,
+	startAction *models.ImpersonationActionModel,
+	client *auth.ClientInfo,
+) (string, *auth.Session, *models.UserModel, error) {
+	if actor.IsImpersonated() || actor.ID == userID {
+		return "", nil, nil, ErrImpersonationNotAllowed
+	}
+
+	apiSecret := os.Getenv("API_SECRET")
+
+	if apiSecret == "" && auth.DefaultKeySet.UsesSecret() {
+		return "", nil, nil, errors.New("Missing API Secret!")
+	}
+
+	user, err := is.userService.GetUserByID(userID)
+
+	if err != nil {
+		return "", nil, nil, ErrImpersonatedUserNotFound
+	}
+
+	if user.Role == control.SuperAdminRole {
+		return "", nil, nil, ErrImpersonationNotAllowed
+	}
+
+	token, session, err := is.authManager.Impersonate(user, &auth.Actor{
+		ID:    actor.ID,
+		Email: actor.Email,
+	}, client, apiSecret)
+
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	startAction.SessionID = session.ID
+	startAction.UserID = user.ID
+	startAction.ActorID = actor.ID
+	startAction.ActorEmail = actor.Email
+	startAction.IP = client.IP
+	startAction.UserAgent = client.UserAgent
+
+	if err := is.auditor.RecordAction(startAction); err != nil {
+		return "", nil, nil, err
+	}
+
+	return token, session, user, nil
+}
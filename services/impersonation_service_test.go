@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type impersonationManagerMock struct {
+	mock.Mock
+}
+
+func (im *impersonationManagerMock) Impersonate(
+	user *models.UserModel,
+	actor *auth.Actor,
+	client *auth.ClientInfo,
+	apiSecret string,
+) (string, *auth.Session, error) {
+	args := im.Called(user, actor, client, apiSecret)
+
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+
+	return args.String(0), args.Get(1).(*auth.Session), args.Error(2)
+}
+
+type impersonationAuditorMock struct {
+	mock.Mock
+}
+
+func (ia *impersonationAuditorMock) RecordAction(action *models.ImpersonationActionModel) error {
+	args := ia.Called(action)
+
+	return args.Error(0)
+}
+
+type impersonationServiceSuite struct {
+	suite.Suite
+	impersonationService *ImpersonationService
+	testActor            *control.ContextUser
+	testUser             *models.UserModel
+	testClient           *auth.ClientInfo
+}
+
+func (s *impersonationServiceSuite) SetupTest() {
+	s.impersonationService = &ImpersonationService{
+		userService: &userServiceMock{},
+		authManager: &impersonationManagerMock{},
+		auditor:     &impersonationAuditorMock{},
+	}
+
+	s.testActor = &control.ContextUser{
+		ID:    uuid.New(),
+		Email: "admin@gochat.com",
+		Role:  control.SuperAdminRole,
+	}
+	s.testUser = &models.UserModel{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "user@gochat.com",
+		Role:      control.UserRole,
+	}
+	s.testClient = &auth.ClientInfo{IP: "127.0.0.1", UserAgent: "test_user_agent"}
+
+	os.Setenv("API_SECRET", "test_secret")
+}
+
+func TestImpersonationServiceSuite(t *testing.T) {
+	suite.Run(t, new(impersonationServiceSuite))
+}
+
+func (s *impersonationServiceSuite) getStartAction() *models.ImpersonationActionModel {
+	return &models.ImpersonationActionModel{
+		Method: "POST",
+		Path:   "/api/admin/impersonate/" + s.testUser.ID.String(),
+		Status: 200,
+	}
+}
+
+func (s *impersonationServiceSuite) TestImpersonate() {
+	testSession := &auth.Session{
+		ID:        uuid.New(),
+		UserID:    s.testUser.ID,
+		ExpiresAt: time.Now().Add(auth.ImpersonationTTL),
+	}
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUser.ID).Return(s.testUser, nil)
+
+	managerMock := new(impersonationManagerMock)
+	managerMock.On(
+		"Impersonate",
+		s.testUser,
+		&auth.Actor{ID: s.testActor.ID, Email: s.testActor.Email},
+		s.testClient,
+		"test_secret",
+	).Return("test_token", testSession, nil)
+
+	auditorMock := new(impersonationAuditorMock)
+	auditorMock.On("RecordAction", mock.Anything).Return(nil)
+
+	s.impersonationService.userService = userServiceMock
+	s.impersonationService.authManager = managerMock
+	s.impersonationService.auditor = auditorMock
+
+	startAction := s.getStartAction()
+
+	token, session, user, err := s.impersonationService.Impersonate(s.testActor, s.testUser.ID, startAction, s.testClient)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "test_token", token)
+	assert.Equal(s.T(), testSession, session)
+	assert.Equal(s.T(), s.testUser, user)
+
+	auditorMock.AssertCalled(s.T(), "RecordAction", mock.MatchedBy(func(action *models.ImpersonationActionModel) bool {
+		return action == startAction &&
+			action.SessionID == testSession.ID &&
+			action.UserID == s.testUser.ID &&
+			action.ActorID == s.testActor.ID &&
+			action.ActorEmail == s.testActor.Email &&
+			action.IP == s.testClient.IP
+	}))
+}
+
+func (s *impersonationServiceSuite) TestImpersonate_SuperAdmin() {
+	s.testUser.Role = control.SuperAdminRole
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUser.ID).Return(s.testUser, nil)
+
+	managerMock := new(impersonationManagerMock)
+
+	s.impersonationService.userService = userServiceMock
+	s.impersonationService.authManager = managerMock
+
+	_, _, _, err := s.impersonationService.Impersonate(s.testActor, s.testUser.ID, s.getStartAction(), s.testClient)
+
+	assert.Equal(s.T(), ErrImpersonationNotAllowed, err)
+
+	managerMock.AssertNotCalled(s.T(), "Impersonate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *impersonationServiceSuite) TestImpersonate_Self() {
+	userServiceMock := new(userServiceMock)
+
+	s.impersonationService.userService = userServiceMock
+
+	_, _, _, err := s.impersonationService.Impersonate(s.testActor, s.testActor.ID, s.getStartAction(), s.testClient)
+
+	assert.Equal(s.T(), ErrImpersonationNotAllowed, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByID", mock.Anything)
+}
+
+func (s *impersonationServiceSuite) TestImpersonate_AlreadyImpersonated() {
+	s.testActor.Actor = &auth.Actor{ID: uuid.New(), Email: "other-admin@gochat.com"}
+
+	userServiceMock := new(userServiceMock)
+
+	s.impersonationService.userService = userServiceMock
+
+	_, _, _, err := s.impersonationService.Impersonate(s.testActor, s.testUser.ID, s.getStartAction(), s.testClient)
+
+	assert.Equal(s.T(), ErrImpersonationNotAllowed, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByID", mock.Anything)
+}
+
+func (s *impersonationServiceSuite) TestImpersonate_UserNotFound() {
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", mock.Anything).Return(nil, errors.New("record not found"))
+
+	s.impersonationService.userService = userServiceMock
+
+	_, _, _, err := s.impersonationService.Impersonate(s.testActor, s.testUser.ID, s.getStartAction(), s.testClient)
+
+	assert.Equal(s.T(), ErrImpersonatedUserNotFound, err)
+}
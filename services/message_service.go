@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+// ErrMessageNotFound - returned when Message does not exist in given Conversation.
+var ErrMessageNotFound = errors.New("Message not found.")
+
+// MessageService - struct for handling Message related logic.
+type MessageService struct {
+	broker persist.DBBroker
+}
+
+// NewMessageService - MessageService constructor func.
+func NewMessageService() *MessageService {
+	return &MessageService{
+		broker: persist.GormBroker,
+	}
+}
+
+// InWorkspace - returns MessageService, which finds and saves only the Messages
+// of given Workspace.
+func (ms *MessageService) InWorkspace(workspaceID uuid.UUID) *MessageService {
+	return &MessageService{
+		broker: ms.broker.InWorkspace(workspaceID),
+	}
+}
+
+// GetMessageByID - returns single Message with given ID.
+func (ms *MessageService) GetMessageByID(id uuid.UUID) (*models.MessageModel, error) {
+	model := &models.MessageModel{}
+
+	if err := ms.broker.First(model, id).Err(); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// GetMessagesByConversationID - returns single page of Messages sent in given Conversation,
+// together with the cursor of the next page.
+func (ms *MessageService) GetMessagesByConversationID(
+	conversationID uuid.UUID,
+	page *persist.PageRequest,
+) ([]*models.MessageModel, string, error) {
+	var messages []*models.MessageModel
+
+	res := ms.broker.FindPage(&messages, page, &models.MessageModel{ConversationID: conversationID})
+
+	if err := res.Err(); err != nil {
+		return nil, "", err
+	}
+
+	return messages, res.NextCursor(), nil
+}
+
+// CreateMessage - saves a new Message sent by given author in given Conversation.
+func (ms *MessageService) CreateMessage(conversationID, authorID uuid.UUID, body string) (*models.MessageModel, error) {
+	message := &models.MessageModel{
+		Body:           body,
+		ConversationID: conversationID,
+	}
+
+	message.CreatedBy = authorID
+	message.UpdatedBy = authorID
+
+	if err := ms.broker.Save(message).Err(); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// UpdateMessage - updates Message's body.
+func (ms *MessageService) UpdateMessage(message *models.MessageModel, body string, updatedBy uuid.UUID) error {
+	message.Body = body
+	message.UpdatedBy = updatedBy
+
+	// Associations (Conversation, Author) are not meant to be saved together with
+	// the Message, therefore only a shallow copy without them is passed to the broker.
+	update := *message
+	update.Conversation = nil
+	update.Author = nil
+
+	if err := ms.broker.Save(&update).Err(); err != nil {
+		return err
+	}
+
+	message.UpdatedAt = update.UpdatedAt
+
+	return nil
+}
+
+// DeleteMessageByID - deletes a Message with given ID.
+func (ms *MessageService) DeleteMessageByID(id uuid.UUID) error {
+	return ms.broker.DeleteByID(&models.MessageModel{}, id).Err()
+}
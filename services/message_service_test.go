@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type messageServiceSuite struct {
+	suite.Suite
+	messageService     *MessageService
+	testMessageID      uuid.UUID
+	testConversationID uuid.UUID
+	testAuthorID       uuid.UUID
+	testBody           string
+}
+
+func (s *messageServiceSuite) SetupSuite() {
+	s.testMessageID = uuid.New()
+	s.testConversationID = uuid.New()
+	s.testAuthorID = uuid.New()
+	s.testBody = "test_body"
+}
+
+func (s *messageServiceSuite) SetupTest() {
+	s.messageService = &MessageService{
+		broker: mocks.NewGormMock(),
+	}
+}
+
+func TestMessageServiceSuite(t *testing.T) {
+	suite.Run(t, new(messageServiceSuite))
+}
+
+func (s *messageServiceSuite) TestNewMessageService() {
+	messageService := NewMessageService()
+
+	assert.NotNil(s.T(), messageService)
+}
+
+func (s *messageServiceSuite) TestGetMessageByID() {
+	messageService := s.messageService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"First",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	messageService.broker = gormMock
+
+	message, err := messageService.GetMessageByID(s.testMessageID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "First", 1)
+
+	assert.NotNil(s.T(), message)
+	assert.Nil(s.T(), err)
+}
+
+func (s *messageServiceSuite) TestGetMessageByID_Error() {
+	messageService := s.messageService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"First",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
+
+	messageService.broker = gormMock
+
+	message, err := messageService.GetMessageByID(s.testMessageID)
+
+	assert.Nil(s.T(), message)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *messageServiceSuite) TestGetMessagesByConversationID() {
+	messageService := s.messageService
+
+	page := persist.NewPageRequest(2, "", persist.SortDescending)
+
+	response := mocks.GetDefaultDBResponse()
+	response.SetNextCursor("test_cursor")
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		page,
+		&models.MessageModel{ConversationID: s.testConversationID},
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		messages := args.Get(0).(*[]*models.MessageModel)
+
+		*messages = []*models.MessageModel{
+			{Body: "second"},
+			{Body: "first"},
+		}
+	}).Return(response)
+
+	messageService.broker = gormMock
+
+	messages, nextCursor, err := messageService.GetMessagesByConversationID(s.testConversationID, page)
+
+	gormMock.AssertNumberOfCalls(s.T(), "FindPage", 1)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), messages, 2)
+	assert.Equal(s.T(), "test_cursor", nextCursor)
+}
+
+func (s *messageServiceSuite) TestGetMessagesByConversationID_Error() {
+	messageService := s.messageService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
+
+	messageService.broker = gormMock
+
+	messages, _, err := messageService.GetMessagesByConversationID(
+		s.testConversationID,
+		persist.NewPageRequest(0, "", persist.SortDescending),
+	)
+
+	assert.Nil(s.T(), messages)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *messageServiceSuite) TestCreateMessage() {
+	messageService := s.messageService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	messageService.broker = gormMock
+
+	message, err := messageService.CreateMessage(s.testConversationID, s.testAuthorID, s.testBody)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testConversationID, message.ConversationID)
+	assert.Equal(s.T(), s.testAuthorID, message.CreatedBy)
+	assert.Equal(s.T(), s.testBody, message.Body)
+}
+
+func (s *messageServiceSuite) TestCreateMessage_Error() {
+	messageService := s.messageService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
+
+	messageService.broker = gormMock
+
+	message, err := messageService.CreateMessage(s.testConversationID, s.testAuthorID, s.testBody)
+
+	assert.Nil(s.T(), message)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *messageServiceSuite) TestUpdateMessage() {
+	messageService := s.messageService
+
+	message := &models.MessageModel{
+		BaseModel:    models.BaseModel{ID: s.testMessageID},
+		Body:         "old_body",
+		Conversation: &models.ConversationModel{},
+	}
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	messageService.broker = gormMock
+
+	err := messageService.UpdateMessage(message, s.testBody, s.testAuthorID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+
+	saved := gormMock.Calls[0].Arguments.Get(0).(*models.MessageModel)
+
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), saved.Conversation)
+	assert.Equal(s.T(), s.testBody, saved.Body)
+	assert.Equal(s.T(), s.testBody, message.Body)
+	assert.NotNil(s.T(), message.Conversation)
+}
+
+func (s *messageServiceSuite) TestDeleteMessageByID() {
+	messageService := s.messageService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"DeleteByID",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	messageService.broker = gormMock
+
+	err := messageService.DeleteMessageByID(s.testMessageID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+	gormMock.AssertCalled(s.T(), "DeleteByID", mock.Anything, s.testMessageID)
+
+	assert.Nil(s.T(), err)
+}
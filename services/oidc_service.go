@@ -0,0 +1,104 @@
+package services
+
+import (
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+)
+
+type oidcProvider interface {
+	AuthorizationURL(state, nonce, codeChallenge string) (string, error)
+	Exchange(code, codeVerifier string) (string, error)
+	VerifyIDToken(rawIDToken, nonce string) (*auth.OIDCClaims, error)
+	GroupRoles() map[string]string
+}
+
+type oidcStateManager interface {
+	CreateOIDCState(loginState *auth.OIDCLoginState) (string, error)
+	ConsumeOIDCState(state string) (*auth.OIDCLoginState, error)
+}
+
+// OIDCService - struct for handling OpenID Connect login related logic.
+type OIDCService struct {
+	broker      persist.DBBroker
+	userService userService
+	authManager oidcStateManager
+	provider    oidcProvider
+}
+
+// NewOIDCService - OIDCService constructor func.
+func NewOIDCService() *OIDCService {
+	oidcService := &OIDCService{
+		broker:      persist.GormBroker,
+		userService: NewUserService(),
+		authManager: auth.NewAuthManager(),
+	}
+
+	// Provider is assigned only when configured, so nil check on the interface works.
+	if auth.DefaultOIDCProvider != nil {
+		oidcService.provider = auth.DefaultOIDCProvider
+	}
+
+	return oidcService
+}
+
+// StartLogin - starts OIDC login, and returns identity provider's URL the user
+// should be redirected to.
+func (oidcs *OIDCService) StartLogin() (string, error) {
+	if oidcs.provider == nil {
+		return "", auth.ErrOIDCNotConfigured
+	}
+
+	loginState, err := auth.NewOIDCLoginState()
+
+	if err != nil {
+		return "", err
+	}
+
+	state, err := oidcs.authManager.CreateOIDCState(loginState)
+
+	if err != nil {
+		return "", err
+	}
+
+	return oidcs.provider.AuthorizationURL(state, loginState.Nonce, auth.PKCEChallenge(loginState.CodeVerifier))
+}
+
+// FinishLogin - finishes OIDC login started with given state, using authorization code
+// the identity provider redirected the user back with. Returns the user the identity
+// belongs to - it's linked to existing user by verified email, or provisioned.
+func (oidcs *OIDCService) FinishLogin(code, state string) (*models.UserModel, error) {
+	if oidcs.provider == nil {
+		return nil, auth.ErrOIDCNotConfigured
+	}
+
+	loginState, err := oidcs.authManager.ConsumeOIDCState(state)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := oidcs.provider.Exchange(code, loginState.CodeVerifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := oidcs.provider.VerifyIDToken(rawIDToken, loginState.Nonce)
+
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &externalIdentity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+		Groups:        claims.Groups,
+	}
+
+	return resolveExternalUser(oidcs.broker, oidcs.userService, identity, oidcs.provider.GroupRoles())
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type oidcProviderMock struct {
+	mock.Mock
+}
+
+func (pm *oidcProviderMock) AuthorizationURL(state, nonce, codeChallenge string) (string, error) {
+	args := pm.Called(state, nonce, codeChallenge)
+
+	return args.String(0), args.Error(1)
+}
+
+func (pm *oidcProviderMock) Exchange(code, codeVerifier string) (string, error) {
+	args := pm.Called(code, codeVerifier)
+
+	return args.String(0), args.Error(1)
+}
+
+func (pm *oidcProviderMock) VerifyIDToken(rawIDToken, nonce string) (*auth.OIDCClaims, error) {
+	args := pm.Called(rawIDToken, nonce)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.OIDCClaims), args.Error(1)
+}
+
+func (pm *oidcProviderMock) GroupRoles() map[string]string {
+	args := pm.Called()
+
+	return args.Get(0).(map[string]string)
+}
+
+type oidcStateManagerMock struct {
+	mock.Mock
+}
+
+func (sm *oidcStateManagerMock) CreateOIDCState(loginState *auth.OIDCLoginState) (string, error) {
+	args := sm.Called(loginState)
+
+	return args.String(0), args.Error(1)
+}
+
+func (sm *oidcStateManagerMock) ConsumeOIDCState(state string) (*auth.OIDCLoginState, error) {
+	args := sm.Called(state)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*auth.OIDCLoginState), args.Error(1)
+}
+
+type oidcServiceSuite struct {
+	suite.Suite
+	oidcService *OIDCService
+	loginState  *auth.OIDCLoginState
+	groupRoles  map[string]string
+}
+
+func (s *oidcServiceSuite) SetupSuite() {
+	s.loginState = &auth.OIDCLoginState{
+		Nonce:        "test-nonce",
+		CodeVerifier: "test-code-verifier",
+	}
+
+	s.groupRoles = map[string]string{
+		"gochat-admins": control.AdminRole,
+		"gochat-ops":    control.SuperAdminRole,
+	}
+}
+
+func (s *oidcServiceSuite) SetupTest() {
+	s.oidcService = &OIDCService{
+		broker:      mocks.NewGormMock(),
+		userService: &userServiceMock{},
+		authManager: &oidcStateManagerMock{},
+		provider:    &oidcProviderMock{},
+	}
+}
+
+func TestOIDCServiceSuite(t *testing.T) {
+	suite.Run(t, new(oidcServiceSuite))
+}
+
+func (s *oidcServiceSuite) getTestClaims(groups ...string) *auth.OIDCClaims {
+	return &auth.OIDCClaims{
+		Issuer:        "https://idp.example.com",
+		Subject:       "idp-user-1",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		GivenName:     "John",
+		FamilyName:    "Doe",
+		Groups:        groups,
+	}
+}
+
+// mockLogin - mocks successful state and authorization code verification, returning given claims.
+func (s *oidcServiceSuite) mockLogin(claims *auth.OIDCClaims, groupRoles map[string]string) {
+	stateManagerMock := new(oidcStateManagerMock)
+	stateManagerMock.On("ConsumeOIDCState", "test-state").Return(s.loginState, nil)
+
+	providerMock := new(oidcProviderMock)
+	providerMock.On("Exchange", "test-code", s.loginState.CodeVerifier).Return("test-id-token", nil)
+	providerMock.On("VerifyIDToken", "test-id-token", s.loginState.Nonce).Return(claims, nil)
+	providerMock.On("GroupRoles").Return(groupRoles)
+
+	s.oidcService.authManager = stateManagerMock
+	s.oidcService.provider = providerMock
+}
+
+func (s *oidcServiceSuite) mockIdentityNotFound(gormMock *mocks.GormMock) {
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+}
+
+func (s *oidcServiceSuite) TestStartLogin() {
+	stateManagerMock := new(oidcStateManagerMock)
+	stateManagerMock.On("CreateOIDCState", mock.Anything).Return("test-state", nil)
+
+	providerMock := new(oidcProviderMock)
+	providerMock.On(
+		"AuthorizationURL",
+		"test-state",
+		mock.Anything,
+		mock.Anything,
+	).Return("https://idp.example.com/authorize?state=test-state", nil)
+
+	s.oidcService.authManager = stateManagerMock
+	s.oidcService.provider = providerMock
+
+	authorizationURL, err := s.oidcService.StartLogin()
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "https://idp.example.com/authorize?state=test-state", authorizationURL)
+
+	// Code challenge has to be derived from the code verifier saved with the state.
+	loginState := stateManagerMock.Calls[0].Arguments.Get(0).(*auth.OIDCLoginState)
+
+	providerMock.AssertCalled(
+		s.T(),
+		"AuthorizationURL",
+		"test-state",
+		loginState.Nonce,
+		auth.PKCEChallenge(loginState.CodeVerifier),
+	)
+}
+
+func (s *oidcServiceSuite) TestStartLogin_NotConfigured() {
+	s.oidcService.provider = nil
+
+	_, err := s.oidcService.StartLogin()
+
+	assert.Equal(s.T(), auth.ErrOIDCNotConfigured, err)
+}
+
+func (s *oidcServiceSuite) TestFinishLogin_StateInvalid() {
+	stateManagerMock := new(oidcStateManagerMock)
+	stateManagerMock.On("ConsumeOIDCState", mock.Anything).Return(nil, auth.ErrOIDCStateInvalid)
+
+	providerMock := new(oidcProviderMock)
+
+	s.oidcService.authManager = stateManagerMock
+	s.oidcService.provider = providerMock
+
+	_, err := s.oidcService.FinishLogin("test-code", "test-state")
+
+	assert.Equal(s.T(), auth.ErrOIDCStateInvalid, err)
+
+	providerMock.AssertNotCalled(s.T(), "Exchange", mock.Anything, mock.Anything)
+}
+
+func (s *oidcServiceSuite) TestFinishLogin_ProvisionUser() {
+	s.mockLogin(s.getTestClaims("gochat-admins"), s.groupRoles)
+
+	gormMock := mocks.NewGormMock()
+	s.mockIdentityNotFound(gormMock)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", "john@example.com").Return(nil, errors.New("record not found"))
+	userServiceMock.On("SaveUser", mock.Anything).Return(nil)
+
+	s.oidcService.broker = gormMock
+	s.oidcService.userService = userServiceMock
+
+	user, err := s.oidcService.FinishLogin("test-code", "test-state")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "john@example.com", user.Email)
+	assert.Equal(s.T(), "John", user.FirstName)
+	assert.Equal(s.T(), "Doe", user.LastName)
+	assert.Equal(s.T(), control.AdminRole, user.Role)
+	assert.Empty(s.T(), user.Password)
+	assert.True(s.T(), user.EmailVerified)
+
+	userServiceMock.AssertCalled(s.T(), "SaveUser", user)
+	gormMock.AssertCalled(s.T(), "Save", mock.MatchedBy(func(identity *models.ExternalIdentityModel) bool {
+		return identity.Issuer == "https://idp.example.com" && identity.Subject == "idp-user-1"
+	}))
+}
+
+func (s *oidcServiceSuite) TestFinishLogin_LinkUserByEmail() {
+	s.mockLogin(s.getTestClaims(), map[string]string{})
+
+	gormMock := mocks.NewGormMock()
+	s.mockIdentityNotFound(gormMock)
+
+	testUser := &models.UserModel{
+		BaseModel:     models.BaseModel{ID: uuid.New()},
+		Email:         "john@example.com",
+		Password:      "test_hash",
+		Role:          control.AdminRole,
+		EmailVerified: true,
+	}
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", "john@example.com").Return(testUser, nil)
+	userServiceMock.On("SaveUser", mock.Anything).Return(nil)
+
+	s.oidcService.broker = gormMock
+	s.oidcService.userService = userServiceMock
+
+	user, err := s.oidcService.FinishLogin("test-code", "test-state")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testUser, user)
+	assert.Equal(s.T(), "test_hash", user.Password)
+
+	// Without group mapping, roles are managed in Gochat.
+	assert.Equal(s.T(), control.AdminRole, user.Role)
+
+	gormMock.AssertCalled(s.T(), "Save", mock.MatchedBy(func(identity *models.ExternalIdentityModel) bool {
+		return identity.UserID == testUser.ID && identity.Subject == "idp-user-1"
+	}))
+}
+
+func (s *oidcServiceSuite) TestFinishLogin_LinkUnverifiedUser() {
+	s.mockLogin(s.getTestClaims(), map[string]string{})
+
+	gormMock := mocks.NewGormMock()
+	s.mockIdentityNotFound(gormMock)
+
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "john@example.com",
+		Password:  "test_hash",
+		Role:      control.UserRole,
+	}
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByEmail", "john@example.com").Return(testUser, nil)
+	userServiceMock.On("SaveUser", mock.Anything).Return(nil)
+
+	s.oidcService.broker = gormMock
+	s.oidcService.userService = userServiceMock
+
+	user, err := s.oidcService.FinishLogin("test-code", "test-state")
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), user.EmailVerified)
+	assert.Empty(s.T(), user.Password)
+}
+
+func (s *oidcServiceSuite) TestFinishLogin_EmailNotVerified() {
+	claims := s.getTestClaims()
+	claims.EmailVerified = false
+
+	s.mockLogin(claims, map[string]string{})
+
+	gormMock := mocks.NewGormMock()
+	s.mockIdentityNotFound(gormMock)
+
+	userServiceMock := new(userServiceMock)
+
+	s.oidcService.broker = gormMock
+	s.oidcService.userService = userServiceMock
+
+	_, err := s.oidcService.FinishLogin("test-code", "test-state")
+
+	assert.Equal(s.T(), ErrExternalEmailNotVerified, err)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByEmail", mock.Anything)
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *oidcServiceSuite) TestFinishLogin_LinkedIdentity() {
+	testUser := &models.UserModel{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		Email:     "john.old@example.com",
+		Role:      control.AdminRole,
+	}
+
+	claims := s.getTestClaims("gochat-ops", "gochat-admins")
+	claims.EmailVerified = false
+
+	s.mockLogin(claims, s.groupRoles)
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		&models.ExternalIdentityModel{Issuer: claims.Issuer, Subject: claims.Subject},
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.ExternalIdentityModel).UserID = testUser.ID
+	})
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", testUser.ID).Return(testUser, nil)
+	userServiceMock.On("SaveUser", mock.Anything).Return(nil)
+
+	s.oidcService.broker = gormMock
+	s.oidcService.userService = userServiceMock
+
+	user, err := s.oidcService.FinishLogin("test-code", "test-state")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testUser.ID, user.ID)
+	assert.Equal(s.T(), control.SuperAdminRole, user.Role)
+
+	userServiceMock.AssertNotCalled(s.T(), "GetUserByEmail", mock.Anything)
+	userServiceMock.AssertNumberOfCalls(s.T(), "SaveUser", 1)
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *oidcServiceSuite) TestMapGroupRole() {
+	assert.Equal(s.T(), control.UserRole, mapGroupRole(s.groupRoles, []string{"staff"}))
+	assert.Equal(s.T(), control.UserRole, mapGroupRole(s.groupRoles, nil))
+	assert.Equal(s.T(), control.AdminRole, mapGroupRole(s.groupRoles, []string{"staff", "gochat-admins"}))
+	assert.Equal(s.T(), control.SuperAdminRole, mapGroupRole(s.groupRoles, []string{"gochat-admins", "gochat-ops"}))
+
+	// Groups mapped to unknown roles are ignored.
+	assert.Equal(s.T(), control.UserRole, mapGroupRole(map[string]string{"staff": "OWNER"}, []string{"staff"}))
+}
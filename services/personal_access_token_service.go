@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPersonalAccessTokenNotFound - returned when personal access token does not exist,
+	// or belongs to other user.
+	ErrPersonalAccessTokenNotFound = errors.New("Personal access token not found.")
+
+	// ErrPersonalAccessTokenScopeInvalid - returned when creating personal access token
+	// with unknown scopes.
+	ErrPersonalAccessTokenScopeInvalid = errors.New("Personal access token scopes are invalid.")
+)
+
+// PersonalAccessTokenService - struct for handling personal access tokens related logic.
+type PersonalAccessTokenService struct {
+	broker persist.DBBroker
+}
+
+// NewPersonalAccessTokenService - PersonalAccessTokenService constructor func.
+func NewPersonalAccessTokenService() *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		broker: persist.GormBroker,
+	}
+}
+
+// CreateToken - creates new personal access token for given user, with given scopes. Token
+// does not expire if expiresAt is nil. Returns the token itself as well - it cannot be read later.
+func (ps *PersonalAccessTokenService) CreateToken(
+	userID uuid.UUID,
+	name string,
+	scopes []string,
+	expiresAt *time.Time,
+) (*models.PersonalAccessTokenModel, string, error) {
+	normalizedScopes, err := normalizeScopes(scopes)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, hash, prefix, err := auth.NewPersonalAccessToken()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	tokenModel := &models.PersonalAccessTokenModel{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hash,
+		Prefix:    prefix,
+		Scopes:    strings.Join(normalizedScopes, " "),
+		ExpiresAt: expiresAt,
+	}
+
+	tokenModel.CreatedBy = userID
+	tokenModel.UpdatedBy = userID
+
+	if err := ps.broker.Save(tokenModel).Err(); err != nil {
+		return nil, "", err
+	}
+
+	return tokenModel, token, nil
+}
+
+// GetTokens - returns personal access tokens of given user.
+func (ps *PersonalAccessTokenService) GetTokens(userID uuid.UUID) ([]*models.PersonalAccessTokenModel, error) {
+	var tokens []*models.PersonalAccessTokenModel
+
+	if err := ps.broker.Find(&tokens, &models.PersonalAccessTokenModel{UserID: userID}).Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// RevokeToken - deletes given user's personal access token with given ID.
+func (ps *PersonalAccessTokenService) RevokeToken(userID, tokenID uuid.UUID) error {
+	tokenModel := &models.PersonalAccessTokenModel{}
+
+	err := ps.broker.FirstWhere(tokenModel, &models.PersonalAccessTokenModel{
+		BaseModel: models.BaseModel{ID: tokenID},
+		UserID:    userID,
+	}).Err()
+
+	if err != nil {
+		return ErrPersonalAccessTokenNotFound
+	}
+
+	return ps.broker.DeleteByID(models.PersonalAccessTokenModel{}, tokenModel.ID).Err()
+}
+
+// normalizeScopes - returns sorted, unique scopes, or ErrPersonalAccessTokenScopeInvalid
+// if any of them does not exist.
+func normalizeScopes(scopes []string) ([]string, error) {
+	unique := map[string]bool{}
+
+	for _, scope := range scopes {
+		if !control.IsValidScope(scope) {
+			return nil, ErrPersonalAccessTokenScopeInvalid
+		}
+
+		unique[scope] = true
+	}
+
+	if len(unique) == 0 {
+		return nil, ErrPersonalAccessTokenScopeInvalid
+	}
+
+	normalized := []string{}
+
+	for scope := range unique {
+		normalized = append(normalized, scope)
+	}
+
+	sort.Strings(normalized)
+
+	return normalized, nil
+}
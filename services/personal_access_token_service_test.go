@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type personalAccessTokenServiceSuite struct {
+	suite.Suite
+	tokenService *PersonalAccessTokenService
+	testUserID   uuid.UUID
+}
+
+func (s *personalAccessTokenServiceSuite) SetupTest() {
+	s.tokenService = &PersonalAccessTokenService{
+		broker: mocks.NewGormMock(),
+	}
+
+	s.testUserID = uuid.New()
+}
+
+func TestPersonalAccessTokenServiceSuite(t *testing.T) {
+	suite.Run(t, new(personalAccessTokenServiceSuite))
+}
+
+func (s *personalAccessTokenServiceSuite) TestCreateToken() {
+	gormMock := mocks.NewGormMock()
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	s.tokenService.broker = gormMock
+
+	tokenModel, token, err := s.tokenService.CreateToken(
+		s.testUserID,
+		"CI bot",
+		[]string{control.MessagesWriteScope, control.ConversationsReadScope, control.MessagesWriteScope},
+		nil,
+	)
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), auth.IsPersonalAccessToken(token))
+	assert.Equal(s.T(), s.testUserID, tokenModel.UserID)
+	assert.Equal(s.T(), "conversations:read messages:write", tokenModel.Scopes)
+	assert.NotEqual(s.T(), token, tokenModel.TokenHash)
+	assert.Contains(s.T(), token, tokenModel.Prefix)
+	assert.Nil(s.T(), tokenModel.ExpiresAt)
+
+	gormMock.AssertCalled(s.T(), "Save", tokenModel)
+}
+
+func (s *personalAccessTokenServiceSuite) TestCreateToken_InvalidScope() {
+	gormMock := mocks.NewGormMock()
+
+	s.tokenService.broker = gormMock
+
+	_, _, err := s.tokenService.CreateToken(s.testUserID, "CI bot", []string{control.MessagesReadScope, "admin"}, nil)
+
+	assert.Equal(s.T(), ErrPersonalAccessTokenScopeInvalid, err)
+
+	_, _, err = s.tokenService.CreateToken(s.testUserID, "CI bot", []string{}, nil)
+
+	assert.Equal(s.T(), ErrPersonalAccessTokenScopeInvalid, err)
+
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *personalAccessTokenServiceSuite) TestRevokeToken() {
+	tokenID := uuid.New()
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.PersonalAccessTokenModel).ID = tokenID
+	})
+	gormMock.On("DeleteByID", mock.Anything, tokenID).Return(mocks.GetDefaultDBResponse())
+
+	s.tokenService.broker = gormMock
+
+	err := s.tokenService.RevokeToken(s.testUserID, tokenID)
+
+	assert.Nil(s.T(), err)
+
+	gormMock.AssertCalled(s.T(), "FirstWhere", mock.Anything, &models.PersonalAccessTokenModel{
+		BaseModel: models.BaseModel{ID: tokenID},
+		UserID:    s.testUserID,
+	}, mock.Anything)
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+}
+
+func (s *personalAccessTokenServiceSuite) TestRevokeToken_NotFound() {
+	gormMock := mocks.NewGormMock()
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+
+	s.tokenService.broker = gormMock
+
+	err := s.tokenService.RevokeToken(s.testUserID, uuid.New())
+
+	assert.Equal(s.T(), ErrPersonalAccessTokenNotFound, err)
+
+	gormMock.AssertNotCalled(s.T(), "DeleteByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/el-Mike/restrict"
+)
+
+var (
+	// ErrPolicyRoleNotFound - returned when the role does not exist in the policy.
+	ErrPolicyRoleNotFound = errors.New("Role not found.")
+
+	// ErrPolicyRoleExists - returned when creating a role with ID which is already used.
+	ErrPolicyRoleExists = errors.New("Role already exists.")
+
+	// ErrPolicyRoleBuiltIn - returned when deleting one of control.BuiltInRoles.
+	ErrPolicyRoleBuiltIn = errors.New("Built-in role cannot be deleted.")
+
+	// ErrPolicyRoleInUse - returned when deleting a role assigned to some users.
+	ErrPolicyRoleInUse = errors.New("Role is assigned to users and cannot be deleted.")
+
+	// ErrPolicyPresetNotFound - returned when the permission preset does not exist in the policy.
+	ErrPolicyPresetNotFound = errors.New("Permission preset not found.")
+)
+
+type policyStorage interface {
+	LoadVersion() (*restrict.PolicyDefinition, int, error)
+	SavePolicy(policy *restrict.PolicyDefinition) error
+}
+
+type policyReloader interface {
+	Reload() error
+}
+
+// PolicyService - struct for handling RBAC policy management related logic. Every change
+// is validated, saved as the next version of the policy and applied on all the instances.
+type PolicyService struct {
+	broker   persist.DBBroker
+	storage  policyStorage
+	reloader policyReloader
+}
+
+// NewPolicyService - PolicyService constructor func.
+func NewPolicyService() *PolicyService {
+	return &PolicyService{
+		broker:   persist.GormBroker,
+		storage:  control.NewDBPolicyAdapter(),
+		reloader: control.DefaultPolicyReloader,
+	}
+}
+
+// GetPolicy - returns the latest version of the policy, together with its number.
+// Presets are not applied to returned definition.
+func (ps *PolicyService) GetPolicy() (*restrict.PolicyDefinition, int, error) {
+	return ps.storage.LoadVersion()
+}
+
+// GetRole - returns the role with given ID from the latest version of the policy.
+func (ps *PolicyService) GetRole(roleID string) (*restrict.Role, error) {
+	policy, _, err := ps.storage.LoadVersion()
+
+	if err != nil {
+		return nil, err
+	}
+
+	role := policy.Roles[roleID]
+
+	if role == nil {
+		return nil, ErrPolicyRoleNotFound
+	}
+
+	return role, nil
+}
+
+// CreateRole - adds given role to the policy.
+func (ps *PolicyService) CreateRole(role *restrict.Role) (*restrict.Role, error) {
+	return ps.updateRole(role.ID, func(policy *restrict.PolicyDefinition) error {
+		if policy.Roles[role.ID] != nil {
+			return ErrPolicyRoleExists
+		}
+
+		if role.Grants == nil {
+			role.Grants = restrict.GrantsMap{}
+		}
+
+		policy.Roles[role.ID] = role
+
+		return nil
+	})
+}
+
+// UpdateRole - sets description and parents of the role with given ID. Role's grants are kept.
+func (ps *PolicyService) UpdateRole(roleID, description string, parents []string) (*restrict.Role, error) {
+	return ps.updateRole(roleID, func(policy *restrict.PolicyDefinition) error {
+		role := policy.Roles[roleID]
+
+		if role == nil {
+			return ErrPolicyRoleNotFound
+		}
+
+		role.Description = description
+		role.Parents = parents
+
+		return nil
+	})
+}
+
+// SetGrants - replaces permissions the role with given ID has for given resource.
+// Passing no permissions revokes the access to the resource.
+func (ps *PolicyService) SetGrants(roleID, resourceID string, permissions restrict.Permissions) (*restrict.Role, error) {
+	return ps.updateRole(roleID, func(policy *restrict.PolicyDefinition) error {
+		role := policy.Roles[roleID]
+
+		if role == nil {
+			return ErrPolicyRoleNotFound
+		}
+
+		if role.Grants == nil {
+			role.Grants = restrict.GrantsMap{}
+		}
+
+		if len(permissions) == 0 {
+			delete(role.Grants, resourceID)
+		} else {
+			role.Grants[resourceID] = permissions
+		}
+
+		return nil
+	})
+}
+
+// DeleteRole - removes the role with given ID from the policy. Built-in roles and roles
+// assigned to any user cannot be deleted.
+func (ps *PolicyService) DeleteRole(roleID string) error {
+	for _, builtInRole := range control.BuiltInRoles {
+		if roleID == builtInRole {
+			return ErrPolicyRoleBuiltIn
+		}
+	}
+
+	if err := ps.broker.FirstWhere(&models.UserModel{}, &models.UserModel{Role: roleID}).Err(); err == nil {
+		return ErrPolicyRoleInUse
+	}
+
+	return ps.update(func(policy *restrict.PolicyDefinition) error {
+		if policy.Roles[roleID] == nil {
+			return ErrPolicyRoleNotFound
+		}
+
+		delete(policy.Roles, roleID)
+
+		return nil
+	})
+}
+
+// UpsertPreset - creates or replaces permission preset with given name.
+func (ps *PolicyService) UpsertPreset(name string, preset *restrict.Permission) error {
+	return ps.update(func(policy *restrict.PolicyDefinition) error {
+		if policy.PermissionPresets == nil {
+			policy.PermissionPresets = restrict.PermissionPresets{}
+		}
+
+		policy.PermissionPresets[name] = preset
+
+		return nil
+	})
+}
+
+// DeletePreset - removes permission preset with given name. Presets used by
+// any role cannot be deleted.
+func (ps *PolicyService) DeletePreset(name string) error {
+	return ps.update(func(policy *restrict.PolicyDefinition) error {
+		if policy.PermissionPresets[name] == nil {
+			return ErrPolicyPresetNotFound
+		}
+
+		delete(policy.PermissionPresets, name)
+
+		return nil
+	})
+}
+
+// updateRole - applies given change and returns changed role with given ID.
+func (ps *PolicyService) updateRole(
+	roleID string,
+	change func(policy *restrict.PolicyDefinition) error,
+) (*restrict.Role, error) {
+	var role *restrict.Role
+
+	err := ps.update(func(policy *restrict.PolicyDefinition) error {
+		if err := change(policy); err != nil {
+			return err
+		}
+
+		role = policy.Roles[roleID]
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// update - applies given change to the latest version of the policy. If changed policy
+// is valid, it's saved as the next version and reloaded on all the instances.
+func (ps *PolicyService) update(change func(policy *restrict.PolicyDefinition) error) error {
+	policy, _, err := ps.storage.LoadVersion()
+
+	if err != nil {
+		return err
+	}
+
+	if err := change(policy); err != nil {
+		return err
+	}
+
+	if err := control.ValidatePolicy(policy); err != nil {
+		return err
+	}
+
+	if err := ps.storage.SavePolicy(policy); err != nil {
+		return err
+	}
+
+	return ps.reloader.Reload()
+}
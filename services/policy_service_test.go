@@ -0,0 +1,255 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/restrict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type policyStorageMock struct {
+	mock.Mock
+}
+
+func (ps *policyStorageMock) LoadVersion() (*restrict.PolicyDefinition, int, error) {
+	args := ps.Called()
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).(*restrict.PolicyDefinition), args.Int(1), args.Error(2)
+}
+
+func (ps *policyStorageMock) SavePolicy(policy *restrict.PolicyDefinition) error {
+	args := ps.Called(policy)
+
+	return args.Error(0)
+}
+
+type policyReloaderMock struct {
+	mock.Mock
+}
+
+func (pr *policyReloaderMock) Reload() error {
+	args := pr.Called()
+
+	return args.Error(0)
+}
+
+type policyServiceSuite struct {
+	suite.Suite
+	policyService *PolicyService
+	storageMock   *policyStorageMock
+	reloaderMock  *policyReloaderMock
+	testPolicy    *restrict.PolicyDefinition
+}
+
+func (s *policyServiceSuite) SetupTest() {
+	testPolicy, err := control.ClonePolicy(control.Policy)
+
+	assert.Nil(s.T(), err)
+
+	s.testPolicy = testPolicy
+
+	s.storageMock = new(policyStorageMock)
+	s.storageMock.On("LoadVersion").Return(s.testPolicy, 1, nil)
+	s.storageMock.On("SavePolicy", mock.Anything).Return(nil)
+
+	s.reloaderMock = new(policyReloaderMock)
+	s.reloaderMock.On("Reload").Return(nil)
+
+	s.policyService = &PolicyService{
+		broker:   mocks.NewGormMock(),
+		storage:  s.storageMock,
+		reloader: s.reloaderMock,
+	}
+}
+
+func TestPolicyServiceSuite(t *testing.T) {
+	suite.Run(t, new(policyServiceSuite))
+}
+
+// getSavedPolicy - returns the policy passed to the storage.
+func (s *policyServiceSuite) getSavedPolicy() *restrict.PolicyDefinition {
+	return s.storageMock.Calls[1].Arguments.Get(0).(*restrict.PolicyDefinition)
+}
+
+// assertNotApplied - asserts that the change has been rejected as invalid, and the policy
+// has been neither saved nor reloaded.
+func (s *policyServiceSuite) assertNotApplied(err error) {
+	assert.IsType(s.T(), &control.PolicyInvalidError{}, err)
+
+	s.storageMock.AssertNotCalled(s.T(), "SavePolicy", mock.Anything)
+	s.reloaderMock.AssertNotCalled(s.T(), "Reload")
+}
+
+func (s *policyServiceSuite) TestCreateRole() {
+	role, err := s.policyService.CreateRole(&restrict.Role{
+		ID:          "MODERATOR",
+		Description: "Moderator can manage conversations.",
+		Parents:     []string{control.UserRole},
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "MODERATOR", role.ID)
+	assert.NotNil(s.T(), role.Grants)
+
+	assert.Equal(s.T(), role, s.getSavedPolicy().Roles["MODERATOR"])
+	s.reloaderMock.AssertCalled(s.T(), "Reload")
+}
+
+func (s *policyServiceSuite) TestCreateRole_Exists() {
+	_, err := s.policyService.CreateRole(&restrict.Role{ID: control.AdminRole})
+
+	assert.Equal(s.T(), ErrPolicyRoleExists, err)
+
+	s.storageMock.AssertNotCalled(s.T(), "SavePolicy", mock.Anything)
+}
+
+func (s *policyServiceSuite) TestCreateRole_UnknownParent() {
+	_, err := s.policyService.CreateRole(&restrict.Role{
+		ID:      "MODERATOR",
+		Parents: []string{"UNKNOWN"},
+	})
+
+	s.assertNotApplied(err)
+}
+
+func (s *policyServiceSuite) TestUpdateRole() {
+	role, err := s.policyService.UpdateRole(control.AdminRole, "Updated description.", []string{control.UserRole})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "Updated description.", role.Description)
+
+	// Grants are kept.
+	assert.Len(s.T(), s.getSavedPolicy().Roles[control.AdminRole].Grants[models.USER_RESOURCE], 4)
+}
+
+func (s *policyServiceSuite) TestUpdateRole_NotFound() {
+	_, err := s.policyService.UpdateRole("UNKNOWN", "", nil)
+
+	assert.Equal(s.T(), ErrPolicyRoleNotFound, err)
+}
+
+func (s *policyServiceSuite) TestUpdateRole_InheritanceCycle() {
+	_, err := s.policyService.UpdateRole(control.UserRole, "", []string{control.SuperAdminRole})
+
+	s.assertNotApplied(err)
+}
+
+func (s *policyServiceSuite) TestSetGrants() {
+	role, err := s.policyService.SetGrants(control.UserRole, models.USER_RESOURCE, restrict.Permissions{
+		&restrict.Permission{Action: control.ReadAction},
+		&restrict.Permission{Action: control.UpdateAction, Preset: control.AccessOwnPreset},
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), role.Grants[models.USER_RESOURCE], 2)
+
+	// Presets are saved as references, not applied.
+	assert.Equal(s.T(), control.AccessOwnPreset, role.Grants[models.USER_RESOURCE][1].Preset)
+}
+
+func (s *policyServiceSuite) TestSetGrants_Revoke() {
+	role, err := s.policyService.SetGrants(control.AdminRole, models.USER_RESOURCE, nil)
+
+	assert.Nil(s.T(), err)
+	assert.NotContains(s.T(), role.Grants, models.USER_RESOURCE)
+}
+
+func (s *policyServiceSuite) TestSetGrants_UnknownPreset() {
+	_, err := s.policyService.SetGrants(control.UserRole, models.USER_RESOURCE, restrict.Permissions{
+		&restrict.Permission{Action: control.ReadAction, Preset: "unknownPreset"},
+	})
+
+	s.assertNotApplied(err)
+}
+
+func (s *policyServiceSuite) TestSetGrants_MissingAction() {
+	_, err := s.policyService.SetGrants(control.UserRole, models.USER_RESOURCE, restrict.Permissions{
+		&restrict.Permission{},
+	})
+
+	s.assertNotApplied(err)
+}
+
+func (s *policyServiceSuite) TestSetGrants_PolicyAccessRevoked() {
+	_, err := s.policyService.SetGrants(control.SuperAdminRole, models.POLICY_RESOURCE, nil)
+
+	s.assertNotApplied(err)
+}
+
+func (s *policyServiceSuite) TestDeleteRole() {
+	s.testPolicy.Roles["MODERATOR"] = &restrict.Role{ID: "MODERATOR"}
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On("FirstWhere", mock.Anything, mock.Anything, mock.Anything).Return(
+		mocks.GetErrorDBResponse(errors.New("record not found")),
+	)
+
+	s.policyService.broker = gormMock
+
+	err := s.policyService.DeleteRole("MODERATOR")
+
+	assert.Nil(s.T(), err)
+	assert.NotContains(s.T(), s.getSavedPolicy().Roles, "MODERATOR")
+
+	gormMock.AssertCalled(s.T(), "FirstWhere", mock.Anything, &models.UserModel{Role: "MODERATOR"}, mock.Anything)
+}
+
+func (s *policyServiceSuite) TestDeleteRole_BuiltIn() {
+	err := s.policyService.DeleteRole(control.AdminRole)
+
+	assert.Equal(s.T(), ErrPolicyRoleBuiltIn, err)
+}
+
+func (s *policyServiceSuite) TestDeleteRole_InUse() {
+	gormMock := mocks.NewGormMock()
+	gormMock.On("FirstWhere", mock.Anything, mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	s.policyService.broker = gormMock
+
+	err := s.policyService.DeleteRole("MODERATOR")
+
+	assert.Equal(s.T(), ErrPolicyRoleInUse, err)
+
+	s.storageMock.AssertNotCalled(s.T(), "LoadVersion")
+}
+
+func (s *policyServiceSuite) TestUpsertPreset() {
+	preset := &restrict.Permission{
+		Conditions: restrict.Conditions{
+			&restrict.NotEmptyCondition{
+				ID: "hasCreator",
+				Value: &restrict.ValueDescriptor{
+					Source: restrict.ResourceField,
+					Field:  "CreatedBy",
+				},
+			},
+		},
+	}
+
+	err := s.policyService.UpsertPreset("hasCreator", preset)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), preset, s.getSavedPolicy().PermissionPresets["hasCreator"])
+}
+
+func (s *policyServiceSuite) TestDeletePreset_InUse() {
+	err := s.policyService.DeletePreset(control.AccessOwnPreset)
+
+	s.assertNotApplied(err)
+}
+
+func (s *policyServiceSuite) TestDeletePreset_NotFound() {
+	err := s.policyService.DeletePreset("unknownPreset")
+
+	assert.Equal(s.T(), ErrPolicyPresetNotFound, err)
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTwoFactorAlreadyEnabled - returned when enrolling two-factor authentication, which is already enabled.
+	ErrTwoFactorAlreadyEnabled = errors.New("Two-factor authentication is already enabled.")
+
+	// ErrTwoFactorNotEnabled - returned when managing two-factor authentication, which is not enabled.
+	ErrTwoFactorNotEnabled = errors.New("Two-factor authentication is not enabled.")
+
+	// ErrTwoFactorEnrollmentNotStarted - returned when confirming enrollment, which has not been started.
+	ErrTwoFactorEnrollmentNotStarted = errors.New("Two-factor authentication enrollment has not been started.")
+
+	// ErrTwoFactorRequired - returned when disabling two-factor authentication required for user's role.
+	ErrTwoFactorRequired = errors.New("Two-factor authentication is required for your role.")
+)
+
+type twoFactorManager interface {
+	VerifyTOTPCode(userID uuid.UUID, secret, code string) error
+	MarkSessionTwoFactorVerified(authUUID uuid.UUID) error
+}
+
+// TOTPEnrollment - TOTP secret generated for a user, together with its provisioning URI.
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// TwoFactorService - struct for handling two-factor authentication related logic.
+type TwoFactorService struct {
+	broker      persist.DBBroker
+	userService userService
+	authManager twoFactorManager
+}
+
+// NewTwoFactorService - TwoFactorService constructor func.
+func NewTwoFactorService() *TwoFactorService {
+	return &TwoFactorService{
+		broker:      persist.GormBroker,
+		userService: NewUserService(),
+		authManager: auth.NewAuthManager(),
+	}
+}
+
+// Enroll - starts TOTP enrollment of given user by generating new secret. Enrollment
+// has to be confirmed with a code generated from that secret.
+func (ts *TwoFactorService) Enroll(userID uuid.UUID) (*TOTPEnrollment, error) {
+	user, err := ts.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.UpdatedBy = user.ID
+
+	if err := ts.userService.SaveUser(user); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// Confirm - enables two-factor authentication of given user, if given code matches
+// the secret generated during enrollment. Session enrollment has been confirmed within
+// is marked as verified. Returns new recovery codes.
+func (ts *TwoFactorService) Confirm(userID, authUUID uuid.UUID, code string) ([]string, error) {
+	user, err := ts.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorEnrollmentNotStarted
+	}
+
+	if err := ts.authManager.VerifyTOTPCode(user.ID, user.TOTPSecret, code); err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.UpdatedBy = user.ID
+
+	if err := ts.userService.SaveUser(user); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := ts.replaceRecoveryCodes(user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.authManager.MarkSessionTwoFactorVerified(authUUID); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// Disable - disables two-factor authentication of given user, if given code is correct
+// and user's role does not require it.
+func (ts *TwoFactorService) Disable(userID uuid.UUID, code string) error {
+	user, err := ts.userService.GetUserByID(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if control.TwoFactorRequiredRoles[user.Role] {
+		return ErrTwoFactorRequired
+	}
+
+	if err := ts.VerifyCode(user, code); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.UpdatedBy = user.ID
+
+	if err := ts.userService.SaveUser(user); err != nil {
+		return err
+	}
+
+	return ts.deleteRecoveryCodes(user.ID)
+}
+
+// RegenerateRecoveryCodes - replaces recovery codes of given user, if given code is correct.
+func (ts *TwoFactorService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	user, err := ts.userService.GetUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.VerifyCode(user, code); err != nil {
+		return nil, err
+	}
+
+	return ts.replaceRecoveryCodes(user.ID)
+}
+
+// VerifyCode - checks given user's second factor, which can be either TOTP code,
+// or one of the recovery codes. Used recovery code is removed - if concurrent request
+// has removed it first, the code is treated as invalid.
+func (ts *TwoFactorService) VerifyCode(user *models.UserModel, code string) error {
+	if !user.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	err := ts.authManager.VerifyTOTPCode(user.ID, user.TOTPSecret, code)
+
+	if err != auth.ErrTwoFactorCodeInvalid {
+		return err
+	}
+
+	recoveryCode := &models.TOTPRecoveryCodeModel{}
+
+	err = ts.broker.FirstWhere(recoveryCode, &models.TOTPRecoveryCodeModel{
+		UserID:   user.ID,
+		CodeHash: auth.HashRecoveryCode(code),
+	}).Err()
+
+	if err != nil {
+		return auth.ErrTwoFactorCodeInvalid
+	}
+
+	res := ts.broker.DeleteByID(models.TOTPRecoveryCodeModel{}, recoveryCode.ID)
+
+	if res.Err() != nil {
+		return res.Err()
+	}
+
+	if res.RowsAffected() == 0 {
+		return auth.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes - removes current recovery codes of given user, and generates new ones.
+func (ts *TwoFactorService) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	if err := ts.deleteRecoveryCodes(userID); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hash := range hashes {
+		recoveryCode := &models.TOTPRecoveryCodeModel{
+			UserID:   userID,
+			CodeHash: hash,
+		}
+
+		recoveryCode.CreatedBy = userID
+
+		if err := ts.broker.Save(recoveryCode).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
+
+// deleteRecoveryCodes - removes all the recovery codes of given user.
+func (ts *TwoFactorService) deleteRecoveryCodes(userID uuid.UUID) error {
+	var recoveryCodes []*models.TOTPRecoveryCodeModel
+
+	if err := ts.broker.Find(&recoveryCodes, &models.TOTPRecoveryCodeModel{UserID: userID}).Err(); err != nil {
+		return err
+	}
+
+	for _, recoveryCode := range recoveryCodes {
+		if err := ts.broker.DeleteByID(models.TOTPRecoveryCodeModel{}, recoveryCode.ID).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/auth"
+	"github.com/el-Mike/gochat/core/control"
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type twoFactorManagerMock struct {
+	mock.Mock
+}
+
+func (tm *twoFactorManagerMock) VerifyTOTPCode(userID uuid.UUID, secret, code string) error {
+	args := tm.Called(userID, secret, code)
+
+	return args.Error(0)
+}
+
+func (tm *twoFactorManagerMock) MarkSessionTwoFactorVerified(authUUID uuid.UUID) error {
+	args := tm.Called(authUUID)
+
+	return args.Error(0)
+}
+
+type twoFactorServiceSuite struct {
+	suite.Suite
+	twoFactorService *TwoFactorService
+	testUserID       uuid.UUID
+	testAuthUUID     uuid.UUID
+	testSecret       string
+}
+
+func (s *twoFactorServiceSuite) SetupSuite() {
+	s.testUserID = uuid.New()
+	s.testAuthUUID = uuid.New()
+	s.testSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+}
+
+func (s *twoFactorServiceSuite) SetupTest() {
+	s.twoFactorService = &TwoFactorService{
+		broker:      mocks.NewGormMock(),
+		userService: &userServiceMock{},
+		authManager: &twoFactorManagerMock{},
+	}
+}
+
+func TestTwoFactorServiceSuite(t *testing.T) {
+	suite.Run(t, new(twoFactorServiceSuite))
+}
+
+func (s *twoFactorServiceSuite) getTestUser(enabled bool, role string) *models.UserModel {
+	return &models.UserModel{
+		BaseModel:   models.BaseModel{ID: s.testUserID},
+		Email:       "test_email@gochat.com",
+		Role:        role,
+		TOTPSecret:  s.testSecret,
+		TOTPEnabled: enabled,
+	}
+}
+
+func (s *twoFactorServiceSuite) TestEnroll() {
+	testUser := s.getTestUser(false, control.UserRole)
+	testUser.TOTPSecret = ""
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(testUser, nil)
+	userServiceMock.On("SaveUser", testUser).Return(nil)
+
+	s.twoFactorService.userService = userServiceMock
+
+	enrollment, err := s.twoFactorService.Enroll(s.testUserID)
+
+	assert.Nil(s.T(), err)
+	assert.NotEmpty(s.T(), enrollment.Secret)
+	assert.Equal(s.T(), enrollment.Secret, testUser.TOTPSecret)
+	assert.Contains(s.T(), enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+	assert.False(s.T(), testUser.TOTPEnabled)
+}
+
+func (s *twoFactorServiceSuite) TestEnroll_AlreadyEnabled() {
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(s.getTestUser(true, control.UserRole), nil)
+
+	s.twoFactorService.userService = userServiceMock
+
+	enrollment, err := s.twoFactorService.Enroll(s.testUserID)
+
+	assert.Nil(s.T(), enrollment)
+	assert.Equal(s.T(), ErrTwoFactorAlreadyEnabled, err)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *twoFactorServiceSuite) TestConfirm() {
+	testUser := s.getTestUser(false, control.AdminRole)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(testUser, nil)
+	userServiceMock.On("SaveUser", testUser).Return(nil)
+
+	authManagerMock := new(twoFactorManagerMock)
+	authManagerMock.On("VerifyTOTPCode", s.testUserID, s.testSecret, "123456").Return(nil)
+	authManagerMock.On("MarkSessionTwoFactorVerified", s.testAuthUUID).Return(nil)
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On("Find", mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse())
+	gormMock.On("Save", mock.Anything).Return(mocks.GetDefaultDBResponse())
+
+	s.twoFactorService.userService = userServiceMock
+	s.twoFactorService.authManager = authManagerMock
+	s.twoFactorService.broker = gormMock
+
+	recoveryCodes, err := s.twoFactorService.Confirm(s.testUserID, s.testAuthUUID, "123456")
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), recoveryCodes, auth.RecoveryCodesCount)
+	assert.True(s.T(), testUser.TOTPEnabled)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", auth.RecoveryCodesCount)
+	authManagerMock.AssertNumberOfCalls(s.T(), "MarkSessionTwoFactorVerified", 1)
+
+	// Only hashes of the recovery codes are stored.
+	saved := gormMock.Calls[1].Arguments.Get(0).(*models.TOTPRecoveryCodeModel)
+	assert.Equal(s.T(), auth.HashRecoveryCode(recoveryCodes[0]), saved.CodeHash)
+}
+
+func (s *twoFactorServiceSuite) TestConfirm_InvalidCode() {
+	testUser := s.getTestUser(false, control.UserRole)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(testUser, nil)
+
+	authManagerMock := new(twoFactorManagerMock)
+	authManagerMock.On("VerifyTOTPCode", mock.Anything, mock.Anything, mock.Anything).Return(auth.ErrTwoFactorCodeInvalid)
+
+	s.twoFactorService.userService = userServiceMock
+	s.twoFactorService.authManager = authManagerMock
+
+	recoveryCodes, err := s.twoFactorService.Confirm(s.testUserID, s.testAuthUUID, "000000")
+
+	assert.Nil(s.T(), recoveryCodes)
+	assert.Equal(s.T(), auth.ErrTwoFactorCodeInvalid, err)
+	assert.False(s.T(), testUser.TOTPEnabled)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *twoFactorServiceSuite) TestConfirm_NotStarted() {
+	testUser := s.getTestUser(false, control.UserRole)
+	testUser.TOTPSecret = ""
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(testUser, nil)
+
+	s.twoFactorService.userService = userServiceMock
+
+	_, err := s.twoFactorService.Confirm(s.testUserID, s.testAuthUUID, "123456")
+
+	assert.Equal(s.T(), ErrTwoFactorEnrollmentNotStarted, err)
+}
+
+func (s *twoFactorServiceSuite) TestDisable() {
+	testUser := s.getTestUser(true, control.UserRole)
+
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(testUser, nil)
+	userServiceMock.On("SaveUser", testUser).Return(nil)
+
+	authManagerMock := new(twoFactorManagerMock)
+	authManagerMock.On("VerifyTOTPCode", s.testUserID, s.testSecret, "123456").Return(nil)
+
+	recoveryCode := &models.TOTPRecoveryCodeModel{BaseModel: models.BaseModel{ID: uuid.New()}}
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On("Find", mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		dest := args.Get(0).(*[]*models.TOTPRecoveryCodeModel)
+		*dest = []*models.TOTPRecoveryCodeModel{recoveryCode}
+	})
+	gormMock.On("DeleteByID", mock.Anything, recoveryCode.ID).Return(mocks.GetDefaultDBResponse())
+
+	s.twoFactorService.userService = userServiceMock
+	s.twoFactorService.authManager = authManagerMock
+	s.twoFactorService.broker = gormMock
+
+	err := s.twoFactorService.Disable(s.testUserID, "123456")
+
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), testUser.TOTPEnabled)
+	assert.Empty(s.T(), testUser.TOTPSecret)
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+}
+
+func (s *twoFactorServiceSuite) TestDisable_RequiredForRole() {
+	userServiceMock := new(userServiceMock)
+	userServiceMock.On("GetUserByID", s.testUserID).Return(s.getTestUser(true, control.SuperAdminRole), nil)
+
+	authManagerMock := new(twoFactorManagerMock)
+
+	s.twoFactorService.userService = userServiceMock
+	s.twoFactorService.authManager = authManagerMock
+
+	err := s.twoFactorService.Disable(s.testUserID, "123456")
+
+	assert.Equal(s.T(), ErrTwoFactorRequired, err)
+	userServiceMock.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+}
+
+func (s *twoFactorServiceSuite) TestVerifyCode_RecoveryCode() {
+	testUser := s.getTestUser(true, control.UserRole)
+	recoveryCodeID := uuid.New()
+
+	authManagerMock := new(twoFactorManagerMock)
+	authManagerMock.On("VerifyTOTPCode", mock.Anything, mock.Anything, mock.Anything).Return(auth.ErrTwoFactorCodeInvalid)
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		&models.TOTPRecoveryCodeModel{UserID: s.testUserID, CodeHash: auth.HashRecoveryCode("abcde-fghjk")},
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.TOTPRecoveryCodeModel).ID = recoveryCodeID
+	})
+	gormMock.On("DeleteByID", mock.Anything, recoveryCodeID).Return(mocks.GetRowsAffectedDBResponse(1))
+
+	s.twoFactorService.authManager = authManagerMock
+	s.twoFactorService.broker = gormMock
+
+	err := s.twoFactorService.VerifyCode(testUser, "ABCDE-FGHJK")
+
+	assert.Nil(s.T(), err)
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+}
+
+func (s *twoFactorServiceSuite) TestVerifyCode_RecoveryCodeAlreadyUsed() {
+	testUser := s.getTestUser(true, control.UserRole)
+	recoveryCodeID := uuid.New()
+
+	authManagerMock := new(twoFactorManagerMock)
+	authManagerMock.On("VerifyTOTPCode", mock.Anything, mock.Anything, mock.Anything).Return(auth.ErrTwoFactorCodeInvalid)
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On("FirstWhere", mock.Anything, mock.Anything, mock.Anything).Return(mocks.GetDefaultDBResponse()).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.TOTPRecoveryCodeModel).ID = recoveryCodeID
+	})
+	gormMock.On("DeleteByID", mock.Anything, recoveryCodeID).Return(mocks.GetRowsAffectedDBResponse(0))
+
+	s.twoFactorService.authManager = authManagerMock
+	s.twoFactorService.broker = gormMock
+
+	err := s.twoFactorService.VerifyCode(testUser, "ABCDE-FGHJK")
+
+	assert.Equal(s.T(), auth.ErrTwoFactorCodeInvalid, err)
+}
+
+func (s *twoFactorServiceSuite) TestVerifyCode_Invalid() {
+	testUser := s.getTestUser(true, control.UserRole)
+
+	authManagerMock := new(twoFactorManagerMock)
+	authManagerMock.On("VerifyTOTPCode", mock.Anything, mock.Anything, mock.Anything).Return(auth.ErrTwoFactorCodeInvalid)
+
+	gormMock := mocks.NewGormMock()
+	gormMock.On("FirstWhere", mock.Anything, mock.Anything, mock.Anything).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+
+	s.twoFactorService.authManager = authManagerMock
+	s.twoFactorService.broker = gormMock
+
+	err := s.twoFactorService.VerifyCode(testUser, "000000")
+
+	assert.Equal(s.T(), auth.ErrTwoFactorCodeInvalid, err)
+	gormMock.AssertNotCalled(s.T(), "DeleteByID", mock.Anything, mock.Anything)
+}
@@ -1,14 +1,31 @@
 package services
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/el-Mike/gochat/models"
 	"github.com/el-Mike/gochat/persist"
 	"github.com/google/uuid"
 )
 
+// ErrUserNotFound - returned when the user to delete does not exist, or is not
+// a member of service's Workspace.
+var ErrUserNotFound = errors.New("User not found.")
+
+// UserFilter - criteria Users can be filtered by. Empty criteria are omitted.
+type UserFilter struct {
+	Role        string
+	EmailPrefix string
+	NamePrefix  string
+}
+
 // UserService - struct for handling User related logic.
 type UserService struct {
 	broker persist.DBBroker
+
+	// workspaceID - the Workspace new users join, or nil if they join the default one.
+	workspaceID *uuid.UUID
 }
 
 // NewUserService - UserService constructor func.
@@ -18,6 +35,15 @@ func NewUserService() *UserService {
 	}
 }
 
+// InWorkspace - returns UserService, which finds only the members of given Workspace.
+// New users saved with it join the Workspace.
+func (us *UserService) InWorkspace(workspaceID uuid.UUID) *UserService {
+	return &UserService{
+		broker:      us.broker.InWorkspace(workspaceID),
+		workspaceID: &workspaceID,
+	}
+}
+
 // GetUserByID = returns single User with given ID.
 func (us *UserService) GetUserByID(id uuid.UUID) (*models.UserModel, error) {
 	model := &models.UserModel{}
@@ -42,24 +68,109 @@ func (us *UserService) GetUserByEmail(email string) (*models.UserModel, error) {
 	return model, nil
 }
 
-// GetUsers - returns collection of users from DB.
-func (us *UserService) GetUsers() ([]*models.UserModel, error) {
+// GetUsers - returns single page of users matching given filter, together with
+// the cursor of the next page.
+func (us *UserService) GetUsers(filter *UserFilter, page *persist.PageRequest) ([]*models.UserModel, string, error) {
 	var users []*models.UserModel
 
-	err := us.broker.Find(&users).Err()
-	if err != nil {
-		return nil, err
+	query, args := filter.toQuery()
+
+	res := us.broker.FindPage(&users, page, query, args...)
+	if err := res.Err(); err != nil {
+		return nil, "", err
 	}
 
-	return users, nil
+	return users, res.NextCursor(), nil
 }
 
-// SaveUser - save single user to DB.
+// SaveUser - save single user to DB. New users become members of service's Workspace,
+// or of the default one, if service is not scoped.
 func (us *UserService) SaveUser(user *models.UserModel) error {
-	return us.broker.Save(user).Err()
+	isNew := user.ID == uuid.Nil
+
+	if err := us.broker.Save(user).Err(); err != nil {
+		return err
+	}
+
+	if !isNew {
+		return nil
+	}
+
+	return us.joinWorkspace(user)
 }
 
-// DeleteUserByID - deletes a User with given ID.
+// DeleteUserByID - deletes a User with given ID. Users are not owned by Workspaces - deleted
+// user is removed from all the Workspaces they are a member of, even if service finds only
+// the members of one of them.
 func (us *UserService) DeleteUserByID(id uuid.UUID) error {
-	return us.broker.DeleteByID(models.UserModel{}, id).Err()
+	res := us.broker.DeleteByID(models.UserModel{}, id)
+
+	if res.Err() != nil {
+		return res.Err()
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// joinWorkspace - makes given user a member of service's Workspace, or of the default one.
+func (us *UserService) joinWorkspace(user *models.UserModel) error {
+	member := newMember(user.ID, models.WorkspaceMemberRole, user.ID)
+
+	if us.workspaceID != nil {
+		member.WorkspaceID = *us.workspaceID
+	} else {
+		workspace := &models.WorkspaceModel{}
+
+		err := us.broker.FirstWhere(workspace, &models.WorkspaceModel{Slug: models.DefaultWorkspaceSlug}).Err()
+		if err != nil {
+			return err
+		}
+
+		member.WorkspaceID = workspace.ID
+	}
+
+	return us.broker.Save(member).Err()
+}
+
+// toQuery - returns query (and its arguments) matching the filter,
+// or nil query if there are no criteria.
+func (uf *UserFilter) toQuery() (interface{}, []interface{}) {
+	if uf == nil {
+		return nil, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+
+	if uf.Role != "" {
+		conditions = append(conditions, "role = ?")
+		args = append(args, uf.Role)
+	}
+
+	if uf.EmailPrefix != "" {
+		conditions = append(conditions, "LOWER(email) LIKE ?")
+		args = append(args, likePrefix(uf.EmailPrefix))
+	}
+
+	if uf.NamePrefix != "" {
+		conditions = append(conditions, "(LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?)")
+		args = append(args, likePrefix(uf.NamePrefix), likePrefix(uf.NamePrefix))
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// likePrefix - returns case-insensitive LIKE pattern matching values starting with given prefix.
+func likePrefix(prefix string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+	return escaper.Replace(strings.ToLower(prefix)) + "%"
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/el-Mike/gochat/mocks"
 	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -141,20 +142,58 @@ func (s *userServiceSuite) TestGetUserByEmail_Error() {
 func (s *userServiceSuite) TestGetUsers() {
 	userService := s.userService
 
+	page := persist.NewPageRequest(0, "", persist.SortAscending)
+
+	response := mocks.GetDefaultDBResponse()
+	response.SetNextCursor("test_cursor")
+
 	gormMock := new(mocks.GormMock)
 	gormMock.On(
-		"Find",
+		"FindPage",
 		mock.Anything,
+		page,
+		nil,
+		[]interface{}(nil),
+	).Return(response)
+
+	userService.broker = gormMock
+
+	_, nextCursor, err := userService.GetUsers(&UserFilter{}, page)
+
+	gormMock.AssertNumberOfCalls(s.T(), "FindPage", 1)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "test_cursor", nextCursor)
+}
+
+func (s *userServiceSuite) TestGetUsers_Filter() {
+	userService := s.userService
+
+	page := persist.NewPageRequest(0, "", persist.SortAscending)
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FindPage",
 		mock.Anything,
+		page,
+		"role = ? AND LOWER(email) LIKE ? AND (LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?)",
+		[]interface{}{s.testRole, `test\_%`, "jo%", "jo%"},
 	).Return(mocks.GetDefaultDBResponse())
 
 	userService.broker = gormMock
 
-	_, err := userService.GetUsers()
+	filter := &UserFilter{
+		Role:        s.testRole,
+		EmailPrefix: "Test_",
+		NamePrefix:  "Jo",
+	}
+
+	_, nextCursor, err := userService.GetUsers(filter, page)
 
-	gormMock.AssertNumberOfCalls(s.T(), "Find", 1)
+	gormMock.AssertNumberOfCalls(s.T(), "FindPage", 1)
 
 	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), nextCursor)
 }
 
 func (s *userServiceSuite) TestGetUsers_Error() {
@@ -162,18 +201,20 @@ func (s *userServiceSuite) TestGetUsers_Error() {
 
 	gormMock := new(mocks.GormMock)
 	gormMock.On(
-		"Find",
+		"FindPage",
+		mock.Anything,
+		mock.Anything,
 		mock.Anything,
 		mock.Anything,
 	).Return(mocks.GetErrorDBResponse(errors.New("GormError")))
 
 	userService.broker = gormMock
 
-	user, err := userService.GetUsers()
+	users, _, err := userService.GetUsers(nil, persist.NewPageRequest(0, "", persist.SortAscending))
 
-	gormMock.AssertNumberOfCalls(s.T(), "Find", 1)
+	gormMock.AssertNumberOfCalls(s.T(), "FindPage", 1)
 
-	assert.Nil(s.T(), user)
+	assert.Nil(s.T(), users)
 	assert.NotNil(s.T(), err)
 }
 
@@ -201,6 +242,45 @@ func (s *userServiceSuite) TestSaveUser() {
 	assert.Nil(s.T(), err)
 }
 
+func (s *userServiceSuite) TestSaveUser_New() {
+	userService := s.userService
+
+	defaultWorkspaceID := uuid.New()
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.WorkspaceModel).ID = defaultWorkspaceID
+	}).Return(mocks.GetDefaultDBResponse())
+
+	userService.broker = gormMock
+
+	err := userService.SaveUser(&models.UserModel{Email: "new@test.com"})
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 2)
+	gormMock.AssertCalled(
+		s.T(),
+		"FirstWhere",
+		mock.Anything,
+		&models.WorkspaceModel{Slug: models.DefaultWorkspaceSlug},
+		mock.Anything,
+	)
+
+	member := gormMock.Calls[2].Arguments.Get(0).(*models.WorkspaceMemberModel)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), defaultWorkspaceID, member.WorkspaceID)
+	assert.Equal(s.T(), models.WorkspaceMemberRole, member.Role)
+}
+
 func (s *userServiceSuite) TestSaveUser_Error() {
 	userService := s.userService
 
@@ -233,7 +313,7 @@ func (s *userServiceSuite) TestDeleteUserByID() {
 		"DeleteByID",
 		mock.Anything,
 		mock.Anything,
-	).Return(mocks.GetDefaultDBResponse())
+	).Return(mocks.GetRowsAffectedDBResponse(1))
 
 	userService.broker = gormMock
 
@@ -250,6 +330,25 @@ func (s *userServiceSuite) TestDeleteUserByID() {
 	assert.Nil(s.T(), err)
 }
 
+func (s *userServiceSuite) TestDeleteUserByID_NotFound() {
+	userService := s.userService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"DeleteByID",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetRowsAffectedDBResponse(0))
+
+	userService.broker = gormMock
+
+	err := userService.DeleteUserByID(s.testUserID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+
+	assert.Equal(s.T(), ErrUserNotFound, err)
+}
+
 func (s *userServiceSuite) TestDeleteUserByID_Error() {
 	userService := s.userService
 
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/el-Mike/gochat/models"
+	"github.com/el-Mike/gochat/persist"
+	"github.com/google/uuid"
+)
+
+// workspaceSlugPattern - slugs consist of lowercase letters and digits, separated by single hyphens.
+var workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var (
+	// ErrWorkspaceSlugInvalid - returned when creating a Workspace with malformed slug.
+	ErrWorkspaceSlugInvalid = errors.New("Slug may contain lowercase letters, digits and single hyphens only.")
+
+	// ErrWorkspaceSlugExists - returned when creating a Workspace with already used slug.
+	ErrWorkspaceSlugExists = errors.New("Workspace with this slug already exists.")
+
+	// ErrWorkspaceUserNotFound - returned when adding a user, who does not exist, to a Workspace.
+	ErrWorkspaceUserNotFound = errors.New("User does not exist.")
+
+	// ErrWorkspaceMemberNotFound - returned when given user is not a member of the Workspace.
+	ErrWorkspaceMemberNotFound = errors.New("User is not a member of the Workspace.")
+
+	// ErrWorkspaceRoleInvalid - returned when assigning a role other than admin or member.
+	ErrWorkspaceRoleInvalid = errors.New("Member's role has to be admin or member.")
+
+	// ErrWorkspaceLastAdmin - returned when removing or demoting the last admin of a Workspace.
+	ErrWorkspaceLastAdmin = errors.New("Workspace has to keep at least one admin.")
+)
+
+// WorkspaceService - struct for handling Workspace related logic.
+type WorkspaceService struct {
+	broker persist.DBBroker
+}
+
+// NewWorkspaceService - WorkspaceService constructor func.
+func NewWorkspaceService() *WorkspaceService {
+	return &WorkspaceService{
+		broker: persist.GormBroker,
+	}
+}
+
+// GetWorkspaceByID - returns single Workspace with given ID, together with its members.
+func (ws *WorkspaceService) GetWorkspaceByID(id uuid.UUID) (*models.WorkspaceModel, error) {
+	model := &models.WorkspaceModel{}
+
+	if err := ws.broker.First(model, id).Err(); err != nil {
+		return nil, err
+	}
+
+	var members []*models.WorkspaceMemberModel
+
+	if err := ws.broker.Find(&members, &models.WorkspaceMemberModel{WorkspaceID: id}).Err(); err != nil {
+		return nil, err
+	}
+
+	model.Members = members
+
+	return model, nil
+}
+
+// GetWorkspacesByUserID - returns all the Workspaces given user is a member of.
+func (ws *WorkspaceService) GetWorkspacesByUserID(userID uuid.UUID) ([]*models.WorkspaceModel, error) {
+	var memberships []*models.WorkspaceMemberModel
+
+	if err := ws.broker.Find(&memberships, &models.WorkspaceMemberModel{UserID: userID}).Err(); err != nil {
+		return nil, err
+	}
+
+	if len(memberships) == 0 {
+		return []*models.WorkspaceModel{}, nil
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(memberships))
+
+	for _, membership := range memberships {
+		workspaceIDs = append(workspaceIDs, membership.WorkspaceID)
+	}
+
+	var workspaces []*models.WorkspaceModel
+
+	if err := ws.broker.Find(&workspaces, "id IN ?", workspaceIDs).Err(); err != nil {
+		return nil, err
+	}
+
+	// Only user's own membership is returned with each Workspace.
+	for _, workspace := range workspaces {
+		for _, membership := range memberships {
+			if membership.WorkspaceID == workspace.ID {
+				workspace.Members = []*models.WorkspaceMemberModel{membership}
+			}
+		}
+	}
+
+	return workspaces, nil
+}
+
+// CreateWorkspace - creates a new Workspace, with its creator as the admin.
+func (ws *WorkspaceService) CreateWorkspace(creatorID uuid.UUID, name, slug string) (*models.WorkspaceModel, error) {
+	if !workspaceSlugPattern.MatchString(slug) {
+		return nil, ErrWorkspaceSlugInvalid
+	}
+
+	if err := ws.broker.FirstWhere(&models.WorkspaceModel{}, &models.WorkspaceModel{Slug: slug}).Err(); err == nil {
+		return nil, ErrWorkspaceSlugExists
+	}
+
+	workspace := &models.WorkspaceModel{
+		Name: name,
+		Slug: slug,
+		Members: []*models.WorkspaceMemberModel{
+			newMember(creatorID, models.WorkspaceAdminRole, creatorID),
+		},
+	}
+
+	workspace.CreatedBy = creatorID
+	workspace.UpdatedBy = creatorID
+
+	if err := ws.broker.Save(workspace).Err(); err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// RenameWorkspace - sets the name of the Workspace.
+func (ws *WorkspaceService) RenameWorkspace(workspace *models.WorkspaceModel, name string, updatedBy uuid.UUID) error {
+	workspace.Name = name
+	workspace.UpdatedBy = updatedBy
+
+	// Members are not meant to be saved together with the Workspace.
+	update := *workspace
+	update.Members = nil
+
+	return ws.broker.Save(&update).Err()
+}
+
+// SetMember - adds given user to the Workspace with given role, or changes their role
+// if they are a member already.
+func (ws *WorkspaceService) SetMember(
+	workspace *models.WorkspaceModel,
+	userID uuid.UUID,
+	role string,
+	updatedBy uuid.UUID,
+) (*models.WorkspaceMemberModel, error) {
+	if role != models.WorkspaceAdminRole && role != models.WorkspaceMemberRole {
+		return nil, ErrWorkspaceRoleInvalid
+	}
+
+	member := workspace.GetMember(userID)
+
+	if member == nil {
+		if err := ws.broker.First(&models.UserModel{}, userID).Err(); err != nil {
+			return nil, ErrWorkspaceUserNotFound
+		}
+
+		member = newMember(userID, role, updatedBy)
+		member.WorkspaceID = workspace.ID
+
+		if err := ws.broker.Save(member).Err(); err != nil {
+			return nil, err
+		}
+
+		workspace.Members = append(workspace.Members, member)
+
+		return member, nil
+	}
+
+	if member.Role == models.WorkspaceAdminRole && role != models.WorkspaceAdminRole && workspace.CountAdmins() == 1 {
+		return nil, ErrWorkspaceLastAdmin
+	}
+
+	previousRole := member.Role
+
+	member.Role = role
+	member.UpdatedBy = updatedBy
+
+	if err := ws.broker.Save(member).Err(); err != nil {
+		member.Role = previousRole
+
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember - removes given user from the Workspace. Conversations they participate in
+// are kept, but they cannot access them anymore.
+func (ws *WorkspaceService) RemoveMember(workspace *models.WorkspaceModel, userID uuid.UUID) error {
+	for i, member := range workspace.Members {
+		if member.UserID != userID {
+			continue
+		}
+
+		if member.Role == models.WorkspaceAdminRole && workspace.CountAdmins() == 1 {
+			return ErrWorkspaceLastAdmin
+		}
+
+		if err := ws.broker.DeleteByID(&models.WorkspaceMemberModel{}, member.ID).Err(); err != nil {
+			return err
+		}
+
+		workspace.Members = append(workspace.Members[:i], workspace.Members[i+1:]...)
+
+		return nil
+	}
+
+	return ErrWorkspaceMemberNotFound
+}
+
+func newMember(userID uuid.UUID, role string, createdBy uuid.UUID) *models.WorkspaceMemberModel {
+	member := &models.WorkspaceMemberModel{
+		UserID: userID,
+		Role:   role,
+	}
+
+	member.CreatedBy = createdBy
+	member.UpdatedBy = createdBy
+
+	return member
+}
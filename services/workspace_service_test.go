@@ -0,0 +1,277 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/el-Mike/gochat/mocks"
+	"github.com/el-Mike/gochat/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type workspaceServiceSuite struct {
+	suite.Suite
+	workspaceService *WorkspaceService
+	testWorkspaceID  uuid.UUID
+	testAdminID      uuid.UUID
+	testMemberID     uuid.UUID
+}
+
+func (s *workspaceServiceSuite) SetupSuite() {
+	s.testWorkspaceID = uuid.New()
+	s.testAdminID = uuid.New()
+	s.testMemberID = uuid.New()
+}
+
+func (s *workspaceServiceSuite) SetupTest() {
+	s.workspaceService = &WorkspaceService{
+		broker: mocks.NewGormMock(),
+	}
+}
+
+func TestWorkspaceServiceSuite(t *testing.T) {
+	suite.Run(t, new(workspaceServiceSuite))
+}
+
+func (s *workspaceServiceSuite) getTestWorkspace() *models.WorkspaceModel {
+	workspace := &models.WorkspaceModel{
+		BaseModel: models.BaseModel{ID: s.testWorkspaceID, CreatedBy: s.testAdminID},
+		Name:      "Test",
+		Slug:      "test",
+	}
+
+	workspace.Members = []*models.WorkspaceMemberModel{
+		{
+			BaseModel:   models.BaseModel{ID: uuid.New()},
+			WorkspaceID: s.testWorkspaceID,
+			UserID:      s.testAdminID,
+			Role:        models.WorkspaceAdminRole,
+		},
+		{
+			BaseModel:   models.BaseModel{ID: uuid.New()},
+			WorkspaceID: s.testWorkspaceID,
+			UserID:      s.testMemberID,
+			Role:        models.WorkspaceMemberRole,
+		},
+	}
+
+	return workspace
+}
+
+func (s *workspaceServiceSuite) TestNewWorkspaceService() {
+	workspaceService := NewWorkspaceService()
+
+	assert.NotNil(s.T(), workspaceService)
+}
+
+func (s *workspaceServiceSuite) TestGetWorkspacesByUserID_NoMemberships() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Find",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	workspaces, err := workspaceService.GetWorkspacesByUserID(s.testMemberID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "Find", 1)
+
+	assert.Empty(s.T(), workspaces)
+	assert.Nil(s.T(), err)
+}
+
+func (s *workspaceServiceSuite) TestCreateWorkspace() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	workspace, err := workspaceService.CreateWorkspace(s.testAdminID, "Test", "test-workspace")
+
+	gormMock.AssertNumberOfCalls(s.T(), "Save", 1)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "test-workspace", workspace.Slug)
+	assert.Equal(s.T(), s.testAdminID, workspace.CreatedBy)
+	assert.Equal(s.T(), models.WorkspaceAdminRole, workspace.GetMember(s.testAdminID).Role)
+}
+
+func (s *workspaceServiceSuite) TestCreateWorkspace_SlugInvalid() {
+	_, err := s.workspaceService.CreateWorkspace(s.testAdminID, "Test", "Test Workspace")
+
+	assert.Equal(s.T(), ErrWorkspaceSlugInvalid, err)
+}
+
+func (s *workspaceServiceSuite) TestCreateWorkspace_SlugExists() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"FirstWhere",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	_, err := workspaceService.CreateWorkspace(s.testAdminID, "Test", "test")
+
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+
+	assert.Equal(s.T(), ErrWorkspaceSlugExists, err)
+}
+
+func (s *workspaceServiceSuite) TestRenameWorkspace() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	workspace := s.getTestWorkspace()
+
+	err := workspaceService.RenameWorkspace(workspace, "Renamed", s.testAdminID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "Renamed", workspace.Name)
+	assert.Len(s.T(), workspace.Members, 2)
+
+	saved := gormMock.Calls[0].Arguments.Get(0).(*models.WorkspaceModel)
+
+	assert.Equal(s.T(), "Renamed", saved.Name)
+	assert.Nil(s.T(), saved.Members)
+}
+
+func (s *workspaceServiceSuite) TestSetMember_New() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"First",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	workspace := s.getTestWorkspace()
+	userID := uuid.New()
+
+	member, err := workspaceService.SetMember(workspace, userID, models.WorkspaceMemberRole, s.testAdminID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.testWorkspaceID, member.WorkspaceID)
+	assert.Equal(s.T(), member, workspace.GetMember(userID))
+}
+
+func (s *workspaceServiceSuite) TestSetMember_UserNotFound() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"First",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetErrorDBResponse(errors.New("record not found")))
+
+	workspaceService.broker = gormMock
+
+	_, err := workspaceService.SetMember(s.getTestWorkspace(), uuid.New(), models.WorkspaceMemberRole, s.testAdminID)
+
+	gormMock.AssertNotCalled(s.T(), "Save", mock.Anything)
+
+	assert.Equal(s.T(), ErrWorkspaceUserNotFound, err)
+}
+
+func (s *workspaceServiceSuite) TestSetMember_Promote() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"Save",
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	workspace := s.getTestWorkspace()
+
+	member, err := workspaceService.SetMember(workspace, s.testMemberID, models.WorkspaceAdminRole, s.testAdminID)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), models.WorkspaceAdminRole, member.Role)
+	assert.Equal(s.T(), 2, workspace.CountAdmins())
+}
+
+func (s *workspaceServiceSuite) TestSetMember_LastAdmin() {
+	_, err := s.workspaceService.SetMember(s.getTestWorkspace(), s.testAdminID, models.WorkspaceMemberRole, s.testAdminID)
+
+	assert.Equal(s.T(), ErrWorkspaceLastAdmin, err)
+}
+
+func (s *workspaceServiceSuite) TestSetMember_RoleInvalid() {
+	_, err := s.workspaceService.SetMember(s.getTestWorkspace(), s.testMemberID, "owner", s.testAdminID)
+
+	assert.Equal(s.T(), ErrWorkspaceRoleInvalid, err)
+}
+
+func (s *workspaceServiceSuite) TestRemoveMember() {
+	workspaceService := s.workspaceService
+
+	gormMock := new(mocks.GormMock)
+	gormMock.On(
+		"DeleteByID",
+		mock.Anything,
+		mock.Anything,
+	).Return(mocks.GetDefaultDBResponse())
+
+	workspaceService.broker = gormMock
+
+	workspace := s.getTestWorkspace()
+
+	err := workspaceService.RemoveMember(workspace, s.testMemberID)
+
+	gormMock.AssertNumberOfCalls(s.T(), "DeleteByID", 1)
+
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), workspace.GetMember(s.testMemberID))
+}
+
+func (s *workspaceServiceSuite) TestRemoveMember_LastAdmin() {
+	err := s.workspaceService.RemoveMember(s.getTestWorkspace(), s.testAdminID)
+
+	assert.Equal(s.T(), ErrWorkspaceLastAdmin, err)
+}
+
+func (s *workspaceServiceSuite) TestRemoveMember_NotFound() {
+	err := s.workspaceService.RemoveMember(s.getTestWorkspace(), uuid.New())
+
+	assert.Equal(s.T(), ErrWorkspaceMemberNotFound, err)
+}